@@ -89,9 +89,12 @@ func main() {
 	// Register analysis tools
 	registry.Register(&tools.TriageScanTool{BaseTool: baseTool, HasOperator: hasOperator})
 	registry.Register(&tools.CheckConfigTool{BaseTool: baseTool, HasOperator: hasOperator})
+	registry.Register(&tools.TopologyTool{BaseTool: baseTool, HasOperator: hasOperator})
 
 	// Conditionally register v2 tools
 	if cfg.V2Enabled {
+		// Sessions do not survive a restart; undo what the previous run left
+		session.RecoverOrphanedSessions(ctx, clients.Clientset, clients.DynamicClient, cfg.ArgoCDNamespace)
 		sessionMgr := session.NewManager(cfg.SessionTTL, cfg.MaxConcurrentSessions)
 		go sessionMgr.StartCleanupLoop(ctx)
 		tools.RegisterV2Tools(registry, baseTool, sessionMgr)
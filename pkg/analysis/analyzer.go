@@ -6,6 +6,8 @@ import (
 	"github.com/hrexed/otel-collector-mcp/pkg/collector"
 	"github.com/hrexed/otel-collector-mcp/pkg/types"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 )
 
 // Analyzer is the function signature for all detection rules.
@@ -18,6 +20,21 @@ type AnalysisInput struct {
 	Logs         []string
 	OperatorLogs []string
 	PodInfo      *corev1.Pod
+	Environment  string // dev, staging, production; empty when unknown
+	Replicas     int32  // desired replicas of a Deployment/StatefulSet; 0 when unknown
+
+	// OperatorCR is the OpenTelemetryCollector object managing the collector,
+	// or nil when it is not operator-managed.
+	OperatorCR map[string]interface{}
+
+	// Clientset and Namespace let analyzers query related cluster objects.
+	// Analyzers must skip those checks when Clientset is nil.
+	Clientset kubernetes.Interface
+	Namespace string
+
+	// Metadata reads object metadata only. When set, Secret existence is
+	// checked without downloading the Secret data.
+	Metadata metadata.Interface
 }
 
 // AllAnalyzers returns all registered config-based analyzers.
@@ -27,13 +44,23 @@ func AllAnalyzers() []Analyzer {
 		AnalyzeMissingMemoryLimiter,
 		AnalyzeHardcodedTokens,
 		AnalyzeMissingRetryQueue,
+		AnalyzeSendingQueue,
 		AnalyzeReceiverBindings,
-		AnalyzeTailSamplingDaemonSet,
+		AnalyzeDeploymentModeFitness,
+		AnalyzeLoadBalancing,
+		AnalyzePrometheusScrape,
+		AnalyzeTargetAllocator,
 		AnalyzeInvalidRegex,
+		AnalyzeOTTL,
 		AnalyzeConnectorMisconfig,
 		AnalyzeResourceDetectorConflicts,
 		AnalyzeCumulativeDelta,
 		AnalyzeHighCardinality,
+		AnalyzeTransportSecurity,
+		AnalyzeExtensions,
+		AnalyzeServiceTelemetry,
+		AnalyzeRBAC,
+		AnalyzeEnvReferences,
 	}
 }
 
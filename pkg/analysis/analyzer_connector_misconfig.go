@@ -41,27 +41,33 @@ func AnalyzeConnectorMisconfig(_ context.Context, input *AnalysisInput) []types.
 
 		if !usedAsExporter && !usedAsReceiver {
 			findings = append(findings, types.DiagnosticFinding{
+				RuleID:     types.RuleConnectorUnused,
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryConfig,
 				Summary:    fmt.Sprintf("Connector %q is defined but not used in any pipeline", connName),
 				Detail:     "This connector is configured but does not appear as an exporter or receiver in any pipeline. It will have no effect.",
 				Suggestion: "Add the connector to the appropriate pipelines or remove it",
+				Evidence:   componentEvidence(connName, ""),
 			})
 		} else if !usedAsExporter {
 			findings = append(findings, types.DiagnosticFinding{
+				RuleID:     types.RuleConnectorNotExporter,
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryConfig,
 				Summary:    fmt.Sprintf("Connector %q is not used as an exporter in any pipeline", connName),
 				Detail:     "A connector must appear as an exporter in one pipeline (source) and a receiver in another (destination). This connector is missing its source pipeline.",
 				Suggestion: "Add the connector as an exporter in the source pipeline",
+				Evidence:   componentEvidence(connName, ""),
 			})
 		} else if !usedAsReceiver {
 			findings = append(findings, types.DiagnosticFinding{
+				RuleID:     types.RuleConnectorNotReceiver,
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryConfig,
 				Summary:    fmt.Sprintf("Connector %q is not used as a receiver in any pipeline", connName),
 				Detail:     "A connector must appear as an exporter in one pipeline (source) and a receiver in another (destination). This connector is missing its destination pipeline.",
 				Suggestion: "Add the connector as a receiver in the destination pipeline",
+				Evidence:   componentEvidence(connName, ""),
 			})
 		}
 	}
@@ -20,6 +20,7 @@ func AnalyzeCumulativeDelta(_ context.Context, input *AnalysisInput) []types.Dia
 		if pipelineHasProcessor(pipeline, "cumulativetodelta") {
 			if input.DeployMode == collector.ModeDeployment || input.DeployMode == collector.ModeDaemonSet {
 				findings = append(findings, types.DiagnosticFinding{
+					RuleID:   types.RuleCumulativeDeltaStateless,
 					Severity: types.SeverityWarning,
 					Category: types.CategoryConfig,
 					Summary:  fmt.Sprintf("cumulativetodelta processor in pipeline %q on non-stateful deployment (%s)", pipelineName, input.DeployMode),
@@ -28,6 +29,7 @@ func AnalyzeCumulativeDelta(_ context.Context, input *AnalysisInput) []types.Dia
 					Remediation: `# Option 1: Use a StatefulSet for stateful processing
 # Option 2: Accept counter resets and configure your backend to handle them
 # Option 3: Use the metrics_transform processor for simpler conversions`,
+					Evidence: pipelineEvidence(pipelineName, pipelineProcessorID(pipeline, "cumulativetodelta")),
 				})
 			}
 		}
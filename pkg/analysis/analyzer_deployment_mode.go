@@ -0,0 +1,260 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+)
+
+// nodeScopedReceivers collect data from the node the collector runs on and
+// belong in a DaemonSet.
+var nodeScopedReceivers = map[string]string{
+	"hostmetrics":  "host metrics",
+	"kubeletstats": "kubelet stats",
+	"filelog":      "container logs",
+}
+
+// clusterScopedReceivers collect cluster-wide data and must run as a single instance.
+var clusterScopedReceivers = map[string]bool{
+	"k8s_cluster": true,
+	"k8sobjects":  true,
+}
+
+// AnalyzeDeploymentModeFitness checks that receivers, processors and connectors
+// suit the collector's deployment mode: node-scoped receivers outside a DaemonSet,
+// cluster-scoped receivers in a DaemonSet, tail sampling on per-node agents,
+// prometheus scraping and spanmetrics on scaled gateways, and filelog without
+// host log mounts.
+func AnalyzeDeploymentModeFitness(_ context.Context, input *AnalysisInput) []types.DiagnosticFinding {
+	if input.Config == nil {
+		return nil
+	}
+
+	mode := effectiveDeployMode(input)
+	used := usedReceivers(input.Config)
+	scaled := (mode == collector.ModeDeployment || mode == collector.ModeStatefulSet) && input.Replicas > 1
+
+	var findings []types.DiagnosticFinding
+
+	if mode == collector.ModeDaemonSet {
+		for name, pipeline := range input.Config.Service.Pipelines {
+			if pipelineHasProcessor(pipeline, "tail_sampling") {
+				findings = append(findings, types.DiagnosticFinding{
+					RuleID:     types.RuleTailSamplingDaemonSet,
+					Severity:   types.SeverityCritical,
+					Category:   types.CategoryConfig,
+					Summary:    "Tail sampling configured on a DaemonSet collector in pipeline " + name,
+					Detail:     "Tail sampling requires all spans for a trace to reach the same collector instance. In a DaemonSet deployment, spans from different pods land on different collector nodes, making tail sampling decisions incorrect. This leads to incomplete traces and sampling bias.",
+					Suggestion: "Move tail sampling to a gateway collector running as a Deployment or StatefulSet",
+					Remediation: `# Tail sampling must run on a centralized gateway, not per-node agents.
+# Architecture pattern:
+#   DaemonSet (agent) -> Deployment/StatefulSet (gateway with tail_sampling)
+#
+# Remove tail_sampling from the DaemonSet pipeline and configure it
+# on a centralized gateway collector instead.`,
+					Evidence: pipelineEvidence(name, pipelineProcessorID(pipeline, "tail_sampling")),
+				})
+			}
+		}
+	}
+
+	for _, id := range sortedKeys(input.Config.Receivers) {
+		if !used[id] {
+			continue
+		}
+		typ := componentType(id)
+		cfg, _ := input.Config.Receivers[id].(map[string]interface{})
+
+		if what, ok := nodeScopedReceivers[typ]; ok && (mode == collector.ModeDeployment || mode == collector.ModeStatefulSet) {
+			findings = append(findings, types.DiagnosticFinding{
+				RuleID:     types.RuleNodeReceiverNotDaemon,
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryConfig,
+				Summary:    fmt.Sprintf("Receiver %q runs in a %s instead of a DaemonSet", id, mode),
+				Detail:     fmt.Sprintf("%s collects %s from the node it runs on. In a %s it only sees the nodes its replicas happen to be scheduled on, so most nodes are not covered.", typ, what, mode),
+				Suggestion: fmt.Sprintf("Move %s to an agent collector deployed as a DaemonSet", id),
+				Remediation: `# Run node-scoped receivers in a DaemonSet agent:
+apiVersion: opentelemetry.io/v1beta1
+kind: OpenTelemetryCollector
+metadata:
+  name: agent
+spec:
+  mode: daemonset`,
+				Evidence: componentEvidence(id, ""),
+			})
+		}
+
+		if clusterScopedReceivers[typ] && mode == collector.ModeDaemonSet {
+			findings = append(findings, types.DiagnosticFinding{
+				RuleID:     types.RuleClusterReceiverDaemon,
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryConfig,
+				Summary:    fmt.Sprintf("Cluster-scoped receiver %q runs in a DaemonSet", id),
+				Detail:     fmt.Sprintf("%s watches the Kubernetes API for the whole cluster. Every DaemonSet pod emits the same data, so it is duplicated once per node and the API server load grows with cluster size.", typ),
+				Suggestion: fmt.Sprintf("Move %s to a single-replica Deployment", id),
+				Remediation: `# Run cluster-scoped receivers in a single-replica collector:
+apiVersion: opentelemetry.io/v1beta1
+kind: OpenTelemetryCollector
+metadata:
+  name: cluster-receiver
+spec:
+  mode: deployment
+  replicas: 1`,
+				Evidence: componentEvidence(id, ""),
+			})
+		}
+
+		if typ == "prometheus" && scaled {
+			if _, hasTA := cfg["target_allocator"]; !hasTA {
+				findings = append(findings, types.DiagnosticFinding{
+					RuleID:     types.RuleScrapeReplicasNoTA,
+					Severity:   types.SeverityWarning,
+					Category:   types.CategoryConfig,
+					Summary:    fmt.Sprintf("Receiver %q scrapes from %d replicas without a target allocator", id, input.Replicas),
+					Detail:     "Every replica runs the same scrape configuration, so each target is scraped once per replica. This duplicates samples and causes out-of-order or duplicate-sample errors in the metrics backend.",
+					Suggestion: "Enable the Target Allocator to shard targets across replicas, or run a single replica",
+					Remediation: fmt.Sprintf(`# OpenTelemetryCollector CR
+spec:
+  mode: statefulset
+  targetAllocator:
+    enabled: true
+    allocationStrategy: consistent-hashing
+
+# collector config
+receivers:
+  %s:
+    target_allocator:
+      endpoint: http://${env:OTEL_TA_SERVICE}:80
+      interval: 30s
+      collector_id: ${env:POD_NAME}`, id),
+					Evidence: &types.Evidence{SignalType: "metrics", ComponentID: id, Count: int(input.Replicas)},
+				})
+			}
+		}
+
+		if typ == "filelog" {
+			findings = append(findings, checkFilelogMounts(input, id, cfg)...)
+		}
+	}
+
+	if scaled {
+		for _, id := range sortedKeys(input.Config.Connectors) {
+			if componentType(id) != "spanmetrics" || !used[id] {
+				continue
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				RuleID:     types.RuleConnectorReplicas,
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryConfig,
+				Summary:    fmt.Sprintf("Connector %q runs on %d replicas behind a non-trace-aware load balancer", id, input.Replicas),
+				Detail:     "A Kubernetes Service spreads spans of the same service across all replicas. Each replica then emits its own cumulative series for the same service and operation, which the backend sees as conflicting or resetting counters.",
+				Suggestion: "Route spans by service name with the loadbalancing exporter in the agent tier and a headless Service in front of this collector",
+				Remediation: `# Agent tier
+exporters:
+  loadbalancing:
+    routing_key: service
+    protocol:
+      otlp:
+        tls:
+          insecure: true
+    resolver:
+      k8s:
+        service: <gateway-headless-service>.<namespace>`,
+				Evidence: &types.Evidence{SignalType: "traces", ComponentID: id, Count: int(input.Replicas)},
+			})
+		}
+	}
+
+	return findings
+}
+
+// effectiveDeployMode resolves the workload kind, falling back to the pod's
+// owner reference when the collector is managed by an operator CRD.
+func effectiveDeployMode(input *AnalysisInput) collector.DeploymentMode {
+	switch input.DeployMode {
+	case collector.ModeDaemonSet, collector.ModeDeployment, collector.ModeStatefulSet:
+		return input.DeployMode
+	}
+	if input.PodInfo != nil {
+		for _, ref := range input.PodInfo.OwnerReferences {
+			switch ref.Kind {
+			case "DaemonSet":
+				return collector.ModeDaemonSet
+			case "ReplicaSet":
+				return collector.ModeDeployment
+			case "StatefulSet":
+				return collector.ModeStatefulSet
+			}
+		}
+	}
+	return input.DeployMode
+}
+
+// checkFilelogMounts verifies that every filelog include path is served by a
+// hostPath volume mounted into the collector container.
+func checkFilelogMounts(input *AnalysisInput, id string, cfg map[string]interface{}) []types.DiagnosticFinding {
+	c := collectorContainer(input.PodInfo)
+	if c == nil {
+		return nil
+	}
+	hostPaths := make(map[string]string)
+	for _, v := range input.PodInfo.Spec.Volumes {
+		if v.HostPath != nil {
+			hostPaths[v.Name] = v.HostPath.Path
+		}
+	}
+
+	includes, _ := cfg["include"].([]interface{})
+	var unmounted []string
+	for _, inc := range includes {
+		pattern, ok := inc.(string)
+		if !ok || pattern == "" {
+			continue
+		}
+		dir := pattern
+		if i := strings.IndexAny(dir, "*?[{"); i >= 0 {
+			dir = path.Dir(dir[:i] + "x")
+		}
+		mounted := false
+		for _, m := range c.VolumeMounts {
+			if _, isHost := hostPaths[m.Name]; !isHost {
+				continue
+			}
+			mp := strings.TrimSuffix(m.MountPath, "/")
+			if dir == mp || strings.HasPrefix(dir, mp+"/") {
+				mounted = true
+				break
+			}
+		}
+		if !mounted {
+			unmounted = append(unmounted, pattern)
+		}
+	}
+	if len(unmounted) == 0 {
+		return nil
+	}
+
+	return []types.DiagnosticFinding{{
+		RuleID:     types.RuleFilelogPathsNotMounted,
+		Severity:   types.SeverityCritical,
+		Category:   types.CategoryConfig,
+		Summary:    fmt.Sprintf("Receiver %q reads paths not mounted from the host: %s", id, strings.Join(unmounted, ", ")),
+		Detail:     fmt.Sprintf("Container %q has no hostPath volume mounted at these paths, so filelog only sees the container's own filesystem and collects nothing.", c.Name),
+		Suggestion: "Mount the node's log directories into the collector container with hostPath volumes",
+		Remediation: `volumes:
+  - name: varlogpods
+    hostPath:
+      path: /var/log/pods
+containers:
+  - name: otc-container
+    volumeMounts:
+      - name: varlogpods
+        mountPath: /var/log/pods
+        readOnly: true`,
+		Evidence: &types.Evidence{SignalType: "logs", ComponentID: id, Path: "include", SampleValues: unmounted},
+	}}
+}
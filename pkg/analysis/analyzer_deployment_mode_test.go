@@ -0,0 +1,153 @@
+package analysis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func receiversConfig(receivers map[string]interface{}) *collector.CollectorConfig {
+	var ids []string
+	for id := range receivers {
+		ids = append(ids, id)
+	}
+	return &collector.CollectorConfig{
+		Receivers: receivers,
+		Service: collector.ServiceConfig{
+			Pipelines: map[string]collector.PipelineConfig{
+				"logs": {Receivers: ids, Exporters: []string{"otlp"}},
+			},
+		},
+	}
+}
+
+func filelogPod(mountPath string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			OwnerReferences: []metav1.OwnerReference{{Kind: "DaemonSet", Name: "agent"}},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "otc-container"}},
+		},
+	}
+	if mountPath != "" {
+		pod.Spec.Volumes = []corev1.Volume{{
+			Name:         "varlogpods",
+			VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: mountPath}},
+		}}
+		pod.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{{Name: "varlogpods", MountPath: mountPath}}
+	}
+	return pod
+}
+
+func TestAnalyzeDeploymentModeFitness(t *testing.T) {
+	filelog := map[string]interface{}{
+		"filelog": map[string]interface{}{"include": []interface{}{"/var/log/pods/*/*/*.log"}},
+	}
+
+	tests := []struct {
+		name           string
+		config         *collector.CollectorConfig
+		mode           collector.DeploymentMode
+		replicas       int32
+		pod            *corev1.Pod
+		expectSeverity []string
+	}{
+		{
+			name:   "nil config returns no findings",
+			config: nil,
+			mode:   collector.ModeDaemonSet,
+		},
+		{
+			name: "tail sampling on DaemonSet",
+			config: &collector.CollectorConfig{
+				Service: collector.ServiceConfig{
+					Pipelines: map[string]collector.PipelineConfig{
+						"traces": {Receivers: []string{"otlp"}, Processors: []string{"tail_sampling"}, Exporters: []string{"otlp"}},
+					},
+				},
+			},
+			mode:           collector.ModeDaemonSet,
+			expectSeverity: []string{types.SeverityCritical},
+		},
+		{
+			name:           "hostmetrics in a Deployment",
+			config:         receiversConfig(map[string]interface{}{"hostmetrics": map[string]interface{}{}}),
+			mode:           collector.ModeDeployment,
+			expectSeverity: []string{types.SeverityWarning},
+		},
+		{
+			name:   "hostmetrics defined but unused",
+			config: &collector.CollectorConfig{Receivers: map[string]interface{}{"hostmetrics": map[string]interface{}{}}},
+			mode:   collector.ModeDeployment,
+		},
+		{
+			name:           "k8s_cluster in a DaemonSet",
+			config:         receiversConfig(map[string]interface{}{"k8s_cluster": map[string]interface{}{}}),
+			mode:           collector.ModeDaemonSet,
+			expectSeverity: []string{types.SeverityWarning},
+		},
+		{
+			name:           "prometheus on scaled Deployment without target allocator",
+			config:         receiversConfig(map[string]interface{}{"prometheus": map[string]interface{}{"config": map[string]interface{}{}}}),
+			mode:           collector.ModeDeployment,
+			replicas:       3,
+			expectSeverity: []string{types.SeverityWarning},
+		},
+		{
+			name: "prometheus with target allocator",
+			config: receiversConfig(map[string]interface{}{"prometheus": map[string]interface{}{
+				"target_allocator": map[string]interface{}{"endpoint": "http://ta:80"},
+			}}),
+			mode:     collector.ModeStatefulSet,
+			replicas: 3,
+		},
+		{
+			name: "spanmetrics on scaled gateway",
+			config: &collector.CollectorConfig{
+				Connectors: map[string]interface{}{"spanmetrics": map[string]interface{}{}},
+				Service: collector.ServiceConfig{
+					Pipelines: map[string]collector.PipelineConfig{
+						"traces":  {Receivers: []string{"otlp"}, Exporters: []string{"spanmetrics"}},
+						"metrics": {Receivers: []string{"spanmetrics"}, Exporters: []string{"otlp"}},
+					},
+				},
+			},
+			mode:           collector.ModeDeployment,
+			replicas:       2,
+			expectSeverity: []string{types.SeverityWarning},
+		},
+		{
+			name:           "filelog without hostPath mount, mode from owner reference",
+			config:         receiversConfig(filelog),
+			mode:           collector.ModeOperatorCRD,
+			pod:            filelogPod(""),
+			expectSeverity: []string{types.SeverityCritical},
+		},
+		{
+			name:   "filelog with hostPath mount",
+			config: receiversConfig(filelog),
+			mode:   collector.ModeDaemonSet,
+			pod:    filelogPod("/var/log/pods"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &AnalysisInput{Config: tt.config, DeployMode: tt.mode, Replicas: tt.replicas, PodInfo: tt.pod}
+			findings := AnalyzeDeploymentModeFitness(context.Background(), input)
+			if len(findings) != len(tt.expectSeverity) {
+				t.Fatalf("expected %d findings, got %d: %+v", len(tt.expectSeverity), len(findings), findings)
+			}
+			for i, f := range findings {
+				if f.Severity != tt.expectSeverity[i] {
+					t.Errorf("finding %d: expected severity %s, got %s (%s)", i, tt.expectSeverity[i], f.Severity, f.Summary)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// secretsGVR addresses Secrets through the metadata client.
+var secretsGVR = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+var (
+	// envBracedPattern matches ${env:NAME}, ${NAME} and the ${env:NAME:-default} form.
+	envBracedPattern = regexp.MustCompile(`\$\{(?:env:)?([A-Za-z_][A-Za-z0-9_]*)(:-[^}]*)?\}`)
+	// envBarePattern matches the legacy $NAME form, skipping escaped $$NAME.
+	envBarePattern = regexp.MustCompile(`(^|[^$])\$([A-Za-z_][A-Za-z0-9_]*)`)
+)
+
+// envReference is an environment variable referenced from the collector config.
+type envReference struct {
+	name string
+	path string // first config location referencing the variable
+}
+
+// AnalyzeEnvReferences cross-references every environment variable used in the
+// config with the collector container's env and envFrom, and confirms that the
+// Secrets and ConfigMaps those come from exist and hold the referenced keys.
+// Secret values are never read: only key names are compared.
+func AnalyzeEnvReferences(ctx context.Context, input *AnalysisInput) []types.DiagnosticFinding {
+	if input.Config == nil {
+		return nil
+	}
+	c := collectorContainer(input.PodInfo)
+	if c == nil {
+		return nil
+	}
+	refs := configEnvReferences(input)
+	if len(refs) == 0 {
+		return nil
+	}
+
+	resolver := &envSourceResolver{input: input, cache: make(map[string]envSource)}
+	env := make(map[string]corev1.EnvVar)
+	for _, e := range c.Env {
+		env[e.Name] = e
+	}
+
+	var findings []types.DiagnosticFinding
+	for _, ref := range refs {
+		if e, ok := env[ref.name]; ok {
+			if f := resolver.checkEnvVar(ctx, c.Name, ref, e); f != nil {
+				findings = append(findings, *f)
+			}
+			continue
+		}
+
+		resolved, unknown := false, false
+		for _, from := range c.EnvFrom {
+			if !strings.HasPrefix(ref.name, from.Prefix) {
+				continue
+			}
+			key := strings.TrimPrefix(ref.name, from.Prefix)
+			var src envSource
+			switch {
+			case from.SecretRef != nil:
+				src = resolver.lookup(ctx, "Secret", from.SecretRef.Name)
+			case from.ConfigMapRef != nil:
+				src = resolver.lookup(ctx, "ConfigMap", from.ConfigMapRef.Name)
+			default:
+				continue
+			}
+			if !src.known {
+				unknown = true
+				continue
+			}
+			if src.keys[key] {
+				resolved = true
+				break
+			}
+		}
+		if resolved || unknown {
+			continue
+		}
+
+		findings = append(findings, types.DiagnosticFinding{
+			RuleID:     types.RuleEnvUndefined,
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConfig,
+			Summary:    fmt.Sprintf("Config references ${env:%s} but container %q does not define it", ref.name, c.Name),
+			Detail:     fmt.Sprintf("%s uses ${env:%s}, which is neither set in env nor provided by any envFrom source. The collector expands it to an empty string, which typically fails config validation or silently drops credentials such as API tokens.", ref.path, ref.name),
+			Suggestion: fmt.Sprintf("Define %s on the collector container, usually from a Secret", ref.name),
+			Remediation: fmt.Sprintf(`env:
+  - name: %s
+    valueFrom:
+      secretKeyRef:
+        name: <secret-name>
+        key: <key>`, ref.name),
+			Evidence: envEvidence(ref),
+		})
+	}
+	return findings
+}
+
+// configEnvReferences returns the environment variables referenced without a
+// default value anywhere in the config, sorted by name.
+func configEnvReferences(input *AnalysisInput) []envReference {
+	seen := make(map[string]string)
+	var walk func(v interface{}, path string)
+	walk = func(v interface{}, path string) {
+		switch val := v.(type) {
+		case map[string]interface{}:
+			for _, k := range sortedKeys(val) {
+				walk(val[k], path+"."+k)
+			}
+		case []interface{}:
+			for i, item := range val {
+				walk(item, fmt.Sprintf("%s[%d]", path, i))
+			}
+		case string:
+			for _, m := range envBracedPattern.FindAllStringSubmatch(val, -1) {
+				if m[2] == "" {
+					if _, ok := seen[m[1]]; !ok {
+						seen[m[1]] = path
+					}
+				}
+			}
+			for _, m := range envBarePattern.FindAllStringSubmatch(val, -1) {
+				if _, ok := seen[m[2]]; !ok {
+					seen[m[2]] = path
+				}
+			}
+		}
+	}
+	sections := []struct {
+		name       string
+		components map[string]interface{}
+	}{
+		{"receivers", input.Config.Receivers},
+		{"processors", input.Config.Processors},
+		{"exporters", input.Config.Exporters},
+		{"connectors", input.Config.Connectors},
+		{"extensions", input.Config.Extensions},
+	}
+	for _, sec := range sections {
+		for _, id := range sortedKeys(sec.components) {
+			walk(sec.components[id], sec.name+"."+id)
+		}
+	}
+
+	refs := make([]envReference, 0, len(seen))
+	for name, path := range seen {
+		refs = append(refs, envReference{name: name, path: path})
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].name < refs[j].name })
+	return refs
+}
+
+// envSource is the set of keys held by a Secret or ConfigMap.
+type envSource struct {
+	known  bool // false when the object could not be checked
+	exists bool
+	keys   map[string]bool
+}
+
+// envSourceResolver looks up Secrets and ConfigMaps once per analysis.
+type envSourceResolver struct {
+	input *AnalysisInput
+	cache map[string]envSource
+}
+
+// lookup returns the key names of a Secret or ConfigMap in the collector namespace.
+//
+// Both lookups need "get" on the object kind in the collector namespace; the
+// Secret check is enabled by the Helm chart's secretChecks.enabled value.
+// A missing Secret is detected from its metadata alone, and the Secret data is
+// only fetched to read key names and is dropped as soon as they are copied.
+func (r *envSourceResolver) lookup(ctx context.Context, kind, name string) envSource {
+	cacheKey := kind + "/" + name
+	if src, ok := r.cache[cacheKey]; ok {
+		return src
+	}
+	src := envSource{}
+	if r.input.Clientset != nil {
+		namespace := r.input.PodInfo.Namespace
+		if namespace == "" {
+			namespace = r.input.Namespace
+		}
+		var keys map[string]bool
+		var err error
+		if kind == "Secret" {
+			keys, err = r.secretKeys(ctx, namespace, name)
+		} else {
+			var cm *corev1.ConfigMap
+			cm, err = r.input.Clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+			if err == nil {
+				keys = make(map[string]bool)
+				for k := range cm.Data {
+					keys[k] = true
+				}
+				for k := range cm.BinaryData {
+					keys[k] = true
+				}
+			}
+		}
+		switch {
+		case err == nil:
+			src = envSource{known: true, exists: true, keys: keys}
+		case apierrors.IsNotFound(err):
+			src = envSource{known: true}
+		default:
+			slog.Debug("could not verify env source", "kind", kind, "name", name, "error", err)
+		}
+	}
+	r.cache[cacheKey] = src
+	return src
+}
+
+// secretKeys returns the key names of a Secret. Existence is checked first
+// through the metadata client, when available, so a missing Secret is reported
+// without any Secret being downloaded.
+func (r *envSourceResolver) secretKeys(ctx context.Context, namespace, name string) (map[string]bool, error) {
+	if r.input.Metadata != nil {
+		if _, err := r.input.Metadata.Resource(secretsGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{}); err != nil {
+			return nil, err
+		}
+	}
+	secret, err := r.input.Clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]bool, len(secret.Data)+len(secret.StringData))
+	for k := range secret.Data {
+		keys[k] = true
+	}
+	for k := range secret.StringData {
+		keys[k] = true
+	}
+	// Only key names are kept: drop the values before returning.
+	secret.Data, secret.StringData = nil, nil
+	return keys, nil
+}
+
+// checkEnvVar verifies that the Secret or ConfigMap key behind an env var exists.
+func (r *envSourceResolver) checkEnvVar(ctx context.Context, container string, ref envReference, e corev1.EnvVar) *types.DiagnosticFinding {
+	if e.ValueFrom == nil {
+		return nil
+	}
+	var kind, name, key string
+	var optional bool
+	switch {
+	case e.ValueFrom.SecretKeyRef != nil:
+		kind, name, key = "Secret", e.ValueFrom.SecretKeyRef.Name, e.ValueFrom.SecretKeyRef.Key
+		optional = e.ValueFrom.SecretKeyRef.Optional != nil && *e.ValueFrom.SecretKeyRef.Optional
+	case e.ValueFrom.ConfigMapKeyRef != nil:
+		kind, name, key = "ConfigMap", e.ValueFrom.ConfigMapKeyRef.Name, e.ValueFrom.ConfigMapKeyRef.Key
+		optional = e.ValueFrom.ConfigMapKeyRef.Optional != nil && *e.ValueFrom.ConfigMapKeyRef.Optional
+	default:
+		return nil
+	}
+
+	src := r.lookup(ctx, kind, name)
+	if !src.known || src.keys[key] {
+		return nil
+	}
+
+	problem := fmt.Sprintf("%s %q does not exist", kind, name)
+	if src.exists {
+		problem = fmt.Sprintf("%s %q has no key %q", kind, name, key)
+	}
+	detail := fmt.Sprintf("%s on container %q comes from %s/%s, but %s. The kubelet refuses to start the container (CreateContainerConfigError).", ref.name, container, kind, name, problem)
+	if optional {
+		detail = fmt.Sprintf("%s on container %q is an optional reference to %s/%s, but %s. The variable is left unset, so %s expands to an empty string.", ref.name, container, kind, name, problem, ref.path)
+	}
+	namespace := r.input.PodInfo.Namespace
+	if namespace == "" {
+		namespace = r.input.Namespace
+	}
+	remediation := fmt.Sprintf("kubectl -n %s create secret generic %s --from-literal=%s=<value>", namespace, name, key)
+	switch {
+	case kind == "ConfigMap" && src.exists:
+		remediation = fmt.Sprintf(`kubectl -n %s patch configmap %s --type merge -p '{"data":{"%s":"<value>"}}'`, namespace, name, key)
+	case kind == "ConfigMap":
+		remediation = fmt.Sprintf("kubectl -n %s create configmap %s --from-literal=%s=<value>", namespace, name, key)
+	case src.exists:
+		remediation = fmt.Sprintf(`kubectl -n %s patch secret %s --type merge -p '{"stringData":{"%s":"<value>"}}'`, namespace, name, key)
+	}
+
+	return &types.DiagnosticFinding{
+		RuleID:      types.RuleEnvUnresolvable,
+		Severity:    types.SeverityCritical,
+		Category:    types.CategoryConfig,
+		Summary:     fmt.Sprintf("Environment variable %s cannot be resolved: %s", ref.name, problem),
+		Detail:      detail,
+		Suggestion:  fmt.Sprintf("Create the %s key %q in the collector namespace, or fix the reference", kind, key),
+		Remediation: remediation,
+		Evidence:    envEvidence(ref),
+	}
+}
+
+// envEvidence locates an env reference: the variable is the attribute key and
+// the component is taken from the referencing config path.
+func envEvidence(ref envReference) *types.Evidence {
+	parts := strings.SplitN(ref.path, ".", 3)
+	ev := &types.Evidence{AttributeKey: ref.name}
+	if len(parts) > 1 {
+		ev.ComponentID = parts[1]
+	}
+	if len(parts) > 2 {
+		ev.Path = parts[2]
+	}
+	return ev
+}
@@ -0,0 +1,184 @@
+package analysis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestAnalyzeEnvReferences(t *testing.T) {
+	config := &collector.CollectorConfig{
+		Exporters: map[string]interface{}{
+			"otlphttp/dynatrace": map[string]interface{}{
+				"endpoint": "${env:DT_ENDPOINT}/api/v2/otlp",
+				"headers": map[string]interface{}{
+					"Authorization": "Api-Token ${env:DT_API_TOKEN}",
+				},
+			},
+		},
+		Processors: map[string]interface{}{
+			"resource": map[string]interface{}{
+				"attributes": []interface{}{
+					map[string]interface{}{"key": "cluster", "value": "${env:CLUSTER_NAME:-unknown}"},
+					map[string]interface{}{"key": "region", "value": "$REGION"},
+				},
+			},
+		},
+	}
+	optional := true
+
+	tests := []struct {
+		name           string
+		env            []corev1.EnvVar
+		envFrom        []corev1.EnvFromSource
+		objects        []metav1.Object
+		expectSeverity []string
+	}{
+		{
+			name: "all references resolved",
+			env: []corev1.EnvVar{
+				{Name: "DT_ENDPOINT", Value: "https://abc.live.dynatrace.com"},
+				{Name: "DT_API_TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "dynatrace"}, Key: "token",
+				}}},
+			},
+			envFrom: []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "cluster-info"},
+			}}},
+			objects: []metav1.Object{
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "dynatrace", Namespace: "obs"}, Data: map[string][]byte{"token": []byte("x")}},
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cluster-info", Namespace: "obs"}, Data: map[string]string{"REGION": "eu"}},
+			},
+		},
+		{
+			name:           "variables not defined on the container",
+			env:            []corev1.EnvVar{{Name: "DT_ENDPOINT", Value: "https://abc.live.dynatrace.com"}},
+			expectSeverity: []string{types.SeverityCritical, types.SeverityCritical},
+		},
+		{
+			name: "secret exists without the key, optional configmap missing",
+			env: []corev1.EnvVar{
+				{Name: "DT_ENDPOINT", ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "dynatrace-endpoint"}, Key: "url", Optional: &optional,
+				}}},
+				{Name: "DT_API_TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "dynatrace"}, Key: "token",
+				}}},
+				{Name: "REGION", Value: "eu"},
+			},
+			objects: []metav1.Object{
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "dynatrace", Namespace: "obs"}, Data: map[string][]byte{"api-token": []byte("x")}},
+			},
+			expectSeverity: []string{types.SeverityCritical, types.SeverityCritical},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewClientset()
+			for _, obj := range tt.objects {
+				switch o := obj.(type) {
+				case *corev1.Secret:
+					_, _ = clientset.CoreV1().Secrets("obs").Create(context.Background(), o, metav1.CreateOptions{})
+				case *corev1.ConfigMap:
+					_, _ = clientset.CoreV1().ConfigMaps("obs").Create(context.Background(), o, metav1.CreateOptions{})
+				}
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "gw-0", Namespace: "obs"},
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{
+					Name: "otc-container", Env: tt.env, EnvFrom: tt.envFrom,
+				}}},
+			}
+
+			findings := AnalyzeEnvReferences(context.Background(), &AnalysisInput{Config: config, PodInfo: pod, Clientset: clientset})
+			if len(findings) != len(tt.expectSeverity) {
+				t.Fatalf("expected %d findings, got %d: %+v", len(tt.expectSeverity), len(findings), findings)
+			}
+			for i, f := range findings {
+				if f.Severity != tt.expectSeverity[i] {
+					t.Errorf("finding %d: expected severity %s, got %s (%s)", i, tt.expectSeverity[i], f.Severity, f.Summary)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeEnvReferences_SecretMetadata(t *testing.T) {
+	config := &collector.CollectorConfig{
+		Exporters: map[string]interface{}{
+			"otlp": map[string]interface{}{"headers": map[string]interface{}{"x-key": "${env:API_KEY}", "x-token": "${env:TOKEN}"}},
+		},
+	}
+	secretRef := func(env, name, key string) corev1.EnvVar {
+		return corev1.EnvVar{Name: env, ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: key,
+		}}}
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw-0", Namespace: "obs"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "otc-container",
+			Env:  []corev1.EnvVar{secretRef("API_KEY", "missing", "key"), secretRef("TOKEN", "tokens", "token")},
+		}}},
+	}
+	tokens := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "tokens", Namespace: "obs"}, Data: map[string][]byte{"token": []byte("x")}}
+	clientset := fake.NewClientset(tokens)
+	scheme := metadatafake.NewTestScheme()
+	if err := metav1.AddMetaToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	meta := metadatafake.NewSimpleMetadataClient(scheme, &metav1.PartialObjectMetadata{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: tokens.ObjectMeta,
+	})
+	var fetched []string
+	clientset.PrependReactor("get", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		fetched = append(fetched, action.(k8stesting.GetAction).GetName())
+		return false, nil, nil
+	})
+
+	findings := AnalyzeEnvReferences(context.Background(), &AnalysisInput{Config: config, PodInfo: pod, Clientset: clientset, Metadata: meta})
+	if len(findings) != 1 || findings[0].RuleID != types.RuleEnvUnresolvable || findings[0].Evidence.AttributeKey != "API_KEY" {
+		t.Fatalf("expected the missing Secret to be reported, got %+v", findings)
+	}
+	if len(fetched) != 1 || fetched[0] != "tokens" {
+		t.Errorf("expected only the existing Secret to be fetched for its keys, got %v", fetched)
+	}
+}
+
+func TestConfigEnvReferences(t *testing.T) {
+	input := &AnalysisInput{Config: &collector.CollectorConfig{
+		Exporters: map[string]interface{}{
+			"otlp": map[string]interface{}{
+				"endpoint": "${OTLP_HOST}:4317",
+				"headers":  map[string]interface{}{"x-key": "${env:API_KEY}", "x-escaped": "$$LITERAL"},
+				"default":  "${env:WITH_DEFAULT:-fallback}",
+				"file":     "${file:/etc/token}",
+			},
+		},
+	}}
+
+	refs := configEnvReferences(input)
+	var names []string
+	for _, r := range refs {
+		names = append(names, r.name)
+	}
+	want := []string{"API_KEY", "OTLP_HOST"}
+	if len(names) != len(want) {
+		t.Fatalf("expected %v, got %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, names)
+		}
+	}
+}
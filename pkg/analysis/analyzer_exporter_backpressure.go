@@ -3,6 +3,8 @@ package analysis
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/hrexed/otel-collector-mcp/pkg/types"
@@ -22,6 +24,10 @@ var backpressurePatterns = []struct {
 	{"retry limit reached", "Exporter retry limit reached — data permanently lost"},
 }
 
+// logComponentPattern extracts the component ID from a structured collector
+// log line, which carries it as "name" or "otelcol.component.id".
+var logComponentPattern = regexp.MustCompile(`"(?:otelcol\.component\.id|name)":\s*"([^"]+)"`)
+
 // AnalyzeExporterBackpressure detects exporter queue saturation from collector logs.
 func AnalyzeExporterBackpressure(_ context.Context, input *AnalysisInput) []types.DiagnosticFinding {
 	if len(input.Logs) == 0 {
@@ -30,12 +36,19 @@ func AnalyzeExporterBackpressure(_ context.Context, input *AnalysisInput) []type
 
 	var findings []types.DiagnosticFinding
 	patternCounts := make(map[string]int)
+	patternExporters := make(map[string]map[string]bool)
 
 	for _, line := range input.Logs {
 		lower := strings.ToLower(line)
 		for _, bp := range backpressurePatterns {
 			if strings.Contains(lower, bp.pattern) {
 				patternCounts[bp.message]++
+				if m := logComponentPattern.FindStringSubmatch(line); m != nil {
+					if patternExporters[bp.message] == nil {
+						patternExporters[bp.message] = make(map[string]bool)
+					}
+					patternExporters[bp.message][m[1]] = true
+				}
 			}
 		}
 	}
@@ -46,7 +59,18 @@ func AnalyzeExporterBackpressure(_ context.Context, input *AnalysisInput) []type
 			severity = types.SeverityCritical
 		}
 
+		exporters := make([]string, 0, len(patternExporters[message]))
+		for id := range patternExporters[message] {
+			exporters = append(exporters, id)
+		}
+		sort.Strings(exporters)
+		evidence := &types.Evidence{SignalType: "logs", Name: message, Count: count, Components: exporters}
+		if len(exporters) == 1 {
+			evidence.ComponentID = exporters[0]
+		}
+
 		findings = append(findings, types.DiagnosticFinding{
+			RuleID:   types.RuleExporterBackpressure,
 			Severity: severity,
 			Category: types.CategoryRuntime,
 			Summary:  message,
@@ -62,6 +86,7 @@ func AnalyzeExporterBackpressure(_ context.Context, input *AnalysisInput) []type
       enabled: true
       initial_interval: 5s
       max_interval: 30s`,
+			Evidence: evidence,
 		})
 	}
 
@@ -0,0 +1,246 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+)
+
+// defaultHealthCheckPort is the health_check extension's default listen port.
+const defaultHealthCheckPort = 13133
+
+// debugExtensionDefaults maps debug extensions to their safe default endpoint.
+var debugExtensionDefaults = map[string]string{
+	"pprof":  "localhost:1777",
+	"zpages": "localhost:55679",
+}
+
+// AnalyzeExtensions checks that extensions are both defined and enabled, that
+// debug extensions are not exposed, that health_check matches the pod's probes,
+// and that auth/storage extensions referenced by components are enabled.
+func AnalyzeExtensions(_ context.Context, input *AnalysisInput) []types.DiagnosticFinding {
+	if input.Config == nil {
+		return nil
+	}
+
+	var findings []types.DiagnosticFinding
+	cfg := input.Config
+
+	for _, id := range sortedKeys(cfg.Extensions) {
+		if !isEnabledExtension(cfg, id) {
+			findings = append(findings, types.DiagnosticFinding{
+				RuleID:      types.RuleExtensionNotEnabled,
+				Severity:    types.SeverityWarning,
+				Category:    types.CategoryConfig,
+				Summary:     fmt.Sprintf("Extension %q is defined but not enabled in service.extensions", id),
+				Detail:      "Extensions only start when listed in service.extensions. This extension is configured but has no effect.",
+				Suggestion:  "Add the extension to service.extensions or remove its definition",
+				Remediation: fmt.Sprintf("service:\n  extensions: [%s]", joinProcessors(append(append([]string{}, cfg.Service.Extensions...), id))),
+				Evidence:    componentEvidence(id, ""),
+			})
+		}
+	}
+
+	for _, id := range cfg.Service.Extensions {
+		if _, ok := cfg.Extensions[id]; !ok {
+			findings = append(findings, types.DiagnosticFinding{
+				RuleID:      types.RuleExtensionNotDefined,
+				Severity:    types.SeverityCritical,
+				Category:    types.CategoryConfig,
+				Summary:     fmt.Sprintf("Extension %q is enabled in service.extensions but not defined", id),
+				Detail:      "Every ID in service.extensions must have a matching entry in the top-level extensions section. The collector refuses to start with an undefined extension.",
+				Suggestion:  "Define the extension or remove it from service.extensions",
+				Remediation: fmt.Sprintf("extensions:\n  %s: {}", id),
+				Evidence:    componentEvidence(id, ""),
+			})
+		}
+	}
+
+	for _, id := range sortedKeys(cfg.Extensions) {
+		safe, ok := debugExtensionDefaults[componentType(id)]
+		if !ok {
+			continue
+		}
+		extCfg, _ := cfg.Extensions[id].(map[string]interface{})
+		endpoint, _ := getNestedString(extCfg, "endpoint")
+		if endpoint == "" {
+			continue
+		}
+		if _, host, _ := parseEndpoint(endpoint); isWildcardHost(host) {
+			findings = append(findings, types.DiagnosticFinding{
+				RuleID:      types.RuleDebugExtensionExposed,
+				Severity:    types.SeverityWarning,
+				Category:    types.CategorySecurity,
+				Summary:     fmt.Sprintf("Debug extension %q is exposed on %s", id, endpoint),
+				Detail:      "pprof and zpages expose heap profiles, goroutine dumps and live span data without authentication. Binding them to all interfaces makes this reachable from any pod in the cluster.",
+				Suggestion:  "Bind the extension to localhost and use kubectl port-forward when debugging",
+				Remediation: remediationYAML("extensions", id, nil, "endpoint: "+safe),
+				Evidence:    &types.Evidence{ComponentID: id, Path: "endpoint", SampleValues: []string{endpoint}},
+			})
+		}
+	}
+
+	findings = append(findings, checkHealthCheckProbes(input)...)
+	findings = append(findings, checkExtensionReferences(input)...)
+
+	return findings
+}
+
+// checkHealthCheckProbes compares HTTP probes on the collector container with the
+// health_check extension configuration.
+func checkHealthCheckProbes(input *AnalysisInput) []types.DiagnosticFinding {
+	c := collectorContainer(input.PodInfo)
+	if c == nil {
+		return nil
+	}
+	probe := c.LivenessProbe
+	if probe == nil || probe.HTTPGet == nil {
+		probe = c.ReadinessProbe
+	}
+	if probe == nil || probe.HTTPGet == nil {
+		return nil
+	}
+	probePort := resolveProbePort(c, probe.HTTPGet.Port)
+
+	var healthID string
+	for _, id := range input.Config.Service.Extensions {
+		if componentType(id) == "health_check" {
+			healthID = id
+			break
+		}
+	}
+
+	if healthID == "" {
+		return []types.DiagnosticFinding{{
+			RuleID:     types.RuleHealthCheckMissing,
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConfig,
+			Summary:    fmt.Sprintf("Container %q has HTTP probes but the health_check extension is not enabled", c.Name),
+			Detail:     fmt.Sprintf("The pod probes %s on port %d, but nothing in the collector serves it. Failing liveness probes make the kubelet restart the collector in a loop.", probe.HTTPGet.Path, probePort),
+			Suggestion: "Enable the health_check extension on the probed port",
+			Remediation: fmt.Sprintf(`extensions:
+  health_check:
+    endpoint: 0.0.0.0:%d
+
+service:
+  extensions: [health_check]`, probePort),
+			Evidence: &types.Evidence{ComponentID: "health_check", Path: "endpoint", Count: int(probePort)},
+		}}
+	}
+
+	extCfg, _ := input.Config.Extensions[healthID].(map[string]interface{})
+	endpoint, _ := getNestedString(extCfg, "endpoint")
+	_, host, portStr := parseEndpoint(endpoint)
+	port := defaultHealthCheckPort
+	if p, err := strconv.Atoi(portStr); err == nil {
+		port = p
+	}
+
+	var findings []types.DiagnosticFinding
+	if probePort != 0 && int(probePort) != port {
+		findings = append(findings, types.DiagnosticFinding{
+			RuleID:      types.RuleProbePortMismatch,
+			Severity:    types.SeverityCritical,
+			Category:    types.CategoryConfig,
+			Summary:     fmt.Sprintf("Probe port %d does not match %s port %d", probePort, healthID, port),
+			Detail:      "The kubelet probes a port the health_check extension does not listen on, so probes fail and the collector is restarted even though it is healthy.",
+			Suggestion:  "Align the health_check endpoint with the probe port",
+			Remediation: remediationYAML("extensions", healthID, nil, fmt.Sprintf("endpoint: 0.0.0.0:%d", probePort)),
+			Evidence:    &types.Evidence{ComponentID: healthID, Path: "endpoint", SampleValues: []string{endpoint}, Count: int(probePort)},
+		})
+	}
+	if host == "localhost" || host == "127.0.0.1" {
+		findings = append(findings, types.DiagnosticFinding{
+			RuleID:      types.RuleHealthCheckUnreachable,
+			Severity:    types.SeverityCritical,
+			Category:    types.CategoryConfig,
+			Summary:     fmt.Sprintf("%s listens on %s, unreachable by kubelet probes", healthID, endpoint),
+			Detail:      "The kubelet sends probes to the pod IP. A health_check bound to loopback never answers them.",
+			Suggestion:  "Bind health_check to 0.0.0.0 (or ${env:MY_POD_IP})",
+			Remediation: remediationYAML("extensions", healthID, nil, fmt.Sprintf("endpoint: 0.0.0.0:%d", port)),
+			Evidence:    &types.Evidence{ComponentID: healthID, Path: "endpoint", SampleValues: []string{endpoint}, Count: port},
+		})
+	}
+	return findings
+}
+
+// checkExtensionReferences verifies that auth and storage extensions referenced
+// by receivers and exporters are defined and enabled.
+func checkExtensionReferences(input *AnalysisInput) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	sections := []struct {
+		name       string
+		components map[string]interface{}
+	}{
+		{"receivers", input.Config.Receivers},
+		{"exporters", input.Config.Exporters},
+	}
+	for _, sec := range sections {
+		for _, id := range sortedKeys(sec.components) {
+			cfgMap, ok := sec.components[id].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, ref := range findExtensionRefs(cfgMap, "") {
+				if isEnabledExtension(input.Config, ref.extension) {
+					continue
+				}
+				state := "not enabled in service.extensions"
+				if _, defined := input.Config.Extensions[ref.extension]; !defined {
+					state = "not defined"
+				}
+				findings = append(findings, types.DiagnosticFinding{
+					RuleID:     types.RuleExtensionBadReference,
+					Severity:   types.SeverityCritical,
+					Category:   types.CategoryConfig,
+					Summary:    fmt.Sprintf("%s %q references %s extension %q which is %s", strings.TrimSuffix(sec.name, "s"), id, ref.kind, ref.extension, state),
+					Detail:     fmt.Sprintf("%s.%s points at an extension the collector does not start. The component fails at startup with an \"extension not found\" error.", id, ref.path),
+					Suggestion: "Define the extension and list it in service.extensions",
+					Remediation: fmt.Sprintf(`extensions:
+  %s: {}  # configure the %s extension
+
+service:
+  extensions: [%s]`, ref.extension, ref.kind, joinProcessors(append(append([]string{}, input.Config.Service.Extensions...), ref.extension))),
+					Evidence: &types.Evidence{ComponentID: id, Path: ref.path, SampleValues: []string{ref.extension}},
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// extensionRef is a reference from a component setting to an extension ID.
+type extensionRef struct {
+	kind      string // auth or storage
+	path      string
+	extension string
+}
+
+// findExtensionRefs finds auth.authenticator and storage references in a component config.
+func findExtensionRefs(m map[string]interface{}, path string) []extensionRef {
+	var refs []extensionRef
+	for _, key := range sortedKeys(m) {
+		fullPath := key
+		if path != "" {
+			fullPath = path + "." + key
+		}
+		switch v := m[key].(type) {
+		case string:
+			if key == "storage" && v != "" {
+				refs = append(refs, extensionRef{kind: "storage", path: fullPath, extension: v})
+			}
+		case map[string]interface{}:
+			if key == "auth" {
+				if authenticator, ok := getNestedString(v, "authenticator"); ok && authenticator != "" {
+					refs = append(refs, extensionRef{kind: "auth", path: fullPath + ".authenticator", extension: authenticator})
+				}
+				continue
+			}
+			refs = append(refs, findExtensionRefs(v, fullPath)...)
+		}
+	}
+	return refs
+}
@@ -0,0 +1,128 @@
+package analysis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func probedPod(port intstr.IntOrString) *corev1.Pod {
+	return &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "otc-container",
+				Image: "otel/opentelemetry-collector-contrib:0.120.0",
+				Ports: []corev1.ContainerPort{{Name: "health", ContainerPort: 13133}},
+				LivenessProbe: &corev1.Probe{
+					ProbeHandler: corev1.ProbeHandler{
+						HTTPGet: &corev1.HTTPGetAction{Path: "/", Port: port},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func TestAnalyzeExtensions(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         *collector.CollectorConfig
+		pod            *corev1.Pod
+		expectSeverity []string
+	}{
+		{
+			name:   "nil config returns no findings",
+			config: nil,
+		},
+		{
+			name: "defined and enabled health_check matching probe",
+			config: &collector.CollectorConfig{
+				Extensions: map[string]interface{}{
+					"health_check": map[string]interface{}{"endpoint": "0.0.0.0:13133"},
+				},
+				Service: collector.ServiceConfig{Extensions: []string{"health_check"}},
+			},
+			pod: probedPod(intstr.FromString("health")),
+		},
+		{
+			name: "defined but not enabled",
+			config: &collector.CollectorConfig{
+				Extensions: map[string]interface{}{"health_check": map[string]interface{}{}},
+			},
+			expectSeverity: []string{types.SeverityWarning},
+		},
+		{
+			name: "enabled but not defined",
+			config: &collector.CollectorConfig{
+				Service: collector.ServiceConfig{Extensions: []string{"health_check"}},
+			},
+			expectSeverity: []string{types.SeverityCritical},
+		},
+		{
+			name: "pprof exposed on all interfaces",
+			config: &collector.CollectorConfig{
+				Extensions: map[string]interface{}{
+					"pprof":  map[string]interface{}{"endpoint": "0.0.0.0:1777"},
+					"zpages": map[string]interface{}{"endpoint": "localhost:55679"},
+				},
+				Service: collector.ServiceConfig{Extensions: []string{"pprof", "zpages"}},
+			},
+			expectSeverity: []string{types.SeverityWarning},
+		},
+		{
+			name:           "liveness probe without health_check",
+			config:         &collector.CollectorConfig{},
+			pod:            probedPod(intstr.FromInt32(13133)),
+			expectSeverity: []string{types.SeverityCritical},
+		},
+		{
+			name: "health_check on loopback and different port",
+			config: &collector.CollectorConfig{
+				Extensions: map[string]interface{}{
+					"health_check": map[string]interface{}{"endpoint": "localhost:8080"},
+				},
+				Service: collector.ServiceConfig{Extensions: []string{"health_check"}},
+			},
+			pod:            probedPod(intstr.FromInt32(13133)),
+			expectSeverity: []string{types.SeverityCritical, types.SeverityCritical},
+		},
+		{
+			name: "exporter references auth and storage extensions that are not enabled",
+			config: &collector.CollectorConfig{
+				Extensions: map[string]interface{}{
+					"file_storage": map[string]interface{}{"directory": "/var/lib/otelcol"},
+				},
+				Exporters: map[string]interface{}{
+					"otlp": map[string]interface{}{
+						"endpoint":      "backend:4317",
+						"auth":          map[string]interface{}{"authenticator": "oauth2client"},
+						"sending_queue": map[string]interface{}{"storage": "file_storage"},
+					},
+				},
+				Service: collector.ServiceConfig{Extensions: []string{"file_storage"}},
+			},
+			expectSeverity: []string{types.SeverityCritical},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := AnalyzeExtensions(context.Background(), &AnalysisInput{Config: tt.config, PodInfo: tt.pod})
+			if len(findings) != len(tt.expectSeverity) {
+				t.Fatalf("expected %d findings, got %d: %+v", len(tt.expectSeverity), len(findings), findings)
+			}
+			for i, f := range findings {
+				if f.Severity != tt.expectSeverity[i] {
+					t.Errorf("finding %d: expected severity %s, got %s (%s)", i, tt.expectSeverity[i], f.Severity, f.Summary)
+				}
+				if f.Remediation == "" {
+					t.Errorf("finding %d: expected remediation", i)
+				}
+			}
+		})
+	}
+}
@@ -45,6 +45,7 @@ func scanMapForTokens(m map[string]interface{}, exporterName, path string) []typ
 		case string:
 			if isTokenField(key) && isHardcoded(v) {
 				findings = append(findings, types.DiagnosticFinding{
+					RuleID:     types.RuleHardcodedToken,
 					Severity:   types.SeverityCritical,
 					Category:   types.CategorySecurity,
 					Summary:    fmt.Sprintf("Hardcoded credential detected in exporter %q at %q", exporterName, fullPath),
@@ -56,6 +57,7 @@ exporters:
     %s: ${env:YOUR_SECRET_ENV_VAR}
 
 # Or mount a Kubernetes secret as an environment variable in the collector pod`, exporterName, fullPath),
+					Evidence: componentEvidence(exporterName, fullPath),
 				})
 			}
 		case map[string]interface{}:
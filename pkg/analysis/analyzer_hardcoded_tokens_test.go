@@ -49,4 +49,7 @@ func TestAnalyzeHardcodedTokens_Hardcoded(t *testing.T) {
 	if findings[0].Summary == "" {
 		t.Error("expected summary")
 	}
+	if ev := findings[0].Evidence; ev == nil || ev.ComponentID != "datadog" || ev.Path != "api_key" {
+		t.Errorf("expected evidence pointing at datadog api_key, got %+v", ev)
+	}
 }
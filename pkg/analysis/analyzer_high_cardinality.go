@@ -33,6 +33,7 @@ func AnalyzeHighCardinality(_ context.Context, input *AnalysisInput) []types.Dia
 
 		if !hasFilter && len(pipeline.Receivers) > 0 {
 			findings = append(findings, types.DiagnosticFinding{
+				RuleID:   types.RuleNoCardinalityControl,
 				Severity: types.SeverityInfo,
 				Category: types.CategoryPerformance,
 				Summary:  fmt.Sprintf("Metrics pipeline %q has no cardinality control processor", pipelineName),
@@ -51,6 +52,7 @@ service:
   pipelines:
     %s:
       processors: [attributes/drop-high-card, %s]`, pipelineName, joinProcessors(pipeline.Processors)),
+				Evidence: pipelineEvidence(pipelineName, ""),
 			})
 		}
 	}
@@ -44,11 +44,17 @@ func scanForRegexPatterns(m map[string]interface{}, processorName, path string)
 			if isRegexField(key) {
 				if _, err := regexp.Compile(v); err != nil {
 					findings = append(findings, types.DiagnosticFinding{
+						RuleID:     types.RuleInvalidRegex,
 						Severity:   types.SeverityWarning,
 						Category:   types.CategoryConfig,
 						Summary:    fmt.Sprintf("Invalid regex pattern in processor %q at %q", processorName, fullPath),
 						Detail:     fmt.Sprintf("The regex pattern %q is invalid: %v", v, err),
 						Suggestion: "Fix the regex pattern syntax",
+						Evidence: &types.Evidence{
+							ComponentID:  processorName,
+							Path:         fullPath,
+							SampleValues: []string{v},
+						},
 					})
 				}
 			}
@@ -0,0 +1,328 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// defaultLoadBalancingPort is the OTLP gRPC port the loadbalancing exporter
+// sends to when the resolver sets no port.
+const defaultLoadBalancingPort = 4317
+
+// lbTarget is the in-cluster Service a loadbalancing resolver points at.
+type lbTarget struct {
+	resolver  string // k8s or dns
+	service   string
+	namespace string
+	ports     []int32
+}
+
+// AnalyzeLoadBalancing validates the topology behind loadbalancing exporters:
+// the resolver's Service exists and is headless, the routing key suits the
+// downstream pipelines, and the downstream collectors listen for OTLP gRPC on
+// the ports the exporter targets. Cluster checks are skipped without a clientset.
+func AnalyzeLoadBalancing(ctx context.Context, input *AnalysisInput) []types.DiagnosticFinding {
+	if input.Config == nil {
+		return nil
+	}
+
+	var findings []types.DiagnosticFinding
+	for _, id := range sortedKeys(input.Config.Exporters) {
+		if componentType(id) != "loadbalancing" {
+			continue
+		}
+		cfg, _ := input.Config.Exporters[id].(map[string]interface{})
+		resolver, ok := getNestedMap(cfg, "resolver")
+		if !ok || len(resolver) == 0 {
+			findings = append(findings, types.DiagnosticFinding{
+				RuleID:     types.RuleLoadBalancingNoResolver,
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryConfig,
+				Summary:    fmt.Sprintf("Exporter %q has no resolver", id),
+				Detail:     "The loadbalancing exporter needs exactly one resolver (static, dns, k8s or aws_cloud_map) to discover its backends. The collector rejects the config without one.",
+				Suggestion: "Add a k8s resolver pointing at the downstream collector's headless Service",
+				Remediation: remediationYAML("exporters", id, []string{"resolver", "k8s"},
+					"service: <gateway-headless-service>.<namespace>", fmt.Sprintf("ports: [%d]", defaultLoadBalancingPort)),
+				Evidence: componentEvidence(id, "resolver"),
+			})
+			continue
+		}
+
+		target, finding := loadBalancingTarget(input, id, resolver)
+		if finding != nil {
+			findings = append(findings, *finding)
+		}
+		if target == nil || input.Clientset == nil {
+			continue
+		}
+		findings = append(findings, checkLoadBalancingTarget(ctx, input, id, cfg, target)...)
+	}
+	return findings
+}
+
+// loadBalancingTarget resolves the Service behind a k8s or dns resolver. Other
+// resolvers, and hostnames built from environment variables, return nil.
+func loadBalancingTarget(input *AnalysisInput, id string, resolver map[string]interface{}) (*lbTarget, *types.DiagnosticFinding) {
+	namespace := input.Namespace
+	if input.PodInfo != nil && input.PodInfo.Namespace != "" {
+		namespace = input.PodInfo.Namespace
+	}
+
+	if k8s, ok := getNestedMap(resolver, "k8s"); ok {
+		service, _ := getNestedString(k8s, "service")
+		if service == "" || strings.Contains(service, "${") {
+			return nil, nil
+		}
+		target := &lbTarget{resolver: "k8s", service: service, namespace: namespace}
+		if name, ns, found := strings.Cut(service, "."); found {
+			target.service, target.namespace = name, ns
+		}
+		if ports, ok := k8s["ports"].([]interface{}); ok {
+			for _, p := range ports {
+				if n, ok := p.(int); ok {
+					target.ports = append(target.ports, int32(n))
+				}
+			}
+		}
+		if len(target.ports) == 0 {
+			target.ports = []int32{defaultLoadBalancingPort}
+		}
+		return target, nil
+	}
+
+	if dns, ok := getNestedMap(resolver, "dns"); ok {
+		hostname, _ := getNestedString(dns, "hostname")
+		if hostname == "" || strings.Contains(hostname, "${") {
+			return nil, nil
+		}
+		if !isClusterLocalHost(hostname) {
+			return nil, &types.DiagnosticFinding{
+				RuleID:     types.RuleLoadBalancingExternal,
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryConfig,
+				Summary:    fmt.Sprintf("Exporter %q resolves backends from %q, which is not an in-cluster Service", id, hostname),
+				Detail:     "The dns resolver expects a hostname returning one A record per backend collector. An external name usually resolves to a single load balancer, so every trace ID maps to the same address and routing is no longer trace-aware.",
+				Suggestion: "Point the dns resolver at the headless Service of the downstream collectors, or use the k8s resolver",
+				Evidence:   &types.Evidence{ComponentID: id, Path: "resolver.dns.hostname", SampleValues: []string{hostname}},
+			}
+		}
+		if net.ParseIP(hostname) != nil {
+			return nil, nil
+		}
+		labelsOf := strings.Split(strings.ToLower(strings.TrimSuffix(hostname, ".")), ".")
+		target := &lbTarget{resolver: "dns", service: labelsOf[0], namespace: namespace}
+		if len(labelsOf) > 1 {
+			target.namespace = labelsOf[1]
+		}
+		port := defaultLoadBalancingPort
+		if v, ok := getNestedNumber(dns, "port"); ok && v > 0 {
+			port = int(v)
+		}
+		target.ports = []int32{int32(port)}
+		return target, nil
+	}
+
+	return nil, nil
+}
+
+// checkLoadBalancingTarget inspects the target Service and the collectors behind it.
+func checkLoadBalancingTarget(ctx context.Context, input *AnalysisInput, id string, cfg map[string]interface{}, target *lbTarget) []types.DiagnosticFinding {
+	svc, err := input.Clientset.CoreV1().Services(target.namespace).Get(ctx, target.service, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return []types.DiagnosticFinding{{
+			RuleID:     types.RuleLoadBalancingNoService,
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConfig,
+			Summary:    fmt.Sprintf("Exporter %q targets Service %s/%s, which does not exist", id, target.namespace, target.service),
+			Detail:     fmt.Sprintf("The %s resolver finds no backends, so the loadbalancing exporter has nowhere to send data and every batch fails.", target.resolver),
+			Suggestion: "Create a headless Service for the downstream collectors or fix the resolver",
+			Remediation: fmt.Sprintf(`apiVersion: v1
+kind: Service
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  clusterIP: None
+  selector:
+    app.kubernetes.io/name: <downstream-collector>
+  ports:
+    - name: otlp-grpc
+      port: %d
+      targetPort: %d`, target.service, target.namespace, target.ports[0], target.ports[0]),
+			Evidence: &types.Evidence{ComponentID: id, Path: "resolver." + target.resolver, Name: target.namespace + "/" + target.service},
+		}}
+	}
+	if err != nil {
+		slog.Debug("could not get loadbalancing target service", "service", target.service, "namespace", target.namespace, "error", err)
+		return nil
+	}
+
+	var findings []types.DiagnosticFinding
+	if svc.Spec.ClusterIP != corev1.ClusterIPNone {
+		f := types.DiagnosticFinding{
+			RuleID:     types.RuleLoadBalancingNotHeadless,
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryConfig,
+			Summary:    fmt.Sprintf("Exporter %q resolves Service %s/%s, which is not headless", id, target.namespace, target.service),
+			Detail:     "A ClusterIP Service resolves to a single virtual IP. The dns resolver then sees one backend and kube-proxy spreads connections at random, so spans of a trace land on different collectors.",
+			Suggestion: "Set clusterIP: None on the Service, or add a headless Service next to it",
+			Remediation: `spec:
+  clusterIP: None`,
+			Evidence: &types.Evidence{ComponentID: id, Path: "resolver." + target.resolver, Name: target.namespace + "/" + target.service},
+		}
+		if target.resolver == "k8s" {
+			f.Severity = types.SeverityInfo
+			f.Detail = "The k8s resolver reads the Service's EndpointSlices, so routing still works, but the Service also load-balances any client that uses it directly. A dedicated headless Service makes the trace-aware path explicit."
+		}
+		findings = append(findings, f)
+	}
+
+	if len(svc.Spec.Selector) == 0 {
+		return findings
+	}
+	pods, err := input.Clientset.CoreV1().Pods(target.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+	})
+	if err != nil {
+		slog.Debug("could not list loadbalancing backends", "service", target.service, "error", err)
+		return findings
+	}
+	if len(pods.Items) == 0 {
+		return append(findings, types.DiagnosticFinding{
+			RuleID:     types.RuleLoadBalancingNoPods,
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryConfig,
+			Summary:    fmt.Sprintf("Service %s/%s targeted by exporter %q selects no pods", target.namespace, target.service, id),
+			Detail:     "The loadbalancing exporter has no backends until pods matching the Service selector are running. Data is queued and then dropped.",
+			Suggestion: "Check the Service selector against the downstream collector's pod labels",
+			Evidence:   &types.Evidence{ComponentID: id, Path: "resolver." + target.resolver, Name: target.namespace + "/" + target.service},
+		})
+	}
+
+	pod := &pods.Items[0]
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase == corev1.PodRunning {
+			pod = &pods.Items[i]
+			break
+		}
+	}
+	downstream, err := collector.PodConfig(ctx, input.Clientset, pod)
+	if err != nil {
+		slog.Debug("could not read downstream collector config", "pod", pod.Name, "error", err)
+		return findings
+	}
+
+	findings = append(findings, checkRoutingKey(id, cfg, pod.Name, downstream)...)
+	findings = append(findings, checkDownstreamReceivers(id, target, pod.Name, downstream)...)
+	return findings
+}
+
+// checkRoutingKey compares the exporter's routing_key with what the downstream
+// pipelines need: traceID for tail_sampling, service for spanmetrics.
+func checkRoutingKey(id string, cfg map[string]interface{}, pod string, downstream *collector.CollectorConfig) []types.DiagnosticFinding {
+	routingKey := "traceID"
+	if v, ok := getNestedString(cfg, "routing_key"); ok && v != "" {
+		routingKey = v
+	}
+
+	needs := make(map[string]string) // routing key -> component requiring it
+	names := make([]string, 0, len(downstream.Service.Pipelines))
+	for name := range downstream.Service.Pipelines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		pipeline := downstream.Service.Pipelines[name]
+		for _, p := range pipeline.Processors {
+			if componentType(p) == "tail_sampling" {
+				if _, ok := needs["traceID"]; !ok {
+					needs["traceID"] = "processor " + p
+				}
+			}
+		}
+		for _, e := range pipeline.Exporters {
+			if _, isConnector := downstream.Connectors[e]; isConnector && componentType(e) == "spanmetrics" {
+				if _, ok := needs["service"]; !ok {
+					needs["service"] = "connector " + e
+				}
+			}
+		}
+	}
+
+	if len(needs) > 1 {
+		return []types.DiagnosticFinding{{
+			RuleID:     types.RuleLoadBalancingKeyConflict,
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryConfig,
+			Summary:    fmt.Sprintf("Collectors behind exporter %q need conflicting routing keys", id),
+			Detail:     fmt.Sprintf("Pod %s runs %s, which needs routing_key traceID, and %s, which needs routing_key service. A single loadbalancing exporter cannot satisfy both, so one of them works on partial data.", pod, needs["traceID"], needs["service"]),
+			Suggestion: "Split tail sampling and span metrics into separate tiers, each fed by its own loadbalancing exporter",
+			Evidence:   &types.Evidence{SignalType: "traces", ComponentID: id, Path: "routing_key", SampleValues: []string{"traceID", "service"}},
+		}}
+	}
+	for key, component := range needs {
+		if key == routingKey {
+			continue
+		}
+		detail := fmt.Sprintf("Pod %s runs %s, which must see every span of a trace to make a sampling decision. With routing_key %s the spans of one trace are spread across collectors, so traces are sampled on partial data and decisions differ between replicas.", pod, component, routingKey)
+		if key == "service" {
+			detail = fmt.Sprintf("Pod %s runs %s, which must see all spans of a service to produce one series per service. With routing_key %s each collector emits its own cumulative series for the same service, which the backend sees as conflicting counters.", pod, component, routingKey)
+		}
+		return []types.DiagnosticFinding{{
+			RuleID:      types.RuleLoadBalancingRoutingKey,
+			Severity:    types.SeverityWarning,
+			Category:    types.CategoryConfig,
+			Summary:     fmt.Sprintf("Exporter %q routes by %s but the downstream %s needs %s", id, routingKey, component, key),
+			Detail:      detail,
+			Suggestion:  fmt.Sprintf("Set routing_key: %s", key),
+			Remediation: remediationYAML("exporters", id, nil, "routing_key: "+key),
+			Evidence:    &types.Evidence{SignalType: "traces", ComponentID: id, Path: "routing_key", SampleValues: []string{routingKey, key}},
+		}}
+	}
+	return nil
+}
+
+// checkDownstreamReceivers verifies that the downstream collector has an OTLP
+// gRPC receiver reachable on every port the resolver targets.
+func checkDownstreamReceivers(id string, target *lbTarget, pod string, downstream *collector.CollectorConfig) []types.DiagnosticFinding {
+	listening := make(map[int32]bool)
+	for _, rp := range receiverPorts(downstream) {
+		if componentType(rp.name) != "otlp" || !strings.HasSuffix(rp.name, "/grpc") {
+			continue
+		}
+		if rp.host == "localhost" || rp.host == "127.0.0.1" || rp.host == "::1" {
+			continue
+		}
+		listening[rp.port] = true
+	}
+
+	var findings []types.DiagnosticFinding
+	for _, port := range target.ports {
+		if listening[port] {
+			continue
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			RuleID:     types.RuleLoadBalancingNoReceiver,
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConfig,
+			Summary:    fmt.Sprintf("Exporter %q sends to port %d but downstream pod %s has no OTLP gRPC receiver there", id, port, pod),
+			Detail:     fmt.Sprintf("The loadbalancing exporter connects to each backend pod directly on port %d using OTLP gRPC. No otlp receiver in a pipeline of the downstream collector listens on that port on a pod-reachable address, so every export fails.", port),
+			Suggestion: "Add an OTLP gRPC receiver on the targeted port to the downstream collector, or align the resolver ports",
+			Remediation: remediationYAML("receivers", "otlp", []string{"protocols", "grpc"},
+				fmt.Sprintf("endpoint: ${env:MY_POD_IP}:%d", port)),
+			Evidence: &types.Evidence{ComponentID: id, Name: pod, Count: int(port)},
+		})
+	}
+	return findings
+}
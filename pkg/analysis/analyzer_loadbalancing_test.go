@@ -0,0 +1,147 @@
+package analysis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const tailSamplingGatewayConfig = `receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+processors:
+  tail_sampling: {}
+exporters:
+  debug: {}
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      processors: [tail_sampling]
+      exporters: [debug]
+`
+
+func loadBalancingConfig(routingKey string, resolver map[string]interface{}) *collector.CollectorConfig {
+	lb := map[string]interface{}{
+		"protocol": map[string]interface{}{"otlp": map[string]interface{}{}},
+		"resolver": resolver,
+	}
+	if routingKey != "" {
+		lb["routing_key"] = routingKey
+	}
+	return &collector.CollectorConfig{Exporters: map[string]interface{}{"loadbalancing": lb}}
+}
+
+func k8sResolver(service string, ports ...interface{}) map[string]interface{} {
+	k8s := map[string]interface{}{"service": service}
+	if len(ports) > 0 {
+		k8s["ports"] = ports
+	}
+	return map[string]interface{}{"k8s": k8s}
+}
+
+func TestAnalyzeLoadBalancing(t *testing.T) {
+	headless := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw-headless", Namespace: "obs"},
+		Spec:       corev1.ServiceSpec{ClusterIP: corev1.ClusterIPNone, Selector: map[string]string{"app": "gw"}},
+	}
+	clusterIP := headless.DeepCopy()
+	clusterIP.Spec.ClusterIP = "10.0.0.12"
+
+	tests := []struct {
+		name           string
+		config         *collector.CollectorConfig
+		service        *corev1.Service
+		expectSeverity []string
+	}{
+		{
+			name:    "headless service with tail sampling and default routing key",
+			config:  loadBalancingConfig("", k8sResolver("gw-headless.obs")),
+			service: headless,
+		},
+		{
+			name:           "missing resolver",
+			config:         loadBalancingConfig("", map[string]interface{}{}),
+			expectSeverity: []string{types.SeverityCritical},
+		},
+		{
+			name:           "k8s resolver service does not exist",
+			config:         loadBalancingConfig("", k8sResolver("gw-headless.obs")),
+			expectSeverity: []string{types.SeverityCritical},
+		},
+		{
+			name: "dns resolver on a ClusterIP service",
+			config: loadBalancingConfig("", map[string]interface{}{
+				"dns": map[string]interface{}{"hostname": "gw-headless.obs.svc.cluster.local"},
+			}),
+			service:        clusterIP,
+			expectSeverity: []string{types.SeverityWarning},
+		},
+		{
+			name:           "k8s resolver on a ClusterIP service",
+			config:         loadBalancingConfig("", k8sResolver("gw-headless")),
+			service:        clusterIP,
+			expectSeverity: []string{types.SeverityInfo},
+		},
+		{
+			name: "dns resolver with an external hostname",
+			config: loadBalancingConfig("", map[string]interface{}{
+				"dns": map[string]interface{}{"hostname": "otel.example.com"},
+			}),
+			expectSeverity: []string{types.SeverityWarning},
+		},
+		{
+			name:           "routing by service in front of tail sampling",
+			config:         loadBalancingConfig("service", k8sResolver("gw-headless.obs")),
+			service:        headless,
+			expectSeverity: []string{types.SeverityWarning},
+		},
+		{
+			name:           "resolver targets a port without an OTLP receiver",
+			config:         loadBalancingConfig("", k8sResolver("gw-headless.obs", 4317, 14317)),
+			service:        headless,
+			expectSeverity: []string{types.SeverityCritical},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			clientset := fake.NewClientset()
+			if tt.service != nil {
+				_, _ = clientset.CoreV1().Services("obs").Create(ctx, tt.service, metav1.CreateOptions{})
+			}
+			_, _ = clientset.CoreV1().ConfigMaps("obs").Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "gw-config", Namespace: "obs"},
+				Data:       map[string]string{"collector.yaml": tailSamplingGatewayConfig},
+			}, metav1.CreateOptions{})
+			_, _ = clientset.CoreV1().Pods("obs").Create(ctx, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "gw-0", Namespace: "obs", Labels: map[string]string{"app": "gw"}},
+				Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+					Name: "config",
+					VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "gw-config"},
+					}},
+				}}},
+			}, metav1.CreateOptions{})
+
+			input := &AnalysisInput{Config: tt.config, Clientset: clientset, Namespace: "obs"}
+			findings := AnalyzeLoadBalancing(ctx, input)
+			if len(findings) != len(tt.expectSeverity) {
+				t.Fatalf("expected %d findings, got %d: %+v", len(tt.expectSeverity), len(findings), findings)
+			}
+			for i, f := range findings {
+				if f.Severity != tt.expectSeverity[i] {
+					t.Errorf("finding %d: expected severity %s, got %s (%s)", i, tt.expectSeverity[i], f.Severity, f.Summary)
+				}
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ func AnalyzeMissingBatch(_ context.Context, input *AnalysisInput) []types.Diagno
 	for name, pipeline := range input.Config.Service.Pipelines {
 		if !pipelineHasProcessor(pipeline, "batch") {
 			findings = append(findings, types.DiagnosticFinding{
+				RuleID:   types.RuleMissingBatch,
 				Severity: types.SeverityWarning,
 				Category: types.CategoryPerformance,
 				Summary:  fmt.Sprintf("Pipeline %q is missing the batch processor", name),
@@ -31,6 +32,7 @@ service:
   pipelines:
     %s:
       processors: [batch, %s]`, name, joinProcessors(pipeline.Processors)),
+				Evidence: pipelineEvidence(name, ""),
 			})
 		}
 	}
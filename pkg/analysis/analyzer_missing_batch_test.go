@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
 )
 
 func TestAnalyzeMissingBatch_WithBatch(t *testing.T) {
@@ -50,6 +51,12 @@ func TestAnalyzeMissingBatch_WithoutBatch(t *testing.T) {
 	if findings[0].Remediation == "" {
 		t.Error("expected remediation to be present")
 	}
+	if findings[0].RuleID != types.RuleMissingBatch {
+		t.Errorf("expected rule %s, got %s", types.RuleMissingBatch, findings[0].RuleID)
+	}
+	if ev := findings[0].Evidence; ev == nil || ev.Pipeline != "traces" {
+		t.Errorf("expected evidence for pipeline traces, got %+v", ev)
+	}
 }
 
 func TestAnalyzeMissingBatch_MultiplePipelines(t *testing.T) {
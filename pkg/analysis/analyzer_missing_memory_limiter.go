@@ -7,7 +7,9 @@ import (
 	"github.com/hrexed/otel-collector-mcp/pkg/types"
 )
 
-// AnalyzeMissingMemoryLimiter checks each pipeline for the memory_limiter processor.
+// AnalyzeMissingMemoryLimiter checks each pipeline for the memory_limiter processor
+// and, when the pod spec is known, validates its limits against the container
+// memory limit and GOMEMLIMIT.
 func AnalyzeMissingMemoryLimiter(_ context.Context, input *AnalysisInput) []types.DiagnosticFinding {
 	if input.Config == nil {
 		return nil
@@ -17,6 +19,7 @@ func AnalyzeMissingMemoryLimiter(_ context.Context, input *AnalysisInput) []type
 	for name, pipeline := range input.Config.Service.Pipelines {
 		if !pipelineHasProcessor(pipeline, "memory_limiter") {
 			findings = append(findings, types.DiagnosticFinding{
+				RuleID:   types.RuleMissingMemoryLimiter,
 				Severity: types.SeverityCritical,
 				Category: types.CategoryPerformance,
 				Summary:  fmt.Sprintf("Pipeline %q is missing the memory_limiter processor", name),
@@ -32,8 +35,178 @@ service:
   pipelines:
     %s:
       processors: [memory_limiter, %s]`, name, joinProcessors(pipeline.Processors)),
+				Evidence: pipelineEvidence(name, ""),
 			})
 		}
 	}
+	findings = append(findings, checkMemoryLimiterSizing(input)...)
 	return findings
 }
+
+// checkMemoryLimiterSizing compares each memory_limiter's limits with the
+// collector container's memory limit and GOMEMLIMIT.
+func checkMemoryLimiterSizing(input *AnalysisInput) []types.DiagnosticFinding {
+	var containerMiB int64
+	var goMemLimitMiB int64
+	hasGoMemLimit := false
+	// A GOMEMLIMIT set through valueFrom is defined but its value is only
+	// known at runtime, so it is neither compared nor reported as unset.
+	goMemLimitDefined := false
+	c := collectorContainer(input.PodInfo)
+	if c != nil {
+		if mem := c.Resources.Limits.Memory(); mem != nil {
+			containerMiB = mem.Value() >> 20
+		}
+		goMemLimitDefined = containerEnvDefined(c, "GOMEMLIMIT")
+		if v, ok := containerEnv(c, "GOMEMLIMIT"); ok {
+			if b, ok := parseGoMemLimit(v); ok {
+				goMemLimitMiB, hasGoMemLimit = b>>20, true
+			}
+		}
+	}
+
+	var findings []types.DiagnosticFinding
+	unsetReported := false
+	for _, id := range sortedKeys(input.Config.Processors) {
+		if componentType(id) != "memory_limiter" {
+			continue
+		}
+		cfg, _ := input.Config.Processors[id].(map[string]interface{})
+
+		var limitMiB, spikeMiB int64
+		if v, ok := getNestedNumber(cfg, "limit_mib"); ok && v > 0 {
+			limitMiB = int64(v)
+		} else if pct, ok := getNestedNumber(cfg, "limit_percentage"); ok && pct > 0 {
+			if containerMiB == 0 {
+				if c != nil {
+					findings = append(findings, types.DiagnosticFinding{
+						RuleID:      types.RuleMemoryLimiterNoContainerLimit,
+						Severity:    types.SeverityWarning,
+						Category:    types.CategoryPerformance,
+						Summary:     fmt.Sprintf("%s uses limit_percentage but container %q has no memory limit", id, c.Name),
+						Detail:      "Without a cgroup memory limit, limit_percentage is computed from the node's total memory. The limiter then allows the collector to grow far beyond what the node can spare, and other pods are evicted first.",
+						Suggestion:  "Set a memory limit on the collector container",
+						Remediation: "resources:\n  limits:\n    memory: 1Gi",
+						Evidence:    componentEvidence(id, "limit_percentage"),
+					})
+				}
+				continue
+			}
+			limitMiB = int64(float64(containerMiB) * pct / 100)
+		}
+		if limitMiB == 0 {
+			continue
+		}
+		if v, ok := getNestedNumber(cfg, "spike_limit_mib"); ok && v > 0 {
+			spikeMiB = int64(v)
+		} else if pct, ok := getNestedNumber(cfg, "spike_limit_percentage"); ok && pct > 0 && containerMiB > 0 {
+			spikeMiB = int64(float64(containerMiB) * pct / 100)
+		} else {
+			spikeMiB = limitMiB / 5
+		}
+		softMiB := limitMiB - spikeMiB
+
+		target := limitMiB
+		if containerMiB > 0 {
+			target = containerMiB * 80 / 100
+		}
+		remediation := memoryLimiterRemediation(id, target)
+
+		if spikeMiB >= limitMiB {
+			findings = append(findings, types.DiagnosticFinding{
+				RuleID:      types.RuleMemoryLimiterSpike,
+				Severity:    types.SeverityCritical,
+				Category:    types.CategoryPerformance,
+				Summary:     fmt.Sprintf("%s spike limit (%d MiB) is not below its limit (%d MiB)", id, spikeMiB, limitMiB),
+				Detail:      "The soft limit is limit minus spike limit. A spike limit equal to or above the limit leaves no soft limit and the collector rejects the configuration at startup.",
+				Suggestion:  "Set spike_limit_mib to about 20% of limit_mib",
+				Remediation: remediation,
+				Evidence:    &types.Evidence{ComponentID: id, Path: "spike_limit_mib", Count: int(spikeMiB)},
+			})
+			continue
+		}
+
+		if containerMiB > 0 {
+			if limitMiB >= containerMiB {
+				findings = append(findings, types.DiagnosticFinding{
+					RuleID:      types.RuleMemoryLimiterAboveContainer,
+					Severity:    types.SeverityCritical,
+					Category:    types.CategoryPerformance,
+					Summary:     fmt.Sprintf("%s limit (%d MiB) is at or above the container memory limit (%d MiB)", id, limitMiB, containerMiB),
+					Detail:      "The kernel OOM-kills the container when it reaches its memory limit, before the memory_limiter starts refusing data. Under load the collector is killed instead of applying backpressure, and all in-memory batches and queues are lost.",
+					Suggestion:  "Set limit_mib to about 80% of the container memory limit",
+					Remediation: remediation,
+					Evidence:    &types.Evidence{ComponentID: id, Path: "limit_mib", Count: int(limitMiB)},
+				})
+			} else if limitMiB*10 > containerMiB*9 {
+				findings = append(findings, types.DiagnosticFinding{
+					RuleID:      types.RuleMemoryLimiterHeadroom,
+					Severity:    types.SeverityWarning,
+					Category:    types.CategoryPerformance,
+					Summary:     fmt.Sprintf("%s limit (%d MiB) leaves less than 10%% headroom below the container limit (%d MiB)", id, limitMiB, containerMiB),
+					Detail:      "The memory_limiter measures heap usage, but the container limit also covers the Go runtime, goroutine stacks and non-heap allocations. With this little headroom, memory can cross the container limit between two check intervals.",
+					Suggestion:  "Set limit_mib to about 80% of the container memory limit",
+					Remediation: remediation,
+					Evidence:    &types.Evidence{ComponentID: id, Path: "limit_mib", Count: int(limitMiB)},
+				})
+			}
+		}
+
+		if hasGoMemLimit {
+			if containerMiB > 0 && goMemLimitMiB >= containerMiB {
+				findings = append(findings, types.DiagnosticFinding{
+					RuleID:      types.RuleGoMemLimitAboveContainer,
+					Severity:    types.SeverityWarning,
+					Category:    types.CategoryPerformance,
+					Summary:     fmt.Sprintf("GOMEMLIMIT (%d MiB) is at or above the container memory limit (%d MiB)", goMemLimitMiB, containerMiB),
+					Detail:      "The Go garbage collector only works harder as the heap approaches GOMEMLIMIT. Set at or above the container limit, it never gets the chance before the container is OOM-killed.",
+					Suggestion:  "Set GOMEMLIMIT to the memory_limiter soft limit (limit minus spike limit)",
+					Remediation: remediation,
+					Evidence:    &types.Evidence{ComponentID: id, Path: "GOMEMLIMIT", Count: int(goMemLimitMiB)},
+				})
+			} else if goMemLimitMiB < softMiB && (containerMiB == 0 || limitMiB < containerMiB) {
+				findings = append(findings, types.DiagnosticFinding{
+					RuleID:      types.RuleGoMemLimitBelowSoftLimit,
+					Severity:    types.SeverityWarning,
+					Category:    types.CategoryPerformance,
+					Summary:     fmt.Sprintf("GOMEMLIMIT (%d MiB) is below the %s soft limit (%d MiB)", goMemLimitMiB, id, softMiB),
+					Detail:      "The garbage collector keeps the heap under GOMEMLIMIT by collecting more and more often, so the memory_limiter never triggers. Under load the collector burns CPU on GC instead of refusing data, and throughput collapses.",
+					Suggestion:  "Raise GOMEMLIMIT to the soft limit, or lower the memory_limiter limits",
+					Remediation: remediation,
+					Evidence:    &types.Evidence{ComponentID: id, Path: "GOMEMLIMIT", Count: int(goMemLimitMiB)},
+				})
+			}
+		} else if containerMiB > 0 && !goMemLimitDefined && !unsetReported {
+			// GOMEMLIMIT belongs to the container, so it is reported once
+			// however many memory_limiter instances there are.
+			unsetReported = true
+			findings = append(findings, types.DiagnosticFinding{
+				RuleID:      types.RuleGoMemLimitUnset,
+				Severity:    types.SeverityInfo,
+				Category:    types.CategoryPerformance,
+				Summary:     fmt.Sprintf("GOMEMLIMIT is not set on container %q", c.Name),
+				Detail:      "Without GOMEMLIMIT the garbage collector is unaware of the container memory limit and lets the heap double between collections. Setting it to the memory_limiter soft limit makes GC and the limiter work together.",
+				Suggestion:  "Set GOMEMLIMIT on the collector container",
+				Remediation: remediation,
+				Evidence:    &types.Evidence{ComponentID: id, Path: "GOMEMLIMIT", Count: int(softMiB)},
+			})
+		}
+	}
+	return findings
+}
+
+// memoryLimiterRemediation renders memory_limiter settings and a matching
+// GOMEMLIMIT for a target hard limit: spike at 20% and GOMEMLIMIT at the soft limit.
+func memoryLimiterRemediation(id string, limitMiB int64) string {
+	spikeMiB := limitMiB / 5
+	return fmt.Sprintf(`processors:
+  %s:
+    check_interval: 1s
+    limit_mib: %d
+    spike_limit_mib: %d
+
+# collector container
+env:
+  - name: GOMEMLIMIT
+    value: %dMiB`, id, limitMiB, spikeMiB, limitMiB-spikeMiB)
+}
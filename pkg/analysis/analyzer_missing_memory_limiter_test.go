@@ -0,0 +1,181 @@
+package analysis
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func memoryPod(limit, goMemLimit string) *corev1.Pod {
+	c := corev1.Container{Name: "otc-container"}
+	if limit != "" {
+		c.Resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(limit)}
+	}
+	if goMemLimit != "" {
+		c.Env = []corev1.EnvVar{{Name: "GOMEMLIMIT", Value: goMemLimit}}
+	}
+	return &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{c}}}
+}
+
+func memoryLimiterConfig(settings map[string]interface{}) *collector.CollectorConfig {
+	return &collector.CollectorConfig{
+		Processors: map[string]interface{}{"memory_limiter": settings},
+		Service: collector.ServiceConfig{
+			Pipelines: map[string]collector.PipelineConfig{
+				"traces": {Receivers: []string{"otlp"}, Processors: []string{"memory_limiter"}, Exporters: []string{"otlp"}},
+			},
+		},
+	}
+}
+
+func TestAnalyzeMissingMemoryLimiter_Sizing(t *testing.T) {
+	tests := []struct {
+		name           string
+		settings       map[string]interface{}
+		pod            *corev1.Pod
+		expectSeverity []string
+	}{
+		{
+			name:     "no pod info only checks presence",
+			settings: map[string]interface{}{"limit_mib": 4096},
+		},
+		{
+			name:     "well sized limiter and GOMEMLIMIT",
+			settings: map[string]interface{}{"limit_mib": 800, "spike_limit_mib": 160},
+			pod:      memoryPod("1Gi", "640MiB"),
+		},
+		{
+			name:           "limit above container limit",
+			settings:       map[string]interface{}{"limit_mib": 2048, "spike_limit_mib": 400},
+			pod:            memoryPod("1Gi", "800MiB"),
+			expectSeverity: []string{types.SeverityCritical},
+		},
+		{
+			name:           "no headroom",
+			settings:       map[string]interface{}{"limit_percentage": 95, "spike_limit_percentage": 15},
+			pod:            memoryPod("1Gi", "820MiB"),
+			expectSeverity: []string{types.SeverityWarning},
+		},
+		{
+			name:           "GOMEMLIMIT below soft limit never triggers the limiter",
+			settings:       map[string]interface{}{"limit_mib": 800, "spike_limit_mib": 160},
+			pod:            memoryPod("1Gi", "256MiB"),
+			expectSeverity: []string{types.SeverityWarning},
+		},
+		{
+			name:           "spike limit not below limit",
+			settings:       map[string]interface{}{"limit_mib": 512, "spike_limit_mib": 512},
+			expectSeverity: []string{types.SeverityCritical},
+		},
+		{
+			name:           "percentage without container limit",
+			settings:       map[string]interface{}{"limit_percentage": 80},
+			pod:            memoryPod("", ""),
+			expectSeverity: []string{types.SeverityWarning},
+		},
+		{
+			name:           "missing GOMEMLIMIT",
+			settings:       map[string]interface{}{"limit_mib": 800, "spike_limit_mib": 160},
+			pod:            memoryPod("1Gi", ""),
+			expectSeverity: []string{types.SeverityInfo},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &AnalysisInput{Config: memoryLimiterConfig(tt.settings), PodInfo: tt.pod}
+			findings := AnalyzeMissingMemoryLimiter(context.Background(), input)
+			if len(findings) != len(tt.expectSeverity) {
+				t.Fatalf("expected %d findings, got %d: %+v", len(tt.expectSeverity), len(findings), findings)
+			}
+			for i, f := range findings {
+				if f.Severity != tt.expectSeverity[i] {
+					t.Errorf("finding %d: expected severity %s, got %s (%s)", i, tt.expectSeverity[i], f.Severity, f.Summary)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeMissingMemoryLimiter_GoMemLimit(t *testing.T) {
+	valueFrom := memoryPod("1Gi", "")
+	valueFrom.Spec.Containers[0].Env = []corev1.EnvVar{{
+		Name:      "GOMEMLIMIT",
+		ValueFrom: &corev1.EnvVarSource{ResourceFieldRef: &corev1.ResourceFieldSelector{Resource: "limits.memory"}},
+	}}
+	twoLimiters := memoryLimiterConfig(map[string]interface{}{"limit_mib": 800, "spike_limit_mib": 160})
+	twoLimiters.Processors["memory_limiter/logs"] = map[string]interface{}{"limit_mib": 700, "spike_limit_mib": 140}
+
+	tests := []struct {
+		name        string
+		config      *collector.CollectorConfig
+		pod         *corev1.Pod
+		expectUnset int
+	}{
+		{
+			name:   "GOMEMLIMIT from valueFrom is set",
+			config: memoryLimiterConfig(map[string]interface{}{"limit_mib": 800, "spike_limit_mib": 160}),
+			pod:    valueFrom,
+		},
+		{
+			name:        "missing GOMEMLIMIT is reported once per container",
+			config:      twoLimiters,
+			pod:         memoryPod("1Gi", ""),
+			expectUnset: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := AnalyzeMissingMemoryLimiter(context.Background(), &AnalysisInput{Config: tt.config, PodInfo: tt.pod})
+			unset := 0
+			for _, f := range findings {
+				if f.RuleID == types.RuleGoMemLimitUnset {
+					unset++
+				}
+			}
+			if unset != tt.expectUnset {
+				t.Errorf("expected %d %s findings, got %d: %+v", tt.expectUnset, types.RuleGoMemLimitUnset, unset, findings)
+			}
+		})
+	}
+}
+
+func TestAnalyzeMissingMemoryLimiter_ComputedRemediation(t *testing.T) {
+	input := &AnalysisInput{
+		Config:  memoryLimiterConfig(map[string]interface{}{"limit_mib": 2048}),
+		PodInfo: memoryPod("1Gi", ""),
+	}
+
+	findings := AnalyzeMissingMemoryLimiter(context.Background(), input)
+	if len(findings) == 0 {
+		t.Fatal("expected findings")
+	}
+	for _, want := range []string{"limit_mib: 819", "spike_limit_mib: 163", "value: 656MiB"} {
+		if !strings.Contains(findings[0].Remediation, want) {
+			t.Errorf("expected %q in remediation, got:\n%s", want, findings[0].Remediation)
+		}
+	}
+}
+
+func TestParseGoMemLimit(t *testing.T) {
+	tests := map[string]int64{
+		"1073741824": 1 << 30,
+		"512MiB":     512 << 20,
+		"2GiB":       2 << 30,
+		"100KiB":     100 << 10,
+	}
+	for in, want := range tests {
+		if got, ok := parseGoMemLimit(in); !ok || got != want {
+			t.Errorf("parseGoMemLimit(%q) = %d, %v; want %d", in, got, ok, want)
+		}
+	}
+	if _, ok := parseGoMemLimit("off"); ok {
+		t.Error("expected off to be rejected")
+	}
+}
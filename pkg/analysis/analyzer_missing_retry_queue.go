@@ -7,6 +7,22 @@ import (
 	"github.com/hrexed/otel-collector-mcp/pkg/types"
 )
 
+// exporterResilienceKeys lists exporter types that configure retries or
+// queueing under their own keys instead of retry_on_failure and sending_queue.
+var exporterResilienceKeys = map[string][2]string{
+	"elasticsearch":         {"retry", "sending_queue"},
+	"prometheusremotewrite": {"retry_on_failure", "remote_write_queue"},
+}
+
+// ExporterResilienceKeys returns the settings that configure retries and the
+// sending queue of an exporter.
+func ExporterResilienceKeys(id string) (retry, queue string) {
+	if keys, ok := exporterResilienceKeys[componentType(id)]; ok {
+		return keys[0], keys[1]
+	}
+	return "retry_on_failure", "sending_queue"
+}
+
 // AnalyzeMissingRetryQueue checks exporters for retry_on_failure and sending_queue settings.
 func AnalyzeMissingRetryQueue(_ context.Context, input *AnalysisInput) []types.DiagnosticFinding {
 	if input.Config == nil {
@@ -25,45 +41,58 @@ func AnalyzeMissingRetryQueue(_ context.Context, input *AnalysisInput) []types.D
 			continue
 		}
 
-		_, hasRetry := cfgMap["retry_on_failure"]
-		_, hasQueue := cfgMap["sending_queue"]
+		retryKey, queueKey := ExporterResilienceKeys(name)
+		_, hasRetry := cfgMap[retryKey]
+		_, hasQueue := cfgMap[queueKey]
 
 		if !hasRetry {
 			findings = append(findings, types.DiagnosticFinding{
+				RuleID:     types.RuleMissingRetry,
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryPerformance,
-				Summary:    fmt.Sprintf("Exporter %q is missing retry_on_failure configuration", name),
-				Detail:     "Without retry configuration, transient export failures will cause permanent data loss. The retry_on_failure setting enables automatic retries with exponential backoff.",
-				Suggestion: "Add retry_on_failure to this exporter",
-				Remediation: fmt.Sprintf(`exporters:
-  %s:
-    retry_on_failure:
-      enabled: true
-      initial_interval: 5s
-      max_interval: 30s
-      max_elapsed_time: 300s`, name),
+				Summary:    fmt.Sprintf("Exporter %q is missing %s configuration", name, retryKey),
+				Detail:     fmt.Sprintf("Without retry configuration, transient export failures will cause permanent data loss. The %s setting enables automatic retries with exponential backoff.", retryKey),
+				Suggestion: fmt.Sprintf("Add %s to this exporter", retryKey),
+				Remediation: retryRemediation(name, retryKey),
+				Evidence: componentEvidence(name, retryKey),
 			})
 		}
 
 		if !hasQueue {
 			findings = append(findings, types.DiagnosticFinding{
+				RuleID:     types.RuleMissingQueue,
 				Severity:   types.SeverityWarning,
 				Category:   types.CategoryPerformance,
-				Summary:    fmt.Sprintf("Exporter %q is missing sending_queue configuration", name),
+				Summary:    fmt.Sprintf("Exporter %q is missing %s configuration", name, queueKey),
 				Detail:     "Without a sending queue, the exporter processes data synchronously. If the backend is slow, this causes backpressure that propagates to receivers. A sending queue buffers data and decouples the exporter from the pipeline.",
-				Suggestion: "Add sending_queue to this exporter",
+				Suggestion: fmt.Sprintf("Add %s to this exporter", queueKey),
 				Remediation: fmt.Sprintf(`exporters:
   %s:
-    sending_queue:
+    %s:
       enabled: true
       num_consumers: 10
-      queue_size: 5000`, name),
+      queue_size: 5000`, name, queueKey),
+				Evidence: componentEvidence(name, queueKey),
 			})
 		}
 	}
 	return findings
 }
 
+// retryRemediation renders the retry settings of an exporter.
+func retryRemediation(name, retryKey string) string {
+	remediation := fmt.Sprintf(`exporters:
+  %s:
+    %s:
+      enabled: true
+      initial_interval: 5s
+      max_interval: 30s`, name, retryKey)
+	if retryKey == "retry_on_failure" {
+		remediation += "\n      max_elapsed_time: 300s"
+	}
+	return remediation
+}
+
 // isLocalExporter returns true for exporters that write locally (debug, logging, file/*)
 // where retry_on_failure and sending_queue are not meaningful.
 func isLocalExporter(name string) bool {
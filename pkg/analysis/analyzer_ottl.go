@@ -0,0 +1,168 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/ottl"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+)
+
+// AnalyzeOTTL parses and validates the OTTL statements and conditions of
+// transform and filter processors: syntax errors, unknown functions, wrong
+// argument counts and paths not available in the statement's context.
+func AnalyzeOTTL(_ context.Context, input *AnalysisInput) []types.DiagnosticFinding {
+	if input.Config == nil {
+		return nil
+	}
+
+	var findings []types.DiagnosticFinding
+	for _, id := range sortedKeys(input.Config.Processors) {
+		cfg, ok := input.Config.Processors[id].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch componentType(id) {
+		case "transform":
+			findings = append(findings, checkTransformStatements(id, cfg)...)
+		case "filter":
+			findings = append(findings, checkFilterConditions(id, cfg)...)
+		}
+	}
+	return findings
+}
+
+func checkTransformStatements(id string, cfg map[string]interface{}) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	for _, key := range sortedKeys(cfg) {
+		signal, ok := ottl.TransformStatementSignals[key]
+		if !ok {
+			continue
+		}
+		groups, _ := cfg[key].([]interface{})
+		for i, g := range groups {
+			switch group := g.(type) {
+			case string:
+				// Statements listed without a group infer their context
+				// from path prefixes.
+				where := fmt.Sprintf("%s[%d]", key, i)
+				findings = append(findings, ottlFindings(id, where, group, ottl.CheckInferred(signal, group))...)
+			case map[string]interface{}:
+				findings = append(findings, checkStatementGroup(id, fmt.Sprintf("%s[%d]", key, i), signal, group)...)
+			}
+		}
+	}
+	return findings
+}
+
+func checkStatementGroup(id, where, signal string, group map[string]interface{}) []types.DiagnosticFinding {
+	statements, _ := group["statements"].([]interface{})
+	conditions, _ := group["conditions"].([]interface{})
+
+	name, _ := getNestedString(group, "context")
+	ctx := ottl.Context(name)
+	if name != "" && !validSignalContext(signal, ctx) {
+		return []types.DiagnosticFinding{{
+			RuleID:     types.RuleOTTLInvalidContext,
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConfig,
+			Summary:    fmt.Sprintf("Processor %q uses context %q in %s, which is not valid for %s", id, name, where, signal),
+			Detail:     fmt.Sprintf("The transform processor rejects the configuration. Valid contexts for %s are %v.", signal, ottl.ContextsForSignal(signal)),
+			Suggestion: "Set context to one of the contexts valid for this signal",
+			Evidence:   &types.Evidence{SignalType: signal, ComponentID: id, Path: where + ".context", SampleValues: []string{name}},
+		}}
+	}
+
+	var findings []types.DiagnosticFinding
+	for j, s := range statements {
+		stmt, ok := s.(string)
+		if !ok {
+			continue
+		}
+		path := fmt.Sprintf("%s.statements[%d]", where, j)
+		if name == "" {
+			findings = append(findings, ottlFindings(id, path, stmt, ottl.CheckInferred(signal, stmt))...)
+			continue
+		}
+		findings = append(findings, ottlFindings(id, path, stmt, ottl.CheckStatement(ctx, stmt))...)
+	}
+	if name == "" {
+		return findings
+	}
+	for j, c := range conditions {
+		cond, ok := c.(string)
+		if !ok {
+			continue
+		}
+		path := fmt.Sprintf("%s.conditions[%d]", where, j)
+		findings = append(findings, ottlFindings(id, path, cond, ottl.CheckCondition(ctx, cond))...)
+	}
+	return findings
+}
+
+func checkFilterConditions(id string, cfg map[string]interface{}) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	for _, signal := range []string{"traces", "metrics", "logs"} {
+		section, ok := getNestedMap(cfg, signal)
+		if !ok {
+			continue
+		}
+		for _, key := range sortedKeys(section) {
+			ctx, ok := ottl.FilterConditionContexts[signal][key]
+			if !ok {
+				continue
+			}
+			conditions, _ := section[key].([]interface{})
+			for i, c := range conditions {
+				cond, ok := c.(string)
+				if !ok {
+					continue
+				}
+				path := fmt.Sprintf("%s.%s[%d]", signal, key, i)
+				findings = append(findings, ottlFindings(id, path, cond, ottl.CheckCondition(ctx, cond))...)
+			}
+		}
+	}
+	return findings
+}
+
+// ottlFindings turns validation problems into one critical finding per problem.
+func ottlFindings(id, path, text string, problems []ottl.Problem) []types.DiagnosticFinding {
+	findings := make([]types.DiagnosticFinding, 0, len(problems))
+	for _, p := range problems {
+		findings = append(findings, types.DiagnosticFinding{
+			RuleID:     types.RuleOTTLInvalidExpression,
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryConfig,
+			Summary:    fmt.Sprintf("Processor %q has an invalid OTTL expression at %s: %s", id, path, p.Message),
+			Detail:     fmt.Sprintf("The expression %q fails validation (%s). The collector rejects the configuration at startup.", text, p.Kind),
+			Suggestion: ottlSuggestion(p.Kind),
+			Evidence:   &types.Evidence{ComponentID: id, Path: path, SampleValues: []string{text}},
+		})
+	}
+	return findings
+}
+
+func ottlSuggestion(kind string) string {
+	switch kind {
+	case ottl.ProblemSyntax:
+		return "Fix the OTTL syntax; conditions use and/or/not and string literals use double quotes"
+	case ottl.ProblemFunction:
+		return "Use an editor (lowercase) as the statement and converters (uppercase) as values; check the function name against the OTTL function reference"
+	case ottl.ProblemArity:
+		return "Pass the number of arguments the function expects"
+	case ottl.ProblemContext, ottl.ProblemPath:
+		return "Move the statement to a context that exposes the path, or reference higher contexts through their prefix such as resource. or metric."
+	default:
+		return "Fix the OTTL expression"
+	}
+}
+
+func validSignalContext(signal string, ctx ottl.Context) bool {
+	for _, c := range ottl.ContextsForSignal(signal) {
+		if c == ctx {
+			return true
+		}
+	}
+	return false
+}
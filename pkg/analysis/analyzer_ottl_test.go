@@ -0,0 +1,89 @@
+package analysis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+)
+
+func TestAnalyzeOTTL(t *testing.T) {
+	tests := []struct {
+		name           string
+		processors     map[string]interface{}
+		expectSeverity []string
+	}{
+		{
+			name: "valid transform and filter",
+			processors: map[string]interface{}{
+				"transform": map[string]interface{}{
+					"log_statements": []interface{}{
+						map[string]interface{}{
+							"context":    "log",
+							"conditions": []interface{}{`IsString(body)`},
+							"statements": []interface{}{`set(severity_text, attributes["level"]) where attributes["level"] != nil`},
+						},
+					},
+					"trace_statements": []interface{}{`set(span.attributes["svc"], resource.attributes["service.name"])`},
+				},
+				"filter/health": map[string]interface{}{
+					"traces": map[string]interface{}{"span": []interface{}{`attributes["http.route"] == "/health"`}},
+				},
+			},
+		},
+		{
+			name: "unknown function and wrong context path",
+			processors: map[string]interface{}{
+				"transform": map[string]interface{}{
+					"trace_statements": []interface{}{
+						map[string]interface{}{
+							"context":    "span",
+							"statements": []interface{}{`rename(attributes["a"], "b")`, `set(attributes["msg"], body)`},
+						},
+					},
+				},
+			},
+			expectSeverity: []string{types.SeverityCritical, types.SeverityCritical},
+		},
+		{
+			name: "metric editor in datapoint context and invalid context",
+			processors: map[string]interface{}{
+				"transform/metrics": map[string]interface{}{
+					"metric_statements": []interface{}{
+						map[string]interface{}{"context": "datapoint", "statements": []interface{}{`convert_sum_to_gauge()`}},
+						map[string]interface{}{"context": "log", "statements": []interface{}{`set(body, "x")`}},
+					},
+				},
+			},
+			expectSeverity: []string{types.SeverityCritical, types.SeverityCritical},
+		},
+		{
+			name: "filter syntax error and uninferrable statement",
+			processors: map[string]interface{}{
+				"filter": map[string]interface{}{
+					"logs": map[string]interface{}{"log_record": []interface{}{`severity_number < SEVERITY_NUMBER_WARN and`}},
+				},
+				"transform": map[string]interface{}{
+					"log_statements": []interface{}{`set(attributes["a"], "b")`},
+				},
+			},
+			expectSeverity: []string{types.SeverityCritical, types.SeverityCritical},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &AnalysisInput{Config: &collector.CollectorConfig{Processors: tt.processors}}
+			findings := AnalyzeOTTL(context.Background(), input)
+			if len(findings) != len(tt.expectSeverity) {
+				t.Fatalf("expected %d findings, got %d: %+v", len(tt.expectSeverity), len(findings), findings)
+			}
+			for i, f := range findings {
+				if f.Severity != tt.expectSeverity[i] {
+					t.Errorf("finding %d: expected severity %s, got %s (%s)", i, tt.expectSeverity[i], f.Severity, f.Summary)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+)
+
+// defaultScrapeInterval is Prometheus' global scrape_interval default.
+const defaultScrapeInterval = time.Minute
+
+// relabelSections are the relabel rule lists of a scrape config.
+var relabelSections = []string{"relabel_configs", "metric_relabel_configs"}
+
+// braceCaptureGroup matches ${1} and ${name}: regex capture group references
+// that the collector reads as environment variables.
+var braceCaptureGroup = regexp.MustCompile(`^\$\{[0-9A-Za-z_]+\}`)
+
+// AnalyzePrometheusScrape checks the scrape_configs embedded in prometheus
+// receivers: duplicate job names, capture group references not escaped from
+// the collector's env expansion, invalid relabel regexes, and scrape timeouts
+// longer than the scrape interval.
+func AnalyzePrometheusScrape(_ context.Context, input *AnalysisInput) []types.DiagnosticFinding {
+	if input.Config == nil {
+		return nil
+	}
+
+	var findings []types.DiagnosticFinding
+	for _, id := range sortedKeys(input.Config.Receivers) {
+		if componentType(id) != "prometheus" {
+			continue
+		}
+		cfg, _ := input.Config.Receivers[id].(map[string]interface{})
+		promCfg, _ := getNestedMap(cfg, "config")
+		scrapeConfigs, _ := promCfg["scrape_configs"].([]interface{})
+
+		globalInterval := defaultScrapeInterval
+		if global, ok := getNestedMap(promCfg, "global"); ok {
+			if d, ok := promDuration(global, "scrape_interval"); ok {
+				globalInterval = d
+			}
+		}
+
+		jobs := make(map[string]int)
+		for i, sc := range scrapeConfigs {
+			job, ok := sc.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := getNestedString(job, "job_name")
+			if name == "" {
+				name = fmt.Sprintf("#%d", i)
+			}
+			jobs[name]++
+			if jobs[name] == 2 {
+				findings = append(findings, types.DiagnosticFinding{
+					RuleID:     types.RuleScrapeDuplicateJob,
+					Severity:   types.SeverityCritical,
+					Category:   types.CategoryConfig,
+					Summary:    fmt.Sprintf("Receiver %q defines scrape job %q more than once", id, name),
+					Detail:     "Prometheus requires unique job names within a scrape configuration. The receiver fails to start with \"found multiple scrape configs with job name\".",
+					Suggestion: "Rename or merge the duplicate scrape jobs",
+					Evidence:   &types.Evidence{SignalType: "metrics", Name: name, ComponentID: id, Path: fmt.Sprintf("config.scrape_configs[%d]", i), Count: jobs[name]},
+				})
+			}
+
+			interval := globalInterval
+			if d, ok := promDuration(job, "scrape_interval"); ok {
+				interval = d
+			}
+			if timeout, ok := promDuration(job, "scrape_timeout"); ok && timeout > interval {
+				findings = append(findings, types.DiagnosticFinding{
+					RuleID:      types.RuleScrapeTimeoutExceeds,
+					Severity:    types.SeverityCritical,
+					Category:    types.CategoryConfig,
+					Summary:     fmt.Sprintf("Scrape job %q in receiver %q has scrape_timeout %s longer than scrape_interval %s", name, id, timeout, interval),
+					Detail:      "Prometheus rejects a scrape timeout greater than the scrape interval, so the receiver fails to start.",
+					Suggestion:  "Set scrape_timeout to at most the scrape interval",
+					Remediation: fmt.Sprintf("scrape_configs:\n  - job_name: %s\n    scrape_interval: %s\n    scrape_timeout: %s", name, interval, interval),
+					Evidence:    &types.Evidence{SignalType: "metrics", Name: name, ComponentID: id, Path: fmt.Sprintf("config.scrape_configs[%d].scrape_timeout", i)},
+				})
+			}
+
+			for _, section := range relabelSections {
+				rules, _ := job[section].([]interface{})
+				for j, r := range rules {
+					rule, ok := r.(map[string]interface{})
+					if !ok {
+						continue
+					}
+					findings = append(findings, checkRelabelRule(id, name, fmt.Sprintf("%s[%d]", section, j), rule)...)
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// checkRelabelRule flags unescaped $ references and invalid regexes in one relabel rule.
+func checkRelabelRule(receiver, job, path string, rule map[string]interface{}) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	for _, field := range []string{"replacement", "target_label", "regex"} {
+		value, ok := getNestedString(rule, field)
+		if !ok {
+			continue
+		}
+		ref, braced := unescapedDollar(value)
+		if ref == "" {
+			continue
+		}
+		f := types.DiagnosticFinding{
+			RuleID:      types.RuleScrapeUnescapedDollar,
+			Severity:    types.SeverityWarning,
+			Category:    types.CategoryConfig,
+			Summary:     fmt.Sprintf("Scrape job %q in receiver %q uses unescaped %s in %s.%s", job, receiver, ref, path, field),
+			Detail:      fmt.Sprintf("The collector expands $ in its config before the Prometheus receiver sees it. Depending on the collector version, %s is replaced with an empty string, so the relabel rule writes empty values.", ref),
+			Suggestion:  "Escape capture group references as $$",
+			Remediation: fmt.Sprintf("%s: %s", field, escapeDollars(value)),
+			Evidence:    &types.Evidence{SignalType: "metrics", Name: job, ComponentID: receiver, Path: path + "." + field, SampleValues: []string{value}},
+		}
+		if braced {
+			f.Severity = types.SeverityCritical
+			f.Detail = fmt.Sprintf("The collector reads %s as an environment variable reference before the Prometheus receiver sees it. The config fails to load or the reference expands to an empty string.", ref)
+		}
+		findings = append(findings, f)
+	}
+
+	if pattern, ok := getNestedString(rule, "regex"); ok {
+		unescaped := strings.ReplaceAll(pattern, "$$", "$")
+		if _, err := regexp.Compile("^(?:" + unescaped + ")$"); err != nil {
+			findings = append(findings, types.DiagnosticFinding{
+				RuleID:     types.RuleScrapeInvalidRegex,
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryConfig,
+				Summary:    fmt.Sprintf("Scrape job %q in receiver %q has an invalid regex in %s", job, receiver, path),
+				Detail:     fmt.Sprintf("The pattern %q does not compile (%v). Prometheus rejects the scrape configuration and the receiver fails to start.", pattern, err),
+				Suggestion: "Fix the regex; Prometheus uses RE2 syntax and anchors patterns at both ends",
+				Evidence:   &types.Evidence{SignalType: "metrics", Name: job, ComponentID: receiver, Path: path + ".regex", SampleValues: []string{pattern}},
+			})
+		}
+	}
+	return findings
+}
+
+// unescapedDollar returns the first $ reference in s that is neither an
+// escaped $$ nor an ${env:...} reference, and whether it uses braces.
+func unescapedDollar(s string) (string, bool) {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' {
+			continue
+		}
+		rest := s[i:]
+		switch {
+		case strings.HasPrefix(rest, "$$"):
+			i++
+		case strings.HasPrefix(rest, "${env:"):
+		case braceCaptureGroup.MatchString(rest):
+			return braceCaptureGroup.FindString(rest), true
+		case len(rest) > 1 && isRefChar(rest[1]):
+			end := 1
+			for end < len(rest) && isRefChar(rest[end]) {
+				end++
+			}
+			return rest[:end], false
+		}
+	}
+	return "", false
+}
+
+// escapeDollars doubles every $ that is not already escaped.
+func escapeDollars(s string) string {
+	const placeholder = "\x00"
+	s = strings.ReplaceAll(s, "$$", placeholder)
+	s = strings.ReplaceAll(s, "$", "$$")
+	return strings.ReplaceAll(s, placeholder, "$$")
+}
+
+func isRefChar(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// promDuration reads a Prometheus duration such as 30s, 1m30s or 1d.
+func promDuration(m map[string]interface{}, key string) (time.Duration, bool) {
+	s, ok := getNestedString(m, key)
+	if !ok || s == "" {
+		return 0, false
+	}
+	var total time.Duration
+	units := map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour, 'y': 365 * 24 * time.Hour}
+	for s != "" {
+		i := 0
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		if i == 0 || i == len(s) {
+			return 0, false
+		}
+		n, err := strconv.Atoi(s[:i])
+		if err != nil {
+			return 0, false
+		}
+		j := i
+		for j < len(s) && (s[j] < '0' || s[j] > '9') {
+			j++
+		}
+		unit := s[i:j]
+		if d, ok := units[unit[0]]; ok && len(unit) == 1 {
+			total += time.Duration(n) * d
+		} else {
+			d, err := time.ParseDuration(s[:j])
+			if err != nil {
+				return 0, false
+			}
+			total += d
+		}
+		s = s[j:]
+	}
+	return total, true
+}
@@ -0,0 +1,117 @@
+package analysis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+)
+
+func prometheusConfig(scrapeConfigs ...interface{}) *collector.CollectorConfig {
+	return &collector.CollectorConfig{
+		Receivers: map[string]interface{}{
+			"prometheus": map[string]interface{}{
+				"config": map[string]interface{}{
+					"global":         map[string]interface{}{"scrape_interval": "30s"},
+					"scrape_configs": scrapeConfigs,
+				},
+			},
+		},
+	}
+}
+
+func scrapeJob(name string, extra map[string]interface{}) map[string]interface{} {
+	job := map[string]interface{}{"job_name": name}
+	for k, v := range extra {
+		job[k] = v
+	}
+	return job
+}
+
+func TestAnalyzePrometheusScrape(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         *collector.CollectorConfig
+		expectSeverity []string
+	}{
+		{
+			name: "valid jobs with escaped relabel rules",
+			config: prometheusConfig(
+				scrapeJob("kubelet", map[string]interface{}{"scrape_interval": "15s", "scrape_timeout": "10s"}),
+				scrapeJob("pods", map[string]interface{}{
+					"relabel_configs": []interface{}{
+						map[string]interface{}{
+							"source_labels": []interface{}{"__meta_kubernetes_pod_annotation_prometheus_io_port"},
+							"regex":         "([^:]+)(?::\\d+)?;(\\d+)",
+							"replacement":   "$$1:$$2",
+							"target_label":  "__address__",
+						},
+					},
+				}),
+			),
+		},
+		{
+			name: "duplicate job names",
+			config: prometheusConfig(
+				scrapeJob("pods", nil),
+				scrapeJob("pods", nil),
+				scrapeJob("pods", nil),
+			),
+			expectSeverity: []string{types.SeverityCritical},
+		},
+		{
+			name: "timeout longer than the global interval",
+			config: prometheusConfig(
+				scrapeJob("slow", map[string]interface{}{"scrape_timeout": "45s"}),
+			),
+			expectSeverity: []string{types.SeverityCritical},
+		},
+		{
+			name: "unescaped capture groups and invalid regex",
+			config: prometheusConfig(
+				scrapeJob("pods", map[string]interface{}{
+					"metric_relabel_configs": []interface{}{
+						map[string]interface{}{"regex": "(.*", "replacement": "$1"},
+						map[string]interface{}{"target_label": "${1}_total"},
+					},
+				}),
+			),
+			expectSeverity: []string{types.SeverityWarning, types.SeverityCritical, types.SeverityCritical},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := AnalyzePrometheusScrape(context.Background(), &AnalysisInput{Config: tt.config})
+			if len(findings) != len(tt.expectSeverity) {
+				t.Fatalf("expected %d findings, got %d: %+v", len(tt.expectSeverity), len(findings), findings)
+			}
+			for i, f := range findings {
+				if f.Severity != tt.expectSeverity[i] {
+					t.Errorf("finding %d: expected severity %s, got %s (%s)", i, tt.expectSeverity[i], f.Severity, f.Summary)
+				}
+			}
+		})
+	}
+}
+
+func TestPromDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"30s":   30 * time.Second,
+		"1m30s": 90 * time.Second,
+		"1d":    24 * time.Hour,
+		"1h5m":  65 * time.Minute,
+		"500ms": 500 * time.Millisecond,
+	}
+	for in, want := range tests {
+		got, ok := promDuration(map[string]interface{}{"d": in}, "d")
+		if !ok || got != want {
+			t.Errorf("promDuration(%q) = %v, %v; want %v", in, got, ok, want)
+		}
+	}
+	if _, ok := promDuration(map[string]interface{}{"d": "soon"}, "d"); ok {
+		t.Error("expected invalid duration to be rejected")
+	}
+}
@@ -0,0 +1,251 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// rbacRule is a set of verbs a component needs on a resource. Subresources are
+// written as "resource/subresource".
+type rbacRule struct {
+	group     string
+	resources []string
+	verbs     []string
+}
+
+var readVerbs = []string{"get", "list", "watch"}
+
+// k8sClusterRules are the permissions the k8s_cluster receiver needs.
+var k8sClusterRules = []rbacRule{
+	{"", []string{"events", "namespaces", "namespaces/status", "nodes", "nodes/spec", "pods", "pods/status",
+		"replicationcontrollers", "replicationcontrollers/status", "resourcequotas", "services"}, readVerbs},
+	{"apps", []string{"daemonsets", "deployments", "replicasets", "statefulsets"}, readVerbs},
+	{"batch", []string{"jobs", "cronjobs"}, readVerbs},
+	{"autoscaling", []string{"horizontalpodautoscalers"}, readVerbs},
+}
+
+// AnalyzeRBAC verifies with SubjectAccessReviews that the collector's
+// ServiceAccount holds the permissions needed by components that call the
+// Kubernetes API. Missing permissions only show up as "forbidden" log lines at
+// runtime, so the finding includes a ClusterRole and binding that grant them.
+func AnalyzeRBAC(ctx context.Context, input *AnalysisInput) []types.DiagnosticFinding {
+	if input.Config == nil || input.Clientset == nil || input.PodInfo == nil {
+		return nil
+	}
+
+	namespace := input.PodInfo.Namespace
+	if namespace == "" {
+		namespace = input.Namespace
+	}
+	serviceAccount := input.PodInfo.Spec.ServiceAccountName
+	if serviceAccount == "" {
+		serviceAccount = "default"
+	}
+
+	var findings []types.DiagnosticFinding
+	for _, comp := range rbacComponents(input) {
+		var missing []rbacRule
+		for _, rule := range comp.rules {
+			for _, resource := range rule.resources {
+				var denied []string
+				for _, verb := range rule.verbs {
+					allowed, err := serviceAccountCan(ctx, input, namespace, serviceAccount, verb, rule.group, resource)
+					if err != nil {
+						slog.Warn("could not run SubjectAccessReview", "error", err)
+						return findings
+					}
+					if !allowed {
+						denied = append(denied, verb)
+					}
+				}
+				if len(denied) > 0 {
+					missing = append(missing, rbacRule{group: rule.group, resources: []string{resource}, verbs: denied})
+				}
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		var parts []string
+		for _, m := range missing {
+			parts = append(parts, fmt.Sprintf("%s %s", strings.Join(m.verbs, "/"), qualifiedResource(m.group, m.resources[0])))
+		}
+		severity := types.SeverityCritical
+		effect := "receives no data"
+		if comp.kind == "processor" {
+			severity = types.SeverityWarning
+			effect = "passes data through without Kubernetes metadata"
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			RuleID:      types.RuleRBACMissingPermissions,
+			Severity:    severity,
+			Category:    types.CategoryConfig,
+			Summary:     fmt.Sprintf("ServiceAccount %s/%s lacks permissions needed by %s %q", namespace, serviceAccount, comp.kind, comp.id),
+			Detail:      fmt.Sprintf("Missing: %s. The API server rejects these calls and the %s %s; the collector only logs \"forbidden\" errors.", strings.Join(parts, ", "), comp.kind, effect),
+			Suggestion:  "Grant the missing permissions with a ClusterRole bound to the collector's ServiceAccount",
+			Remediation: clusterRoleManifest(serviceAccount+"-"+strings.NewReplacer("/", "-", "_", "-").Replace(strings.ToLower(comp.id)), namespace, serviceAccount, missing),
+			Evidence:    &types.Evidence{ComponentID: comp.id, SampleValues: parts, Count: len(parts)},
+		})
+	}
+	return findings
+}
+
+// rbacComponent is a configured component and the API permissions it needs.
+type rbacComponent struct {
+	kind  string // receiver or processor
+	id    string
+	rules []rbacRule
+}
+
+// rbacComponents returns the components in use that call the Kubernetes API.
+func rbacComponents(input *AnalysisInput) []rbacComponent {
+	used := make(map[string]bool)
+	for _, pipeline := range input.Config.Service.Pipelines {
+		for _, id := range pipeline.Receivers {
+			used["receiver:"+id] = true
+		}
+		for _, id := range pipeline.Processors {
+			used["processor:"+id] = true
+		}
+	}
+
+	var comps []rbacComponent
+	for _, id := range sortedKeys(input.Config.Receivers) {
+		if !used["receiver:"+id] {
+			continue
+		}
+		cfg, _ := input.Config.Receivers[id].(map[string]interface{})
+		switch componentType(id) {
+		case "k8s_cluster":
+			comps = append(comps, rbacComponent{"receiver", id, k8sClusterRules})
+		case "k8s_events":
+			comps = append(comps, rbacComponent{"receiver", id, []rbacRule{{"", []string{"events"}, readVerbs}}})
+		case "k8sobjects":
+			comps = append(comps, rbacComponent{"receiver", id, k8sObjectsRules(cfg)})
+		}
+	}
+	for _, id := range sortedKeys(input.Config.Processors) {
+		if !used["processor:"+id] {
+			continue
+		}
+		cfg, _ := input.Config.Processors[id].(map[string]interface{})
+		switch componentType(id) {
+		case "k8sattributes":
+			if passthrough, _ := getNestedBool(cfg, "passthrough"); passthrough {
+				continue
+			}
+			comps = append(comps, rbacComponent{"processor", id, []rbacRule{
+				{"", []string{"pods", "namespaces"}, readVerbs},
+				{"apps", []string{"replicasets"}, readVerbs},
+			}})
+		case "resourcedetection":
+			detectors, _ := cfg["detectors"].([]interface{})
+			for _, d := range detectors {
+				if d == "k8snode" {
+					comps = append(comps, rbacComponent{"processor", id, []rbacRule{{"", []string{"nodes"}, []string{"get"}}}})
+					break
+				}
+			}
+		}
+	}
+	return comps
+}
+
+// k8sObjectsRules derives permissions from the k8sobjects receiver's object
+// list: pull mode lists objects, watch mode lists and watches them.
+func k8sObjectsRules(cfg map[string]interface{}) []rbacRule {
+	objects, _ := cfg["objects"].([]interface{})
+	var rules []rbacRule
+	for _, o := range objects {
+		obj, ok := o.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := getNestedString(obj, "name")
+		if name == "" {
+			continue
+		}
+		group, _ := getNestedString(obj, "group")
+		verbs := []string{"list"}
+		if mode, _ := getNestedString(obj, "mode"); mode == "watch" {
+			verbs = []string{"list", "watch"}
+		}
+		rules = append(rules, rbacRule{group, []string{name}, verbs})
+	}
+	return rules
+}
+
+// serviceAccountCan asks the API server whether a ServiceAccount may perform a
+// verb on a resource across all namespaces.
+func serviceAccountCan(ctx context.Context, input *AnalysisInput, namespace, serviceAccount, verb, group, resource string) (bool, error) {
+	res, sub, _ := strings.Cut(resource, "/")
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   fmt.Sprintf("system:serviceaccount:%s:%s", namespace, serviceAccount),
+			Groups: []string{"system:serviceaccounts", "system:serviceaccounts:" + namespace, "system:authenticated"},
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:        verb,
+				Group:       group,
+				Resource:    res,
+				Subresource: sub,
+			},
+		},
+	}
+	result, err := input.Clientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return result.Status.Allowed, nil
+}
+
+// qualifiedResource renders a resource with its API group, e.g. "replicasets.apps".
+func qualifiedResource(group, resource string) string {
+	if group == "" {
+		return resource
+	}
+	return resource + "." + group
+}
+
+// clusterRoleManifest renders a ClusterRole granting the rules and a binding to
+// the ServiceAccount. Rules with the same group and verbs are merged.
+func clusterRoleManifest(name, namespace, serviceAccount string, rules []rbacRule) string {
+	type key struct{ group, verbs string }
+	merged := make(map[key][]string)
+	var order []key
+	for _, r := range rules {
+		k := key{r.group, strings.Join(r.verbs, ", ")}
+		if _, ok := merged[k]; !ok {
+			order = append(order, k)
+		}
+		merged[k] = append(merged[k], r.resources...)
+	}
+	sort.SliceStable(order, func(i, j int) bool { return order[i].group < order[j].group })
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "apiVersion: rbac.authorization.k8s.io/v1\nkind: ClusterRole\nmetadata:\n  name: %s\nrules:\n", name)
+	for _, k := range order {
+		fmt.Fprintf(&b, "  - apiGroups: [%q]\n    resources: [%s]\n    verbs: [%s]\n", k.group, strings.Join(merged[k], ", "), k.verbs)
+	}
+	fmt.Fprintf(&b, `---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: %s
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: %s
+subjects:
+  - kind: ServiceAccount
+    name: %s
+    namespace: %s`, name, name, serviceAccount, namespace)
+	return b.String()
+}
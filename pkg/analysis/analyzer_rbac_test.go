@@ -0,0 +1,115 @@
+package analysis
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// sarClientset returns a fake clientset whose SubjectAccessReviews allow only
+// the given "verb resource" pairs.
+func sarClientset(allowed ...string) *fake.Clientset {
+	allow := make(map[string]bool)
+	for _, a := range allowed {
+		allow[a] = true
+	}
+	clientset := fake.NewClientset()
+	clientset.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attrs := review.Spec.ResourceAttributes
+		resource := attrs.Resource
+		if attrs.Subresource != "" {
+			resource += "/" + attrs.Subresource
+		}
+		review.Status.Allowed = allow[attrs.Verb+" "+resource]
+		return true, review, nil
+	})
+	return clientset
+}
+
+func TestAnalyzeRBAC(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent-x", Namespace: "obs"},
+		Spec:       corev1.PodSpec{ServiceAccountName: "otel-agent"},
+	}
+	config := &collector.CollectorConfig{
+		Receivers: map[string]interface{}{
+			"k8sobjects": map[string]interface{}{
+				"objects": []interface{}{
+					map[string]interface{}{"name": "events", "mode": "watch", "group": "events.k8s.io"},
+				},
+			},
+		},
+		Processors: map[string]interface{}{
+			"k8sattributes":     map[string]interface{}{},
+			"resourcedetection": map[string]interface{}{"detectors": []interface{}{"env", "k8snode"}},
+		},
+		Service: collector.ServiceConfig{
+			Pipelines: map[string]collector.PipelineConfig{
+				"logs": {
+					Receivers:  []string{"k8sobjects"},
+					Processors: []string{"k8sattributes", "resourcedetection"},
+					Exporters:  []string{"otlp"},
+				},
+			},
+		},
+	}
+
+	t.Run("skipped without clientset", func(t *testing.T) {
+		if findings := AnalyzeRBAC(context.Background(), &AnalysisInput{Config: config, PodInfo: pod}); len(findings) != 0 {
+			t.Fatalf("expected no findings, got %d", len(findings))
+		}
+	})
+
+	t.Run("all permissions granted", func(t *testing.T) {
+		clientset := sarClientset(
+			"list events", "watch events",
+			"get pods", "list pods", "watch pods",
+			"get namespaces", "list namespaces", "watch namespaces",
+			"get replicasets", "list replicasets", "watch replicasets",
+			"get nodes",
+		)
+		findings := AnalyzeRBAC(context.Background(), &AnalysisInput{Config: config, PodInfo: pod, Clientset: clientset})
+		if len(findings) != 0 {
+			t.Fatalf("expected no findings, got %d: %+v", len(findings), findings)
+		}
+	})
+
+	t.Run("missing permissions", func(t *testing.T) {
+		clientset := sarClientset("list events", "get pods", "list pods", "watch pods", "get namespaces", "list namespaces", "watch namespaces")
+		findings := AnalyzeRBAC(context.Background(), &AnalysisInput{Config: config, PodInfo: pod, Clientset: clientset})
+		expect := []string{types.SeverityCritical, types.SeverityWarning, types.SeverityWarning}
+		if len(findings) != len(expect) {
+			t.Fatalf("expected %d findings, got %d: %+v", len(expect), len(findings), findings)
+		}
+		for i, f := range findings {
+			if f.Severity != expect[i] {
+				t.Errorf("finding %d: expected severity %s, got %s (%s)", i, expect[i], f.Severity, f.Summary)
+			}
+		}
+
+		if !strings.Contains(findings[0].Detail, "watch events.events.k8s.io") {
+			t.Errorf("expected missing watch on events in detail, got %q", findings[0].Detail)
+		}
+		for _, want := range []string{
+			"kind: ClusterRole\n",
+			`- apiGroups: ["apps"]`,
+			"resources: [replicasets]",
+			"kind: ClusterRoleBinding",
+			"name: otel-agent\n    namespace: obs",
+		} {
+			if !strings.Contains(findings[1].Remediation, want) {
+				t.Errorf("expected %q in remediation, got:\n%s", want, findings[1].Remediation)
+			}
+		}
+	})
+}
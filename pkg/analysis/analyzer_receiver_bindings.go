@@ -3,18 +3,44 @@ package analysis
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
 	"github.com/hrexed/otel-collector-mcp/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
-// AnalyzeReceiverBindings checks for receiver endpoint port conflicts and missing protocols.
-func AnalyzeReceiverBindings(_ context.Context, input *AnalysisInput) []types.DiagnosticFinding {
+// defaultProtocolPorts are the listen ports of receiver protocols whose endpoint
+// is not set. Since v0.104 the collector binds these to localhost by default.
+var defaultProtocolPorts = map[string]map[string]int32{
+	"otlp": {"grpc": 4317, "http": 4318},
+}
+
+// receiverPort is a port a receiver listens on.
+type receiverPort struct {
+	name     string // receiver ID, with the protocol for multi-protocol receivers
+	id       string
+	path     string // endpoint setting within the receiver config
+	host     string
+	port     int32
+	implicit bool // endpoint not set, collector default applies
+}
+
+// AnalyzeReceiverBindings checks for receiver endpoint port conflicts and missing
+// protocols, and cross-checks receiver ports against the pod's container ports,
+// the Services selecting it, and the health and metrics ports.
+func AnalyzeReceiverBindings(ctx context.Context, input *AnalysisInput) []types.DiagnosticFinding {
 	if input.Config == nil {
 		return nil
 	}
 
 	var findings []types.DiagnosticFinding
 	portUsage := make(map[string][]string) // port -> list of receivers using it
+	portPaths := make(map[string][]string) // "receiver id|endpoint setting" per use
 
 	for name, receiverCfg := range input.Config.Receivers {
 		cfgMap, ok := receiverCfg.(map[string]interface{})
@@ -25,6 +51,7 @@ func AnalyzeReceiverBindings(_ context.Context, input *AnalysisInput) []types.Di
 		// Check top-level endpoint
 		if endpoint, ok := getNestedString(cfgMap, "endpoint"); ok {
 			portUsage[endpoint] = append(portUsage[endpoint], name)
+			portPaths[endpoint] = append(portPaths[endpoint], name+"|endpoint")
 		}
 
 		// Check protocol-level endpoints (e.g., otlp receiver with grpc/http)
@@ -36,23 +63,307 @@ func AnalyzeReceiverBindings(_ context.Context, input *AnalysisInput) []types.Di
 				}
 				if endpoint, ok := getNestedString(protoCfgMap, "endpoint"); ok {
 					portUsage[endpoint] = append(portUsage[endpoint], fmt.Sprintf("%s/%s", name, proto))
+					portPaths[endpoint] = append(portPaths[endpoint], fmt.Sprintf("%s|protocols.%s.endpoint", name, proto))
 				}
 			}
 		}
 	}
 
 	// Check for port conflicts
+	var taken []int
 	for endpoint, receivers := range portUsage {
 		if len(receivers) > 1 {
+			if taken == nil {
+				taken = configPorts(input, portUsage)
+			}
+			sort.Strings(receivers)
+			uses := portPaths[endpoint]
+			sort.Strings(uses)
+			ids := make([]string, len(uses))
+			paths := make([]string, len(uses))
+			for i, use := range uses {
+				ids[i], paths[i], _ = strings.Cut(use, "|")
+			}
 			findings = append(findings, types.DiagnosticFinding{
+				RuleID:     types.RulePortConflict,
 				Severity:   types.SeverityCritical,
 				Category:   types.CategoryConfig,
 				Summary:    fmt.Sprintf("Port conflict: endpoint %q is used by multiple receivers", endpoint),
 				Detail:     fmt.Sprintf("Receivers %v are all configured to listen on %s. Only one receiver can bind to a given endpoint.", receivers, endpoint),
 				Suggestion: "Assign unique endpoints to each receiver",
+				Evidence:   &types.Evidence{Components: ids, Paths: paths, SampleValues: []string{endpoint}, Count: len(receivers), Ports: taken},
 			})
 		}
 	}
 
+	findings = append(findings, checkReceiverPortExposure(ctx, input)...)
 	return findings
 }
+
+// configPorts returns every port the config listens on, sorted: the explicit
+// receiver endpoints, the default ports of receivers used in pipelines, and
+// the extension and internal metrics ports. The port conflict fix moves
+// receivers to a port outside this list.
+func configPorts(input *AnalysisInput, endpoints map[string][]string) []int {
+	seen := make(map[int]bool)
+	for endpoint := range endpoints {
+		if _, _, p := parseEndpoint(endpoint); p != "" {
+			if n, err := strconv.Atoi(p); err == nil {
+				seen[n] = true
+			}
+		}
+	}
+	for _, rp := range receiverPorts(input.Config) {
+		seen[int(rp.port)] = true
+	}
+	for port := range reservedPorts(input) {
+		seen[int(port)] = true
+	}
+	ports := make([]int, 0, len(seen))
+	for port := range seen {
+		ports = append(ports, port)
+	}
+	sort.Ints(ports)
+	return ports
+}
+
+// receiverPorts returns the ports of receivers used in pipelines, including the
+// default ports of protocols configured without an endpoint.
+func receiverPorts(cfg *collector.CollectorConfig) []receiverPort {
+	used := usedReceivers(cfg)
+	var ports []receiverPort
+	for _, id := range sortedKeys(cfg.Receivers) {
+		if !used[id] {
+			continue
+		}
+		cfgMap, _ := cfg.Receivers[id].(map[string]interface{})
+		for _, ep := range receiverEndpoints(cfgMap) {
+			_, host, portStr := parseEndpoint(ep.endpoint)
+			port, err := strconv.Atoi(portStr)
+			if err != nil {
+				continue
+			}
+			name := id
+			if len(ep.path) > 0 {
+				name = id + "/" + ep.path[len(ep.path)-1]
+			}
+			path := strings.Join(append(append([]string{}, ep.path...), "endpoint"), ".")
+			ports = append(ports, receiverPort{name: name, id: id, path: path, host: host, port: int32(port)})
+		}
+		protocols, _ := getNestedMap(cfgMap, "protocols")
+		for _, proto := range sortedKeys(protocols) {
+			defaultPort, ok := defaultProtocolPorts[componentType(id)][proto]
+			if !ok {
+				continue
+			}
+			protoCfg, _ := protocols[proto].(map[string]interface{})
+			if _, hasEndpoint := protoCfg["endpoint"]; !hasEndpoint {
+				ports = append(ports, receiverPort{name: id + "/" + proto, id: id, path: "protocols." + proto + ".endpoint", host: "localhost", port: defaultPort, implicit: true})
+			}
+		}
+	}
+	return ports
+}
+
+// reservedPorts returns the ports used by enabled extensions and the internal
+// metrics endpoint, keyed by port with a description of the user.
+func reservedPorts(input *AnalysisInput) map[int32]string {
+	defaults := map[string]int32{"health_check": defaultHealthCheckPort, "pprof": 1777, "zpages": 55679}
+	reserved := make(map[int32]string)
+	for _, id := range input.Config.Service.Extensions {
+		extCfg, _ := input.Config.Extensions[id].(map[string]interface{})
+		port := defaults[componentType(id)]
+		if endpoint, ok := getNestedString(extCfg, "endpoint"); ok {
+			if _, _, p := parseEndpoint(endpoint); p != "" {
+				if n, err := strconv.Atoi(p); err == nil {
+					port = int32(n)
+				}
+			}
+		}
+		if port != 0 {
+			reserved[port] = "extension " + id
+		}
+	}
+	if input.Config.Service.Telemetry.Metrics.Level != "none" {
+		_, port := telemetryMetricsEndpoint(input)
+		reserved[int32(port)] = "internal metrics"
+	}
+	return reserved
+}
+
+func checkReceiverPortExposure(ctx context.Context, input *AnalysisInput) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	ports := receiverPorts(input.Config)
+	reserved := reservedPorts(input)
+
+	var exposed []receiverPort
+	for _, rp := range ports {
+		if user, ok := reserved[rp.port]; ok {
+			findings = append(findings, types.DiagnosticFinding{
+				RuleID:     types.RulePortCollision,
+				Severity:   types.SeverityCritical,
+				Category:   types.CategoryConfig,
+				Summary:    fmt.Sprintf("Receiver %q port %d collides with %s", rp.name, rp.port, user),
+				Detail:     "Two listeners in the same collector cannot bind the same port. The collector fails to start with \"address already in use\".",
+				Suggestion: "Move the receiver or the conflicting listener to a free port",
+				Evidence:   rp.evidence(),
+			})
+			continue
+		}
+		if rp.host == "localhost" || rp.host == "127.0.0.1" || rp.host == "::1" {
+			if rp.implicit {
+				findings = append(findings, types.DiagnosticFinding{
+					RuleID:      types.RuleReceiverDefaultLocalhost,
+					Severity:    types.SeverityInfo,
+					Category:    types.CategoryConfig,
+					Summary:     fmt.Sprintf("Receiver %q has no endpoint and defaults to localhost:%d", rp.name, rp.port),
+					Detail:      "Since v0.104 the collector binds receivers without an explicit endpoint to localhost. Other pods cannot reach this receiver unless the endpoint is set.",
+					Suggestion:  "Set the endpoint explicitly to the pod IP or 0.0.0.0",
+					Remediation: fmt.Sprintf("endpoint: ${env:MY_POD_IP}:%d", rp.port),
+					Evidence:    rp.evidence(),
+				})
+			} else {
+				findings = append(findings, types.DiagnosticFinding{
+					RuleID:      types.RuleReceiverLoopback,
+					Severity:    types.SeverityWarning,
+					Category:    types.CategoryConfig,
+					Summary:     fmt.Sprintf("Receiver %q listens on %s:%d, unreachable from other pods", rp.name, rp.host, rp.port),
+					Detail:      "A receiver bound to loopback only accepts connections from inside the pod. Applications and agents in other pods cannot send data to it, even through a Service.",
+					Suggestion:  "Bind the receiver to the pod IP or 0.0.0.0",
+					Remediation: fmt.Sprintf("endpoint: ${env:MY_POD_IP}:%d", rp.port),
+					Evidence:    rp.evidence(),
+				})
+			}
+			continue
+		}
+		exposed = append(exposed, rp)
+	}
+
+	c := collectorContainer(input.PodInfo)
+	if c == nil {
+		return findings
+	}
+
+	containerPorts := containerPortSet(input.PodInfo)
+	hostPorts := make(map[int32]bool)
+	for _, p := range c.Ports {
+		if p.HostPort != 0 {
+			hostPorts[p.ContainerPort] = true
+		}
+	}
+	for _, rp := range exposed {
+		if !containerPorts[rp.port] {
+			findings = append(findings, types.DiagnosticFinding{
+				RuleID:     types.RuleReceiverPortUndeclared,
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryConfig,
+				Summary:    fmt.Sprintf("Receiver %q port %d is not declared in container %q", rp.name, rp.port, c.Name),
+				Detail:     "The receiver listens on a port the container does not declare. Services with named target ports, NetworkPolicies and hostPort mappings rely on declared container ports, so traffic may never reach the receiver.",
+				Suggestion: "Add the port to the collector container",
+				Remediation: fmt.Sprintf(`ports:
+  - name: %s
+    containerPort: %d
+    protocol: TCP`, portName(rp.name), rp.port),
+				Evidence: rp.evidence(),
+			})
+		}
+	}
+
+	if input.Clientset == nil {
+		return findings
+	}
+	services, err := input.Clientset.CoreV1().Services(input.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		slog.Warn("could not list services for receiver port check", "error", err)
+		return findings
+	}
+
+	receiverByPort := make(map[int32]string)
+	for _, rp := range ports {
+		receiverByPort[rp.port] = rp.name
+	}
+	targeted := make(map[int32]bool)
+	for _, svc := range services.Items {
+		if len(svc.Spec.Selector) == 0 || !labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(input.PodInfo.Labels)) {
+			continue
+		}
+		for _, sp := range svc.Spec.Ports {
+			target := sp.Port
+			if sp.TargetPort.IntVal != 0 || sp.TargetPort.StrVal != "" {
+				target = serviceTargetPort(input.PodInfo, sp.TargetPort)
+			}
+			if target == 0 {
+				// A named targetPort that no container declares cannot be
+				// matched with a receiver.
+				continue
+			}
+			targeted[target] = true
+			if _, ok := receiverByPort[target]; ok {
+				continue
+			}
+			if _, ok := reserved[target]; ok {
+				continue
+			}
+			findings = append(findings, types.DiagnosticFinding{
+				RuleID:     types.RuleServiceTargetNoReceiver,
+				Severity:   types.SeverityWarning,
+				Category:   types.CategoryConfig,
+				Summary:    fmt.Sprintf("Service %q port %q targets port %s where no receiver listens", svc.Name, sp.Name, sp.TargetPort.String()),
+				Detail:     "Clients sending to this Service port get connection refused, because no receiver, extension or metrics endpoint in the collector listens on the target port.",
+				Suggestion: "Point the Service port at a receiver port, or configure a receiver on the target port",
+				Evidence:   &types.Evidence{Name: svc.Name, Path: "spec.ports." + sp.Name, SampleValues: []string{sp.TargetPort.String()}, Count: int(target)},
+			})
+		}
+	}
+	for _, rp := range exposed {
+		if !targeted[rp.port] && !hostPorts[rp.port] {
+			findings = append(findings, types.DiagnosticFinding{
+				RuleID:     types.RuleReceiverPortUnexposed,
+				Severity:   types.SeverityInfo,
+				Category:   types.CategoryConfig,
+				Summary:    fmt.Sprintf("Receiver %q port %d is not exposed by any Service or hostPort", rp.name, rp.port),
+				Detail:     "No Service selecting the collector pods targets this port, so other workloads can only reach it by pod IP.",
+				Suggestion: "Add the port to the collector Service",
+				Remediation: fmt.Sprintf(`spec:
+  ports:
+    - name: %s
+      port: %d
+      targetPort: %d
+      protocol: TCP`, portName(rp.name), rp.port, rp.port),
+				Evidence: rp.evidence(),
+			})
+		}
+	}
+
+	return findings
+}
+
+// evidence locates a finding on the receiver endpoint.
+func (rp receiverPort) evidence() *types.Evidence {
+	return &types.Evidence{ComponentID: rp.id, Path: rp.path, Count: int(rp.port)}
+}
+
+// portName derives a valid Kubernetes port name (max 15 chars) from a receiver name.
+func portName(receiver string) string {
+	name := make([]byte, 0, len(receiver))
+	for i := 0; i < len(receiver); i++ {
+		ch := receiver[i]
+		switch {
+		case ch >= 'a' && ch <= 'z', ch >= '0' && ch <= '9':
+			name = append(name, ch)
+		case ch >= 'A' && ch <= 'Z':
+			name = append(name, ch+'a'-'A')
+		default:
+			if len(name) > 0 && name[len(name)-1] != '-' {
+				name = append(name, '-')
+			}
+		}
+	}
+	if len(name) > 15 {
+		name = name[:15]
+	}
+	for len(name) > 0 && name[len(name)-1] == '-' {
+		name = name[:len(name)-1]
+	}
+	return string(name)
+}
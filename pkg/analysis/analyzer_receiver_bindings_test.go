@@ -0,0 +1,179 @@
+package analysis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func otlpReceiverConfig(grpcEndpoint interface{}) *collector.CollectorConfig {
+	grpc := map[string]interface{}{}
+	if grpcEndpoint != nil {
+		grpc["endpoint"] = grpcEndpoint
+	}
+	return &collector.CollectorConfig{
+		Receivers: map[string]interface{}{
+			"otlp": map[string]interface{}{
+				"protocols": map[string]interface{}{"grpc": grpc},
+			},
+		},
+		Service: collector.ServiceConfig{
+			Pipelines: map[string]collector.PipelineConfig{
+				"traces": {Receivers: []string{"otlp"}, Exporters: []string{"debug"}},
+			},
+		},
+	}
+}
+
+func collectorPodWithPorts(ports ...corev1.ContainerPort) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw-0", Namespace: "obs", Labels: map[string]string{"app": "gw"}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "otc-container", Ports: ports}},
+		},
+	}
+}
+
+func service(name, app string, ports ...corev1.ServicePort) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "obs"},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": app}, Ports: ports},
+	}
+}
+
+func TestAnalyzeReceiverBindings_PortExposure(t *testing.T) {
+	grpcPort := corev1.ContainerPort{Name: "otlp-grpc", ContainerPort: 4317}
+
+	tests := []struct {
+		name           string
+		config         *collector.CollectorConfig
+		pod            *corev1.Pod
+		services       []*corev1.Service
+		expectSeverity []string
+	}{
+		{
+			name:   "receiver declared and exposed by named target port",
+			config: otlpReceiverConfig("0.0.0.0:4317"),
+			pod:    collectorPodWithPorts(grpcPort),
+			services: []*corev1.Service{
+				service("gw", "gw", corev1.ServicePort{Name: "otlp-grpc", Port: 4317, TargetPort: intstr.FromString("otlp-grpc")}),
+			},
+		},
+		{
+			name:           "explicit localhost binding",
+			config:         otlpReceiverConfig("localhost:4317"),
+			expectSeverity: []string{types.SeverityWarning},
+		},
+		{
+			name:           "no endpoint defaults to localhost",
+			config:         otlpReceiverConfig(nil),
+			expectSeverity: []string{types.SeverityInfo},
+		},
+		{
+			name: "receiver collides with health_check port",
+			config: func() *collector.CollectorConfig {
+				cfg := otlpReceiverConfig("0.0.0.0:13133")
+				cfg.Extensions = map[string]interface{}{"health_check": map[string]interface{}{}}
+				cfg.Service.Extensions = []string{"health_check"}
+				return cfg
+			}(),
+			expectSeverity: []string{types.SeverityCritical},
+		},
+		{
+			name:           "receiver port not declared on container",
+			config:         otlpReceiverConfig("0.0.0.0:4317"),
+			pod:            collectorPodWithPorts(),
+			expectSeverity: []string{types.SeverityWarning},
+		},
+		{
+			name:   "service targets a port nobody listens on and receiver is not exposed",
+			config: otlpReceiverConfig("0.0.0.0:4317"),
+			pod:    collectorPodWithPorts(grpcPort),
+			services: []*corev1.Service{
+				service("gw", "gw", corev1.ServicePort{Name: "otlp-http", Port: 4318}),
+				service("other", "other", corev1.ServicePort{Name: "x", Port: 9999}),
+			},
+			expectSeverity: []string{types.SeverityWarning, types.SeverityInfo},
+		},
+		{
+			name:   "unresolved named target port is skipped",
+			config: otlpReceiverConfig("0.0.0.0:4317"),
+			pod:    collectorPodWithPorts(grpcPort),
+			services: []*corev1.Service{
+				service("gw", "gw", corev1.ServicePort{Name: "otlp", Port: 4317, TargetPort: intstr.FromString("otlp")}),
+			},
+			expectSeverity: []string{types.SeverityInfo},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &AnalysisInput{Config: tt.config, PodInfo: tt.pod, Namespace: "obs"}
+			if tt.services != nil {
+				clientset := fake.NewClientset()
+				for _, svc := range tt.services {
+					if _, err := clientset.CoreV1().Services("obs").Create(context.Background(), svc, metav1.CreateOptions{}); err != nil {
+						t.Fatal(err)
+					}
+				}
+				input.Clientset = clientset
+			}
+
+			findings := AnalyzeReceiverBindings(context.Background(), input)
+			if len(findings) != len(tt.expectSeverity) {
+				t.Fatalf("expected %d findings, got %d: %+v", len(tt.expectSeverity), len(findings), findings)
+			}
+			for i, f := range findings {
+				if f.Severity != tt.expectSeverity[i] {
+					t.Errorf("finding %d: expected severity %s, got %s (%s)", i, tt.expectSeverity[i], f.Severity, f.Summary)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeReceiverBindings_PortConflict(t *testing.T) {
+	cfg := otlpReceiverConfig("0.0.0.0:4317")
+	cfg.Receivers["jaeger"] = map[string]interface{}{
+		"protocols": map[string]interface{}{
+			"grpc": map[string]interface{}{"endpoint": "0.0.0.0:4317"},
+		},
+	}
+
+	findings := AnalyzeReceiverBindings(context.Background(), &AnalysisInput{Config: cfg})
+	var conflict *types.DiagnosticFinding
+	for i := range findings {
+		if findings[i].RuleID == types.RulePortConflict {
+			conflict = &findings[i]
+		}
+	}
+	if conflict == nil {
+		t.Fatalf("expected a port conflict finding, got %+v", findings)
+	}
+	ev := conflict.Evidence
+	if ev == nil || len(ev.Components) != 2 || len(ev.Paths) != 2 {
+		t.Fatalf("expected two components with paths, got %+v", ev)
+	}
+	if ev.Components[0] != "jaeger" || ev.Paths[1] != "protocols.grpc.endpoint" {
+		t.Errorf("unexpected evidence %+v", ev)
+	}
+}
+
+func TestPortName(t *testing.T) {
+	tests := map[string]string{
+		"otlp/grpc":                   "otlp-grpc",
+		"jaeger/thrift_http":          "jaeger-thrift-h",
+		"prometheus/self-scrape-long": "prometheus-self",
+	}
+	for in, want := range tests {
+		if got := portName(in); got != want {
+			t.Errorf("portName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
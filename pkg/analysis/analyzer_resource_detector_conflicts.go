@@ -27,6 +27,7 @@ func AnalyzeResourceDetectorConflicts(_ context.Context, input *AnalysisInput) [
 
 		if len(resourceDetectors) > 1 {
 			findings = append(findings, types.DiagnosticFinding{
+				RuleID:   types.RuleResourceDetectorConflict,
 				Severity: types.SeverityWarning,
 				Category: types.CategoryConfig,
 				Summary:  fmt.Sprintf("Pipeline %q has multiple resource detection processors: %v", pipelineName, resourceDetectors),
@@ -38,9 +39,37 @@ processors:
     detectors: [env, system, gcp, eks, azure]
     timeout: 5s
     override: false  # Set to false to preserve existing attributes`,
+				Evidence: &types.Evidence{
+					SignalType:   pipelineSignal(pipelineName),
+					Pipeline:     pipelineName,
+					ComponentID:  resourceDetectors[len(resourceDetectors)-1],
+					Components:   resourceDetectors,
+					Count:        len(resourceDetectors),
+					SampleValues: mergedDetectors(input.Config.Processors, resourceDetectors),
+				},
 			})
 		}
 	}
 
 	return findings
 }
+
+// mergedDetectors returns the detectors of the given resourcedetection
+// processors in pipeline order, without duplicates.
+func mergedDetectors(processors map[string]interface{}, ids []string) []string {
+	seen := make(map[string]bool)
+	var detectors []string
+	for _, id := range ids {
+		cfg, _ := processors[id].(map[string]interface{})
+		list, _ := cfg["detectors"].([]interface{})
+		for _, d := range list {
+			name, ok := d.(string)
+			if !ok || seen[name] {
+				continue
+			}
+			seen[name] = true
+			detectors = append(detectors, name)
+		}
+	}
+	return detectors
+}
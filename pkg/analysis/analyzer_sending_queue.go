@@ -0,0 +1,324 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// defaultFileStorageDir is where file_storage writes when directory is unset.
+	defaultFileStorageDir = "/var/lib/otelcol/file_storage"
+	// defaultQueueSize and defaultNumConsumers are the exporterhelper defaults.
+	defaultQueueSize    = 1000
+	defaultNumConsumers = 10
+	// defaultBatchItems is the batch processor's default send_batch_size.
+	defaultBatchItems = 8192
+	// unbatchedRequestItems is the assumed size of a request that no batch
+	// processor has resized: the OpenTelemetry SDKs' default export batch size.
+	unbatchedRequestItems = 512
+	// assumedItemBytes is a rough in-memory size of one span, data point or log
+	// record with a handful of attributes.
+	assumedItemBytes = 256
+)
+
+// AnalyzeSendingQueue checks exporter sending queues beyond their presence:
+// persistent queue storage backed by a suitable volume, the memory needed by
+// explicitly sized in-memory queues, and consumer concurrency against the
+// exporter endpoint. Existence and enablement of the storage extension are
+// reported by AnalyzeExtensions.
+func AnalyzeSendingQueue(_ context.Context, input *AnalysisInput) []types.DiagnosticFinding {
+	if input.Config == nil {
+		return nil
+	}
+
+	var findings []types.DiagnosticFinding
+	var inMemoryBytes int64
+	var inMemoryQueues []string
+
+	for _, id := range sortedKeys(input.Config.Exporters) {
+		if isLocalExporter(id) {
+			continue
+		}
+		cfg, _ := input.Config.Exporters[id].(map[string]interface{})
+		queue, ok := getNestedMap(cfg, "sending_queue")
+		if !ok {
+			continue
+		}
+		if enabled, set := getNestedBool(queue, "enabled"); set && !enabled {
+			continue
+		}
+
+		queueSize := int64(defaultQueueSize)
+		size, sizeSet := getNestedNumber(queue, "queue_size")
+		if sizeSet && size > 0 {
+			queueSize = int64(size)
+		}
+		numConsumers := int64(defaultNumConsumers)
+		if v, ok := getNestedNumber(queue, "num_consumers"); ok && v > 0 {
+			numConsumers = int64(v)
+		}
+
+		if storage, ok := getNestedString(queue, "storage"); ok && storage != "" {
+			findings = append(findings, checkQueueStorage(input, id, storage)...)
+		} else if sizeSet {
+			inMemoryBytes += queueBytes(input.Config, id, queue, queueSize)
+			inMemoryQueues = append(inMemoryQueues, id)
+		}
+
+		if numConsumers > queueSize {
+			findings = append(findings, types.DiagnosticFinding{
+				RuleID:      types.RuleQueueConsumersExceed,
+				Severity:    types.SeverityInfo,
+				Category:    types.CategoryPerformance,
+				Summary:     fmt.Sprintf("Exporter %q has more consumers (%d) than queue slots (%d)", id, numConsumers, queueSize),
+				Detail:      "Each consumer takes one batch from the queue. Consumers beyond the queue size never have work and only add goroutines.",
+				Suggestion:  "Lower num_consumers or raise queue_size",
+				Remediation: remediationYAML("exporters", id, []string{"sending_queue"}, fmt.Sprintf("num_consumers: %d", min(numConsumers, int64(defaultNumConsumers))), fmt.Sprintf("queue_size: %d", max(queueSize, int64(defaultQueueSize)))),
+				Evidence:    &types.Evidence{ComponentID: id, Path: "sending_queue.num_consumers", Count: int(numConsumers)},
+			})
+		}
+		if endpoint, ok := getNestedString(cfg, "endpoint"); ok && numConsumers < 4 {
+			if _, host, _ := parseEndpoint(endpoint); !isClusterLocalHost(host) {
+				findings = append(findings, types.DiagnosticFinding{
+					RuleID:      types.RuleQueueFewConsumers,
+					Severity:    types.SeverityWarning,
+					Category:    types.CategoryPerformance,
+					Summary:     fmt.Sprintf("Exporter %q sends to remote host %q with only %d consumer(s)", id, host, numConsumers),
+					Detail:      "Each consumer sends one request at a time, so throughput is capped at num_consumers batches per round trip. With the latency of a backend outside the cluster, the queue fills under load and data is dropped.",
+					Suggestion:  "Raise num_consumers to at least 10 for remote backends",
+					Remediation: remediationYAML("exporters", id, []string{"sending_queue"}, "enabled: true", fmt.Sprintf("num_consumers: %d", defaultNumConsumers), fmt.Sprintf("queue_size: %d", queueSize)),
+					Evidence:    &types.Evidence{ComponentID: id, Path: "sending_queue.num_consumers", SampleValues: []string{host}, Count: int(numConsumers)},
+				})
+			}
+		}
+	}
+
+	if budget := memoryBudgetBytes(input); budget > 0 && inMemoryBytes*2 > budget {
+		findings = append(findings, types.DiagnosticFinding{
+			RuleID:     types.RuleQueueMemoryBudget,
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryPerformance,
+			Summary:    fmt.Sprintf("In-memory sending queues can hold ~%d MiB, over half of the %d MiB memory budget", inMemoryBytes>>20, budget>>20),
+			Detail:     fmt.Sprintf("Full queues of %s are estimated at %d MiB (queue_size × items per request × ~256 bytes per item). When the backend is down the queues fill, memory_limiter starts refusing data and the collector risks an OOMKill.", strings.Join(inMemoryQueues, ", "), inMemoryBytes>>20),
+			Suggestion: "Reduce queue_size, or move large queues to a persistent queue backed by file_storage",
+			Remediation: `extensions:
+  file_storage:
+    directory: /var/lib/otelcol/file_storage
+
+exporters:
+  <exporter>:
+    sending_queue:
+      enabled: true
+      storage: file_storage`,
+			Evidence: &types.Evidence{Path: "sending_queue.queue_size", Components: inMemoryQueues, Count: int(inMemoryBytes >> 20)},
+		})
+	}
+
+	return findings
+}
+
+// queueBytes estimates the memory held by a full in-memory queue. With the
+// default requests sizer, a request holds the exporter's own batch size, the
+// send_batch_size of a batch processor in a pipeline feeding the exporter, or
+// the SDK default export batch size when neither batches.
+func queueBytes(cfg *collector.CollectorConfig, exporter string, queue map[string]interface{}, queueSize int64) int64 {
+	switch sizer, _ := getNestedString(queue, "sizer"); sizer {
+	case "bytes":
+		return queueSize
+	case "items":
+		return queueSize * assumedItemBytes
+	}
+	return queueSize * requestItems(cfg, exporter, queue) * assumedItemBytes
+}
+
+// requestItems returns the number of items expected in one exporter request.
+func requestItems(cfg *collector.CollectorConfig, exporter string, queue map[string]interface{}) int64 {
+	if batch, ok := getNestedMap(queue, "batch"); ok {
+		for _, key := range []string{"max_size", "min_size"} {
+			if v, ok := getNestedNumber(batch, key); ok && v > 0 {
+				return int64(v)
+			}
+		}
+	}
+	names := make([]string, 0, len(cfg.Service.Pipelines))
+	for name := range cfg.Service.Pipelines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		pipeline := cfg.Service.Pipelines[name]
+		if !containsString(pipeline.Exporters, exporter) {
+			continue
+		}
+		for _, id := range pipeline.Processors {
+			if componentType(id) != "batch" {
+				continue
+			}
+			batch, _ := cfg.Processors[id].(map[string]interface{})
+			if v, ok := getNestedNumber(batch, "send_batch_max_size"); ok && v > 0 {
+				return int64(v)
+			}
+			if v, ok := getNestedNumber(batch, "send_batch_size"); ok && v > 0 {
+				return int64(v)
+			}
+			return defaultBatchItems
+		}
+	}
+	return unbatchedRequestItems
+}
+
+// memoryBudgetBytes returns the memory_limiter limit, or the container memory
+// limit when no limiter sets an absolute limit.
+func memoryBudgetBytes(input *AnalysisInput) int64 {
+	for _, id := range sortedKeys(input.Config.Processors) {
+		if componentType(id) != "memory_limiter" {
+			continue
+		}
+		limiter, _ := input.Config.Processors[id].(map[string]interface{})
+		if v, ok := getNestedNumber(limiter, "limit_mib"); ok && v > 0 {
+			return int64(v) << 20
+		}
+	}
+	if c := collectorContainer(input.PodInfo); c != nil {
+		if mem := c.Resources.Limits.Memory(); mem != nil {
+			return mem.Value()
+		}
+	}
+	return 0
+}
+
+// checkQueueStorage verifies that a persistent queue's file_storage directory is
+// backed by a volume that survives the collector pod for its deployment mode.
+func checkQueueStorage(input *AnalysisInput, exporter, storage string) []types.DiagnosticFinding {
+	raw, exists := input.Config.Extensions[storage]
+	if !exists {
+		return nil
+	}
+	extCfg, _ := raw.(map[string]interface{})
+	if !strings.HasSuffix(componentType(storage), "storage") {
+		return []types.DiagnosticFinding{{
+			RuleID:      types.RuleQueueInvalidStorage,
+			Severity:    types.SeverityCritical,
+			Category:    types.CategoryConfig,
+			Summary:     fmt.Sprintf("Exporter %q uses extension %q as queue storage, which is not a storage extension", exporter, storage),
+			Detail:      "sending_queue.storage must reference a storage extension such as file_storage. The collector fails to start the exporter with any other extension type.",
+			Suggestion:  "Point sending_queue.storage at a file_storage extension",
+			Remediation: remediationYAML("exporters", exporter, []string{"sending_queue"}, "enabled: true", "storage: file_storage"),
+			Evidence:    &types.Evidence{ComponentID: exporter, Path: "sending_queue.storage", SampleValues: []string{storage}},
+		}}
+	}
+	if componentType(storage) != "file_storage" {
+		return nil
+	}
+
+	c := collectorContainer(input.PodInfo)
+	if c == nil {
+		return nil
+	}
+	dir := defaultFileStorageDir
+	if d, ok := getNestedString(extCfg, "directory"); ok && d != "" {
+		dir = d
+	}
+
+	volume := volumeForPath(input.PodInfo, c, dir)
+	mode := effectiveDeployMode(input)
+	storageRemediation := fileStorageVolumeRemediation(mode, dir)
+
+	if volume == nil {
+		return []types.DiagnosticFinding{{
+			RuleID:      types.RuleQueueStorageUnmounted,
+			Severity:    types.SeverityCritical,
+			Category:    types.CategoryConfig,
+			Summary:     fmt.Sprintf("Persistent queue of exporter %q writes to %s, which is not a mounted volume", exporter, dir),
+			Detail:      fmt.Sprintf("%s stores its queue under %s, but container %q mounts no volume there. The directory is on the container's writable layer, so queued data is lost on every restart, and with a read-only root filesystem the extension fails to start.", storage, dir, c.Name),
+			Suggestion:  "Mount a volume at the file_storage directory",
+			Remediation: storageRemediation,
+			Evidence:    &types.Evidence{ComponentID: storage, Path: "directory", SampleValues: []string{dir}, Components: []string{exporter}},
+		}}
+	}
+
+	var problem string
+	switch {
+	case mode == collector.ModeStatefulSet && volume.PersistentVolumeClaim == nil:
+		problem = "a StatefulSet should keep its queue on a PersistentVolumeClaim from volumeClaimTemplates"
+	case mode == collector.ModeDaemonSet && volume.EmptyDir != nil:
+		problem = "an emptyDir is deleted with the DaemonSet pod; use a hostPath so the queue survives pod replacement on the node"
+	case mode == collector.ModeDeployment && volume.EmptyDir != nil:
+		problem = "an emptyDir is deleted when the Deployment pod is rescheduled or rolled out; run the collector as a StatefulSet with a PersistentVolumeClaim"
+	default:
+		return nil
+	}
+	return []types.DiagnosticFinding{{
+		RuleID:      types.RuleQueueStorageEphemeral,
+		Severity:    types.SeverityWarning,
+		Category:    types.CategoryConfig,
+		Summary:     fmt.Sprintf("Persistent queue of exporter %q is backed by volume %q that does not outlive the pod", exporter, volume.Name),
+		Detail:      fmt.Sprintf("%s stores its queue in %s. The queue survives container restarts but not pod replacement: %s.", storage, dir, problem),
+		Suggestion:  "Back the file_storage directory with storage that survives pod replacement",
+		Remediation: storageRemediation,
+		Evidence:    &types.Evidence{ComponentID: storage, Path: "directory", SampleValues: []string{dir, volume.Name}, Components: []string{exporter}},
+	}}
+}
+
+// volumeForPath returns the pod volume mounted at the longest prefix of dir in
+// the container, or nil when dir is not on a mounted volume.
+func volumeForPath(pod *corev1.Pod, c *corev1.Container, dir string) *corev1.Volume {
+	var best *corev1.VolumeMount
+	for i := range c.VolumeMounts {
+		m := &c.VolumeMounts[i]
+		mp := strings.TrimSuffix(m.MountPath, "/")
+		if dir != mp && !strings.HasPrefix(dir, mp+"/") {
+			continue
+		}
+		if best == nil || len(mp) > len(strings.TrimSuffix(best.MountPath, "/")) {
+			best = m
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	for i := range pod.Spec.Volumes {
+		if pod.Spec.Volumes[i].Name == best.Name {
+			return &pod.Spec.Volumes[i]
+		}
+	}
+	return nil
+}
+
+// fileStorageVolumeRemediation renders the volume setup suited to a deployment mode.
+func fileStorageVolumeRemediation(mode collector.DeploymentMode, dir string) string {
+	switch mode {
+	case collector.ModeDaemonSet:
+		return fmt.Sprintf(`volumes:
+  - name: otel-queue
+    hostPath:
+      path: /var/lib/otelcol/queue
+      type: DirectoryOrCreate
+containers:
+  - name: otc-container
+    volumeMounts:
+      - name: otel-queue
+        mountPath: %s`, dir)
+	default:
+		return fmt.Sprintf(`# Run as a StatefulSet and give each replica its own volume
+volumeClaimTemplates:
+  - metadata:
+      name: otel-queue
+    spec:
+      accessModes: [ReadWriteOnce]
+      resources:
+        requests:
+          storage: 5Gi
+containers:
+  - name: otc-container
+    volumeMounts:
+      - name: otel-queue
+        mountPath: %s`, dir)
+	}
+}
@@ -0,0 +1,192 @@
+package analysis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func queuePod(volume *corev1.Volume, mountPath string) *corev1.Pod {
+	c := corev1.Container{Name: "otc-container"}
+	pod := &corev1.Pod{Spec: corev1.PodSpec{}}
+	if volume != nil {
+		pod.Spec.Volumes = []corev1.Volume{*volume}
+		c.VolumeMounts = []corev1.VolumeMount{{Name: volume.Name, MountPath: mountPath}}
+	}
+	pod.Spec.Containers = []corev1.Container{c}
+	return pod
+}
+
+func persistentQueueConfig() *collector.CollectorConfig {
+	return &collector.CollectorConfig{
+		Extensions: map[string]interface{}{
+			"file_storage": map[string]interface{}{"directory": "/var/lib/otelcol/queue"},
+		},
+		Exporters: map[string]interface{}{
+			"otlp": map[string]interface{}{
+				"endpoint":      "gateway:4317",
+				"sending_queue": map[string]interface{}{"enabled": true, "storage": "file_storage"},
+			},
+		},
+		Service: collector.ServiceConfig{Extensions: []string{"file_storage"}},
+	}
+}
+
+func TestAnalyzeSendingQueue(t *testing.T) {
+	emptyDir := &corev1.Volume{Name: "queue", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}
+	pvc := &corev1.Volume{Name: "queue", VolumeSource: corev1.VolumeSource{
+		PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "queue-gw-0"},
+	}}
+
+	tests := []struct {
+		name           string
+		config         *collector.CollectorConfig
+		mode           collector.DeploymentMode
+		pod            *corev1.Pod
+		expectSeverity []string
+	}{
+		{
+			name:   "nil config returns no findings",
+			config: nil,
+		},
+		{
+			name:   "persistent queue on StatefulSet PVC",
+			config: persistentQueueConfig(),
+			mode:   collector.ModeStatefulSet,
+			pod:    queuePod(pvc, "/var/lib/otelcol"),
+		},
+		{
+			name:           "persistent queue directory not mounted",
+			config:         persistentQueueConfig(),
+			mode:           collector.ModeDeployment,
+			pod:            queuePod(nil, ""),
+			expectSeverity: []string{types.SeverityCritical},
+		},
+		{
+			name:           "persistent queue on emptyDir in a Deployment",
+			config:         persistentQueueConfig(),
+			mode:           collector.ModeDeployment,
+			pod:            queuePod(emptyDir, "/var/lib/otelcol/queue"),
+			expectSeverity: []string{types.SeverityWarning},
+		},
+		{
+			name:           "persistent queue on emptyDir in a StatefulSet",
+			config:         persistentQueueConfig(),
+			mode:           collector.ModeStatefulSet,
+			pod:            queuePod(emptyDir, "/var/lib/otelcol/queue"),
+			expectSeverity: []string{types.SeverityWarning},
+		},
+		{
+			name: "storage references a non-storage extension",
+			config: func() *collector.CollectorConfig {
+				cfg := persistentQueueConfig()
+				cfg.Extensions["health_check"] = map[string]interface{}{}
+				cfg.Exporters["otlp"].(map[string]interface{})["sending_queue"] = map[string]interface{}{"storage": "health_check"}
+				return cfg
+			}(),
+			expectSeverity: []string{types.SeverityCritical},
+		},
+		{
+			name: "few consumers to a remote backend and more consumers than slots",
+			config: &collector.CollectorConfig{
+				Exporters: map[string]interface{}{
+					"otlphttp": map[string]interface{}{
+						"endpoint":      "https://otlp.vendor.io",
+						"sending_queue": map[string]interface{}{"num_consumers": 2},
+					},
+					"otlp/gateway": map[string]interface{}{
+						"endpoint":      "gateway:4317",
+						"sending_queue": map[string]interface{}{"num_consumers": 50, "queue_size": 20},
+					},
+				},
+			},
+			expectSeverity: []string{types.SeverityInfo, types.SeverityWarning},
+		},
+		{
+			name: "in-memory queues exceed memory budget",
+			config: &collector.CollectorConfig{
+				Processors: map[string]interface{}{
+					"batch":          map[string]interface{}{"send_batch_size": 1000},
+					"memory_limiter": map[string]interface{}{"limit_mib": 512},
+				},
+				Exporters: map[string]interface{}{
+					"otlp": map[string]interface{}{
+						"endpoint":      "gateway:4317",
+						"sending_queue": map[string]interface{}{"queue_size": 2000},
+					},
+				},
+				Service: collector.ServiceConfig{Pipelines: map[string]collector.PipelineConfig{
+					"traces": {Receivers: []string{"otlp"}, Processors: []string{"memory_limiter", "batch"}, Exporters: []string{"otlp"}},
+				}},
+			},
+			expectSeverity: []string{types.SeverityWarning},
+		},
+		{
+			name: "unbatched requests queue fits the memory budget",
+			config: &collector.CollectorConfig{
+				Processors: map[string]interface{}{
+					"memory_limiter": map[string]interface{}{"limit_mib": 512},
+				},
+				Exporters: map[string]interface{}{
+					"otlp": map[string]interface{}{
+						"endpoint":      "gateway:4317",
+						"sending_queue": map[string]interface{}{"queue_size": 1000},
+					},
+				},
+			},
+		},
+		{
+			name: "exporter batch size sets the request size",
+			config: &collector.CollectorConfig{
+				Processors: map[string]interface{}{
+					"memory_limiter": map[string]interface{}{"limit_mib": 512},
+				},
+				Exporters: map[string]interface{}{
+					"otlp": map[string]interface{}{
+						"endpoint": "gateway:4317",
+						"sending_queue": map[string]interface{}{
+							"queue_size": 1000,
+							"batch":      map[string]interface{}{"min_size": 2000, "max_size": 4000},
+						},
+					},
+				},
+			},
+			expectSeverity: []string{types.SeverityWarning},
+		},
+		{
+			name: "in-memory queue within container budget",
+			config: &collector.CollectorConfig{
+				Exporters: map[string]interface{}{
+					"otlp": map[string]interface{}{
+						"endpoint":      "gateway:4317",
+						"sending_queue": map[string]interface{}{"queue_size": 100, "sizer": "items"},
+					},
+				},
+			},
+			pod: func() *corev1.Pod {
+				pod := queuePod(nil, "")
+				pod.Spec.Containers[0].Resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")}
+				return pod
+			}(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &AnalysisInput{Config: tt.config, DeployMode: tt.mode, PodInfo: tt.pod}
+			findings := AnalyzeSendingQueue(context.Background(), input)
+			if len(findings) != len(tt.expectSeverity) {
+				t.Fatalf("expected %d findings, got %d: %+v", len(tt.expectSeverity), len(findings), findings)
+			}
+			for i, f := range findings {
+				if f.Severity != tt.expectSeverity[i] {
+					t.Errorf("finding %d: expected severity %s, got %s (%s)", i, tt.expectSeverity[i], f.Severity, f.Summary)
+				}
+			}
+		})
+	}
+}
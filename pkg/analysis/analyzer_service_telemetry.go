@@ -0,0 +1,144 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+)
+
+// defaultTelemetryMetricsPort is the port the collector serves its own metrics on.
+const defaultTelemetryMetricsPort = 8888
+
+// AnalyzeServiceTelemetry checks the collector's own telemetry settings: internal
+// metrics disabled or not scrapeable, and debug-level logging outside dev.
+func AnalyzeServiceTelemetry(_ context.Context, input *AnalysisInput) []types.DiagnosticFinding {
+	if input.Config == nil {
+		return nil
+	}
+
+	var findings []types.DiagnosticFinding
+	telemetry := input.Config.Service.Telemetry
+
+	if telemetry.Metrics.Level == "none" {
+		findings = append(findings, types.DiagnosticFinding{
+			RuleID:     types.RuleTelemetryMetricsDisabled,
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryConfig,
+			Summary:    "Collector internal metrics are disabled (service.telemetry.metrics.level: none)",
+			Detail:     "Without internal metrics there is no visibility into dropped data, queue sizes, exporter failures or memory_limiter refusals. Most collector health dashboards and alerts depend on them.",
+			Suggestion: "Set the internal metrics level to basic or normal",
+			Remediation: `service:
+  telemetry:
+    metrics:
+      level: normal`,
+			Evidence: &types.Evidence{SignalType: "metrics", Path: "service.telemetry.metrics.level", SampleValues: []string{telemetry.Metrics.Level}},
+		})
+	} else {
+		host, port := telemetryMetricsEndpoint(input)
+		if host == "localhost" || host == "127.0.0.1" {
+			findings = append(findings, types.DiagnosticFinding{
+				RuleID:     types.RuleTelemetryMetricsLocalhost,
+				Severity:   types.SeverityInfo,
+				Category:   types.CategoryConfig,
+				Summary:    fmt.Sprintf("Collector internal metrics are only served on %s:%d", host, port),
+				Detail:     "The internal metrics endpoint is bound to loopback, so Prometheus or other scrapers running outside the pod cannot collect it.",
+				Suggestion: "Bind the internal metrics endpoint to 0.0.0.0 if you scrape it from outside the pod",
+				Remediation: fmt.Sprintf(`service:
+  telemetry:
+    metrics:
+      readers:
+        - pull:
+            exporter:
+              prometheus:
+                host: 0.0.0.0
+                port: %d`, port),
+				Evidence: &types.Evidence{SignalType: "metrics", Path: "service.telemetry.metrics", SampleValues: []string{host}, Count: port},
+			})
+		} else if input.PodInfo != nil && collectorContainer(input.PodInfo) != nil && !containerPortSet(input.PodInfo)[int32(port)] {
+			findings = append(findings, types.DiagnosticFinding{
+				RuleID:     types.RuleTelemetryPortUndeclared,
+				Severity:   types.SeverityInfo,
+				Category:   types.CategoryConfig,
+				Summary:    fmt.Sprintf("Internal metrics port %d is not declared as a container port", port),
+				Detail:     "The collector serves its own metrics on this port but the container does not declare it. Pod-based scrape configs and PodMonitors that select by port name will not find it.",
+				Suggestion: "Expose the internal metrics port on the collector container",
+				Remediation: fmt.Sprintf(`ports:
+  - name: metrics
+    containerPort: %d
+    protocol: TCP`, port),
+				Evidence: &types.Evidence{SignalType: "metrics", Path: "service.telemetry.metrics", Count: port},
+			})
+		}
+	}
+
+	// Debug logging is only flagged where the environment is known not to be
+	// dev; an unset environment gives no basis for the warning.
+	if env := input.Environment; telemetry.Logs.Level == "debug" && (env == "staging" || env == "production") {
+		findings = append(findings, types.DiagnosticFinding{
+			RuleID:     types.RuleTelemetryDebugLogs,
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryPerformance,
+			Summary:    fmt.Sprintf("Collector logs at debug level in %s", env),
+			Detail:     "Debug logging is very verbose, costs CPU on every batch and can write telemetry payloads, including sensitive attributes, to the container log.",
+			Suggestion: "Use info level outside development environments",
+			Remediation: `service:
+  telemetry:
+    logs:
+      level: info`,
+			Evidence: &types.Evidence{SignalType: "logs", Path: "service.telemetry.logs.level", SampleValues: []string{telemetry.Logs.Level}},
+		})
+	}
+
+	return findings
+}
+
+// TelemetryMetricsPort returns the port a collector config serves its internal
+// metrics on.
+func TelemetryMetricsPort(cfg *collector.CollectorConfig) int {
+	_, port := telemetryMetricsEndpoint(&AnalysisInput{Config: cfg})
+	return port
+}
+
+// telemetryMetricsEndpoint returns the host and port of the internal metrics
+// endpoint from the first prometheus pull reader, the legacy address setting, or
+// the default.
+func telemetryMetricsEndpoint(input *AnalysisInput) (string, int) {
+	metrics := input.Config.Service.Telemetry.Metrics
+	for _, reader := range metrics.Readers {
+		pull, ok := getNestedMap(reader, "pull")
+		if !ok {
+			continue
+		}
+		exporter, ok := getNestedMap(pull, "exporter")
+		if !ok {
+			continue
+		}
+		prom, ok := getNestedMap(exporter, "prometheus")
+		if !ok {
+			continue
+		}
+		host, _ := getNestedString(prom, "host")
+		port := defaultTelemetryMetricsPort
+		switch p := prom["port"].(type) {
+		case int:
+			port = p
+		case string:
+			if n, err := strconv.Atoi(p); err == nil {
+				port = n
+			}
+		}
+		return host, port
+	}
+
+	if metrics.Address != "" {
+		_, host, portStr := parseEndpoint(metrics.Address)
+		if n, err := strconv.Atoi(portStr); err == nil {
+			return host, n
+		}
+		return host, defaultTelemetryMetricsPort
+	}
+	return "", defaultTelemetryMetricsPort
+}
@@ -0,0 +1,100 @@
+package analysis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestAnalyzeServiceTelemetry(t *testing.T) {
+	podWithPorts := func(ports ...int32) *corev1.Pod {
+		c := corev1.Container{Name: "otc-container"}
+		for _, p := range ports {
+			c.Ports = append(c.Ports, corev1.ContainerPort{ContainerPort: p})
+		}
+		return &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{c}}}
+	}
+
+	tests := []struct {
+		name           string
+		telemetry      collector.TelemetryConfig
+		environment    string
+		pod            *corev1.Pod
+		expectSeverity []string
+	}{
+		{
+			name: "defaults produce no findings",
+		},
+		{
+			name:           "metrics disabled",
+			telemetry:      collector.TelemetryConfig{Metrics: collector.TelemetryMetricsConfig{Level: "none"}},
+			expectSeverity: []string{types.SeverityWarning},
+		},
+		{
+			name: "metrics reader on localhost",
+			telemetry: collector.TelemetryConfig{Metrics: collector.TelemetryMetricsConfig{
+				Readers: []map[string]interface{}{{
+					"pull": map[string]interface{}{
+						"exporter": map[string]interface{}{
+							"prometheus": map[string]interface{}{"host": "localhost", "port": 8888},
+						},
+					},
+				}},
+			}},
+			expectSeverity: []string{types.SeverityInfo},
+		},
+		{
+			name:           "metrics port not declared on container",
+			telemetry:      collector.TelemetryConfig{Metrics: collector.TelemetryMetricsConfig{Address: "0.0.0.0:9999"}},
+			pod:            podWithPorts(4317, 8888),
+			expectSeverity: []string{types.SeverityInfo},
+		},
+		{
+			name: "default metrics port declared",
+			pod:  podWithPorts(8888),
+		},
+		{
+			name:           "debug logs in production",
+			telemetry:      collector.TelemetryConfig{Logs: collector.TelemetryLogsConfig{Level: "debug"}},
+			environment:    "production",
+			expectSeverity: []string{types.SeverityWarning},
+		},
+		{
+			name:        "debug logs in dev",
+			telemetry:   collector.TelemetryConfig{Logs: collector.TelemetryLogsConfig{Level: "debug"}},
+			environment: "dev",
+		},
+		{
+			name:      "debug logs with no environment",
+			telemetry: collector.TelemetryConfig{Logs: collector.TelemetryLogsConfig{Level: "debug"}},
+		},
+		{
+			name:           "debug logs in staging",
+			telemetry:      collector.TelemetryConfig{Logs: collector.TelemetryLogsConfig{Level: "debug"}},
+			environment:    "staging",
+			expectSeverity: []string{types.SeverityWarning},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &AnalysisInput{
+				Config:      &collector.CollectorConfig{Service: collector.ServiceConfig{Telemetry: tt.telemetry}},
+				PodInfo:     tt.pod,
+				Environment: tt.environment,
+			}
+			findings := AnalyzeServiceTelemetry(context.Background(), input)
+			if len(findings) != len(tt.expectSeverity) {
+				t.Fatalf("expected %d findings, got %d: %+v", len(tt.expectSeverity), len(findings), findings)
+			}
+			for i, f := range findings {
+				if f.Severity != tt.expectSeverity[i] {
+					t.Errorf("finding %d: expected severity %s, got %s (%s)", i, tt.expectSeverity[i], f.Severity, f.Summary)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+)
+
+// targetAllocatorRules are the permissions the Target Allocator needs to turn
+// ServiceMonitors and PodMonitors into scrape targets.
+var targetAllocatorRules = []rbacRule{
+	{"monitoring.coreos.com", []string{"servicemonitors", "podmonitors"}, readVerbs},
+	{"", []string{"pods", "services", "endpoints", "namespaces"}, readVerbs},
+	{"discovery.k8s.io", []string{"endpointslices"}, readVerbs},
+}
+
+// AnalyzeTargetAllocator checks the Target Allocator settings of the
+// OpenTelemetryCollector CR: the allocation strategy against the collector
+// mode, prometheusCR discovery without selectors, and whether the allocator's
+// ServiceAccount may read ServiceMonitors and PodMonitors.
+func AnalyzeTargetAllocator(ctx context.Context, input *AnalysisInput) []types.DiagnosticFinding {
+	spec, _ := getNestedMap(input.OperatorCR, "spec")
+	ta, _ := getNestedMap(spec, "targetAllocator")
+	if enabled, _ := getNestedBool(ta, "enabled"); !enabled {
+		return nil
+	}
+
+	metadata, _ := getNestedMap(input.OperatorCR, "metadata")
+	crName, _ := getNestedString(metadata, "name")
+	namespace, _ := getNestedString(metadata, "namespace")
+	if namespace == "" {
+		namespace = input.Namespace
+	}
+	mode, _ := getNestedString(spec, "mode")
+	if mode == "" {
+		mode = "deployment"
+	}
+	strategy, _ := getNestedString(ta, "allocationStrategy")
+	if strategy == "" {
+		strategy = "consistent-hashing"
+	}
+
+	var findings []types.DiagnosticFinding
+	switch {
+	case mode == "sidecar":
+		findings = append(findings, types.DiagnosticFinding{
+			RuleID:     types.RuleTargetAllocatorSidecar,
+			Severity:   types.SeverityCritical,
+			Category:   types.CategoryOperator,
+			Summary:    fmt.Sprintf("OpenTelemetryCollector %q enables the Target Allocator in sidecar mode", crName),
+			Detail:     "The operator does not support the Target Allocator for sidecar collectors and rejects the resource.",
+			Suggestion: "Run the scraping collector as a statefulset or daemonset",
+			Evidence:   &types.Evidence{Name: crName, Path: "spec.mode", SampleValues: []string{mode}},
+		})
+	case strategy == "per-node" && mode != "daemonset":
+		findings = append(findings, types.DiagnosticFinding{
+			RuleID:      types.RuleTargetAllocatorPerNode,
+			Severity:    types.SeverityCritical,
+			Category:    types.CategoryOperator,
+			Summary:     fmt.Sprintf("OpenTelemetryCollector %q uses the per-node allocation strategy in %s mode", crName, mode),
+			Detail:      "per-node assigns each target to the collector running on the target's node. It only works with one collector per node, and the operator rejects it for other modes.",
+			Suggestion:  "Use mode daemonset, or switch to consistent-hashing",
+			Remediation: "spec:\n  mode: daemonset\n  targetAllocator:\n    enabled: true\n    allocationStrategy: per-node",
+			Evidence:    &types.Evidence{Name: crName, Path: "spec.targetAllocator.allocationStrategy", SampleValues: []string{strategy, mode}},
+		})
+	case mode == "daemonset" && strategy != "per-node":
+		findings = append(findings, types.DiagnosticFinding{
+			RuleID:      types.RuleTargetAllocatorDaemonSet,
+			Severity:    types.SeverityWarning,
+			Category:    types.CategoryOperator,
+			Summary:     fmt.Sprintf("OpenTelemetryCollector %q runs as a daemonset with the %s allocation strategy", crName, strategy),
+			Detail:      fmt.Sprintf("With %s, each node's collector is assigned targets anywhere in the cluster. Scrapes cross nodes and a node's collector carries load unrelated to its node.", strategy),
+			Suggestion:  "Use the per-node allocation strategy for daemonset collectors",
+			Remediation: "spec:\n  targetAllocator:\n    allocationStrategy: per-node",
+			Evidence:    &types.Evidence{Name: crName, Path: "spec.targetAllocator.allocationStrategy", SampleValues: []string{strategy, mode}},
+		})
+	case mode == "deployment":
+		findings = append(findings, types.DiagnosticFinding{
+			RuleID:      types.RuleTargetAllocatorDeployment,
+			Severity:    types.SeverityInfo,
+			Category:    types.CategoryOperator,
+			Summary:     fmt.Sprintf("OpenTelemetryCollector %q uses the Target Allocator in deployment mode", crName),
+			Detail:      "Deployment pods get a new name on every rollout, so the allocator reassigns all targets each time. A statefulset keeps stable collector IDs and target assignments.",
+			Suggestion:  "Use mode statefulset for collectors fed by the Target Allocator",
+			Remediation: "spec:\n  mode: statefulset",
+			Evidence:    &types.Evidence{Name: crName, Path: "spec.mode", SampleValues: []string{mode}},
+		})
+	}
+
+	promCR, _ := getNestedMap(ta, "prometheusCR")
+	if enabled, _ := getNestedBool(promCR, "enabled"); !enabled {
+		return findings
+	}
+
+	_, hasSM := promCR["serviceMonitorSelector"]
+	_, hasPM := promCR["podMonitorSelector"]
+	if !hasSM && !hasPM {
+		findings = append(findings, types.DiagnosticFinding{
+			RuleID:     types.RuleTargetAllocatorSelectors,
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryOperator,
+			Summary:    fmt.Sprintf("OpenTelemetryCollector %q discovers Prometheus CRs without selectors", crName),
+			Detail:     "With prometheusCR enabled and no serviceMonitorSelector or podMonitorSelector, the allocator picks up every ServiceMonitor and PodMonitor it can read. Targets also scraped by a Prometheus instance are collected twice.",
+			Suggestion: "Restrict discovery to the monitors meant for this collector",
+			Remediation: `spec:
+  targetAllocator:
+    prometheusCR:
+      enabled: true
+      serviceMonitorSelector:
+        matchLabels:
+          otel-scrape: "true"
+      podMonitorSelector:
+        matchLabels:
+          otel-scrape: "true"`,
+			Evidence: &types.Evidence{Name: crName, Path: "spec.targetAllocator.prometheusCR"},
+		})
+	}
+
+	if input.Clientset == nil {
+		return findings
+	}
+	serviceAccount, _ := getNestedString(ta, "serviceAccount")
+	if serviceAccount == "" {
+		serviceAccount = crName + "-targetallocator"
+	}
+
+	var missing []rbacRule
+	for _, rule := range targetAllocatorRules {
+		for _, resource := range rule.resources {
+			var denied []string
+			for _, verb := range rule.verbs {
+				allowed, err := serviceAccountCan(ctx, input, namespace, serviceAccount, verb, rule.group, resource)
+				if err != nil {
+					slog.Warn("could not run SubjectAccessReview", "error", err)
+					return findings
+				}
+				if !allowed {
+					denied = append(denied, verb)
+				}
+			}
+			if len(denied) > 0 {
+				missing = append(missing, rbacRule{group: rule.group, resources: []string{resource}, verbs: denied})
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return findings
+	}
+
+	var parts []string
+	for _, m := range missing {
+		parts = append(parts, fmt.Sprintf("%s %s", strings.Join(m.verbs, "/"), qualifiedResource(m.group, m.resources[0])))
+	}
+	return append(findings, types.DiagnosticFinding{
+		RuleID:      types.RuleTargetAllocatorRBAC,
+		Severity:    types.SeverityCritical,
+		Category:    types.CategoryOperator,
+		Summary:     fmt.Sprintf("Target Allocator ServiceAccount %s/%s cannot read ServiceMonitors and their targets", namespace, serviceAccount),
+		Detail:      fmt.Sprintf("Missing: %s. The allocator cannot turn ServiceMonitors and PodMonitors into targets, so the collectors scrape nothing from them; the allocator only logs \"forbidden\" errors.", strings.Join(parts, ", ")),
+		Suggestion:  "Grant the missing permissions with a ClusterRole bound to the Target Allocator's ServiceAccount",
+		Remediation: clusterRoleManifest(serviceAccount, namespace, serviceAccount, missing),
+		Evidence:    &types.Evidence{Name: crName, ComponentID: serviceAccount, SampleValues: parts, Count: len(parts)},
+	})
+}
@@ -0,0 +1,104 @@
+package analysis
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+)
+
+func collectorCR(mode string, ta map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "scraper", "namespace": "obs"},
+		"spec":     map[string]interface{}{"mode": mode, "targetAllocator": ta},
+	}
+}
+
+func TestAnalyzeTargetAllocator(t *testing.T) {
+	selectors := map[string]interface{}{
+		"enabled":                true,
+		"serviceMonitorSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"team": "a"}},
+	}
+
+	tests := []struct {
+		name           string
+		cr             map[string]interface{}
+		expectSeverity []string
+	}{
+		{
+			name: "no operator CR",
+		},
+		{
+			name: "target allocator disabled",
+			cr:   collectorCR("deployment", map[string]interface{}{"enabled": false}),
+		},
+		{
+			name: "statefulset with consistent hashing",
+			cr:   collectorCR("statefulset", map[string]interface{}{"enabled": true}),
+		},
+		{
+			name:           "per-node outside daemonset",
+			cr:             collectorCR("statefulset", map[string]interface{}{"enabled": true, "allocationStrategy": "per-node"}),
+			expectSeverity: []string{types.SeverityCritical},
+		},
+		{
+			name:           "daemonset without per-node",
+			cr:             collectorCR("daemonset", map[string]interface{}{"enabled": true, "allocationStrategy": "least-weighted"}),
+			expectSeverity: []string{types.SeverityWarning},
+		},
+		{
+			name:           "deployment mode",
+			cr:             collectorCR("", map[string]interface{}{"enabled": true}),
+			expectSeverity: []string{types.SeverityInfo},
+		},
+		{
+			name: "prometheusCR without selectors",
+			cr: collectorCR("statefulset", map[string]interface{}{
+				"enabled":      true,
+				"prometheusCR": map[string]interface{}{"enabled": true},
+			}),
+			expectSeverity: []string{types.SeverityWarning},
+		},
+		{
+			name:           "sidecar mode",
+			cr:             collectorCR("sidecar", map[string]interface{}{"enabled": true, "prometheusCR": selectors}),
+			expectSeverity: []string{types.SeverityCritical},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := AnalyzeTargetAllocator(context.Background(), &AnalysisInput{OperatorCR: tt.cr})
+			if len(findings) != len(tt.expectSeverity) {
+				t.Fatalf("expected %d findings, got %d: %+v", len(tt.expectSeverity), len(findings), findings)
+			}
+			for i, f := range findings {
+				if f.Severity != tt.expectSeverity[i] {
+					t.Errorf("finding %d: expected severity %s, got %s (%s)", i, tt.expectSeverity[i], f.Severity, f.Summary)
+				}
+			}
+		})
+	}
+
+	t.Run("missing ServiceMonitor RBAC", func(t *testing.T) {
+		cr := collectorCR("statefulset", map[string]interface{}{"enabled": true, "prometheusCR": selectors})
+		clientset := sarClientset(
+			"get pods", "list pods", "watch pods",
+			"get services", "list services", "watch services",
+			"get endpoints", "list endpoints", "watch endpoints",
+			"get namespaces", "list namespaces", "watch namespaces",
+			"get endpointslices", "list endpointslices", "watch endpointslices",
+			"get servicemonitors",
+		)
+		findings := AnalyzeTargetAllocator(context.Background(), &AnalysisInput{OperatorCR: cr, Clientset: clientset})
+		if len(findings) != 1 || findings[0].Severity != types.SeverityCritical {
+			t.Fatalf("expected one critical finding, got %+v", findings)
+		}
+		for _, want := range []string{"list/watch servicemonitors.monitoring.coreos.com", "get/list/watch podmonitors.monitoring.coreos.com", "scraper-targetallocator"} {
+			if !strings.Contains(findings[0].Detail+findings[0].Summary, want) {
+				t.Errorf("expected %q in finding, got %q / %q", want, findings[0].Summary, findings[0].Detail)
+			}
+		}
+	})
+}
@@ -0,0 +1,290 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+)
+
+// weakTLSVersions are min_version values below TLS 1.2.
+var weakTLSVersions = map[string]bool{"1.0": true, "1.1": true}
+
+// tlsBlock is a tls settings map found inside a component config.
+type tlsBlock struct {
+	path []string // path from the component root to the tls map, inclusive
+	cfg  map[string]interface{}
+}
+
+// listenEndpoint is an endpoint a receiver listens on, along with the settings
+// map that holds it (where tls and auth are configured).
+type listenEndpoint struct {
+	path     []string // path from the component root to the settings map
+	endpoint string
+	cfg      map[string]interface{}
+}
+
+// AnalyzeTransportSecurity checks TLS and transport settings of every receiver,
+// exporter and extension: disabled verification, plaintext export to hosts outside
+// the cluster, unauthenticated wildcard listeners, incomplete certificate pairs and
+// weak minimum TLS versions.
+func AnalyzeTransportSecurity(_ context.Context, input *AnalysisInput) []types.DiagnosticFinding {
+	if input.Config == nil {
+		return nil
+	}
+
+	var findings []types.DiagnosticFinding
+
+	sections := []struct {
+		name       string
+		components map[string]interface{}
+	}{
+		{"receivers", input.Config.Receivers},
+		{"exporters", input.Config.Exporters},
+		{"extensions", input.Config.Extensions},
+	}
+	for _, sec := range sections {
+		for _, id := range sortedKeys(sec.components) {
+			cfgMap, ok := sec.components[id].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, blk := range findTLSBlocks(cfgMap, nil) {
+				findings = append(findings, checkTLSBlock(sec.name, id, blk)...)
+			}
+			switch sec.name {
+			case "exporters":
+				findings = append(findings, checkExporterTransport(id, cfgMap)...)
+			case "receivers":
+				findings = append(findings, checkReceiverExposure(id, cfgMap)...)
+			}
+		}
+	}
+
+	return findings
+}
+
+// findTLSBlocks returns every "tls" map nested anywhere in a component config.
+func findTLSBlocks(m map[string]interface{}, path []string) []tlsBlock {
+	var blocks []tlsBlock
+	for _, key := range sortedKeys(m) {
+		nested, ok := m[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		p := append(append([]string{}, path...), key)
+		if key == "tls" {
+			blocks = append(blocks, tlsBlock{path: p, cfg: nested})
+			continue
+		}
+		blocks = append(blocks, findTLSBlocks(nested, p)...)
+	}
+	return blocks
+}
+
+// receiverEndpoints returns the explicit listen endpoints of a receiver, both at
+// the top level and per protocol (e.g. otlp grpc/http).
+func receiverEndpoints(cfg map[string]interface{}) []listenEndpoint {
+	var endpoints []listenEndpoint
+	if endpoint, ok := getNestedString(cfg, "endpoint"); ok {
+		endpoints = append(endpoints, listenEndpoint{endpoint: endpoint, cfg: cfg})
+	}
+	if protocols, ok := getNestedMap(cfg, "protocols"); ok {
+		for _, proto := range sortedKeys(protocols) {
+			protoCfg, ok := protocols[proto].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if endpoint, ok := getNestedString(protoCfg, "endpoint"); ok {
+				endpoints = append(endpoints, listenEndpoint{
+					path:     []string{"protocols", proto},
+					endpoint: endpoint,
+					cfg:      protoCfg,
+				})
+			}
+		}
+	}
+	return endpoints
+}
+
+func checkTLSBlock(section, id string, blk tlsBlock) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	kind := strings.TrimSuffix(section, "s")
+	at := strings.Join(blk.path, ".")
+
+	if skip, _ := getNestedBool(blk.cfg, "insecure_skip_verify"); skip {
+		findings = append(findings, types.DiagnosticFinding{
+			RuleID:      types.RuleTLSVerifyDisabled,
+			Severity:    types.SeverityWarning,
+			Category:    types.CategorySecurity,
+			Summary:     fmt.Sprintf("TLS certificate verification disabled in %s %q at %q", kind, id, at),
+			Detail:      "insecure_skip_verify accepts any certificate presented by the peer, so traffic is encrypted but the peer is not authenticated. A man-in-the-middle can intercept telemetry and credentials sent in headers.",
+			Suggestion:  "Remove insecure_skip_verify and trust the peer's CA explicitly with ca_file",
+			Remediation: remediationYAML(section, id, blk.path, "insecure_skip_verify: false", "ca_file: /etc/otel/certs/ca.crt"),
+			Evidence:    componentEvidence(id, at+".insecure_skip_verify"),
+		})
+	}
+
+	certFile, hasCert := getNestedString(blk.cfg, "cert_file")
+	keyFile, hasKey := getNestedString(blk.cfg, "key_file")
+	hasCert = hasCert && certFile != ""
+	hasKey = hasKey && keyFile != ""
+	if hasCert != hasKey {
+		missing := "key_file"
+		if !hasCert {
+			missing = "cert_file"
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			RuleID:      types.RuleTLSIncompletePair,
+			Severity:    types.SeverityCritical,
+			Category:    types.CategorySecurity,
+			Summary:     fmt.Sprintf("Incomplete TLS certificate pair in %s %q at %q: %s is missing", kind, id, at, missing),
+			Detail:      "cert_file and key_file must be configured together. With only one of them set, the collector fails to load the TLS configuration and the component does not start.",
+			Suggestion:  fmt.Sprintf("Set %s alongside the existing certificate setting", missing),
+			Remediation: remediationYAML(section, id, blk.path, "cert_file: /etc/otel/certs/tls.crt", "key_file: /etc/otel/certs/tls.key"),
+			Evidence:    componentEvidence(id, at+"."+missing),
+		})
+	}
+
+	if clientCA, ok := getNestedString(blk.cfg, "client_ca_file"); ok && clientCA != "" && !hasCert && !hasKey {
+		findings = append(findings, types.DiagnosticFinding{
+			RuleID:      types.RuleTLSClientCAWithoutCert,
+			Severity:    types.SeverityCritical,
+			Category:    types.CategorySecurity,
+			Summary:     fmt.Sprintf("client_ca_file set without a server certificate in %s %q at %q", kind, id, at),
+			Detail:      "Mutual TLS verifies client certificates against client_ca_file, but the server still needs its own certificate and key. Without cert_file and key_file the listener cannot complete a TLS handshake.",
+			Suggestion:  "Add the server certificate and key next to client_ca_file",
+			Remediation: remediationYAML(section, id, blk.path, "cert_file: /etc/otel/certs/tls.crt", "key_file: /etc/otel/certs/tls.key", "client_ca_file: "+clientCA),
+			Evidence:    componentEvidence(id, at+".client_ca_file"),
+		})
+	}
+
+	if section == "exporters" && hasCert {
+		insecure, _ := getNestedBool(blk.cfg, "insecure")
+		if caFile, ok := getNestedString(blk.cfg, "ca_file"); (!ok || caFile == "") && !insecure {
+			findings = append(findings, types.DiagnosticFinding{
+				RuleID:      types.RuleTLSClientCertWithoutCA,
+				Severity:    types.SeverityInfo,
+				Category:    types.CategorySecurity,
+				Summary:     fmt.Sprintf("Client certificate configured without ca_file in exporter %q at %q", id, at),
+				Detail:      "The exporter presents a client certificate but verifies the server against the system trust store only. Backends using a private PKI for mutual TLS will fail verification.",
+				Suggestion:  "Set ca_file to the CA that signed the backend certificate",
+				Remediation: remediationYAML(section, id, blk.path, "ca_file: /etc/otel/certs/ca.crt", "cert_file: "+certFile, "key_file: "+keyFile),
+				Evidence:    componentEvidence(id, at+".ca_file"),
+			})
+		}
+	}
+
+	if minVersion, ok := getNestedString(blk.cfg, "min_version"); ok && weakTLSVersions[minVersion] {
+		findings = append(findings, types.DiagnosticFinding{
+			RuleID:      types.RuleTLSWeakMinVersion,
+			Severity:    types.SeverityWarning,
+			Category:    types.CategorySecurity,
+			Summary:     fmt.Sprintf("Weak TLS min_version %s in %s %q at %q", minVersion, kind, id, at),
+			Detail:      "TLS 1.0 and 1.1 are deprecated (RFC 8996) and vulnerable to known downgrade attacks. The collector defaults to TLS 1.2 when min_version is unset.",
+			Suggestion:  "Raise min_version to 1.2 or later",
+			Remediation: remediationYAML(section, id, blk.path, `min_version: "1.2"`),
+			Evidence:    &types.Evidence{ComponentID: id, Path: at + ".min_version", SampleValues: []string{minVersion}},
+		})
+	}
+
+	return findings
+}
+
+func checkExporterTransport(id string, cfg map[string]interface{}) []types.DiagnosticFinding {
+	endpoint, ok := getNestedString(cfg, "endpoint")
+	if !ok || endpoint == "" {
+		return nil
+	}
+
+	scheme, host, _ := parseEndpoint(endpoint)
+	insecure := false
+	if tlsCfg, ok := getNestedMap(cfg, "tls"); ok {
+		insecure, _ = getNestedBool(tlsCfg, "insecure")
+	}
+	if !insecure && scheme != "http" {
+		return nil
+	}
+
+	if isClusterLocalHost(host) {
+		return []types.DiagnosticFinding{{
+			RuleID:      types.RulePlaintextInCluster,
+			Severity:    types.SeverityInfo,
+			Category:    types.CategorySecurity,
+			Summary:     fmt.Sprintf("Exporter %q uses plaintext transport to in-cluster endpoint %q", id, endpoint),
+			Detail:      "TLS is disabled for this exporter. This is common between agents and gateways inside a cluster, but telemetry and any auth headers travel unencrypted over the pod network.",
+			Suggestion:  "Enable TLS if the pod network is shared or untrusted",
+			Remediation: secureExporterRemediation(id, endpoint, scheme),
+			Evidence:    &types.Evidence{ComponentID: id, Path: "endpoint", SampleValues: []string{endpoint}},
+		}}
+	}
+
+	return []types.DiagnosticFinding{{
+		RuleID:      types.RulePlaintextExternal,
+		Severity:    types.SeverityCritical,
+		Category:    types.CategorySecurity,
+		Summary:     fmt.Sprintf("Exporter %q sends telemetry in plaintext to external host %q", id, host),
+		Detail:      "The exporter targets a host outside the cluster without TLS. Telemetry, which often contains user data, and authentication headers such as API tokens are sent unencrypted over networks you do not control.",
+		Suggestion:  "Enable TLS for this exporter and use an https endpoint",
+		Remediation: secureExporterRemediation(id, endpoint, scheme),
+		Evidence:    &types.Evidence{ComponentID: id, Path: "endpoint", SampleValues: []string{endpoint}},
+	}}
+}
+
+// secureExporterRemediation renders the exporter block with TLS enabled.
+func secureExporterRemediation(id, endpoint, scheme string) string {
+	if scheme == "http" {
+		endpoint = "https://" + strings.TrimPrefix(endpoint, "http://")
+	}
+	return remediationYAML("exporters", id, nil,
+		"endpoint: "+endpoint,
+		"tls:",
+		"  insecure: false",
+		"  ca_file: /etc/otel/certs/ca.crt  # omit for publicly trusted backends",
+	)
+}
+
+func checkReceiverExposure(id string, cfg map[string]interface{}) []types.DiagnosticFinding {
+	var findings []types.DiagnosticFinding
+	for _, ep := range receiverEndpoints(cfg) {
+		_, host, _ := parseEndpoint(ep.endpoint)
+		if !isWildcardHost(host) {
+			continue
+		}
+		_, hasTLS := ep.cfg["tls"]
+		_, hasAuth := ep.cfg["auth"]
+		if hasTLS || hasAuth {
+			continue
+		}
+		name := id
+		if len(ep.path) > 0 {
+			name = id + "/" + ep.path[len(ep.path)-1]
+		}
+		findings = append(findings, types.DiagnosticFinding{
+			RuleID:     types.RuleReceiverUnauthenticated,
+			Severity:   types.SeverityInfo,
+			Category:   types.CategorySecurity,
+			Summary:    fmt.Sprintf("Receiver %q listens on %s without TLS or authentication", name, ep.endpoint),
+			Detail:     "The receiver accepts unauthenticated plaintext connections on all interfaces. Any workload that can reach the pod can push arbitrary telemetry into your pipelines. Restrict access with TLS plus an auth extension, or with a NetworkPolicy.",
+			Suggestion: "Add TLS and an authenticator extension to the receiver, or restrict ingress with a NetworkPolicy",
+			Remediation: remediationYAML("receivers", id, ep.path,
+				"endpoint: "+ep.endpoint,
+				"tls:",
+				"  cert_file: /etc/otel/certs/tls.crt",
+				"  key_file: /etc/otel/certs/tls.key",
+				"auth:",
+				"  authenticator: bearertokenauth/server",
+			) + `
+
+extensions:
+  bearertokenauth/server:
+    token: ${env:OTEL_RECEIVER_TOKEN}
+
+service:
+  extensions: [bearertokenauth/server]`,
+			Evidence: &types.Evidence{ComponentID: id, Path: strings.Join(append(append([]string{}, ep.path...), "endpoint"), "."), SampleValues: []string{ep.endpoint}},
+		})
+	}
+	return findings
+}
@@ -0,0 +1,152 @@
+package analysis
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+)
+
+func TestAnalyzeTransportSecurity(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         *collector.CollectorConfig
+		expectSeverity []string
+	}{
+		{
+			name:   "nil config returns no findings",
+			config: nil,
+		},
+		{
+			name: "secure exporter and loopback receiver",
+			config: &collector.CollectorConfig{
+				Receivers: map[string]interface{}{
+					"otlp": map[string]interface{}{
+						"protocols": map[string]interface{}{
+							"grpc": map[string]interface{}{"endpoint": "localhost:4317"},
+						},
+					},
+				},
+				Exporters: map[string]interface{}{
+					"otlp": map[string]interface{}{
+						"endpoint": "ingest.example.com:443",
+						"tls":      map[string]interface{}{"ca_file": "/certs/ca.crt"},
+					},
+				},
+			},
+		},
+		{
+			name: "plaintext export to external host",
+			config: &collector.CollectorConfig{
+				Exporters: map[string]interface{}{
+					"otlp": map[string]interface{}{
+						"endpoint": "ingest.example.com:4317",
+						"tls":      map[string]interface{}{"insecure": true},
+					},
+				},
+			},
+			expectSeverity: []string{types.SeverityCritical},
+		},
+		{
+			name: "plaintext otlphttp to in-cluster gateway",
+			config: &collector.CollectorConfig{
+				Exporters: map[string]interface{}{
+					"otlphttp": map[string]interface{}{
+						"endpoint": "http://gateway.observability.svc.cluster.local:4318",
+					},
+				},
+			},
+			expectSeverity: []string{types.SeverityInfo},
+		},
+		{
+			name: "skip verify and weak min_version",
+			config: &collector.CollectorConfig{
+				Exporters: map[string]interface{}{
+					"otlp/backend": map[string]interface{}{
+						"endpoint": "backend.example.com:443",
+						"tls": map[string]interface{}{
+							"insecure_skip_verify": true,
+							"min_version":          "1.0",
+						},
+					},
+				},
+			},
+			expectSeverity: []string{types.SeverityWarning, types.SeverityWarning},
+		},
+		{
+			name: "incomplete certificate pair on receiver",
+			config: &collector.CollectorConfig{
+				Receivers: map[string]interface{}{
+					"otlp": map[string]interface{}{
+						"protocols": map[string]interface{}{
+							"grpc": map[string]interface{}{
+								"endpoint": "0.0.0.0:4317",
+								"tls":      map[string]interface{}{"cert_file": "/certs/tls.crt"},
+							},
+						},
+					},
+				},
+			},
+			expectSeverity: []string{types.SeverityCritical},
+		},
+		{
+			name: "wildcard receiver without tls or auth",
+			config: &collector.CollectorConfig{
+				Receivers: map[string]interface{}{
+					"otlp": map[string]interface{}{
+						"protocols": map[string]interface{}{
+							"grpc": map[string]interface{}{"endpoint": "0.0.0.0:4317"},
+							"http": map[string]interface{}{
+								"endpoint": "0.0.0.0:4318",
+								"auth":     map[string]interface{}{"authenticator": "bearertokenauth"},
+							},
+						},
+					},
+				},
+			},
+			expectSeverity: []string{types.SeverityInfo},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := AnalyzeTransportSecurity(context.Background(), &AnalysisInput{Config: tt.config})
+			if len(findings) != len(tt.expectSeverity) {
+				t.Fatalf("expected %d findings, got %d: %+v", len(tt.expectSeverity), len(findings), findings)
+			}
+			for i, f := range findings {
+				if f.Severity != tt.expectSeverity[i] {
+					t.Errorf("finding %d: expected severity %s, got %s (%s)", i, tt.expectSeverity[i], f.Severity, f.Summary)
+				}
+				if f.Category != types.CategorySecurity {
+					t.Errorf("finding %d: expected security category, got %s", i, f.Category)
+				}
+				if f.Remediation == "" {
+					t.Errorf("finding %d: expected remediation", i)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeTransportSecurity_RemediationUsesHTTPS(t *testing.T) {
+	input := &AnalysisInput{
+		Config: &collector.CollectorConfig{
+			Exporters: map[string]interface{}{
+				"otlphttp/vendor": map[string]interface{}{
+					"endpoint": "http://otlp.vendor.io:4318",
+				},
+			},
+		},
+	}
+
+	findings := AnalyzeTransportSecurity(context.Background(), input)
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding, got %d", len(findings))
+	}
+	if !strings.Contains(findings[0].Remediation, "endpoint: https://otlp.vendor.io:4318") {
+		t.Errorf("expected https endpoint in remediation, got:\n%s", findings[0].Remediation)
+	}
+}
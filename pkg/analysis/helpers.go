@@ -1,6 +1,17 @@
 package analysis
 
-import "github.com/hrexed/otel-collector-mcp/pkg/collector"
+import (
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+)
 
 // pipelineHasProcessor checks if a pipeline contains a processor with the given prefix.
 func pipelineHasProcessor(pipeline collector.PipelineConfig, prefix string) bool {
@@ -12,6 +23,17 @@ func pipelineHasProcessor(pipeline collector.PipelineConfig, prefix string) bool
 	return false
 }
 
+// usedReceivers returns the receiver IDs referenced by any pipeline.
+func usedReceivers(cfg *collector.CollectorConfig) map[string]bool {
+	used := make(map[string]bool)
+	for _, pipeline := range cfg.Service.Pipelines {
+		for _, r := range pipeline.Receivers {
+			used[r] = true
+		}
+	}
+	return used
+}
+
 // getNestedMap tries to get a nested map from a parent map.
 func getNestedMap(m map[string]interface{}, key string) (map[string]interface{}, bool) {
 	v, ok := m[key]
@@ -31,3 +53,259 @@ func getNestedString(m map[string]interface{}, key string) (string, bool) {
 	s, ok := v.(string)
 	return s, ok
 }
+
+// getNestedBool tries to get a nested bool from a parent map.
+func getNestedBool(m map[string]interface{}, key string) (bool, bool) {
+	v, ok := m[key]
+	if !ok {
+		return false, false
+	}
+	b, ok := v.(bool)
+	return b, ok
+}
+
+// getNestedNumber tries to get a nested number from a parent map, accepting the
+// int and float64 values produced by YAML decoding as well as numeric strings.
+func getNestedNumber(m map[string]interface{}, key string) (float64, bool) {
+	switch v := m[key].(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// componentType returns the type part of a component ID ("otlp/backend" -> "otlp").
+func componentType(id string) string {
+	typ, _, _ := strings.Cut(id, "/")
+	return typ
+}
+
+// sortedKeys returns the keys of a component map in a stable order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// parseEndpoint splits a component endpoint such as "0.0.0.0:4317",
+// "https://backend:4318/v1/traces" or "dns:///gateway:4317" into its
+// scheme, host and port. Missing parts are returned empty.
+func parseEndpoint(endpoint string) (scheme, host, port string) {
+	rest := endpoint
+	if s, r, ok := strings.Cut(endpoint, "://"); ok {
+		scheme = strings.ToLower(s)
+		rest = strings.TrimLeft(r, "/")
+	}
+	if i := strings.Index(rest, "/"); i >= 0 {
+		rest = rest[:i]
+	}
+	h, p, err := net.SplitHostPort(rest)
+	if err != nil {
+		return scheme, rest, ""
+	}
+	return scheme, h, p
+}
+
+// isWildcardHost reports whether a listen host binds every interface.
+func isWildcardHost(host string) bool {
+	return host == "" || host == "0.0.0.0" || host == "::" || host == "[::]"
+}
+
+// isClusterLocalHost reports whether a host resolves inside the cluster or the pod:
+// short Service names, *.svc / *.cluster.local names, loopback and private IPs.
+// Hosts built from environment variables are treated as local since they cannot
+// be resolved statically.
+func isClusterLocalHost(host string) bool {
+	if host == "" || host == "localhost" || strings.Contains(host, "${") {
+		return true
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast()
+	}
+	lower := strings.ToLower(strings.TrimSuffix(host, "."))
+	if !strings.Contains(lower, ".") {
+		return true
+	}
+	return strings.HasSuffix(lower, ".svc") || strings.HasSuffix(lower, ".cluster.local") ||
+		strings.Contains(lower, ".svc.")
+}
+
+// remediationYAML renders a config block for a single component, nesting the
+// given body lines under section -> component ID -> path.
+func remediationYAML(section, id string, path []string, body ...string) string {
+	var b strings.Builder
+	b.WriteString(section + ":\n")
+	b.WriteString("  " + id + ":\n")
+	indent := "    "
+	for _, p := range path {
+		b.WriteString(indent + p + ":\n")
+		indent += "  "
+	}
+	for i, line := range body {
+		b.WriteString(indent + line)
+		if i < len(body)-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// collectorContainer returns the container running the collector in a pod,
+// preferring containers whose name or image identifies the collector.
+func collectorContainer(pod *corev1.Pod) *corev1.Container {
+	if pod == nil || len(pod.Spec.Containers) == 0 {
+		return nil
+	}
+	for i := range pod.Spec.Containers {
+		c := &pod.Spec.Containers[i]
+		if c.Name == "otc-container" || strings.Contains(c.Name, "collector") ||
+			strings.Contains(c.Image, "opentelemetry-collector") || strings.Contains(c.Image, "otelcol") {
+			return c
+		}
+	}
+	return &pod.Spec.Containers[0]
+}
+
+// containerPortSet returns the declared container ports of the collector container.
+func containerPortSet(pod *corev1.Pod) map[int32]bool {
+	ports := make(map[int32]bool)
+	if c := collectorContainer(pod); c != nil {
+		for _, p := range c.Ports {
+			ports[p.ContainerPort] = true
+		}
+	}
+	return ports
+}
+
+// resolveProbePort resolves a probe port that may be a named container port.
+func resolveProbePort(c *corev1.Container, port intstr.IntOrString) int32 {
+	if port.Type == intstr.Int {
+		return port.IntVal
+	}
+	for _, p := range c.Ports {
+		if p.Name == port.StrVal {
+			return p.ContainerPort
+		}
+	}
+	return 0
+}
+
+// serviceTargetPort resolves a Service targetPort against the ports of all
+// containers in a pod. It returns 0 for a port name no container declares.
+func serviceTargetPort(pod *corev1.Pod, port intstr.IntOrString) int32 {
+	if port.Type == intstr.Int {
+		return port.IntVal
+	}
+	for i := range pod.Spec.Containers {
+		if p := resolveProbePort(&pod.Spec.Containers[i], port); p != 0 {
+			return p
+		}
+	}
+	return 0
+}
+
+// isEnabledExtension reports whether an extension ID is listed in service.extensions.
+func isEnabledExtension(cfg *collector.CollectorConfig, id string) bool {
+	for _, ext := range cfg.Service.Extensions {
+		if ext == id {
+			return true
+		}
+	}
+	return false
+}
+
+// parseGoMemLimit parses a GOMEMLIMIT value such as "400MiB" or "1073741824"
+// into bytes. It returns false for "off" and unparseable values.
+func parseGoMemLimit(v string) (int64, bool) {
+	v = strings.TrimSpace(v)
+	units := []struct {
+		suffix string
+		mult   int64
+	}{
+		{"TiB", 1 << 40}, {"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10}, {"B", 1},
+	}
+	mult := int64(1)
+	for _, u := range units {
+		if strings.HasSuffix(v, u.suffix) {
+			v = strings.TrimSuffix(v, u.suffix)
+			mult = u.mult
+			break
+		}
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n * mult, true
+}
+
+// containerEnv returns the literal value of an environment variable on a container.
+func containerEnv(c *corev1.Container, name string) (string, bool) {
+	for _, e := range c.Env {
+		if e.Name == name && e.ValueFrom == nil {
+			return e.Value, true
+		}
+	}
+	return "", false
+}
+
+// containerEnvDefined reports whether a container defines an environment
+// variable, either literally or through valueFrom.
+func containerEnvDefined(c *corev1.Container, name string) bool {
+	for _, e := range c.Env {
+		if e.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// containsString reports whether list holds s.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// pipelineEvidence locates a finding on a pipeline, and on a component in it
+// when componentID is set.
+func pipelineEvidence(pipeline, componentID string) *types.Evidence {
+	return &types.Evidence{
+		SignalType:  pipelineSignal(pipeline),
+		Pipeline:    pipeline,
+		ComponentID: componentID,
+	}
+}
+
+// pipelineProcessorID returns the ID of the first processor of type typ in a pipeline.
+func pipelineProcessorID(pipeline collector.PipelineConfig, typ string) string {
+	for _, p := range pipeline.Processors {
+		if componentType(p) == typ {
+			return p
+		}
+	}
+	return ""
+}
+
+// componentEvidence locates a finding on a component setting.
+func componentEvidence(componentID, path string) *types.Evidence {
+	return &types.Evidence{ComponentID: componentID, Path: path}
+}
+
+// pipelineSignal returns the signal type of a pipeline ID such as traces/tail.
+func pipelineSignal(pipeline string) string {
+	return strings.SplitN(pipeline, "/", 2)[0]
+}
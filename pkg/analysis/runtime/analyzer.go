@@ -2,7 +2,9 @@ package runtime
 
 import (
 	"context"
+	"strings"
 
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
 	"github.com/hrexed/otel-collector-mcp/pkg/signals"
 	"github.com/hrexed/otel-collector-mcp/pkg/types"
 )
@@ -30,3 +32,27 @@ func AllRuntimeAnalyzers() map[string]RuntimeAnalyzer {
 		"resource_sizing":     AnalyzeResourceSizing,
 	}
 }
+
+// signalPipeline returns the pipeline carrying a signal type when the collector
+// config has exactly one, so fixes can name it. It returns "" when the config is
+// unknown or several pipelines carry the signal.
+func signalPipeline(input *RuntimeAnalysisInput, signal string) string {
+	if input.CollectorConfig == "" {
+		return ""
+	}
+	cfg, err := collector.ParseConfig([]byte(input.CollectorConfig))
+	if err != nil {
+		return ""
+	}
+	var found string
+	for id := range cfg.Service.Pipelines {
+		if id != signal && !strings.HasPrefix(id, signal+"/") {
+			continue
+		}
+		if found != "" {
+			return ""
+		}
+		found = id
+	}
+	return found
+}
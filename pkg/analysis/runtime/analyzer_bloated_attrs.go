@@ -16,16 +16,26 @@ func AnalyzeBloatedAttributes(_ context.Context, input *RuntimeAnalysisInput) []
 	}
 
 	var findings []types.DiagnosticFinding
+	tracesPipeline := signalPipeline(input, "traces")
+	logsPipeline := signalPipeline(input, "logs")
 
 	// Check span attributes
 	for _, span := range input.Signals.Traces {
 		for key, value := range span.Attributes {
 			if len(value) > bloatedAttrThreshold {
 				findings = append(findings, types.DiagnosticFinding{
+					RuleID:      types.RuleRuntimeBloatedAttribute,
 					Severity:    "warning",
 					Category:    "bloated_attrs",
 					Summary:     fmt.Sprintf("Bloated attribute '%s' on span '%s' (~%d bytes)", key, span.Name, len(value)),
 					Remediation: "Use a transform processor to truncate or remove this attribute.",
+					Evidence: &types.Evidence{
+						SignalType:   "traces",
+						Name:         span.Name,
+						AttributeKey: key,
+						Count:        len(value),
+						Pipeline:     tracesPipeline,
+					},
 				})
 			}
 		}
@@ -36,10 +46,17 @@ func AnalyzeBloatedAttributes(_ context.Context, input *RuntimeAnalysisInput) []
 		for key, value := range log.Attributes {
 			if len(value) > bloatedAttrThreshold {
 				findings = append(findings, types.DiagnosticFinding{
+					RuleID:      types.RuleRuntimeBloatedAttribute,
 					Severity:    "warning",
 					Category:    "bloated_attrs",
 					Summary:     fmt.Sprintf("Bloated attribute '%s' on log record (~%d bytes)", key, len(value)),
 					Remediation: "Use a transform processor to truncate or remove this attribute.",
+					Evidence: &types.Evidence{
+						SignalType:   "logs",
+						AttributeKey: key,
+						Count:        len(value),
+						Pipeline:     logsPipeline,
+					},
 				})
 			}
 		}
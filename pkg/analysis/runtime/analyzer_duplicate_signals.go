@@ -3,6 +3,7 @@ package runtime
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/hrexed/otel-collector-mcp/pkg/types"
@@ -21,19 +22,32 @@ func AnalyzeDuplicateSignals(_ context.Context, input *RuntimeAnalysisInput) []t
 	}
 
 	var findings []types.DiagnosticFinding
-	var duplicates []string
+	var names []string
 	for name, count := range metricCounts {
 		if count > 1 {
-			duplicates = append(duplicates, fmt.Sprintf("%s (%d points)", name, count))
+			names = append(names, name)
 		}
 	}
+	sort.Strings(names)
+	duplicates := make([]string, 0, len(names))
+	for _, name := range names {
+		duplicates = append(duplicates, fmt.Sprintf("%s (%d points)", name, metricCounts[name]))
+	}
 
 	if len(duplicates) > 10 {
 		findings = append(findings, types.DiagnosticFinding{
+			RuleID:      types.RuleRuntimeDuplicateSignals,
 			Severity:    "info",
 			Category:    "duplicates",
 			Summary:     fmt.Sprintf("Found %d metrics with multiple data points: %s", len(duplicates), strings.Join(duplicates[:5], ", ")),
 			Remediation: "Review for duplicate collection. Consider using a filter processor to deduplicate.",
+			Evidence: &types.Evidence{
+				SignalType: "metrics",
+				Name:       names[0],
+				Names:      names,
+				Count:      len(names),
+				Pipeline:   signalPipeline(input, "metrics"),
+			},
 		})
 	}
 
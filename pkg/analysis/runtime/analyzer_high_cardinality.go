@@ -11,6 +11,9 @@ import (
 
 const cardinalityThreshold = 100
 
+// maxSampleValues caps the sample values carried in evidence.
+const maxSampleValues = 5
+
 // AnalyzeHighCardinality detects metrics with high-cardinality label dimensions.
 func AnalyzeHighCardinality(_ context.Context, input *RuntimeAnalysisInput) []types.DiagnosticFinding {
 	if input.Signals == nil || len(input.Signals.Metrics) == 0 {
@@ -20,7 +23,7 @@ func AnalyzeHighCardinality(_ context.Context, input *RuntimeAnalysisInput) []ty
 	// Group by metric name, collect unique label combinations
 	type metricStats struct {
 		uniqueCombos map[string]struct{}
-		labelKeys    map[string]struct{}
+		labelValues  map[string]map[string]struct{}
 	}
 
 	stats := make(map[string]*metricStats)
@@ -29,7 +32,7 @@ func AnalyzeHighCardinality(_ context.Context, input *RuntimeAnalysisInput) []ty
 		if !ok {
 			ms = &metricStats{
 				uniqueCombos: make(map[string]struct{}),
-				labelKeys:    make(map[string]struct{}),
+				labelValues:  make(map[string]map[string]struct{}),
 			}
 			stats[dp.Name] = ms
 		}
@@ -37,7 +40,10 @@ func AnalyzeHighCardinality(_ context.Context, input *RuntimeAnalysisInput) []ty
 		// Build combo key from sorted labels
 		var parts []string
 		for k, v := range dp.Labels {
-			ms.labelKeys[k] = struct{}{}
+			if ms.labelValues[k] == nil {
+				ms.labelValues[k] = make(map[string]struct{})
+			}
+			ms.labelValues[k][v] = struct{}{}
 			parts = append(parts, k+"="+v)
 		}
 		sort.Strings(parts)
@@ -45,20 +51,47 @@ func AnalyzeHighCardinality(_ context.Context, input *RuntimeAnalysisInput) []ty
 		ms.uniqueCombos[combo] = struct{}{}
 	}
 
+	pipeline := signalPipeline(input, "metrics")
 	var findings []types.DiagnosticFinding
 	for name, ms := range stats {
 		if len(ms.uniqueCombos) > cardinalityThreshold {
 			var keys []string
-			for k := range ms.labelKeys {
+			for k := range ms.labelValues {
 				keys = append(keys, k)
 			}
 			sort.Strings(keys)
 
+			// The label with the most distinct values drives the explosion.
+			top := ""
+			for _, k := range keys {
+				if top == "" || len(ms.labelValues[k]) > len(ms.labelValues[top]) {
+					top = k
+				}
+			}
+			var samples []string
+			for v := range ms.labelValues[top] {
+				samples = append(samples, v)
+			}
+			sort.Strings(samples)
+			if len(samples) > maxSampleValues {
+				samples = samples[:maxSampleValues]
+			}
+
 			findings = append(findings, types.DiagnosticFinding{
+				RuleID:      types.RuleRuntimeHighCardinality,
 				Severity:    "warning",
 				Category:    "cardinality",
 				Summary:     fmt.Sprintf("High-cardinality metric: %s (%d unique combinations)", name, len(ms.uniqueCombos)),
-				Remediation: fmt.Sprintf("Review label keys [%s] for unbounded values. Consider using OTTL to drop or aggregate high-cardinality dimensions.", strings.Join(keys, ", ")),
+				Remediation: fmt.Sprintf("Review label keys [%s] for unbounded values; %q alone has %d distinct values. Consider using OTTL to drop or aggregate high-cardinality dimensions.", strings.Join(keys, ", "), top, len(ms.labelValues[top])),
+				Evidence: &types.Evidence{
+					SignalType:    "metrics",
+					Name:          name,
+					AttributeKey:  top,
+					AttributeKeys: keys,
+					SampleValues:  samples,
+					Count:         len(ms.uniqueCombos),
+					Pipeline:      pipeline,
+				},
 			})
 		}
 	}
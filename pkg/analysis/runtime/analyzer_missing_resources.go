@@ -29,26 +29,40 @@ func AnalyzeMissingResources(_ context.Context, input *RuntimeAnalysisInput) []t
 
 	var findings []types.DiagnosticFinding
 	var missing []string
+	var missingKeys []string
 
 	for _, attr := range requiredResourceAttrs {
 		values, exists := seen[attr]
 		if !exists {
 			missing = append(missing, attr)
+			missingKeys = append(missingKeys, attr)
 			continue
 		}
+		invalid := false
 		for v := range values {
 			if v == "" || v == "unknown" {
 				missing = append(missing, attr+" (set to '"+v+"')")
+				invalid = true
 			}
 		}
+		if invalid {
+			missingKeys = append(missingKeys, attr)
+		}
 	}
 
 	if len(missing) > 0 {
 		findings = append(findings, types.DiagnosticFinding{
+			RuleID:      types.RuleRuntimeMissingResource,
 			Severity:    "warning",
 			Category:    "missing_resource",
 			Summary:     fmt.Sprintf("Missing or invalid resource attributes: %s", strings.Join(missing, ", ")),
 			Remediation: "Add a resource processor to set these attributes.",
+			Evidence: &types.Evidence{
+				SignalType:    "logs",
+				AttributeKeys: missingKeys,
+				Count:         len(input.Signals.Logs),
+				Pipeline:      signalPipeline(input, "logs"),
+			},
 		})
 	}
 
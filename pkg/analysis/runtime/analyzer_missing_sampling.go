@@ -19,10 +19,15 @@ func AnalyzeMissingSampling(_ context.Context, input *RuntimeAnalysisInput) []ty
 	if !hasSampling {
 		return []types.DiagnosticFinding{
 			{
+				RuleID:      types.RuleRuntimeMissingSampling,
 				Severity:    "info",
 				Category:    "sampling",
 				Summary:     "No sampling processor configured",
 				Remediation: "Consider adding probabilistic_sampler or tail_sampling to reduce trace volume if not intentional.",
+				Evidence: &types.Evidence{
+					SignalType: "traces",
+					Pipeline:   signalPipeline(input, "traces"),
+				},
 			},
 		}
 	}
@@ -28,10 +28,16 @@ func AnalyzeOrphanSpans(_ context.Context, input *RuntimeAnalysisInput) []types.
 	for _, span := range input.Signals.Traces {
 		if !hasParent[span.SpanID] && !hasChildren[span.SpanID] {
 			findings = append(findings, types.DiagnosticFinding{
+				RuleID:      types.RuleRuntimeOrphanSpan,
 				Severity:    "warning",
 				Category:    "orphan_spans",
 				Summary:     fmt.Sprintf("Orphan span detected: %s (no parent or children)", span.Name),
 				Remediation: "Check instrumentation for missing context propagation.",
+				Evidence: &types.Evidence{
+					SignalType:   "traces",
+					Name:         span.Name,
+					SampleValues: []string{span.TraceID},
+				},
 			})
 		}
 	}
@@ -31,6 +31,8 @@ func AnalyzePII(_ context.Context, input *RuntimeAnalysisInput) []types.Diagnost
 	}
 
 	var findings []types.DiagnosticFinding
+	logsPipeline := signalPipeline(input, "logs")
+	tracesPipeline := signalPipeline(input, "traces")
 
 	// Check log attributes
 	for _, log := range input.Signals.Logs {
@@ -40,10 +42,18 @@ func AnalyzePII(_ context.Context, input *RuntimeAnalysisInput) []types.Diagnost
 			}
 			if piiType := detectPII(value); piiType != "" {
 				findings = append(findings, types.DiagnosticFinding{
+					RuleID:      types.RuleRuntimePII,
 					Severity:    "warning",
 					Category:    "pii",
 					Summary:     fmt.Sprintf("Potential %s detected in log attribute '%s'", piiType, key),
 					Remediation: "Add a filter or transform processor to redact this attribute before export.",
+					Evidence: &types.Evidence{
+						SignalType:   "logs",
+						Kind:         piiType,
+						AttributeKey: key,
+						SampleValues: []string{maskValue(value)},
+						Pipeline:     logsPipeline,
+					},
 				})
 			}
 		}
@@ -57,10 +67,19 @@ func AnalyzePII(_ context.Context, input *RuntimeAnalysisInput) []types.Diagnost
 			}
 			if piiType := detectPII(value); piiType != "" {
 				findings = append(findings, types.DiagnosticFinding{
+					RuleID:      types.RuleRuntimePII,
 					Severity:    "warning",
 					Category:    "pii",
 					Summary:     fmt.Sprintf("Potential %s detected in span attribute '%s'", piiType, key),
 					Remediation: "Add a transform processor to redact this attribute.",
+					Evidence: &types.Evidence{
+						SignalType:   "traces",
+						Name:         span.Name,
+						Kind:         piiType,
+						AttributeKey: key,
+						SampleValues: []string{maskValue(value)},
+						Pipeline:     tracesPipeline,
+					},
 				})
 			}
 		}
@@ -84,3 +103,13 @@ func detectPII(value string) string {
 	}
 	return ""
 }
+
+// maskValue keeps the first two characters of a detected value so evidence
+// shows its shape without carrying the personal data itself.
+func maskValue(value string) string {
+	runes := []rune(value)
+	if len(runes) <= 2 {
+		return "***"
+	}
+	return string(runes[:2]) + "***"
+}
@@ -26,10 +26,12 @@ func AnalyzeResourceSizing(_ context.Context, input *RuntimeAnalysisInput) []typ
 	// High throughput warning (>10k points/sec suggests potential resource pressure)
 	if throughput > 10000 {
 		findings = append(findings, types.DiagnosticFinding{
+			RuleID:      types.RuleRuntimeHighThroughput,
 			Severity:    "warning",
 			Category:    "sizing",
 			Summary:     fmt.Sprintf("High signal throughput: %.0f data points/sec observed", throughput),
 			Remediation: "Review collector CPU/memory limits. Consider scaling or adding sampling.",
+			Evidence:    &types.Evidence{Count: int(throughput)},
 		})
 	}
 
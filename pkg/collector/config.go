@@ -8,6 +8,7 @@ type CollectorConfig struct {
 	Processors map[string]interface{} `yaml:"processors"`
 	Exporters  map[string]interface{} `yaml:"exporters"`
 	Connectors map[string]interface{} `yaml:"connectors"`
+	Extensions map[string]interface{} `yaml:"extensions"`
 	Service    ServiceConfig          `yaml:"service"`
 }
 
@@ -15,6 +16,27 @@ type CollectorConfig struct {
 type ServiceConfig struct {
 	Pipelines  map[string]PipelineConfig `yaml:"pipelines"`
 	Extensions []string                  `yaml:"extensions,omitempty"`
+	Telemetry  TelemetryConfig           `yaml:"telemetry,omitempty"`
+}
+
+// TelemetryConfig holds the collector's own telemetry settings (service.telemetry).
+type TelemetryConfig struct {
+	Logs    TelemetryLogsConfig    `yaml:"logs,omitempty"`
+	Metrics TelemetryMetricsConfig `yaml:"metrics,omitempty"`
+}
+
+// TelemetryLogsConfig holds service.telemetry.logs.
+type TelemetryLogsConfig struct {
+	Level    string `yaml:"level,omitempty"`
+	Encoding string `yaml:"encoding,omitempty"`
+}
+
+// TelemetryMetricsConfig holds service.telemetry.metrics. Address is the legacy
+// listen address; newer collectors configure Readers instead.
+type TelemetryMetricsConfig struct {
+	Level   string                   `yaml:"level,omitempty"`
+	Address string                   `yaml:"address,omitempty"`
+	Readers []map[string]interface{} `yaml:"readers,omitempty"`
 }
 
 // PipelineConfig represents a single pipeline within the service config.
@@ -61,3 +61,41 @@ func TestParseConfigInvalid(t *testing.T) {
 		t.Error("expected error for invalid YAML")
 	}
 }
+
+func TestParseConfigTelemetry(t *testing.T) {
+	yamlData := `
+extensions:
+  health_check: {}
+service:
+  extensions: [health_check]
+  telemetry:
+    logs:
+      level: debug
+    metrics:
+      level: detailed
+      readers:
+        - pull:
+            exporter:
+              prometheus:
+                host: 0.0.0.0
+                port: 8888
+  pipelines: {}
+`
+	cfg, err := ParseConfig([]byte(yamlData))
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+
+	if len(cfg.Extensions) != 1 {
+		t.Errorf("expected 1 extension, got %d", len(cfg.Extensions))
+	}
+	if cfg.Service.Telemetry.Logs.Level != "debug" {
+		t.Errorf("expected logs level debug, got %q", cfg.Service.Telemetry.Logs.Level)
+	}
+	if cfg.Service.Telemetry.Metrics.Level != "detailed" {
+		t.Errorf("expected metrics level detailed, got %q", cfg.Service.Telemetry.Metrics.Level)
+	}
+	if len(cfg.Service.Telemetry.Metrics.Readers) != 1 {
+		t.Errorf("expected 1 metrics reader, got %d", len(cfg.Service.Telemetry.Metrics.Readers))
+	}
+}
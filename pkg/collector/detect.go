@@ -60,6 +60,24 @@ func DetectDeploymentMode(ctx context.Context, clientset kubernetes.Interface, n
 	return ModeUnknown, fmt.Errorf("no workload found for %s/%s", namespace, name)
 }
 
+// WorkloadReplicas returns the desired replica count of a Deployment or
+// StatefulSet. DaemonSets have no replica count and return 0.
+func WorkloadReplicas(ctx context.Context, clientset kubernetes.Interface, namespace, name string) (int32, error) {
+	if dep, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{}); err == nil {
+		if dep.Spec.Replicas == nil {
+			return 1, nil
+		}
+		return *dep.Spec.Replicas, nil
+	}
+	if ss, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{}); err == nil {
+		if ss.Spec.Replicas == nil {
+			return 1, nil
+		}
+		return *ss.Spec.Replicas, nil
+	}
+	return 0, fmt.Errorf("no Deployment or StatefulSet found for %s/%s", namespace, name)
+}
+
 // DetectDeploymentModeWithCRD checks both standard workloads and OTel Operator CRDs.
 func DetectDeploymentModeWithCRD(ctx context.Context, clientset kubernetes.Interface, dynClient dynamic.Interface, namespace, name string, hasOperator bool) (DeploymentMode, error) {
 	// Check standard workloads first
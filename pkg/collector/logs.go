@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -43,7 +44,19 @@ func FetchPodLogs(ctx context.Context, clientset kubernetes.Interface, namespace
 	opts := &corev1.PodLogOptions{
 		TailLines: &tailLines,
 	}
+	return streamPodLogs(ctx, clientset, namespace, podName, opts)
+}
 
+// FetchPodLogsSince retrieves the log lines a pod wrote since the given time.
+func FetchPodLogsSince(ctx context.Context, clientset kubernetes.Interface, namespace, podName string, since time.Time) ([]string, error) {
+	sinceTime := metav1.NewTime(since)
+	opts := &corev1.PodLogOptions{
+		SinceTime: &sinceTime,
+	}
+	return streamPodLogs(ctx, clientset, namespace, podName, opts)
+}
+
+func streamPodLogs(ctx context.Context, clientset kubernetes.Interface, namespace, podName string, opts *corev1.PodLogOptions) ([]string, error) {
 	req := clientset.CoreV1().Pods(namespace).GetLogs(podName, opts)
 	stream, err := req.Stream(ctx)
 	if err != nil {
@@ -184,8 +197,9 @@ func GetCollectorConfig(ctx context.Context, clientset kubernetes.Interface, nam
 	return nil, fmt.Errorf("no configuration data found in configmap %s/%s", namespace, configMapName)
 }
 
-// GetConfigFromCRD reads .spec.config from an OpenTelemetryCollector CR.
-func GetConfigFromCRD(ctx context.Context, dynClient dynamic.Interface, namespace, name string) ([]byte, error) {
+// GetCollectorCR returns an OpenTelemetryCollector CR as an unstructured object,
+// trying v1beta1 before v1alpha1.
+func GetCollectorCR(ctx context.Context, dynClient dynamic.Interface, namespace, name string) (map[string]interface{}, error) {
 	gvr := schema.GroupVersionResource{
 		Group:    "opentelemetry.io",
 		Version:  "v1beta1",
@@ -200,8 +214,17 @@ func GetConfigFromCRD(ctx context.Context, dynClient dynamic.Interface, namespac
 			return nil, fmt.Errorf("OpenTelemetryCollector %s/%s not found: %w", namespace, name, err)
 		}
 	}
+	return obj.Object, nil
+}
+
+// GetConfigFromCRD reads .spec.config from an OpenTelemetryCollector CR.
+func GetConfigFromCRD(ctx context.Context, dynClient dynamic.Interface, namespace, name string) ([]byte, error) {
+	obj, err := GetCollectorCR(ctx, dynClient, namespace, name)
+	if err != nil {
+		return nil, err
+	}
 
-	spec, ok := obj.Object["spec"].(map[string]interface{})
+	spec, ok := obj["spec"].(map[string]interface{})
 	if !ok {
 		return nil, fmt.Errorf("OpenTelemetryCollector %s/%s has no spec", namespace, name)
 	}
@@ -0,0 +1,113 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// FindCollectorPod returns a running pod of the named collector workload.
+// When podName is set the pod is fetched directly. Otherwise the selector of the
+// matching DaemonSet, Deployment or StatefulSet is used, falling back to the
+// app.kubernetes.io/instance label.
+func FindCollectorPod(ctx context.Context, clientset kubernetes.Interface, namespace, name, podName string) (*corev1.Pod, error) {
+	if podName != "" {
+		pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod %s/%s: %w", namespace, podName, err)
+		}
+		return pod, nil
+	}
+
+	selector := workloadSelector(ctx, clientset, namespace, name)
+	if selector == "" {
+		selector = fmt.Sprintf("app.kubernetes.io/instance=%s", name)
+	}
+
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods for %s/%s: %w", namespace, name, err)
+	}
+	if len(pods.Items) == 0 {
+		return nil, fmt.Errorf("no pods found for collector %s/%s", namespace, name)
+	}
+
+	// Prefer a running pod so container statuses are populated
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase == corev1.PodRunning {
+			return &pods.Items[i], nil
+		}
+	}
+	return &pods.Items[0], nil
+}
+
+// workloadSelector returns the pod label selector of the named workload, or ""
+// if no workload with that name exists.
+func workloadSelector(ctx context.Context, clientset kubernetes.Interface, namespace, name string) string {
+	var sel *metav1.LabelSelector
+	if ds, err := clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{}); err == nil {
+		sel = ds.Spec.Selector
+	} else if dep, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{}); err == nil {
+		sel = dep.Spec.Selector
+	} else if ss, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{}); err == nil {
+		sel = ss.Spec.Selector
+	}
+	if sel == nil {
+		return ""
+	}
+	selector, err := metav1.LabelSelectorAsSelector(sel)
+	if err != nil || selector.Empty() {
+		return ""
+	}
+	return selector.String()
+}
+
+// PodConfig returns the collector configuration mounted into a pod from a
+// ConfigMap volume. The first ConfigMap holding a config with pipelines wins.
+func PodConfig(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod) (*CollectorConfig, error) {
+	_, cfg, err := podConfigMap(ctx, clientset, pod)
+	return cfg, err
+}
+
+// PodConfigMap returns the name of the ConfigMap PodConfig reads a pod's
+// collector configuration from.
+func PodConfigMap(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod) (string, error) {
+	name, _, err := podConfigMap(ctx, clientset, pod)
+	return name, err
+}
+
+func podConfigMap(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod) (string, *CollectorConfig, error) {
+	for _, v := range pod.Spec.Volumes {
+		if v.ConfigMap == nil {
+			continue
+		}
+		data, err := GetCollectorConfig(ctx, clientset, pod.Namespace, v.ConfigMap.Name)
+		if err != nil {
+			continue
+		}
+		cfg, err := ParseConfig(data)
+		if err != nil || len(cfg.Service.Pipelines) == 0 {
+			continue
+		}
+		return v.ConfigMap.Name, cfg, nil
+	}
+	return "", nil, fmt.Errorf("no collector configuration mounted in pod %s/%s", pod.Namespace, pod.Name)
+}
+
+// OperatorCRName returns the name of the OpenTelemetryCollector CR that manages
+// a pod, read from the operator's app.kubernetes.io/instance label
+// (<namespace>.<name>), or "" when the pod is not operator-managed.
+func OperatorCRName(pod *corev1.Pod) string {
+	if pod == nil || pod.Labels["app.kubernetes.io/managed-by"] != "opentelemetry-operator" {
+		return ""
+	}
+	instance := pod.Labels["app.kubernetes.io/instance"]
+	if ns, name, ok := strings.Cut(instance, "."); ok && ns == pod.Namespace {
+		return name
+	}
+	return ""
+}
@@ -19,6 +19,39 @@ type Config struct {
 	V2Enabled             bool
 	SessionTTL            time.Duration
 	MaxConcurrentSessions int
+
+	// Pre-apply config validation. ValidatorBinary is a local otelcol or
+	// otelcol-contrib binary; ValidatorJob runs validation in a Job using
+	// the collector's own image. The binary takes precedence.
+	ValidatorBinary  string
+	ValidatorJob     bool
+	ValidatorTimeout time.Duration
+
+	// Post-apply telemetry gate: the collector's internal metrics are
+	// compared before and after a mutation, which is rolled back when they
+	// regress beyond these thresholds.
+	TelemetryGate                bool
+	TelemetryWindow              time.Duration
+	TelemetryMaxFailureIncrease  float64
+	TelemetryMaxRefusalIncrease  float64
+	TelemetryMaxQueueUtilization float64
+
+	// Canary stage: DaemonSet and multi-replica collectors run a mutation on
+	// one canary pod before it reaches every pod.
+	Canary        bool
+	CanaryTimeout time.Duration
+
+	// HistoryRevisions bounds the revision history kept per collector; 0
+	// disables it.
+	HistoryRevisions int
+
+	// GitOps patch mode: fixes to GitOps-managed collectors are committed to
+	// a branch of the local Git working tree at GitOpsRepo instead of being
+	// applied to the cluster. Argo CD Applications are looked up in
+	// ArgoCDNamespace when the collector's tracking ID does not name one.
+	GitOpsRepo         string
+	GitOpsBranchPrefix string
+	ArgoCDNamespace    string
 }
 
 // NewFromEnv creates a Config by reading environment variables with defaults.
@@ -80,6 +113,86 @@ func NewFromEnv() *Config {
 		}
 	}
 
+	validatorJob := false
+	if v := os.Getenv("V2_VALIDATOR_JOB"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			slog.Warn("invalid V2_VALIDATOR_JOB value, defaulting to false")
+		} else {
+			validatorJob = parsed
+		}
+	}
+
+	validatorTimeout := 60 * time.Second
+	if v := os.Getenv("V2_VALIDATOR_TIMEOUT"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			slog.Warn("invalid V2_VALIDATOR_TIMEOUT value, defaulting to 60s")
+		} else {
+			validatorTimeout = parsed
+		}
+	}
+
+	telemetryGate := false
+	if v := os.Getenv("V2_TELEMETRY_GATE"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			slog.Warn("invalid V2_TELEMETRY_GATE value, defaulting to false")
+		} else {
+			telemetryGate = parsed
+		}
+	}
+
+	telemetryWindow := 30 * time.Second
+	if v := os.Getenv("V2_TELEMETRY_WINDOW"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			slog.Warn("invalid V2_TELEMETRY_WINDOW value, defaulting to 30s")
+		} else {
+			telemetryWindow = parsed
+		}
+	}
+
+	canary := false
+	if v := os.Getenv("V2_CANARY"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			slog.Warn("invalid V2_CANARY value, defaulting to false")
+		} else {
+			canary = parsed
+		}
+	}
+
+	canaryTimeout := 60 * time.Second
+	if v := os.Getenv("V2_CANARY_TIMEOUT"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			slog.Warn("invalid V2_CANARY_TIMEOUT value, defaulting to 60s")
+		} else {
+			canaryTimeout = parsed
+		}
+	}
+
+	historyRevisions := 10
+	if v := os.Getenv("V2_HISTORY_REVISIONS"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			slog.Warn("invalid V2_HISTORY_REVISIONS value, defaulting to 10")
+		} else {
+			historyRevisions = parsed
+		}
+	}
+
+	gitOpsBranchPrefix := "otel-collector-mcp/"
+	if v := os.Getenv("V2_GITOPS_BRANCH_PREFIX"); v != "" {
+		gitOpsBranchPrefix = v
+	}
+
+	argoCDNamespace := "argocd"
+	if v := os.Getenv("V2_ARGOCD_NAMESPACE"); v != "" {
+		argoCDNamespace = v
+	}
+
 	return &Config{
 		Port:                  port,
 		LogLevel:              logLevel,
@@ -89,7 +202,39 @@ func NewFromEnv() *Config {
 		V2Enabled:             v2Enabled,
 		SessionTTL:            sessionTTL,
 		MaxConcurrentSessions: maxSessions,
+		ValidatorBinary:       os.Getenv("V2_VALIDATOR_BINARY"),
+		ValidatorJob:          validatorJob,
+		ValidatorTimeout:      validatorTimeout,
+
+		TelemetryGate:                telemetryGate,
+		TelemetryWindow:              telemetryWindow,
+		TelemetryMaxFailureIncrease:  ratioFromEnv("V2_TELEMETRY_MAX_FAILURE_INCREASE", 0.05),
+		TelemetryMaxRefusalIncrease:  ratioFromEnv("V2_TELEMETRY_MAX_REFUSAL_INCREASE", 0.05),
+		TelemetryMaxQueueUtilization: ratioFromEnv("V2_TELEMETRY_MAX_QUEUE_UTILIZATION", 0.8),
+
+		Canary:        canary,
+		CanaryTimeout: canaryTimeout,
+
+		HistoryRevisions: historyRevisions,
+
+		GitOpsRepo:         os.Getenv("V2_GITOPS_REPO"),
+		GitOpsBranchPrefix: gitOpsBranchPrefix,
+		ArgoCDNamespace:    argoCDNamespace,
+	}
+}
+
+// ratioFromEnv reads a ratio between 0 and 1 from an environment variable.
+func ratioFromEnv(name string, def float64) float64 {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	parsed, err := strconv.ParseFloat(v, 64)
+	if err != nil || parsed < 0 || parsed > 1 {
+		slog.Warn("invalid ratio value, using default", "variable", name, "default", def)
+		return def
 	}
+	return parsed
 }
 
 // SetupLogging configures slog with a JSON handler at the configured log level.
@@ -16,6 +16,20 @@ func TestNewFromEnvDefaults(t *testing.T) {
 	t.Setenv("V2_ENABLED", "")
 	t.Setenv("V2_SESSION_TTL", "")
 	t.Setenv("V2_MAX_SESSIONS", "")
+	t.Setenv("V2_VALIDATOR_BINARY", "")
+	t.Setenv("V2_VALIDATOR_JOB", "")
+	t.Setenv("V2_VALIDATOR_TIMEOUT", "")
+	t.Setenv("V2_TELEMETRY_GATE", "")
+	t.Setenv("V2_TELEMETRY_WINDOW", "")
+	t.Setenv("V2_TELEMETRY_MAX_FAILURE_INCREASE", "")
+	t.Setenv("V2_TELEMETRY_MAX_REFUSAL_INCREASE", "")
+	t.Setenv("V2_TELEMETRY_MAX_QUEUE_UTILIZATION", "")
+	t.Setenv("V2_CANARY", "")
+	t.Setenv("V2_CANARY_TIMEOUT", "")
+	t.Setenv("V2_HISTORY_REVISIONS", "")
+	t.Setenv("V2_GITOPS_REPO", "")
+	t.Setenv("V2_GITOPS_BRANCH_PREFIX", "")
+	t.Setenv("V2_ARGOCD_NAMESPACE", "")
 
 	cfg := NewFromEnv()
 
@@ -40,6 +54,43 @@ func TestNewFromEnvDefaults(t *testing.T) {
 	if cfg.MaxConcurrentSessions != 5 {
 		t.Errorf("expected default MaxConcurrentSessions 5, got %d", cfg.MaxConcurrentSessions)
 	}
+	if cfg.ValidatorBinary != "" || cfg.ValidatorJob {
+		t.Errorf("expected no validator by default")
+	}
+	if cfg.ValidatorTimeout != 60*time.Second {
+		t.Errorf("expected default ValidatorTimeout 60s, got %v", cfg.ValidatorTimeout)
+	}
+	if cfg.TelemetryGate {
+		t.Errorf("expected telemetry gate disabled by default")
+	}
+	if cfg.TelemetryWindow != 30*time.Second {
+		t.Errorf("expected default TelemetryWindow 30s, got %v", cfg.TelemetryWindow)
+	}
+	if cfg.TelemetryMaxFailureIncrease != 0.05 || cfg.TelemetryMaxRefusalIncrease != 0.05 {
+		t.Errorf("expected default failure and refusal increase 0.05, got %v and %v",
+			cfg.TelemetryMaxFailureIncrease, cfg.TelemetryMaxRefusalIncrease)
+	}
+	if cfg.TelemetryMaxQueueUtilization != 0.8 {
+		t.Errorf("expected default TelemetryMaxQueueUtilization 0.8, got %v", cfg.TelemetryMaxQueueUtilization)
+	}
+	if cfg.Canary {
+		t.Errorf("expected canary disabled by default")
+	}
+	if cfg.CanaryTimeout != 60*time.Second {
+		t.Errorf("expected default CanaryTimeout 60s, got %v", cfg.CanaryTimeout)
+	}
+	if cfg.HistoryRevisions != 10 {
+		t.Errorf("expected default HistoryRevisions 10, got %d", cfg.HistoryRevisions)
+	}
+	if cfg.GitOpsRepo != "" {
+		t.Errorf("expected GitOps patch mode disabled by default")
+	}
+	if cfg.GitOpsBranchPrefix != "otel-collector-mcp/" {
+		t.Errorf("expected default GitOpsBranchPrefix otel-collector-mcp/, got %s", cfg.GitOpsBranchPrefix)
+	}
+	if cfg.ArgoCDNamespace != "argocd" {
+		t.Errorf("expected default ArgoCDNamespace argocd, got %s", cfg.ArgoCDNamespace)
+	}
 }
 
 func TestNewFromEnvOverrides(t *testing.T) {
@@ -86,6 +137,86 @@ func TestNewFromEnvV2Custom(t *testing.T) {
 	}
 }
 
+func TestNewFromEnvValidator(t *testing.T) {
+	t.Setenv("V2_VALIDATOR_BINARY", "/usr/local/bin/otelcol-contrib")
+	t.Setenv("V2_VALIDATOR_JOB", "true")
+	t.Setenv("V2_VALIDATOR_TIMEOUT", "2m")
+
+	cfg := NewFromEnv()
+
+	if cfg.ValidatorBinary != "/usr/local/bin/otelcol-contrib" {
+		t.Errorf("expected ValidatorBinary=/usr/local/bin/otelcol-contrib, got %s", cfg.ValidatorBinary)
+	}
+	if !cfg.ValidatorJob {
+		t.Errorf("expected ValidatorJob=true")
+	}
+	if cfg.ValidatorTimeout != 2*time.Minute {
+		t.Errorf("expected ValidatorTimeout=2m, got %v", cfg.ValidatorTimeout)
+	}
+}
+
+func TestNewFromEnvTelemetryGate(t *testing.T) {
+	t.Setenv("V2_TELEMETRY_GATE", "true")
+	t.Setenv("V2_TELEMETRY_WINDOW", "1m")
+	t.Setenv("V2_TELEMETRY_MAX_FAILURE_INCREASE", "0.1")
+	t.Setenv("V2_TELEMETRY_MAX_REFUSAL_INCREASE", "2")
+	t.Setenv("V2_TELEMETRY_MAX_QUEUE_UTILIZATION", "0.5")
+
+	cfg := NewFromEnv()
+
+	if !cfg.TelemetryGate {
+		t.Errorf("expected TelemetryGate=true")
+	}
+	if cfg.TelemetryWindow != time.Minute {
+		t.Errorf("expected TelemetryWindow=1m, got %v", cfg.TelemetryWindow)
+	}
+	if cfg.TelemetryMaxFailureIncrease != 0.1 {
+		t.Errorf("expected TelemetryMaxFailureIncrease=0.1, got %v", cfg.TelemetryMaxFailureIncrease)
+	}
+	if cfg.TelemetryMaxRefusalIncrease != 0.05 {
+		t.Errorf("expected default TelemetryMaxRefusalIncrease=0.05 for an out of range ratio, got %v", cfg.TelemetryMaxRefusalIncrease)
+	}
+	if cfg.TelemetryMaxQueueUtilization != 0.5 {
+		t.Errorf("expected TelemetryMaxQueueUtilization=0.5, got %v", cfg.TelemetryMaxQueueUtilization)
+	}
+}
+
+func TestNewFromEnvCanary(t *testing.T) {
+	t.Setenv("V2_CANARY", "true")
+	t.Setenv("V2_CANARY_TIMEOUT", "2m")
+	t.Setenv("V2_HISTORY_REVISIONS", "0")
+
+	cfg := NewFromEnv()
+
+	if !cfg.Canary {
+		t.Errorf("expected Canary=true")
+	}
+	if cfg.CanaryTimeout != 2*time.Minute {
+		t.Errorf("expected CanaryTimeout=2m, got %v", cfg.CanaryTimeout)
+	}
+	if cfg.HistoryRevisions != 0 {
+		t.Errorf("expected HistoryRevisions=0, got %d", cfg.HistoryRevisions)
+	}
+}
+
+func TestNewFromEnvGitOps(t *testing.T) {
+	t.Setenv("V2_GITOPS_REPO", "/var/lib/gitops")
+	t.Setenv("V2_GITOPS_BRANCH_PREFIX", "fix/")
+	t.Setenv("V2_ARGOCD_NAMESPACE", "gitops")
+
+	cfg := NewFromEnv()
+
+	if cfg.GitOpsRepo != "/var/lib/gitops" {
+		t.Errorf("expected GitOpsRepo=/var/lib/gitops, got %s", cfg.GitOpsRepo)
+	}
+	if cfg.GitOpsBranchPrefix != "fix/" {
+		t.Errorf("expected GitOpsBranchPrefix=fix/, got %s", cfg.GitOpsBranchPrefix)
+	}
+	if cfg.ArgoCDNamespace != "gitops" {
+		t.Errorf("expected ArgoCDNamespace=gitops, got %s", cfg.ArgoCDNamespace)
+	}
+}
+
 func TestNewFromEnvV2InvalidValues(t *testing.T) {
 	t.Setenv("V2_ENABLED", "notabool")
 	t.Setenv("V2_SESSION_TTL", "invalid")
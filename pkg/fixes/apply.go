@@ -0,0 +1,166 @@
+package fixes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ApplyToConfig returns configYAML with the fix merged in: the fix's config
+// block is merged over the matching sections and its pipeline edits are
+// carried out. configYAML itself is not modified.
+func ApplyToConfig(configYAML string, fix FixSuggestion) (string, error) {
+	var config map[string]interface{}
+	if err := yaml.Unmarshal([]byte(configYAML), &config); err != nil {
+		return "", fmt.Errorf("failed to parse collector config: %w", err)
+	}
+	if config == nil {
+		config = make(map[string]interface{})
+	}
+
+	if strings.TrimSpace(fix.ProcessorConfig) != "" {
+		var block map[string]interface{}
+		if err := yaml.Unmarshal([]byte(fix.ProcessorConfig), &block); err != nil {
+			return "", fmt.Errorf("failed to parse fix config: %w", err)
+		}
+		mergeMaps(config, block)
+	}
+
+	if len(fix.Edits) > 0 {
+		service, _ := config["service"].(map[string]interface{})
+		pipelines, _ := service["pipelines"].(map[string]interface{})
+		if pipelines == nil {
+			return "", fmt.Errorf("no pipelines section found in service config")
+		}
+		for _, edit := range fix.Edits {
+			if err := applyEdit(pipelines, edit); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	out, err := marshalYAML(config)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return out, nil
+}
+
+// mergeMaps merges src into dst. Nested maps are merged key by key; any
+// other value in src replaces the one in dst.
+func mergeMaps(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, ok := value.(map[string]interface{})
+		if !ok {
+			dst[key] = value
+			continue
+		}
+		dstMap, ok := dst[key].(map[string]interface{})
+		if !ok {
+			dst[key] = srcMap
+			continue
+		}
+		mergeMaps(dstMap, srcMap)
+	}
+}
+
+func applyEdit(pipelines map[string]interface{}, edit PipelineEdit) error {
+	targets := editTargets(pipelines, edit)
+	if len(targets) == 0 {
+		if edit.Pipeline != "" {
+			return fmt.Errorf("pipeline %q not found", edit.Pipeline)
+		}
+		return fmt.Errorf("no %s pipeline found", edit.Signal)
+	}
+
+	for _, name := range targets {
+		pipeline, ok := pipelines[name].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("pipeline %q is not a map", name)
+		}
+		list := stringList(pipeline[edit.Section])
+		for _, id := range edit.Remove {
+			list = without(list, id)
+		}
+		if edit.Add != "" && !containsString(list, edit.Add) {
+			list = insertComponent(list, edit.Add, edit.Section, edit.Position)
+		}
+		items := make([]interface{}, len(list))
+		for i, id := range list {
+			items[i] = id
+		}
+		pipeline[edit.Section] = items
+	}
+	return nil
+}
+
+// editTargets returns the pipelines an edit applies to, sorted.
+func editTargets(pipelines map[string]interface{}, edit PipelineEdit) []string {
+	if edit.Pipeline != "" {
+		if _, ok := pipelines[edit.Pipeline]; !ok {
+			return nil
+		}
+		return []string{edit.Pipeline}
+	}
+	var targets []string
+	for name := range pipelines {
+		if strings.SplitN(name, "/", 2)[0] == edit.Signal {
+			targets = append(targets, name)
+		}
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// insertComponent adds id to list at the edit's position. Processors without
+// a position go after memory_limiter and before batch.
+func insertComponent(list []string, id, section, position string) []string {
+	at := len(list)
+	switch {
+	case position == PositionFirst:
+		at = 0
+	case position == PositionLast || section != "processors":
+	default:
+		start := 0
+		for i, p := range list {
+			if componentKind(p) == "memory_limiter" {
+				start = i + 1
+			}
+		}
+		for i := start; i < len(list); i++ {
+			if componentKind(list[i]) == "batch" {
+				at = i
+				break
+			}
+		}
+	}
+	out := make([]string, 0, len(list)+1)
+	out = append(out, list[:at]...)
+	out = append(out, id)
+	return append(out, list[at:]...)
+}
+
+func componentKind(id string) string {
+	return strings.SplitN(id, "/", 2)[0]
+}
+
+func without(list []string, id string) []string {
+	out := list[:0:0]
+	for _, s := range list {
+		if s != id {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func containsString(list []string, id string) bool {
+	for _, s := range list {
+		if s == id {
+			return true
+		}
+	}
+	return false
+}
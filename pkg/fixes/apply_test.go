@@ -0,0 +1,153 @@
+package fixes
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const applyBaseConfig = `receivers:
+  otlp: {}
+processors:
+  memory_limiter:
+    check_interval: 1s
+  batch:
+    timeout: 1s
+exporters:
+  otlp:
+    endpoint: gateway:4317
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      processors: [memory_limiter, batch]
+      exporters: [otlp]
+    traces/internal:
+      receivers: [otlp]
+      processors: [batch]
+      exporters: [otlp]
+    metrics:
+      receivers: [otlp]
+      processors: [memory_limiter]
+      exporters: [otlp]
+`
+
+type appliedConfig struct {
+	Processors map[string]map[string]interface{} `yaml:"processors"`
+	Exporters  map[string]map[string]interface{} `yaml:"exporters"`
+	Service    struct {
+		Pipelines map[string]map[string][]string `yaml:"pipelines"`
+	} `yaml:"service"`
+}
+
+func TestApplyToConfig(t *testing.T) {
+	tests := []struct {
+		name  string
+		fix   FixSuggestion
+		check func(t *testing.T, cfg appliedConfig)
+	}{
+		{
+			name: "config block merges into existing components",
+			fix: FixSuggestion{ProcessorConfig: `exporters:
+  otlp:
+    retry_on_failure:
+      enabled: true
+processors:
+  batch:
+    send_batch_size: 8192
+`},
+			check: func(t *testing.T, cfg appliedConfig) {
+				if cfg.Exporters["otlp"]["endpoint"] != "gateway:4317" || cfg.Exporters["otlp"]["retry_on_failure"] == nil {
+					t.Errorf("expected retry_on_failure next to the endpoint, got %v", cfg.Exporters["otlp"])
+				}
+				if cfg.Processors["batch"]["timeout"] != "1s" || cfg.Processors["batch"]["send_batch_size"] != 8192 {
+					t.Errorf("expected batch settings merged, got %v", cfg.Processors["batch"])
+				}
+			},
+		},
+		{
+			name: "processor without position goes between memory_limiter and batch",
+			fix: FixSuggestion{
+				ProcessorConfig: "processors:\n  transform/x: {}\n",
+				Edits:           []PipelineEdit{{Pipeline: "traces", Section: "processors", Add: "transform/x"}},
+			},
+			check: func(t *testing.T, cfg appliedConfig) {
+				expectList(t, cfg, "traces", "processors", "memory_limiter,transform/x,batch")
+				expectList(t, cfg, "traces/internal", "processors", "batch")
+			},
+		},
+		{
+			name: "first and last positions",
+			fix: FixSuggestion{Edits: []PipelineEdit{
+				{Pipeline: "metrics", Section: "processors", Add: "batch", Position: PositionLast},
+				{Pipeline: "traces/internal", Section: "processors", Add: "memory_limiter", Position: PositionFirst},
+			}},
+			check: func(t *testing.T, cfg appliedConfig) {
+				expectList(t, cfg, "metrics", "processors", "memory_limiter,batch")
+				expectList(t, cfg, "traces/internal", "processors", "memory_limiter,batch")
+			},
+		},
+		{
+			name: "signal edit applies to every pipeline of the signal",
+			fix:  FixSuggestion{Edits: []PipelineEdit{{Signal: "traces", Section: "exporters", Add: "debug"}}},
+			check: func(t *testing.T, cfg appliedConfig) {
+				expectList(t, cfg, "traces", "exporters", "otlp,debug")
+				expectList(t, cfg, "traces/internal", "exporters", "otlp,debug")
+				expectList(t, cfg, "metrics", "exporters", "otlp")
+			},
+		},
+		{
+			name: "remove and existing add",
+			fix: FixSuggestion{Edits: []PipelineEdit{
+				{Pipeline: "traces", Section: "processors", Remove: []string{"memory_limiter"}},
+				{Pipeline: "traces", Section: "processors", Add: "batch"},
+			}},
+			check: func(t *testing.T, cfg appliedConfig) {
+				expectList(t, cfg, "traces", "processors", "batch")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := ApplyToConfig(applyBaseConfig, tt.fix)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var cfg appliedConfig
+			if err := yaml.Unmarshal([]byte(out), &cfg); err != nil {
+				t.Fatalf("invalid output: %v\n%s", err, out)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestApplyToConfigErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		fix    FixSuggestion
+	}{
+		{name: "unknown pipeline", config: applyBaseConfig, fix: FixSuggestion{Edits: []PipelineEdit{{Pipeline: "logs", Section: "processors", Add: "batch"}}}},
+		{name: "no pipeline of the signal", config: applyBaseConfig, fix: FixSuggestion{Edits: []PipelineEdit{{Signal: "logs", Section: "processors", Add: "batch"}}}},
+		{name: "no pipelines section", config: "receivers:\n  otlp: {}\n", fix: FixSuggestion{Edits: []PipelineEdit{{Signal: "traces", Section: "processors", Add: "batch"}}}},
+		{name: "invalid fix config", config: applyBaseConfig, fix: FixSuggestion{ProcessorConfig: "processors: [unclosed"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ApplyToConfig(tt.config, tt.fix); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func expectList(t *testing.T, cfg appliedConfig, pipeline, section, want string) {
+	t.Helper()
+	if got := strings.Join(cfg.Service.Pipelines[pipeline][section], ","); got != want {
+		t.Errorf("pipeline %s %s: expected %s, got %s", pipeline, section, want, got)
+	}
+}
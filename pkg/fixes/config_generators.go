@@ -0,0 +1,334 @@
+package fixes
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+)
+
+// credentialsSecret is the Secret hardcoded credentials are moved to.
+const credentialsSecret = "otel-collector-credentials"
+
+// GenerateMissingBatchFix adds a batch processor at the end of the pipeline.
+func GenerateMissingBatchFix(finding types.DiagnosticFinding, index int) *FixSuggestion {
+	ev := finding.Evidence
+	if ev == nil || ev.Pipeline == "" {
+		return nil
+	}
+
+	return &FixSuggestion{
+		FindingIndex: index,
+		FixType:      "config",
+		Description:  fmt.Sprintf("Add a batch processor to pipeline %s", ev.Pipeline),
+		ProcessorConfig: renderYAML(map[string]interface{}{
+			"processors": map[string]interface{}{
+				"batch": map[string]interface{}{"send_batch_size": 8192, "timeout": "200ms"},
+			},
+		}),
+		PipelineChanges: fmt.Sprintf("Add batch as the last processor of service.pipelines.%s.processors", ev.Pipeline),
+		ProcessorID:     "batch",
+		Pipelines:       []string{ev.Pipeline},
+		Edits:           []PipelineEdit{{Pipeline: ev.Pipeline, Section: "processors", Add: "batch", Position: PositionLast}},
+		Risk:            "low",
+	}
+}
+
+// GenerateMissingMemoryLimiterFix adds a memory_limiter sized from the
+// container memory limit at the start of the pipeline.
+func GenerateMissingMemoryLimiterFix(finding types.DiagnosticFinding, index int) *FixSuggestion {
+	ev := finding.Evidence
+	if ev == nil || ev.Pipeline == "" {
+		return nil
+	}
+
+	return &FixSuggestion{
+		FindingIndex: index,
+		FixType:      "config",
+		Description:  fmt.Sprintf("Add a memory_limiter processor to pipeline %s, limited to 80%% of the container memory limit", ev.Pipeline),
+		ProcessorConfig: renderYAML(map[string]interface{}{
+			"processors": map[string]interface{}{
+				"memory_limiter": map[string]interface{}{
+					"check_interval":         "1s",
+					"limit_percentage":       80,
+					"spike_limit_percentage": 20,
+				},
+			},
+		}),
+		PipelineChanges: fmt.Sprintf("Add memory_limiter as the first processor of service.pipelines.%s.processors; the collector container needs a memory limit", ev.Pipeline),
+		ProcessorID:     "memory_limiter",
+		Pipelines:       []string{ev.Pipeline},
+		Edits:           []PipelineEdit{{Pipeline: ev.Pipeline, Section: "processors", Add: "memory_limiter", Position: PositionFirst}},
+		Risk:            "low",
+	}
+}
+
+// GenerateMissingRetryFix enables retry_on_failure on the exporter.
+func GenerateMissingRetryFix(finding types.DiagnosticFinding, index int) *FixSuggestion {
+	ev := finding.Evidence
+	if ev == nil || ev.ComponentID == "" {
+		return nil
+	}
+
+	return &FixSuggestion{
+		FindingIndex: index,
+		FixType:      "config",
+		Description:  fmt.Sprintf("Enable retry_on_failure on exporter %s", ev.ComponentID),
+		ProcessorConfig: componentConfig("exporters", ev.ComponentID, "retry_on_failure", map[string]interface{}{
+			"enabled":          true,
+			"initial_interval": "5s",
+			"max_interval":     "30s",
+			"max_elapsed_time": "300s",
+		}),
+		Risk: "low",
+	}
+}
+
+// GenerateMissingQueueFix enables sending_queue on the exporter.
+func GenerateMissingQueueFix(finding types.DiagnosticFinding, index int) *FixSuggestion {
+	ev := finding.Evidence
+	if ev == nil || ev.ComponentID == "" {
+		return nil
+	}
+
+	return &FixSuggestion{
+		FindingIndex: index,
+		FixType:      "config",
+		Description:  fmt.Sprintf("Enable sending_queue on exporter %s", ev.ComponentID),
+		ProcessorConfig: componentConfig("exporters", ev.ComponentID, "sending_queue", map[string]interface{}{
+			"enabled":       true,
+			"num_consumers": 10,
+			"queue_size":    5000,
+		}),
+		Risk: "low",
+	}
+}
+
+// GenerateHardcodedTokenFix moves the credential into a Secret exposed to
+// the collector as an environment variable, and references the variable
+// from the exporter.
+func GenerateHardcodedTokenFix(finding types.DiagnosticFinding, index int) *FixSuggestion {
+	ev := finding.Evidence
+	if ev == nil || ev.ComponentID == "" || ev.Path == "" {
+		return nil
+	}
+
+	envVar := envVarName(ev.ComponentID + "_" + ev.Path)
+	manifest := fmt.Sprintf(`apiVersion: v1
+kind: Secret
+metadata:
+  name: %s
+type: Opaque
+stringData:
+  %s: <current value of exporters.%s.%s>
+---
+# Collector container env, or spec.env of the OpenTelemetryCollector
+env:
+  - name: %s
+    valueFrom:
+      secretKeyRef:
+        name: %s
+        key: %s`, credentialsSecret, envVar, ev.ComponentID, ev.Path, envVar, credentialsSecret, envVar)
+
+	return &FixSuggestion{
+		FindingIndex:    index,
+		FixType:         "config",
+		Description:     fmt.Sprintf("Move the credential at exporters.%s.%s into Secret %s and reference it as ${env:%s}", ev.ComponentID, ev.Path, credentialsSecret, envVar),
+		ProcessorConfig: componentConfig("exporters", ev.ComponentID, ev.Path, fmt.Sprintf("${env:%s}", envVar)),
+		PipelineChanges: "Create the Secret and add the env entry before rolling out the config; without the variable the exporter sends an empty credential",
+		Manifest:        manifest,
+		Env:             []string{envVar},
+		Risk:            "medium",
+	}
+}
+
+// GeneratePortConflictFix keeps the first receiver on the contested endpoint
+// and moves the others to the next ports above it that nothing else in the
+// config listens on.
+func GeneratePortConflictFix(finding types.DiagnosticFinding, index int) *FixSuggestion {
+	ev := finding.Evidence
+	if ev == nil || len(ev.Components) < 2 || len(ev.Paths) != len(ev.Components) || len(ev.SampleValues) == 0 {
+		return nil
+	}
+	host, portStr, err := net.SplitHostPort(ev.SampleValues[0])
+	if err != nil {
+		return nil
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil
+	}
+
+	taken := map[int]bool{port: true}
+	for _, p := range ev.Ports {
+		taken[p] = true
+	}
+
+	receivers := make(map[string]interface{})
+	var moved []string
+	next := port
+	for i := 1; i < len(ev.Components); i++ {
+		for taken[next] {
+			next++
+		}
+		taken[next] = true
+		endpoint := net.JoinHostPort(host, strconv.Itoa(next))
+		cfg, _ := receivers[ev.Components[i]].(map[string]interface{})
+		if cfg == nil {
+			cfg = make(map[string]interface{})
+			receivers[ev.Components[i]] = cfg
+		}
+		setPath(cfg, ev.Paths[i], endpoint)
+		moved = append(moved, fmt.Sprintf("%s.%s to %s", ev.Components[i], ev.Paths[i], endpoint))
+	}
+
+	return &FixSuggestion{
+		FindingIndex:    index,
+		FixType:         "config",
+		Description:     fmt.Sprintf("Keep %s on %s and move %s", ev.Components[0], ev.SampleValues[0], strings.Join(moved, ", ")),
+		ProcessorConfig: renderYAML(map[string]interface{}{"receivers": receivers}),
+		PipelineChanges: "Check the new ports are free, then add them to the container ports and Services and point the senders at them",
+		Risk:            "medium",
+	}
+}
+
+// GenerateTailSamplingDaemonSetFix replaces tail sampling on the agent with
+// trace-aware load balancing to a gateway that runs the tail_sampling processor.
+// The fix is advisory: the gateway and its headless Service are deployed by
+// hand, and the resolver placeholder is replaced with that Service.
+func GenerateTailSamplingDaemonSetFix(finding types.DiagnosticFinding, index int) *FixSuggestion {
+	ev := finding.Evidence
+	if ev == nil || ev.Pipeline == "" {
+		return nil
+	}
+	processor := ev.ComponentID
+	if processor == "" {
+		processor = "tail_sampling"
+	}
+
+	return &FixSuggestion{
+		FindingIndex: index,
+		FixType:      "workload",
+		Description:  fmt.Sprintf("Route traces from pipeline %s to a gateway by trace ID and run %s there", ev.Pipeline, processor),
+		ProcessorConfig: renderYAML(map[string]interface{}{
+			"exporters": map[string]interface{}{
+				"loadbalancing": map[string]interface{}{
+					"routing_key": "traceID",
+					"protocol": map[string]interface{}{
+						"otlp": map[string]interface{}{"tls": map[string]interface{}{"insecure": true}},
+					},
+					"resolver": map[string]interface{}{
+						"k8s": map[string]interface{}{"service": "<gateway-headless-service>.<namespace>"},
+					},
+				},
+			},
+		}),
+		PipelineChanges: fmt.Sprintf("Remove %s from service.pipelines.%s.processors and add loadbalancing to its exporters; move %s to a gateway Deployment or StatefulSet behind the headless Service", processor, ev.Pipeline, processor),
+		Pipelines:       []string{ev.Pipeline},
+		Risk:            "high",
+		Advisory:        true,
+	}
+}
+
+// GenerateCumulativeDeltaFix moves the collector to a StatefulSet so each
+// replica keeps a stable identity for the series it converts.
+func GenerateCumulativeDeltaFix(finding types.DiagnosticFinding, index int) *FixSuggestion {
+	ev := finding.Evidence
+	if ev == nil || ev.Pipeline == "" {
+		return nil
+	}
+	processor := ev.ComponentID
+	if processor == "" {
+		processor = "cumulativetodelta"
+	}
+
+	return &FixSuggestion{
+		FindingIndex:    index,
+		FixType:         "workload",
+		Description:     fmt.Sprintf("Run the collector as a StatefulSet so %s in pipeline %s keeps its series on a stable replica", processor, ev.Pipeline),
+		PipelineChanges: "No pipeline change; expect one counter reset per series when the workload is replaced",
+		Manifest:        "# OpenTelemetryCollector\nspec:\n  mode: statefulset",
+		Pipelines:       []string{ev.Pipeline},
+		Risk:            "medium",
+		Advisory:        true,
+	}
+}
+
+// GenerateResourceDetectorFix merges the pipeline's resourcedetection
+// processors into the first one.
+func GenerateResourceDetectorFix(finding types.DiagnosticFinding, index int) *FixSuggestion {
+	ev := finding.Evidence
+	if ev == nil || ev.Pipeline == "" || len(ev.SampleValues) == 0 {
+		return nil
+	}
+	var detectors []string
+	for _, id := range ev.Components {
+		if strings.HasPrefix(id, "resourcedetection") {
+			detectors = append(detectors, id)
+		}
+	}
+	if len(detectors) < 2 {
+		return nil
+	}
+
+	keep := detectors[0]
+	return &FixSuggestion{
+		FindingIndex: index,
+		FixType:      "config",
+		Description:  fmt.Sprintf("Merge %s into %s with detectors %s", strings.Join(detectors[1:], ", "), keep, strings.Join(ev.SampleValues, ", ")),
+		ProcessorConfig: renderYAML(map[string]interface{}{
+			"processors": map[string]interface{}{
+				keep: map[string]interface{}{
+					"detectors": ev.SampleValues,
+					"timeout":   "5s",
+					"override":  false,
+				},
+			},
+		}),
+		PipelineChanges: fmt.Sprintf("Remove %s from service.pipelines.%s.processors", strings.Join(detectors[1:], ", "), ev.Pipeline),
+		ProcessorID:     keep,
+		Pipelines:       []string{ev.Pipeline},
+		Edits:           []PipelineEdit{{Pipeline: ev.Pipeline, Section: "processors", Remove: detectors[1:]}},
+		Risk:            "low",
+	}
+}
+
+// componentConfig renders one setting of a component as a config block.
+func componentConfig(section, id, path string, value interface{}) string {
+	cfg := make(map[string]interface{})
+	setPath(cfg, path, value)
+	return renderYAML(map[string]interface{}{
+		section: map[string]interface{}{id: cfg},
+	})
+}
+
+// setPath sets a dotted path in m, creating intermediate maps.
+func setPath(m map[string]interface{}, path string, value interface{}) {
+	keys := strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := m[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			m[key] = next
+		}
+		m = next
+	}
+	m[keys[len(keys)-1]] = value
+}
+
+// envVarName turns a component ID and setting path into an environment
+// variable name.
+func envVarName(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(s) {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return strings.Trim(b.String(), "_")
+}
@@ -0,0 +1,84 @@
+package fixes
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/analysis"
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+)
+
+func TestGeneratePortConflictFix_SkipsTakenPorts(t *testing.T) {
+	// jaeger shares 4317 with OTLP/gRPC; 4318 is OTLP/HTTP and 4319 a
+	// zipkin receiver, so the first free port is 4320
+	config := `receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+      http:
+        endpoint: 0.0.0.0:4318
+  jaeger:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+  zipkin:
+    endpoint: 0.0.0.0:4319
+exporters:
+  debug: {}
+service:
+  pipelines:
+    traces:
+      receivers: [otlp, jaeger, zipkin]
+      exporters: [debug]
+`
+	cfg, err := collector.ParseConfig([]byte(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var conflict *types.DiagnosticFinding
+	for _, f := range analysis.AnalyzeReceiverBindings(context.Background(), &analysis.AnalysisInput{Config: cfg}) {
+		if f.RuleID == types.RulePortConflict {
+			conflict = &f
+		}
+	}
+	if conflict == nil {
+		t.Fatal("expected a port conflict finding")
+	}
+
+	fix := GeneratePortConflictFix(*conflict, 0)
+	if fix == nil {
+		t.Fatal("expected a suggestion")
+	}
+	if !strings.Contains(fix.ProcessorConfig, "0.0.0.0:4320") {
+		t.Errorf("expected the receiver to move to 4320, got:\n%s", fix.ProcessorConfig)
+	}
+
+	patched, err := ApplyToConfig(config, *fix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := VerifyStatic(context.Background(), analysis.AnalysisInput{}, config, patched, *conflict)
+	if v.Verdict != VerdictResolved {
+		t.Errorf("expected the patched config to resolve the conflict, got %s: %s %+v", v.Verdict, v.Message, v.NewCritical)
+	}
+}
+
+func TestGenerateTailSamplingDaemonSetFix_Advisory(t *testing.T) {
+	finding := types.DiagnosticFinding{
+		RuleID:   types.RuleTailSamplingDaemonSet,
+		Evidence: &types.Evidence{Pipeline: "traces", ComponentID: "tail_sampling"},
+	}
+	fix := GenerateTailSamplingDaemonSetFix(finding, 0)
+	if fix == nil {
+		t.Fatal("expected a fix")
+	}
+	if !fix.Advisory || len(fix.Edits) != 0 {
+		t.Errorf("expected an advisory fix without pipeline edits, got %+v", fix)
+	}
+	if _, err := BuildPlan(applyBaseConfig, []FixSuggestion{*fix}, []int{0}); err == nil {
+		t.Error("expected the fix to be refused by plan building")
+	}
+}
@@ -0,0 +1,135 @@
+package fixes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/ottl"
+	"github.com/hrexed/otel-collector-mcp/pkg/signals"
+	"gopkg.in/yaml.v3"
+)
+
+// Effect is the measured effect of a fix's OTTL statements and filter
+// conditions on captured signals.
+type Effect struct {
+	Transforms []*ottl.TransformPreview `json:"transforms,omitempty"`
+	Filters    []*ottl.FilterPreview    `json:"filters,omitempty"`
+	Summary    []string                 `json:"summary"`
+}
+
+// MeasureEffect previews the transform and filter processors of a fix's
+// ProcessorConfig against captured signals. It returns nil when the config
+// holds no OTTL to evaluate.
+func MeasureEffect(processorConfig string, captured *signals.CapturedSignals) *Effect {
+	var doc struct {
+		Processors map[string]map[string]interface{} `yaml:"processors"`
+	}
+	if err := yaml.Unmarshal([]byte(processorConfig), &doc); err != nil {
+		return nil
+	}
+
+	effect := &Effect{}
+	ids := make([]string, 0, len(doc.Processors))
+	for id := range doc.Processors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		cfg := doc.Processors[id]
+		switch strings.SplitN(id, "/", 2)[0] {
+		case "transform":
+			for _, group := range transformGroups(cfg) {
+				p := ottl.PreviewTransform(group.ctx, group.statements, captured)
+				effect.Transforms = append(effect.Transforms, p)
+				effect.Summary = append(effect.Summary, transformSummary(id, p))
+			}
+		case "filter":
+			for _, signal := range []string{"traces", "metrics", "logs"} {
+				section, _ := cfg[signal].(map[string]interface{})
+				for _, key := range sortedContextKeys(ottl.FilterConditionContexts[signal]) {
+					ctx := ottl.FilterConditionContexts[signal][key]
+					conditions := stringList(section[key])
+					if len(conditions) == 0 {
+						continue
+					}
+					p := ottl.PreviewFilter(ctx, conditions, captured)
+					effect.Filters = append(effect.Filters, p)
+					effect.Summary = append(effect.Summary, filterSummary(id, p))
+				}
+			}
+		}
+	}
+
+	if len(effect.Transforms) == 0 && len(effect.Filters) == 0 {
+		return nil
+	}
+	return effect
+}
+
+// statementGroup is a list of statements sharing a context.
+type statementGroup struct {
+	ctx        ottl.Context
+	statements []string
+}
+
+func transformGroups(cfg map[string]interface{}) []statementGroup {
+	var groups []statementGroup
+	keys := make([]string, 0, len(ottl.TransformStatementSignals))
+	for key := range ottl.TransformStatementSignals {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		entries, _ := cfg[key].([]interface{})
+		for _, entry := range entries {
+			switch g := entry.(type) {
+			case map[string]interface{}:
+				ctx, _ := g["context"].(string)
+				groups = append(groups, statementGroup{ctx: ottl.Context(ctx), statements: stringList(g["statements"])})
+			case string:
+				ctx := ottl.InferContext(ottl.TransformStatementSignals[key], g)
+				if ctx != "" {
+					groups = append(groups, statementGroup{ctx: ctx, statements: []string{g}})
+				}
+			}
+		}
+	}
+	return groups
+}
+
+func sortedContextKeys(m map[string]ottl.Context) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func stringList(v interface{}) []string {
+	list, _ := v.([]interface{})
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func transformSummary(id string, p *ottl.TransformPreview) string {
+	if p.Records == 0 {
+		return fmt.Sprintf("%s: no captured %s records to measure against", id, p.Context)
+	}
+	return fmt.Sprintf("%s: modifies %d of %d captured %s records", id, p.Modified, p.Records, p.Context)
+}
+
+func filterSummary(id string, p *ottl.FilterPreview) string {
+	if p.Records == 0 {
+		return fmt.Sprintf("%s: no captured %s records to measure against", id, p.Context)
+	}
+	return fmt.Sprintf("%s: drops %d of %d captured %s records (%.0f%%)", id, p.Dropped, p.Records, p.Context, p.DropRatio*100)
+}
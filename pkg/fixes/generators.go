@@ -1,129 +1,279 @@
 package fixes
 
 import (
+	"bytes"
 	"fmt"
 	"strings"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/hrexed/otel-collector-mcp/pkg/types"
 )
 
-// GenerateCardinalityFix generates an attributes processor fix for high-cardinality metrics.
+// maxAttributeBytes is the length bloated attribute values are truncated to.
+const maxAttributeBytes = 1024
+
+// piiPatterns are the RE2 patterns used to redact each kind of PII the
+// runtime analyzer detects.
+var piiPatterns = map[string]string{
+	"email address": `[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`,
+	"IP address":    `\b(?:\d{1,3}\.){3}\d{1,3}\b`,
+	"IPv6 address":  `(?i)([0-9a-f]{1,4}:){7}[0-9a-f]{1,4}`,
+	"phone number":  `\+?\d{1,4}[-.\s]?\(?\d{1,3}\)?[-.\s]?\d{1,4}[-.\s]?\d{1,9}`,
+}
+
+// resourcePlaceholders are the values suggested for missing resource attributes.
+var resourcePlaceholders = map[string]string{
+	"service.name":           "<your-service-name>",
+	"service.version":        "<your-version>",
+	"deployment.environment": "<your-environment>",
+}
+
+// GenerateCardinalityFix drops the label with the most distinct values from
+// the high-cardinality metric.
 func GenerateCardinalityFix(finding types.DiagnosticFinding, index int) *FixSuggestion {
-	// Extract label keys from remediation text
-	config := `processors:
-  attributes/drop-high-cardinality:
-    actions:
-      - key: <high-cardinality-label>
-        action: delete`
+	ev := finding.Evidence
+	if ev == nil || ev.AttributeKey == "" || ev.Name == "" {
+		return nil
+	}
+
+	id := "transform/cardinality-" + componentSuffix(ev.Name)
+	statement := fmt.Sprintf("delete_key(attributes, %s) where metric.name == %s", ottlString(ev.AttributeKey), ottlString(ev.Name))
+	changes, pipelines, edits := placement(ev, id)
 
 	return &FixSuggestion{
 		FindingIndex:    index,
-		FixType:         "attribute",
-		Description:     "Drop high-cardinality label keys using attributes processor",
-		ProcessorConfig: config,
-		PipelineChanges: "Add attributes/drop-high-cardinality to pipeline processors",
+		FixType:         "ottl",
+		Description:     fmt.Sprintf("Drop label %q from metric %s (%d label combinations captured)", ev.AttributeKey, ev.Name, ev.Count),
+		ProcessorConfig: transformConfig(id, "metric_statements", "datapoint", statement),
+		PipelineChanges: changes,
+		ProcessorID:     id,
+		Pipelines:       pipelines,
+		Edits:           edits,
 		Risk:            "medium",
 	}
 }
 
-// GeneratePIIFix generates an OTTL transform fix for PII detection findings.
+// GeneratePIIFix redacts the detected pattern from the attribute, or deletes
+// the attribute when the kind of PII has no redaction pattern.
 func GeneratePIIFix(finding types.DiagnosticFinding, index int) *FixSuggestion {
-	fixType := "ottl"
-	var config string
-
-	if strings.Contains(finding.Summary, "email") {
-		config = `processors:
-  transform/redact-pii:
-    log_statements:
-      - context: log
-        statements:
-          - replace_pattern(attributes["<attribute>"], "\\b[\\w.+-]+@[\\w-]+\\.[\\w.]+\\b", "***REDACTED***")`
-	} else if strings.Contains(finding.Summary, "IP") {
-		config = `processors:
-  transform/redact-ip:
-    log_statements:
-      - context: log
-        statements:
-          - replace_pattern(attributes["<attribute>"], "\\b\\d{1,3}\\.\\d{1,3}\\.\\d{1,3}\\.\\d{1,3}\\b", "***REDACTED***")`
-	} else {
-		config = `processors:
-  attributes/remove-pii:
-    actions:
-      - key: <pii-attribute>
-        action: delete`
-		fixType = "attribute"
+	ev := finding.Evidence
+	if ev == nil || ev.AttributeKey == "" {
+		return nil
+	}
+	key, ctx := statementContext(ev.SignalType)
+	if key == "" {
+		return nil
+	}
+
+	id := "transform/redact-" + componentSuffix(ev.AttributeKey)
+	statement := fmt.Sprintf("delete_key(attributes, %s)", ottlString(ev.AttributeKey))
+	description := fmt.Sprintf("Delete %s attribute %q, which contains %s", ctx, ev.AttributeKey, kindOr(ev.Kind, "personal data"))
+	risk := "medium"
+	if pattern, ok := piiPatterns[ev.Kind]; ok {
+		statement = fmt.Sprintf(`replace_pattern(attributes[%s], %s, "***REDACTED***")`, ottlString(ev.AttributeKey), ottlString(pattern))
+		description = fmt.Sprintf("Redact %s from %s attribute %q", ev.Kind, ctx, ev.AttributeKey)
+		risk = "low"
 	}
+	changes, pipelines, edits := placement(ev, id)
 
 	return &FixSuggestion{
 		FindingIndex:    index,
-		FixType:         fixType,
-		Description:     fmt.Sprintf("Redact PII from %s", finding.Summary),
-		ProcessorConfig: config,
-		PipelineChanges: "Add transform/redact-pii to pipeline processors",
-		Risk:            "low",
+		FixType:         "ottl",
+		Description:     description,
+		ProcessorConfig: transformConfig(id, key, ctx, statement),
+		PipelineChanges: changes,
+		ProcessorID:     id,
+		Pipelines:       pipelines,
+		Edits:           edits,
+		Risk:            risk,
 	}
 }
 
-// GenerateBloatedAttrsFix generates a fix to truncate or delete bloated attributes.
+// GenerateBloatedAttrsFix truncates the bloated attribute to 1 KiB.
 func GenerateBloatedAttrsFix(finding types.DiagnosticFinding, index int) *FixSuggestion {
-	config := `processors:
-  transform/truncate-attrs:
-    log_statements:
-      - context: log
-        statements:
-          - truncate_all(attributes, 1024)`
+	ev := finding.Evidence
+	if ev == nil || ev.AttributeKey == "" {
+		return nil
+	}
+	key, ctx := statementContext(ev.SignalType)
+	if key == "" {
+		return nil
+	}
+
+	id := "transform/truncate-" + componentSuffix(ev.AttributeKey)
+	attr := fmt.Sprintf("attributes[%s]", ottlString(ev.AttributeKey))
+	statement := fmt.Sprintf("set(%s, Substring(%s, 0, %d)) where Len(%s) > %d", attr, attr, maxAttributeBytes, attr, maxAttributeBytes)
+	changes, pipelines, edits := placement(ev, id)
 
 	return &FixSuggestion{
 		FindingIndex:    index,
 		FixType:         "ottl",
-		Description:     "Truncate bloated attributes to 1KB maximum",
-		ProcessorConfig: config,
-		PipelineChanges: "Add transform/truncate-attrs to pipeline processors",
+		Description:     fmt.Sprintf("Truncate %s attribute %q (~%d bytes observed) to %d bytes", ctx, ev.AttributeKey, ev.Count, maxAttributeBytes),
+		ProcessorConfig: transformConfig(id, key, ctx, statement),
+		PipelineChanges: changes,
+		ProcessorID:     id,
+		Pipelines:       pipelines,
+		Edits:           edits,
 		Risk:            "low",
 	}
 }
 
-// GenerateDuplicatesFix generates a filter processor fix for duplicate signals.
+// GenerateDuplicatesFix proposes a filter processor for the metrics the
+// finding names. The finding only shows that these metrics carry more than
+// one data point per batch, which ordinary metrics with several attribute
+// sets also do, so the suggestion is advisory: it has no pipeline edits and
+// applying it filters nothing until the filter is wired in by hand.
 func GenerateDuplicatesFix(finding types.DiagnosticFinding, index int) *FixSuggestion {
-	config := `processors:
-  filter/drop-duplicates:
-    metrics:
-      exclude:
-        match_type: strict
-        metric_names:
-          - <duplicate-metric-name>`
+	ev := finding.Evidence
+	if ev == nil || len(ev.Names) == 0 {
+		return nil
+	}
+
+	id := "filter/drop-duplicates"
+	conditions := make([]string, 0, len(ev.Names))
+	for _, name := range ev.Names {
+		conditions = append(conditions, "name == "+ottlString(name))
+	}
+	config := renderYAML(map[string]interface{}{
+		"processors": map[string]interface{}{
+			id: map[string]interface{}{
+				"error_mode": "ignore",
+				"metrics":    map[string]interface{}{"metric": conditions},
+			},
+		},
+	})
 
 	return &FixSuggestion{
 		FindingIndex:    index,
 		FixType:         "filter",
-		Description:     "Drop duplicate metrics using filter processor",
+		Description:     fmt.Sprintf("Review %d metrics reported more than once (%s) and drop the ones sent by more than one receiver or pod", len(ev.Names), strings.Join(ev.Names, ", ")),
 		ProcessorConfig: config,
-		PipelineChanges: "Add filter/drop-duplicates to pipeline processors",
+		PipelineChanges: fmt.Sprintf("No pipeline change; keep only the conditions for confirmed duplicates before adding %s to a metrics pipeline", id),
+		ProcessorID:     id,
 		Risk:            "medium",
 	}
 }
 
-// GenerateMissingResourceFix generates a resource processor fix for missing attributes.
+// GenerateMissingResourceFix sets the missing resource attributes with a
+// resource processor.
 func GenerateMissingResourceFix(finding types.DiagnosticFinding, index int) *FixSuggestion {
-	config := `processors:
-  resource/add-missing:
-    attributes:
-      - key: service.name
-        value: <your-service-name>
-        action: upsert
-      - key: service.version
-        value: <your-version>
-        action: upsert
-      - key: deployment.environment
-        value: <your-environment>
-        action: upsert`
+	ev := finding.Evidence
+	if ev == nil || len(ev.AttributeKeys) == 0 {
+		return nil
+	}
+
+	id := "resource/add-missing"
+	actions := make([]map[string]interface{}, 0, len(ev.AttributeKeys))
+	for _, key := range ev.AttributeKeys {
+		value, ok := resourcePlaceholders[key]
+		if !ok {
+			value = "<value>"
+		}
+		actions = append(actions, map[string]interface{}{"key": key, "value": value, "action": "upsert"})
+	}
+	config := renderYAML(map[string]interface{}{
+		"processors": map[string]interface{}{
+			id: map[string]interface{}{"attributes": actions},
+		},
+	})
+	changes, pipelines, edits := placement(ev, id)
 
 	return &FixSuggestion{
 		FindingIndex:    index,
 		FixType:         "resource",
-		Description:     "Add missing resource attributes using resource processor",
+		Description:     fmt.Sprintf("Set resource attributes %s; replace the placeholder values", strings.Join(ev.AttributeKeys, ", ")),
 		ProcessorConfig: config,
-		PipelineChanges: "Add resource/add-missing to pipeline processors",
+		PipelineChanges: changes,
+		ProcessorID:     id,
+		Pipelines:       pipelines,
+		Edits:           edits,
 		Risk:            "low",
 	}
 }
+
+// statementContext returns the transform statement key and OTTL context
+// that operate on a signal's record attributes.
+func statementContext(signal string) (string, string) {
+	switch signal {
+	case "traces":
+		return "trace_statements", "span"
+	case "logs":
+		return "log_statements", "log"
+	case "metrics":
+		return "metric_statements", "datapoint"
+	}
+	return "", ""
+}
+
+// transformConfig renders a transform processor with one statement group.
+func transformConfig(id, key, ctx string, statements ...string) string {
+	return renderYAML(map[string]interface{}{
+		"processors": map[string]interface{}{
+			id: map[string]interface{}{
+				"error_mode": "ignore",
+				key: []map[string]interface{}{
+					{"context": ctx, "statements": statements},
+				},
+			},
+		},
+	})
+}
+
+// placement describes where a new processor goes, naming the finding's
+// pipeline when the evidence has one.
+func placement(ev *types.Evidence, id string) (string, []string, []PipelineEdit) {
+	edit := PipelineEdit{Pipeline: ev.Pipeline, Signal: ev.SignalType, Section: "processors", Add: id}
+	if ev.Pipeline != "" {
+		return fmt.Sprintf("Add %s to service.pipelines.%s.processors, after memory_limiter and before batch", id, ev.Pipeline), []string{ev.Pipeline}, []PipelineEdit{edit}
+	}
+	return fmt.Sprintf("Add %s to the processors of every %s pipeline, after memory_limiter and before batch", id, ev.SignalType), nil, []PipelineEdit{edit}
+}
+
+// renderYAML marshals v with the two-space indent used in collector configs.
+func renderYAML(v interface{}) string {
+	out, err := marshalYAML(v)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(out, "\n")
+}
+
+func marshalYAML(v interface{}) (string, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// ottlString quotes s as an OTTL string literal.
+func ottlString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// componentSuffix turns a metric or attribute name into a component ID suffix.
+func componentSuffix(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+func kindOr(kind, def string) string {
+	if kind == "" {
+		return def
+	}
+	return kind
+}
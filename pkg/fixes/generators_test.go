@@ -0,0 +1,60 @@
+package fixes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateDuplicatesFix_MultiPointMetricsNotFiltered(t *testing.T) {
+	// What AnalyzeDuplicateSignals reports for ordinary metrics that carry
+	// one data point per attribute set
+	finding := types.DiagnosticFinding{
+		RuleID: types.RuleRuntimeDuplicateSignals,
+		Evidence: &types.Evidence{
+			SignalType: "metrics",
+			Name:       "http.server.duration",
+			Names:      []string{"http.server.duration", "process.cpu.time", "system.cpu.time"},
+			Pipeline:   "metrics",
+		},
+	}
+	config := `receivers:
+  otlp: {}
+exporters:
+  otlp: {}
+service:
+  pipelines:
+    metrics:
+      receivers: [otlp]
+      processors: [batch]
+      exporters: [otlp]
+`
+
+	fix := GenerateDuplicatesFix(finding, 0)
+	if fix == nil {
+		t.Fatal("expected a suggestion")
+	}
+	if len(fix.Edits) != 0 || len(fix.Pipelines) != 0 {
+		t.Errorf("expected an advisory suggestion without pipeline edits, got edits %+v pipelines %v", fix.Edits, fix.Pipelines)
+	}
+
+	patched, err := ApplyToConfig(config, *fix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Service struct {
+			Pipelines map[string]struct {
+				Processors []string `yaml:"processors"`
+			} `yaml:"pipelines"`
+		} `yaml:"service"`
+	}
+	if err := yaml.Unmarshal([]byte(patched), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if got := doc.Service.Pipelines["metrics"].Processors; strings.Join(got, ",") != "batch" {
+		t.Errorf("expected the metrics pipeline to be left alone, got processors %v", got)
+	}
+}
@@ -0,0 +1,261 @@
+package fixes
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Conflict kinds between the suggestions of a plan.
+const (
+	ConflictComponent = "component"      // suggestions configure the same setting differently
+	ConflictOrdering  = "ordering"       // suggestions need incompatible positions in a pipeline
+	ConflictDuplicate = "duplicate_edit" // suggestions make the same pipeline edit
+)
+
+// Conflict is an incompatibility between two suggestions of a plan.
+type Conflict struct {
+	Kind        string `json:"kind"`
+	Suggestions []int  `json:"suggestions"`
+	Message     string `json:"message"`
+}
+
+// Plan combines several fix suggestions into one config change that is
+// applied as a single transaction.
+type Plan struct {
+	Suggestions   []int           `json:"suggestions"`
+	Fixes         []FixSuggestion `json:"-"`
+	Conflicts     []Conflict      `json:"conflicts,omitempty"`
+	Risk          string          `json:"risk"`
+	Diff          string          `json:"diff"`
+	Manifests     []string        `json:"manifests,omitempty"`
+	Verifications []*Verification `json:"verifications,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+
+	// Base is the config the plan was built against and Patched the
+	// config with every suggestion applied.
+	Base    string `json:"-"`
+	Patched string `json:"-"`
+}
+
+// Applicable reports whether the plan is free of conflicts and every
+// suggestion passed static verification.
+func (p *Plan) Applicable() bool {
+	if len(p.Conflicts) > 0 {
+		return false
+	}
+	for _, v := range p.Verifications {
+		if !v.Passed() {
+			return false
+		}
+	}
+	return true
+}
+
+// BuildPlan applies the selected suggestions to configYAML in index order
+// and reports the conflicts between them. The diff compares the normalized
+// current config with the merged one, so it only shows what the plan changes.
+func BuildPlan(configYAML string, suggestions []FixSuggestion, indexes []int) (*Plan, error) {
+	if len(indexes) == 0 {
+		return nil, fmt.Errorf("no suggestions selected")
+	}
+	selected := append([]int(nil), indexes...)
+	sort.Ints(selected)
+	for i, idx := range selected {
+		if idx < 0 || idx >= len(suggestions) {
+			return nil, fmt.Errorf("suggestion index %d out of range. Available: 0-%d", idx, len(suggestions)-1)
+		}
+		if i > 0 && selected[i-1] == idx {
+			return nil, fmt.Errorf("suggestion index %d is selected twice", idx)
+		}
+		if suggestions[idx].Advisory {
+			return nil, fmt.Errorf("suggestion %d is advisory and is carried out by hand", idx)
+		}
+	}
+
+	original, err := NormalizeConfig(configYAML)
+	if err != nil {
+		return nil, err
+	}
+	var config map[string]interface{}
+	if err := yaml.Unmarshal([]byte(configYAML), &config); err != nil {
+		return nil, fmt.Errorf("failed to parse collector config: %w", err)
+	}
+	service, _ := config["service"].(map[string]interface{})
+	pipelines, _ := service["pipelines"].(map[string]interface{})
+
+	plan := &Plan{Suggestions: selected, Base: configYAML, CreatedAt: time.Now()}
+	patched := configYAML
+	for _, idx := range selected {
+		fix := suggestions[idx]
+		plan.Fixes = append(plan.Fixes, fix)
+		if patched, err = ApplyToConfig(patched, fix); err != nil {
+			return nil, fmt.Errorf("suggestion %d: %w", idx, err)
+		}
+		if fix.Manifest != "" {
+			plan.Manifests = append(plan.Manifests, fix.Manifest)
+		}
+		if riskRank(fix.Risk) > riskRank(plan.Risk) {
+			plan.Risk = fix.Risk
+		}
+	}
+	plan.Patched = patched
+	plan.Diff = UnifiedDiff(original, patched, "current/config.yaml", "planned/config.yaml")
+
+	for a := 0; a < len(selected); a++ {
+		for b := a + 1; b < len(selected); b++ {
+			plan.Conflicts = append(plan.Conflicts, conflictsBetween(pipelines, selected[a], selected[b], plan.Fixes[a], plan.Fixes[b])...)
+		}
+	}
+	return plan, nil
+}
+
+// conflictsBetween compares the config blocks and pipeline edits of two
+// suggestions.
+func conflictsBetween(pipelines map[string]interface{}, ia, ib int, a, b FixSuggestion) []Conflict {
+	var conflicts []Conflict
+	pair := []int{ia, ib}
+
+	settingsA, settingsB := settings(a.ProcessorConfig), settings(b.ProcessorConfig)
+	reported := map[string]bool{}
+	for _, path := range sortedKeys(settingsA) {
+		valueB, ok := settingsB[path]
+		component := componentOf(path)
+		if !ok || reported[component] || reflect.DeepEqual(settingsA[path], valueB) {
+			continue
+		}
+		reported[component] = true
+		conflicts = append(conflicts, Conflict{
+			Kind:        ConflictComponent,
+			Suggestions: pair,
+			Message:     fmt.Sprintf("Suggestions %d and %d set %s to different values", ia, ib, path),
+		})
+	}
+
+	editsA, editsB := resolveEdits(pipelines, a.Edits), resolveEdits(pipelines, b.Edits)
+	for _, ea := range editsA {
+		for _, eb := range editsB {
+			if ea.Pipeline != eb.Pipeline || ea.Section != eb.Section {
+				continue
+			}
+			where := fmt.Sprintf("%s of pipeline %s", ea.Section, ea.Pipeline)
+			switch {
+			case ea.Add != "" && ea.Add == eb.Add:
+				conflicts = append(conflicts, Conflict{
+					Kind:        ConflictDuplicate,
+					Suggestions: pair,
+					Message:     fmt.Sprintf("Suggestions %d and %d both add %s to the %s", ia, ib, ea.Add, where),
+				})
+			case ea.Add != "" && containsString(eb.Remove, ea.Add):
+				conflicts = append(conflicts, Conflict{
+					Kind:        ConflictOrdering,
+					Suggestions: pair,
+					Message:     fmt.Sprintf("Suggestion %d adds %s to the %s and suggestion %d removes it", ia, ea.Add, where, ib),
+				})
+			case eb.Add != "" && containsString(ea.Remove, eb.Add):
+				conflicts = append(conflicts, Conflict{
+					Kind:        ConflictOrdering,
+					Suggestions: pair,
+					Message:     fmt.Sprintf("Suggestion %d adds %s to the %s and suggestion %d removes it", ib, eb.Add, where, ia),
+				})
+			case ea.Add != "" && eb.Add != "" && ea.Position != "" && ea.Position == eb.Position:
+				conflicts = append(conflicts, Conflict{
+					Kind:        ConflictOrdering,
+					Suggestions: pair,
+					Message:     fmt.Sprintf("Suggestions %d and %d both need to be %s in the %s (%s, %s)", ia, ib, ea.Position, where, ea.Add, eb.Add),
+				})
+			}
+		}
+	}
+	return conflicts
+}
+
+// resolveEdits expands edits that apply to every pipeline of a signal into
+// one edit per pipeline.
+func resolveEdits(pipelines map[string]interface{}, edits []PipelineEdit) []PipelineEdit {
+	var out []PipelineEdit
+	for _, edit := range edits {
+		for _, name := range editTargets(pipelines, edit) {
+			resolved := edit
+			resolved.Pipeline = name
+			out = append(out, resolved)
+		}
+	}
+	return out
+}
+
+// settings flattens a fix's config block into its leaf settings keyed by
+// dotted path, such as processors.batch.timeout.
+func settings(block string) map[string]interface{} {
+	out := map[string]interface{}{}
+	var parsed map[string]interface{}
+	if err := yaml.Unmarshal([]byte(block), &parsed); err != nil {
+		return out
+	}
+	var walk func(prefix string, v interface{})
+	walk = func(prefix string, v interface{}) {
+		m, ok := v.(map[string]interface{})
+		if !ok || len(m) == 0 {
+			out[prefix] = v
+			return
+		}
+		for key, value := range m {
+			walk(prefix+"."+key, value)
+		}
+	}
+	for key, value := range parsed {
+		walk(key, value)
+	}
+	return out
+}
+
+// componentOf returns the section and component of a settings path.
+func componentOf(path string) string {
+	parts := strings.SplitN(path, ".", 3)
+	if len(parts) < 2 {
+		return path
+	}
+	return parts[0] + "." + parts[1]
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func riskRank(risk string) int {
+	switch risk {
+	case "low":
+		return 1
+	case "medium":
+		return 2
+	case "high":
+		return 3
+	}
+	return 0
+}
+
+// NormalizeConfig re-encodes configYAML the way ApplyToConfig does, so that
+// a diff between two configs shows only real changes.
+func NormalizeConfig(configYAML string) (string, error) {
+	var config map[string]interface{}
+	if err := yaml.Unmarshal([]byte(configYAML), &config); err != nil {
+		return "", fmt.Errorf("failed to parse collector config: %w", err)
+	}
+	if config == nil {
+		config = make(map[string]interface{})
+	}
+	out, err := marshalYAML(config)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,113 @@
+package fixes
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildPlan(t *testing.T) {
+	batch := FixSuggestion{
+		ProcessorConfig: "processors:\n  batch:\n    timeout: 200ms\n",
+		Edits:           []PipelineEdit{{Pipeline: "metrics", Section: "processors", Add: "batch", Position: PositionLast}},
+		Risk:            "low",
+	}
+	suggestions := []FixSuggestion{
+		0: batch,
+		1: {
+			ProcessorConfig: "processors:\n  batch:\n    timeout: 5s\n",
+			Risk:            "low",
+		},
+		2: batch,
+		3: {
+			Edits: []PipelineEdit{{Signal: "metrics", Section: "processors", Remove: []string{"batch"}}},
+			Risk:  "medium",
+		},
+		4: {
+			ProcessorConfig: "processors:\n  transform/x: {}\n",
+			Edits:           []PipelineEdit{{Pipeline: "metrics", Section: "processors", Add: "transform/x", Position: PositionLast}},
+			Risk:            "high",
+		},
+		5: {
+			ProcessorConfig: "exporters:\n  otlp:\n    sending_queue:\n      enabled: true\n",
+			Manifest:        "kind: Secret",
+			Risk:            "medium",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		indexes   []int
+		wantKinds []string
+		wantRisk  string
+	}{
+		{name: "independent suggestions", indexes: []int{5, 0}, wantRisk: "medium"},
+		{name: "same setting, different values", indexes: []int{0, 1}, wantKinds: []string{ConflictComponent}, wantRisk: "low"},
+		{name: "same edit twice", indexes: []int{0, 2}, wantKinds: []string{ConflictDuplicate}, wantRisk: "low"},
+		{name: "add and remove", indexes: []int{3, 0}, wantKinds: []string{ConflictOrdering}, wantRisk: "medium"},
+		{name: "same position", indexes: []int{0, 4}, wantKinds: []string{ConflictOrdering}, wantRisk: "high"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := BuildPlan(applyBaseConfig, suggestions, tt.indexes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var kinds []string
+			for _, c := range plan.Conflicts {
+				kinds = append(kinds, c.Kind)
+			}
+			if strings.Join(kinds, ",") != strings.Join(tt.wantKinds, ",") {
+				t.Errorf("expected conflicts %v, got %+v", tt.wantKinds, plan.Conflicts)
+			}
+			if plan.Applicable() != (len(tt.wantKinds) == 0) {
+				t.Errorf("unexpected Applicable() with conflicts %+v", plan.Conflicts)
+			}
+			if plan.Risk != tt.wantRisk {
+				t.Errorf("expected risk %s, got %s", tt.wantRisk, plan.Risk)
+			}
+			if plan.Diff == "" || !strings.HasPrefix(plan.Diff, "--- current/config.yaml\n+++ planned/config.yaml\n") {
+				t.Errorf("expected a diff of the planned config, got:\n%s", plan.Diff)
+			}
+		})
+	}
+
+	t.Run("suggestions apply in index order", func(t *testing.T) {
+		plan, err := BuildPlan(applyBaseConfig, suggestions, []int{5, 0})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(plan.Suggestions) != 2 || plan.Suggestions[0] != 0 || plan.Suggestions[1] != 5 {
+			t.Errorf("expected suggestions [0 5], got %v", plan.Suggestions)
+		}
+		if len(plan.Manifests) != 1 {
+			t.Errorf("expected the manifest of suggestion 5, got %v", plan.Manifests)
+		}
+		if !strings.Contains(plan.Diff, "+        - batch") || !strings.Contains(plan.Diff, "+    sending_queue:") {
+			t.Errorf("expected the diff to show both changes, got:\n%s", plan.Diff)
+		}
+	})
+}
+
+func TestBuildPlanErrors(t *testing.T) {
+	suggestions := []FixSuggestion{
+		{Risk: "low"},
+		{Edits: []PipelineEdit{{Pipeline: "logs", Section: "processors", Add: "batch"}}},
+		{FixType: "workload", Manifest: "spec:\n  mode: statefulset", Advisory: true},
+	}
+	tests := map[string][]int{
+		"nothing selected": nil,
+		"out of range":     {3},
+		"advisory":         {0, 2},
+		"selected twice":   {0, 0},
+		"suggestion fails": {1},
+		"negative index":   {-1},
+	}
+	for name, indexes := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := BuildPlan(applyBaseConfig, suggestions, indexes); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
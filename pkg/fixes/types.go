@@ -5,23 +5,79 @@ import "github.com/hrexed/otel-collector-mcp/pkg/types"
 // FixSuggestion represents a generated fix for a detected issue.
 type FixSuggestion struct {
 	FindingIndex    int    `json:"finding_index"`
-	FixType         string `json:"fix_type"` // ottl, filter, attribute, resource, config
+	FixType         string `json:"fix_type"` // ottl, filter, attribute, resource, config, workload
 	Description     string `json:"description"`
 	ProcessorConfig string `json:"processor_config"` // Complete YAML config block
 	PipelineChanges string `json:"pipeline_changes"`
 	Risk            string `json:"risk"` // low, medium, high
+
+	// ProcessorID is the processor the fix adds, and Pipelines the
+	// pipelines it goes into. Pipelines is empty when every pipeline of
+	// the finding's signal type needs it.
+	ProcessorID string   `json:"processor_id,omitempty"`
+	Pipelines   []string `json:"pipelines,omitempty"`
+
+	// Edits are the pipeline changes in PipelineChanges, in a form
+	// ApplyToConfig can carry out.
+	Edits []PipelineEdit `json:"edits,omitempty"`
+
+	// Manifest holds the Kubernetes objects or workload changes the fix
+	// needs besides the collector config, such as a Secret.
+	Manifest string `json:"manifest,omitempty"`
+
+	// Advisory marks a fix that changes more than the collector config,
+	// such as its workload. It is carried out by hand from PipelineChanges
+	// and Manifest; apply_fix and plan_changes refuse it.
+	Advisory bool `json:"advisory,omitempty"`
+
+	// Env names the environment variables Manifest defines on the collector
+	// container. Static verification treats them as defined.
+	Env []string `json:"env,omitempty"`
+
+	// Effect is the fix's measured effect on the session's captured
+	// signals, when signals were captured.
+	Effect *Effect `json:"effect,omitempty"`
 }
 
-// FixGenerator generates fix suggestions for a specific category of findings.
+// Positions at which PipelineEdit adds a component.
+const (
+	PositionFirst = "first"
+	PositionLast  = "last"
+)
+
+// PipelineEdit adds a component to or removes components from one section
+// of a pipeline. When Pipeline is empty the edit applies to every pipeline
+// of Signal.
+type PipelineEdit struct {
+	Pipeline string   `json:"pipeline,omitempty"`
+	Signal   string   `json:"signal,omitempty"`
+	Section  string   `json:"section"` // processors or exporters
+	Add      string   `json:"add,omitempty"`
+	Position string   `json:"position,omitempty"` // first, last; empty adds processors after memory_limiter and before batch
+	Remove   []string `json:"remove,omitempty"`
+}
+
+// FixGenerator generates a fix suggestion from a finding's evidence. It
+// returns nil when the evidence is not specific enough for a concrete fix.
 type FixGenerator func(finding types.DiagnosticFinding, index int) *FixSuggestion
 
-// AllFixGenerators returns all registered fix generators keyed by finding category.
+// AllFixGenerators returns all registered fix generators keyed by rule ID.
 func AllFixGenerators() map[string]FixGenerator {
 	return map[string]FixGenerator{
-		"cardinality":     GenerateCardinalityFix,
-		"pii":             GeneratePIIFix,
-		"bloated_attrs":   GenerateBloatedAttrsFix,
-		"duplicates":      GenerateDuplicatesFix,
-		"missing_resource": GenerateMissingResourceFix,
+		types.RuleMissingBatch:             GenerateMissingBatchFix,
+		types.RuleMissingMemoryLimiter:     GenerateMissingMemoryLimiterFix,
+		types.RuleMissingRetry:             GenerateMissingRetryFix,
+		types.RuleMissingQueue:             GenerateMissingQueueFix,
+		types.RuleHardcodedToken:           GenerateHardcodedTokenFix,
+		types.RulePortConflict:             GeneratePortConflictFix,
+		types.RuleTailSamplingDaemonSet:    GenerateTailSamplingDaemonSetFix,
+		types.RuleCumulativeDeltaStateless: GenerateCumulativeDeltaFix,
+		types.RuleResourceDetectorConflict: GenerateResourceDetectorFix,
+
+		types.RuleRuntimeHighCardinality:  GenerateCardinalityFix,
+		types.RuleRuntimePII:              GeneratePIIFix,
+		types.RuleRuntimeBloatedAttribute: GenerateBloatedAttrsFix,
+		types.RuleRuntimeDuplicateSignals: GenerateDuplicatesFix,
+		types.RuleRuntimeMissingResource:  GenerateMissingResourceFix,
 	}
 }
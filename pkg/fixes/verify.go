@@ -0,0 +1,218 @@
+package fixes
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/analysis"
+	"github.com/hrexed/otel-collector-mcp/pkg/analysis/runtime"
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/signals"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Verification stages: static re-analyses the patched config before it is
+// applied, live re-analyses signals captured after it was applied.
+const (
+	StageStatic = "static"
+	StageLive   = "live"
+)
+
+// Verification verdicts.
+const (
+	VerdictResolved     = "resolved"     // the finding is no longer reported
+	VerdictUnresolved   = "unresolved"   // the finding is still reported
+	VerdictRegressed    = "regressed"    // the fix introduces new critical findings
+	VerdictInconclusive = "inconclusive" // the stage cannot check this finding
+)
+
+// Verification records whether a fix resolves the finding it was generated for.
+type Verification struct {
+	FindingIndex int                       `json:"finding_index"`
+	RuleID       string                    `json:"rule_id"`
+	Stage        string                    `json:"stage"`
+	Verdict      string                    `json:"verdict"`
+	Message      string                    `json:"message"`
+	NewCritical  []types.DiagnosticFinding `json:"new_critical,omitempty"`
+	VerifiedAt   time.Time                 `json:"verified_at"`
+}
+
+// Passed reports whether the fix may proceed to the next stage.
+func (v *Verification) Passed() bool {
+	return v.Verdict == VerdictResolved || v.Verdict == VerdictInconclusive
+}
+
+// VerifyStatic runs the config analyzers against the original and patched
+// configs. The fix passes when the originating finding is gone from the
+// patched config and no critical finding appears that the original did not
+// have. Runtime findings cannot be checked from config, so only the
+// regression check applies to them.
+func VerifyStatic(ctx context.Context, base analysis.AnalysisInput, original, patched string, finding types.DiagnosticFinding) *Verification {
+	v := &Verification{RuleID: finding.RuleID, Stage: StageStatic, VerifiedAt: time.Now()}
+
+	patchedCfg, err := collector.ParseConfig([]byte(patched))
+	if err != nil {
+		v.Verdict = VerdictRegressed
+		v.Message = fmt.Sprintf("The patched config does not parse: %v", err)
+		return v
+	}
+	before := map[string]bool{}
+	if originalCfg, err := collector.ParseConfig([]byte(original)); err == nil {
+		for _, f := range runAnalyzers(ctx, base, originalCfg) {
+			before[findingKey(f)] = true
+		}
+	}
+
+	remaining := false
+	for _, f := range runAnalyzers(ctx, base, patchedCfg) {
+		if sameFinding(finding, f) {
+			remaining = true
+		}
+		if f.Severity == types.SeverityCritical && !before[findingKey(f)] {
+			v.NewCritical = append(v.NewCritical, f)
+		}
+	}
+
+	switch {
+	case len(v.NewCritical) > 0:
+		v.Verdict = VerdictRegressed
+		v.Message = fmt.Sprintf("The patched config has %d new critical findings", len(v.NewCritical))
+	case isRuntimeRule(finding.RuleID):
+		v.Verdict = VerdictInconclusive
+		v.Message = "No new findings in the patched config; runtime findings are confirmed on signals captured after the apply"
+	case remaining:
+		v.Verdict = VerdictUnresolved
+		v.Message = "The finding is still reported against the patched config"
+	default:
+		v.Verdict = VerdictResolved
+		v.Message = "The finding is gone from the patched config and no new critical findings appear"
+	}
+	return v
+}
+
+// VerificationInput returns base with the environment variables the
+// suggestions' manifests define added to the collector pod's containers, so
+// that a config referencing them is verified as it runs once the manifests
+// are applied. base itself is not modified.
+func VerificationInput(base analysis.AnalysisInput, suggestions ...FixSuggestion) analysis.AnalysisInput {
+	var env []corev1.EnvVar
+	for _, s := range suggestions {
+		for _, name := range s.Env {
+			env = append(env, corev1.EnvVar{Name: name})
+		}
+	}
+	if len(env) == 0 || base.PodInfo == nil {
+		return base
+	}
+	pod := base.PodInfo.DeepCopy()
+	for i := range pod.Spec.Containers {
+		pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, env...)
+	}
+	base.PodInfo = pod
+	return base
+}
+
+// VerifyLive runs the runtime analyzers against signals captured after the
+// fix was applied. It is inconclusive when nothing of the finding's signal
+// type was captured.
+func VerifyLive(ctx context.Context, captured *signals.CapturedSignals, configYAML, deploymentMode string, finding types.DiagnosticFinding) *Verification {
+	v := &Verification{RuleID: finding.RuleID, Stage: StageLive, VerifiedAt: time.Now()}
+
+	if captured == nil || capturedRecords(captured, findingSignal(finding)) == 0 {
+		v.Verdict = VerdictInconclusive
+		v.Message = "No matching signals were captured after the apply; capture again with the debug exporter injected"
+		return v
+	}
+
+	input := &runtime.RuntimeAnalysisInput{Signals: captured, CollectorConfig: configYAML, DeploymentMode: deploymentMode}
+	for name, analyzer := range runtime.AllRuntimeAnalyzers() {
+		for _, f := range runRuntimeAnalyzer(ctx, name, analyzer, input) {
+			if sameFinding(finding, f) {
+				v.Verdict = VerdictUnresolved
+				v.Message = "The finding is still reported on signals captured after the apply: " + f.Summary
+				return v
+			}
+		}
+	}
+	v.Verdict = VerdictResolved
+	v.Message = fmt.Sprintf("The finding is gone from %d records captured after the apply", capturedRecords(captured, findingSignal(finding)))
+	return v
+}
+
+func runAnalyzers(ctx context.Context, base analysis.AnalysisInput, cfg *collector.CollectorConfig) []types.DiagnosticFinding {
+	input := base
+	input.Config = cfg
+	var findings []types.DiagnosticFinding
+	for _, analyzer := range analysis.AllAnalyzers() {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					slog.Error("analyzer panicked during fix verification", "error", r)
+				}
+			}()
+			findings = append(findings, analyzer(ctx, &input)...)
+		}()
+	}
+	return findings
+}
+
+func runRuntimeAnalyzer(ctx context.Context, name string, analyzer runtime.RuntimeAnalyzer, input *runtime.RuntimeAnalysisInput) (findings []types.DiagnosticFinding) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("runtime analyzer panicked during fix verification", "analyzer", name, "panic", r)
+		}
+	}()
+	return analyzer(ctx, input)
+}
+
+// sameFinding reports whether f is the finding orig again.
+func sameFinding(orig, f types.DiagnosticFinding) bool {
+	return findingKey(orig) == findingKey(f)
+}
+
+// findingKey identifies a finding across analysis runs by its rule and what
+// it is about. A high-cardinality finding is about the metric, whichever
+// label currently contributes most.
+func findingKey(f types.DiagnosticFinding) string {
+	ev := f.Evidence
+	if ev == nil {
+		return f.RuleID + "|" + f.Summary
+	}
+	attribute := ev.AttributeKey
+	if f.RuleID == types.RuleRuntimeHighCardinality {
+		attribute = ""
+	}
+	return strings.Join([]string{
+		f.RuleID, ev.SignalType, ev.Pipeline, ev.ComponentID, ev.Path, ev.Name, attribute, ev.Kind,
+		strings.Join(ev.Components, ","),
+	}, "|")
+}
+
+func isRuntimeRule(ruleID string) bool {
+	return strings.HasPrefix(ruleID, "runtime.")
+}
+
+func findingSignal(f types.DiagnosticFinding) string {
+	if f.Evidence == nil {
+		return ""
+	}
+	return f.Evidence.SignalType
+}
+
+// capturedRecords counts the captured records of a signal type, or of all
+// types when signal is empty.
+func capturedRecords(captured *signals.CapturedSignals, signal string) int {
+	switch signal {
+	case "traces":
+		return len(captured.Traces)
+	case "metrics":
+		return len(captured.Metrics)
+	case "logs":
+		return len(captured.Logs)
+	}
+	return len(captured.Traces) + len(captured.Metrics) + len(captured.Logs)
+}
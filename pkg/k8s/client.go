@@ -8,6 +8,7 @@ import (
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
@@ -18,6 +19,7 @@ type Clients struct {
 	Clientset     kubernetes.Interface
 	DynamicClient dynamic.Interface
 	Discovery     discovery.DiscoveryInterface
+	Metadata      metadata.Interface
 }
 
 // NewClients creates Kubernetes clients, trying in-cluster config first,
@@ -48,9 +50,15 @@ func NewClients() (*Clients, error) {
 		return nil, fmt.Errorf("failed to create discovery client: %w", err)
 	}
 
+	metaClient, err := metadata.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metadata client: %w", err)
+	}
+
 	return &Clients{
 		Clientset:     clientset,
 		DynamicClient: dynClient,
 		Discovery:     disc,
+		Metadata:      metaClient,
 	}, nil
 }
@@ -0,0 +1,412 @@
+package mutator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Canary strategies.
+const (
+	// CanaryWorkload runs a temporary single-replica copy of a Deployment or
+	// StatefulSet with the new config next to the unchanged collector.
+	CanaryWorkload = "workload"
+	// CanaryPaced switches a DaemonSet to OnDelete and replaces its pod on
+	// one node before the rest of the nodes are rolled.
+	CanaryPaced = "paced"
+)
+
+// LabelCanary marks the pods and objects of a canary workload.
+const LabelCanary = "mcp.otel.dev/canary"
+
+// CanaryOptions enables the canary stage of SafeApply.
+type CanaryOptions struct {
+	// Timeout bounds how long canary pods get to become healthy.
+	Timeout time.Duration
+}
+
+// CanaryResult records what the canary stage did.
+type CanaryResult struct {
+	Strategy  string          `json:"strategy,omitempty"`
+	Workload  string          `json:"workload,omitempty"`
+	Node      string          `json:"node,omitempty"`
+	Promoted  bool            `json:"promoted"`
+	Failure   string          `json:"failure,omitempty"`
+	Telemetry *TelemetryCheck `json:"telemetry,omitempty"`
+	Skipped   string          `json:"skipped,omitempty"`
+}
+
+// canaryStrategy picks the canary strategy for a collector, resolving its
+// ConfigMap and owning workload from its pods when the reference does not
+// name them. An empty strategy comes with the reason the canary is skipped.
+func canaryStrategy(ctx context.Context, clientset kubernetes.Interface, ref *CollectorRef) (string, string) {
+	if ref.DeploymentMode == ModeOperatorCRD {
+		return "", "the OpenTelemetry Operator rolls out operator-managed collectors"
+	}
+	resolved, err := ResolveCollectorRef(ctx, clientset, *ref)
+	*ref = resolved
+	if err != nil {
+		return "", err.Error()
+	}
+
+	// A paced DaemonSet rollout goes through the mutator and needs no copy
+	// of the ConfigMap
+	if ref.OwnerKind == "DaemonSet" {
+		return CanaryPaced, ""
+	}
+	if ref.ConfigMapName == "" {
+		return "", "the collector's ConfigMap is not known"
+	}
+
+	var replicas *int32
+	switch ref.OwnerKind {
+	case "Deployment":
+		d, err := clientset.AppsV1().Deployments(ref.Namespace).Get(ctx, ref.OwnerName, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Sprintf("failed to get Deployment %s: %v", ref.OwnerName, err)
+		}
+		replicas = d.Spec.Replicas
+	case "StatefulSet":
+		s, err := clientset.AppsV1().StatefulSets(ref.Namespace).Get(ctx, ref.OwnerName, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Sprintf("failed to get StatefulSet %s: %v", ref.OwnerName, err)
+		}
+		replicas = s.Spec.Replicas
+	default:
+		return "", fmt.Sprintf("unsupported owner kind: %s", ref.OwnerKind)
+	}
+	if replicas != nil && *replicas <= 1 {
+		return "", "the collector runs a single replica"
+	}
+	return CanaryWorkload, ""
+}
+
+// runWorkloadCanary runs a single-replica copy of the collector's workload
+// with configYAML and checks it with the health and telemetry gates. The
+// copy and its ConfigMap are deleted afterwards; the collector itself is
+// not changed.
+func runWorkloadCanary(ctx context.Context, clientset kubernetes.Interface, ref CollectorRef, configYAML string, opts SafeApplyOptions, before *TelemetrySnapshot, result *CanaryResult) error {
+	cm, err := clientset.CoreV1().ConfigMaps(ref.Namespace).Get(ctx, ref.ConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get ConfigMap %s/%s: %w", ref.Namespace, ref.ConfigMapName, err)
+	}
+	data := make(map[string]string, len(cm.Data))
+	for k, v := range cm.Data {
+		data[k] = v
+	}
+	key := (&ConfigMapMutator{ref: ref}).configKey(data)
+	if key == "" {
+		return fmt.Errorf("no collector config key found in ConfigMap %s/%s", ref.Namespace, ref.ConfigMapName)
+	}
+	data[key] = configYAML
+
+	name := ref.OwnerName + "-mcp-canary"
+	objectLabels := map[string]string{"app.kubernetes.io/managed-by": "otel-collector-mcp", LabelCanary: "true"}
+	canaryCM := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ref.Namespace, Labels: objectLabels},
+		Data:       data,
+	}
+	if _, err := clientset.CoreV1().ConfigMaps(ref.Namespace).Create(ctx, canaryCM, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create canary ConfigMap: %w", err)
+	}
+	defer deleteCanary(clientset, ref, name)
+
+	var selector map[string]string
+	switch ref.OwnerKind {
+	case "Deployment":
+		owner, err := clientset.AppsV1().Deployments(ref.Namespace).Get(ctx, ref.OwnerName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get Deployment %s: %w", ref.OwnerName, err)
+		}
+		canary, err := canaryDeployment(owner, name, ref.ConfigMapName)
+		if err != nil {
+			return err
+		}
+		canary.Labels = objectLabels
+		if _, err := clientset.AppsV1().Deployments(ref.Namespace).Create(ctx, canary, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create canary Deployment: %w", err)
+		}
+		selector = canary.Spec.Selector.MatchLabels
+	case "StatefulSet":
+		owner, err := clientset.AppsV1().StatefulSets(ref.Namespace).Get(ctx, ref.OwnerName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get StatefulSet %s: %w", ref.OwnerName, err)
+		}
+		canary, err := canaryStatefulSet(owner, name, ref.ConfigMapName)
+		if err != nil {
+			return err
+		}
+		canary.Labels = objectLabels
+		if _, err := clientset.AppsV1().StatefulSets(ref.Namespace).Create(ctx, canary, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create canary StatefulSet: %w", err)
+		}
+		selector = canary.Spec.Selector.MatchLabels
+	}
+	result.Workload = name
+
+	labelSelector := labels.SelectorFromSet(selector).String()
+	if err := waitPodsHealthy(ctx, clientset, ref.Namespace, labelSelector, "canary "+name, opts.Canary.Timeout); err != nil {
+		result.Failure = err.Error()
+		return nil
+	}
+
+	result.Failure = canaryTelemetry(ctx, clientset, ref.Namespace, opts.Telemetry, before, result, func(ctx context.Context) ([]corev1.Pod, error) {
+		pods, err := clientset.CoreV1().Pods(ref.Namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			return nil, err
+		}
+		return pods.Items, nil
+	})
+	result.Promoted = result.Failure == ""
+	return nil
+}
+
+// canaryTelemetry observes the canary pods for the telemetry gate's window
+// and compares them with the collector's metrics from before the change. It
+// returns the regressions found, or "" when there are none or the gate is
+// off or cannot run.
+func canaryTelemetry(ctx context.Context, clientset kubernetes.Interface, namespace string, gate *TelemetryGate, before *TelemetrySnapshot, result *CanaryResult, pods func(context.Context) ([]corev1.Pod, error)) string {
+	if gate == nil || before == nil {
+		return ""
+	}
+	check := &TelemetryCheck{Before: before}
+	result.Telemetry = check
+
+	select {
+	case <-time.After(gate.Thresholds.Window):
+	case <-ctx.Done():
+		check.Skipped = "telemetry observation was cancelled"
+		return ""
+	}
+
+	items, err := pods(ctx)
+	if err == nil {
+		check.After, err = scrapePods(ctx, clientset, namespace, items, gate.AfterPort)
+	}
+	if err != nil {
+		check.Skipped = err.Error()
+		return ""
+	}
+	check.Regressions = CompareTelemetry(check.Before, check.After, gate.Thresholds)
+	if len(check.Regressions) > 0 {
+		return fmt.Sprintf("canary telemetry regressed: %v", check.Regressions)
+	}
+	return ""
+}
+
+// canaryDeployment returns a single-replica copy of owner named name whose
+// pods mount the ConfigMap of the same name instead of configMap.
+func canaryDeployment(owner *appsv1.Deployment, name, configMap string) (*appsv1.Deployment, error) {
+	template, selector, err := canaryTemplate(owner.Spec.Template, name, configMap)
+	if err != nil {
+		return nil, err
+	}
+	replicas := int32(1)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: owner.Namespace},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+			Template: template,
+		},
+	}, nil
+}
+
+// canaryStatefulSet returns a single-replica copy of owner named name whose
+// pods mount the ConfigMap of the same name instead of configMap. Volume
+// claims of the canary are deleted with it.
+func canaryStatefulSet(owner *appsv1.StatefulSet, name, configMap string) (*appsv1.StatefulSet, error) {
+	template, selector, err := canaryTemplate(owner.Spec.Template, name, configMap)
+	if err != nil {
+		return nil, err
+	}
+	replicas := int32(1)
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: owner.Namespace},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:             &replicas,
+			Selector:             &metav1.LabelSelector{MatchLabels: selector},
+			Template:             template,
+			ServiceName:          owner.Spec.ServiceName,
+			VolumeClaimTemplates: owner.Spec.VolumeClaimTemplates,
+			PersistentVolumeClaimRetentionPolicy: &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+				WhenDeleted: appsv1.DeletePersistentVolumeClaimRetentionPolicyType,
+				WhenScaled:  appsv1.DeletePersistentVolumeClaimRetentionPolicyType,
+			},
+		},
+	}, nil
+}
+
+// canaryTemplate copies a pod template for a canary: the config volume points
+// at the canary ConfigMap and the pods carry LabelCanary, which the returned
+// selector matches on. The pods keep the collector's other labels, so they
+// receive a share of its traffic.
+func canaryTemplate(template corev1.PodTemplateSpec, name, configMap string) (corev1.PodTemplateSpec, map[string]string, error) {
+	t := *template.DeepCopy()
+
+	mounted := false
+	for i := range t.Spec.Volumes {
+		if cm := t.Spec.Volumes[i].ConfigMap; cm != nil && cm.Name == configMap {
+			cm.Name = name
+			mounted = true
+		}
+	}
+	if !mounted {
+		return t, nil, fmt.Errorf("the collector's pods do not mount ConfigMap %s", configMap)
+	}
+
+	selector := map[string]string{LabelCanary: "true"}
+	for k, v := range t.Labels {
+		selector[k] = v
+	}
+	t.Labels = selector
+	return t, selector, nil
+}
+
+// deleteCanary deletes a canary workload and its ConfigMap.
+func deleteCanary(clientset kubernetes.Interface, ref CollectorRef, name string) {
+	ctx := context.Background()
+	propagation := metav1.DeletePropagationBackground
+	opts := metav1.DeleteOptions{PropagationPolicy: &propagation}
+
+	var err error
+	switch ref.OwnerKind {
+	case "Deployment":
+		err = clientset.AppsV1().Deployments(ref.Namespace).Delete(ctx, name, opts)
+	case "StatefulSet":
+		err = clientset.AppsV1().StatefulSets(ref.Namespace).Delete(ctx, name, opts)
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		slog.Warn("failed to delete canary workload", "workload", name, "error", err)
+	}
+	if err := clientset.CoreV1().ConfigMaps(ref.Namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		slog.Warn("failed to delete canary ConfigMap", "configmap", name, "error", err)
+	}
+}
+
+// pacedDaemonSet holds a DaemonSet on the OnDelete update strategy while its
+// pod on one node runs the new config.
+type pacedDaemonSet struct {
+	clientset kubernetes.Interface
+	ref       CollectorRef
+	strategy  appsv1.DaemonSetUpdateStrategy
+}
+
+// pauseDaemonSet switches the collector's DaemonSet to the OnDelete update
+// strategy so that a rollout replaces no pods by itself.
+func pauseDaemonSet(ctx context.Context, clientset kubernetes.Interface, ref CollectorRef) (*pacedDaemonSet, error) {
+	ds, err := clientset.AppsV1().DaemonSets(ref.Namespace).Get(ctx, ref.OwnerName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get DaemonSet %s: %w", ref.OwnerName, err)
+	}
+	p := &pacedDaemonSet{clientset: clientset, ref: ref, strategy: ds.Spec.UpdateStrategy}
+	if err := p.setStrategy(ctx, appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType}); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// resume restores the DaemonSet's own update strategy; with RollingUpdate
+// the controller then rolls the remaining pods at its maxUnavailable pace.
+func (p *pacedDaemonSet) resume(ctx context.Context) error {
+	return p.setStrategy(ctx, p.strategy)
+}
+
+func (p *pacedDaemonSet) setStrategy(ctx context.Context, strategy appsv1.DaemonSetUpdateStrategy) error {
+	// A merge patch replaces updateStrategy as a whole, so rollingUpdate is
+	// dropped when switching to OnDelete and restored when switching back
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"updateStrategy": map[string]interface{}{
+			"type":          strategy.Type,
+			"rollingUpdate": strategy.RollingUpdate,
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal update strategy patch: %w", err)
+	}
+	_, err = p.clientset.AppsV1().DaemonSets(p.ref.Namespace).Patch(ctx, p.ref.OwnerName, k8stypes.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to set update strategy of DaemonSet %s: %w", p.ref.OwnerName, err)
+	}
+	return nil
+}
+
+// runPacedCanary replaces the collector pod on one node, which picks up the
+// already applied config, and checks it with the health and telemetry gates.
+func (p *pacedDaemonSet) runPacedCanary(ctx context.Context, opts SafeApplyOptions, before *TelemetrySnapshot, result *CanaryResult) error {
+	selector := fmt.Sprintf("app.kubernetes.io/instance=%s", p.ref.Name)
+	pods, err := p.clientset.CoreV1().Pods(p.ref.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+	var old *corev1.Pod
+	for i := range pods.Items {
+		if pods.Items[i].Spec.NodeName != "" && pods.Items[i].DeletionTimestamp == nil {
+			old = &pods.Items[i]
+			break
+		}
+	}
+	if old == nil {
+		return fmt.Errorf("no scheduled pod of collector %s/%s to replace", p.ref.Namespace, p.ref.Name)
+	}
+	result.Node = old.Spec.NodeName
+	result.Workload = p.ref.OwnerName
+
+	if err := p.clientset.CoreV1().Pods(p.ref.Namespace).Delete(ctx, old.Name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("failed to delete pod %s: %w", old.Name, err)
+	}
+
+	// The replacement is the pod on the canary node that is not the old one
+	nodePods := func(ctx context.Context) ([]corev1.Pod, error) {
+		list, err := p.clientset.CoreV1().Pods(p.ref.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, err
+		}
+		var out []corev1.Pod
+		for _, pod := range list.Items {
+			if pod.Spec.NodeName == result.Node && pod.UID != old.UID && pod.DeletionTimestamp == nil {
+				out = append(out, pod)
+			}
+		}
+		return out, nil
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, opts.Canary.Timeout)
+	defer cancel()
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	for {
+		items, err := nodePods(waitCtx)
+		if err != nil {
+			return fmt.Errorf("failed to list pods: %w", err)
+		}
+		status := StatusNotFound
+		if len(items) > 0 {
+			status = CheckPodHealth(&items[0]).Status
+		}
+		if status == StatusHealthy {
+			break
+		}
+
+		select {
+		case <-waitCtx.Done():
+			result.Failure = fmt.Sprintf("timed out waiting for the canary pod on node %s to become healthy (last status: %s)", result.Node, status)
+			return nil
+		case <-ticker.C:
+		}
+	}
+
+	result.Failure = canaryTelemetry(ctx, p.clientset, p.ref.Namespace, opts.Telemetry, before, result, nodePods)
+	result.Promoted = result.Failure == ""
+	return nil
+}
@@ -0,0 +1,228 @@
+package mutator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func collectorDeployment(replicas int32) *appsv1.Deployment {
+	labels := map[string]string{"app.kubernetes.io/instance": "otel"}
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "otel-collector", Namespace: "obs"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "otc", Image: "otel/opentelemetry-collector-contrib:0.120.0"}},
+					Volumes: []corev1.Volume{{
+						Name: "config",
+						VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "otel-config"}},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func healthyPod(name string, labels map[string]string, owner *metav1.OwnerReference) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "obs", Labels: labels},
+		Spec:       corev1.PodSpec{NodeName: "node-a"},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	if owner != nil {
+		pod.OwnerReferences = []metav1.OwnerReference{*owner}
+	}
+	return pod
+}
+
+func TestCanaryStrategy(t *testing.T) {
+	controller := true
+	rsOwner := &metav1.OwnerReference{Kind: "ReplicaSet", Name: "otel-collector-abc", Controller: &controller}
+	rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name: "otel-collector-abc", Namespace: "obs",
+		OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "otel-collector", Controller: &controller}},
+	}}
+	dsOwner := &metav1.OwnerReference{Kind: "DaemonSet", Name: "otel-agent", Controller: &controller}
+	labels := map[string]string{"app.kubernetes.io/instance": "otel"}
+
+	tests := []struct {
+		name        string
+		ref         CollectorRef
+		pod         *corev1.Pod
+		replicas    int32
+		want        string
+		wantSkipped bool
+	}{
+		{name: "multi-replica Deployment", ref: CollectorRef{ConfigMapName: "otel-config"}, pod: healthyPod("otel-1", labels, rsOwner), replicas: 3, want: CanaryWorkload},
+		{name: "single replica", ref: CollectorRef{ConfigMapName: "otel-config"}, pod: healthyPod("otel-1", labels, rsOwner), replicas: 1, wantSkipped: true},
+		{name: "DaemonSet", ref: CollectorRef{ConfigMapName: "otel-config"}, pod: healthyPod("otel-1", labels, dsOwner), replicas: 3, want: CanaryPaced},
+		{name: "operator managed", ref: CollectorRef{DeploymentMode: ModeOperatorCRD}, pod: healthyPod("otel-1", labels, rsOwner), replicas: 3, wantSkipped: true},
+		{name: "unknown ConfigMap", ref: CollectorRef{}, pod: healthyPod("otel-1", labels, rsOwner), replicas: 3, wantSkipped: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(tt.pod, rs, collectorDeployment(tt.replicas))
+			ref := tt.ref
+			ref.Name, ref.Namespace = "otel", "obs"
+
+			got, skipped := canaryStrategy(context.Background(), clientset, &ref)
+			if got != tt.want {
+				t.Errorf("expected strategy %q, got %q (skipped: %s)", tt.want, got, skipped)
+			}
+			if (skipped != "") != tt.wantSkipped {
+				t.Errorf("expected skipped %v, got %q", tt.wantSkipped, skipped)
+			}
+		})
+	}
+}
+
+func TestCanaryStrategy_StartAnalysisRef(t *testing.T) {
+	controller := true
+	rsOwner := &metav1.OwnerReference{Kind: "ReplicaSet", Name: "otel-collector-abc", Controller: &controller}
+	rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name: "otel-collector-abc", Namespace: "obs",
+		OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "otel-collector", Controller: &controller}},
+	}}
+	dsOwner := &metav1.OwnerReference{Kind: "DaemonSet", Name: "otel-agent", Controller: &controller}
+	labels := map[string]string{"app.kubernetes.io/instance": "otel"}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "otel-config", Namespace: "obs"},
+		Data:       map[string]string{"relay": testConfig},
+	}
+	mounted := func(pod *corev1.Pod) *corev1.Pod {
+		pod.Spec.Volumes = collectorDeployment(1).Spec.Template.Spec.Volumes
+		return pod
+	}
+
+	tests := []struct {
+		name          string
+		pod           *corev1.Pod
+		want          string
+		wantConfigMap string
+		wantOwner     string
+	}{
+		{name: "Deployment", pod: mounted(healthyPod("otel-1", labels, rsOwner)), want: CanaryWorkload, wantConfigMap: "otel-config", wantOwner: "otel-collector"},
+		{name: "DaemonSet", pod: mounted(healthyPod("otel-1", labels, dsOwner)), want: CanaryPaced, wantConfigMap: "otel-config", wantOwner: "otel-agent"},
+		{name: "DaemonSet without a config volume", pod: healthyPod("otel-1", labels, dsOwner), want: CanaryPaced, wantOwner: "otel-agent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(tt.pod, rs, cm, collectorDeployment(3))
+			// start_analysis only knows the collector's name and namespace
+			ref := CollectorRef{Name: "otel", Namespace: "obs"}
+
+			got, skipped := canaryStrategy(context.Background(), clientset, &ref)
+			if got != tt.want {
+				t.Errorf("expected strategy %q, got %q (skipped: %s)", tt.want, got, skipped)
+			}
+			if ref.ConfigMapName != tt.wantConfigMap || ref.OwnerName != tt.wantOwner {
+				t.Errorf("expected ConfigMap %q and owner %q, got %+v", tt.wantConfigMap, tt.wantOwner, ref)
+			}
+		})
+	}
+}
+
+func TestCanaryDeployment(t *testing.T) {
+	canary, err := canaryDeployment(collectorDeployment(3), "otel-collector-mcp-canary", "otel-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *canary.Spec.Replicas != 1 {
+		t.Errorf("expected 1 canary replica, got %d", *canary.Spec.Replicas)
+	}
+	if got := canary.Spec.Template.Spec.Volumes[0].ConfigMap.Name; got != "otel-collector-mcp-canary" {
+		t.Errorf("expected the canary ConfigMap to be mounted, got %s", got)
+	}
+	selector := canary.Spec.Selector.MatchLabels
+	if selector[LabelCanary] != "true" || selector["app.kubernetes.io/instance"] != "otel" {
+		t.Errorf("expected the selector to keep the collector labels and add the canary label, got %v", selector)
+	}
+
+	if _, err := canaryDeployment(collectorDeployment(3), "otel-collector-mcp-canary", "other-config"); err == nil {
+		t.Errorf("expected an error when the pods do not mount the collector's ConfigMap")
+	}
+}
+
+func TestRunWorkloadCanary(t *testing.T) {
+	canaryLabels := map[string]string{"app.kubernetes.io/instance": "otel", LabelCanary: "true"}
+	clientset := fake.NewSimpleClientset(
+		collectorDeployment(3),
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "otel-config", Namespace: "obs"},
+			Data:       map[string]string{"relay": testConfig},
+		},
+		// Stands in for the pod the canary Deployment would start
+		healthyPod("otel-collector-mcp-canary-1", canaryLabels, nil),
+	)
+	ref := CollectorRef{Name: "otel", Namespace: "obs", ConfigMapName: "otel-config", OwnerKind: "Deployment", OwnerName: "otel-collector"}
+	opts := SafeApplyOptions{Canary: &CanaryOptions{Timeout: 5 * time.Second}}
+
+	result := &CanaryResult{}
+	if err := runWorkloadCanary(context.Background(), clientset, ref, "receivers: {}\n", opts, nil, result); err != nil {
+		t.Fatal(err)
+	}
+	if !result.Promoted || result.Failure != "" {
+		t.Errorf("expected the canary to be promoted, got %+v", result)
+	}
+
+	// The canary workload and its ConfigMap are removed; the collector's config is not touched
+	if _, err := clientset.AppsV1().Deployments("obs").Get(context.Background(), "otel-collector-mcp-canary", metav1.GetOptions{}); err == nil {
+		t.Errorf("expected the canary Deployment to be deleted")
+	}
+	if _, err := clientset.CoreV1().ConfigMaps("obs").Get(context.Background(), "otel-collector-mcp-canary", metav1.GetOptions{}); err == nil {
+		t.Errorf("expected the canary ConfigMap to be deleted")
+	}
+	cm, _ := clientset.CoreV1().ConfigMaps("obs").Get(context.Background(), "otel-config", metav1.GetOptions{})
+	if cm.Data["relay"] != testConfig {
+		t.Errorf("expected the collector's config to be unchanged")
+	}
+}
+
+func TestPauseDaemonSet(t *testing.T) {
+	maxUnavailable := intstr.FromInt(2)
+	clientset := fake.NewSimpleClientset(&appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "otel-agent", Namespace: "obs"},
+		Spec: appsv1.DaemonSetSpec{UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
+			Type:          appsv1.RollingUpdateDaemonSetStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxUnavailable: &maxUnavailable},
+		}},
+	})
+	ref := CollectorRef{Name: "otel", Namespace: "obs", OwnerKind: "DaemonSet", OwnerName: "otel-agent"}
+	ctx := context.Background()
+
+	paced, err := pauseDaemonSet(ctx, clientset, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds, _ := clientset.AppsV1().DaemonSets("obs").Get(ctx, "otel-agent", metav1.GetOptions{})
+	if ds.Spec.UpdateStrategy.Type != appsv1.OnDeleteDaemonSetStrategyType || ds.Spec.UpdateStrategy.RollingUpdate != nil {
+		t.Errorf("expected OnDelete without rollingUpdate, got %+v", ds.Spec.UpdateStrategy)
+	}
+
+	if err := paced.resume(ctx); err != nil {
+		t.Fatal(err)
+	}
+	ds, _ = clientset.AppsV1().DaemonSets("obs").Get(ctx, "otel-agent", metav1.GetOptions{})
+	if ds.Spec.UpdateStrategy.Type != appsv1.RollingUpdateDaemonSetStrategyType ||
+		ds.Spec.UpdateStrategy.RollingUpdate == nil || ds.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable.IntValue() != 2 {
+		t.Errorf("expected the original RollingUpdate strategy back, got %+v", ds.Spec.UpdateStrategy)
+	}
+}
@@ -54,7 +54,70 @@ func (m *ConfigMapMutator) Backup(ctx context.Context, sessionID string) error {
 	return nil
 }
 
+// configKeys are the ConfigMap keys that commonly hold the collector config.
+var configKeys = []string{"relay", "config.yaml", "collector.yaml", "otel-collector-config"}
+
+// configKey returns the data key holding the collector config: the key from
+// the collector reference, else the first common key present, else the only key.
+func (m *ConfigMapMutator) configKey(data map[string]string) string {
+	if m.ref.ConfigKey != "" {
+		return m.ref.ConfigKey
+	}
+	for _, key := range configKeys {
+		if _, ok := data[key]; ok {
+			return key
+		}
+	}
+	if len(data) == 1 {
+		for key := range data {
+			return key
+		}
+	}
+	return ""
+}
+
+func (m *ConfigMapMutator) CurrentConfig(ctx context.Context) (string, error) {
+	cm, err := m.clientset.CoreV1().ConfigMaps(m.ref.Namespace).Get(ctx, m.ref.ConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get ConfigMap %s/%s: %w", m.ref.Namespace, m.ref.ConfigMapName, err)
+	}
+	key := m.configKey(cm.Data)
+	if key == "" {
+		return "", fmt.Errorf("no collector config key found in ConfigMap %s/%s", m.ref.Namespace, m.ref.ConfigMapName)
+	}
+	return cm.Data[key], nil
+}
+
+func (m *ConfigMapMutator) BackedUpConfig(ctx context.Context) (string, error) {
+	cm, err := m.clientset.CoreV1().ConfigMaps(m.ref.Namespace).Get(ctx, m.ref.ConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get ConfigMap %s/%s: %w", m.ref.Namespace, m.ref.ConfigMapName, err)
+	}
+
+	backupJSON, ok := cm.Annotations[AnnotationConfigBackup]
+	if !ok {
+		return "", fmt.Errorf("no backup annotation found on ConfigMap %s/%s", m.ref.Namespace, m.ref.ConfigMapName)
+	}
+	var backupData map[string]string
+	if err := json.Unmarshal([]byte(backupJSON), &backupData); err != nil {
+		return "", fmt.Errorf("failed to unmarshal backup data: %w", err)
+	}
+	key := m.configKey(backupData)
+	if key == "" {
+		return "", fmt.Errorf("no collector config key found in the backup of ConfigMap %s/%s", m.ref.Namespace, m.ref.ConfigMapName)
+	}
+	return backupData[key], nil
+}
+
 func (m *ConfigMapMutator) ApplyConfig(ctx context.Context, configYAML string) error {
+	return m.updateConfig(ctx, configYAML, metav1.UpdateOptions{})
+}
+
+func (m *ConfigMapMutator) DryRunConfig(ctx context.Context, configYAML string) error {
+	return m.updateConfig(ctx, configYAML, metav1.UpdateOptions{DryRun: []string{metav1.DryRunAll}})
+}
+
+func (m *ConfigMapMutator) updateConfig(ctx context.Context, configYAML string, opts metav1.UpdateOptions) error {
 	cm, err := m.clientset.CoreV1().ConfigMaps(m.ref.Namespace).Get(ctx, m.ref.ConfigMapName, metav1.GetOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to get ConfigMap: %w", err)
@@ -64,9 +127,13 @@ func (m *ConfigMapMutator) ApplyConfig(ctx context.Context, configYAML string) e
 	if cm.Data == nil {
 		cm.Data = make(map[string]string)
 	}
-	cm.Data[m.ref.ConfigKey] = configYAML
+	key := m.configKey(cm.Data)
+	if key == "" {
+		return fmt.Errorf("no collector config key found in ConfigMap %s/%s", m.ref.Namespace, m.ref.ConfigMapName)
+	}
+	cm.Data[key] = configYAML
 
-	_, err = m.clientset.CoreV1().ConfigMaps(m.ref.Namespace).Update(ctx, cm, metav1.UpdateOptions{})
+	_, err = m.clientset.CoreV1().ConfigMaps(m.ref.Namespace).Update(ctx, cm, opts)
 	if err != nil {
 		return fmt.Errorf("failed to update ConfigMap config: %w", err)
 	}
@@ -0,0 +1,117 @@
+package mutator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestConfigMapMutator_CurrentConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		configKey string
+		data      map[string]string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name: "common key",
+			data: map[string]string{"relay": testConfig, "other": "x"},
+			want: testConfig,
+		},
+		{
+			name: "single key",
+			data: map[string]string{"otelcol.yaml": testConfig},
+			want: testConfig,
+		},
+		{
+			name:      "key from reference",
+			configKey: "other",
+			data:      map[string]string{"relay": testConfig, "other": "x"},
+			want:      "x",
+		},
+		{
+			name:    "ambiguous keys",
+			data:    map[string]string{"a": "x", "b": "y"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "otel", Namespace: "obs"},
+				Data:       tt.data,
+			})
+			m := NewConfigMapMutator(clientset, CollectorRef{Name: "otel", Namespace: "obs", ConfigMapName: "otel", ConfigKey: tt.configKey})
+
+			got, err := m.CurrentConfig(context.Background())
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+
+			if err := m.ApplyConfig(context.Background(), "updated"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got, _ := m.CurrentConfig(context.Background()); got != "updated" {
+				t.Errorf("expected the applied config to be read back, got %q", got)
+			}
+		})
+	}
+}
+
+func TestConfigMapMutator_DryRunConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		reject  bool
+		wantErr bool
+	}{
+		{name: "accepted"},
+		{name: "rejected by admission webhook", reject: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "otel", Namespace: "obs"},
+				Data:       map[string]string{"relay": testConfig},
+			})
+			var dryRun []string
+			clientset.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				dryRun = action.(k8stesting.UpdateActionImpl).UpdateOptions.DryRun
+				if tt.reject {
+					return true, nil, fmt.Errorf(`admission webhook "validate.otel.dev" denied the request`)
+				}
+				// The fake clientset does not honour dry runs, so stop the update here
+				return true, action.(k8stesting.UpdateActionImpl).GetObject(), nil
+			})
+			m := NewConfigMapMutator(clientset, CollectorRef{Name: "otel", Namespace: "obs", ConfigMapName: "otel"})
+
+			err := m.DryRunConfig(context.Background(), "updated")
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if len(dryRun) != 1 || dryRun[0] != metav1.DryRunAll {
+				t.Errorf("expected DryRun [All], got %v", dryRun)
+			}
+			if got, _ := m.CurrentConfig(context.Background()); got != testConfig {
+				t.Errorf("expected the live config to be unchanged, got %q", got)
+			}
+		})
+	}
+}
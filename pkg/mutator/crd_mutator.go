@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"gopkg.in/yaml.v3"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -80,7 +81,80 @@ func (m *CRDMutator) Backup(ctx context.Context, sessionID string) error {
 	return nil
 }
 
+func (m *CRDMutator) CurrentConfig(ctx context.Context) (string, error) {
+	if m.dynamicClient == nil {
+		return "", fmt.Errorf("dynamic client not configured for CRD operations")
+	}
+
+	cr, err := m.dynamicClient.Resource(otelCollectorGVR).Namespace(m.ref.Namespace).Get(ctx, m.ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get OpenTelemetryCollector CR %s/%s: %w", m.ref.Namespace, m.ref.Name, err)
+	}
+
+	spec, _, _ := unstructured.NestedMap(cr.Object, "spec")
+	config, err := configFromSpec(spec)
+	if err != nil {
+		return "", err
+	}
+	if config == "" {
+		return "", fmt.Errorf("no spec.config found in CR %s/%s", m.ref.Namespace, m.ref.Name)
+	}
+	return config, nil
+}
+
+func (m *CRDMutator) BackedUpConfig(ctx context.Context) (string, error) {
+	if m.dynamicClient == nil {
+		return "", fmt.Errorf("dynamic client not configured for CRD operations")
+	}
+
+	cr, err := m.dynamicClient.Resource(otelCollectorGVR).Namespace(m.ref.Namespace).Get(ctx, m.ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get OpenTelemetryCollector CR %s/%s: %w", m.ref.Namespace, m.ref.Name, err)
+	}
+
+	backupJSON, ok := cr.GetAnnotations()[AnnotationConfigBackup]
+	if !ok {
+		return "", fmt.Errorf("no backup annotation found on CR %s/%s", m.ref.Namespace, m.ref.Name)
+	}
+	var backupSpec map[string]interface{}
+	if err := json.Unmarshal([]byte(backupJSON), &backupSpec); err != nil {
+		return "", fmt.Errorf("failed to unmarshal backup spec: %w", err)
+	}
+	config, err := configFromSpec(backupSpec)
+	if err != nil {
+		return "", err
+	}
+	if config == "" {
+		return "", fmt.Errorf("no spec.config found in the backup of CR %s/%s", m.ref.Namespace, m.ref.Name)
+	}
+	return config, nil
+}
+
+// configFromSpec returns the collector config of a CR spec. v1alpha1 stores
+// the config as a string, v1beta1 as an object.
+func configFromSpec(spec map[string]interface{}) (string, error) {
+	switch config := spec["config"].(type) {
+	case string:
+		return config, nil
+	case map[string]interface{}:
+		out, err := yaml.Marshal(config)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal CR spec.config: %w", err)
+		}
+		return string(out), nil
+	}
+	return "", nil
+}
+
 func (m *CRDMutator) ApplyConfig(ctx context.Context, configYAML string) error {
+	return m.patchConfig(ctx, configYAML, metav1.PatchOptions{})
+}
+
+func (m *CRDMutator) DryRunConfig(ctx context.Context, configYAML string) error {
+	return m.patchConfig(ctx, configYAML, metav1.PatchOptions{DryRun: []string{metav1.DryRunAll}})
+}
+
+func (m *CRDMutator) patchConfig(ctx context.Context, configYAML string, opts metav1.PatchOptions) error {
 	if m.dynamicClient == nil {
 		return fmt.Errorf("dynamic client not configured for CRD operations")
 	}
@@ -97,7 +171,7 @@ func (m *CRDMutator) ApplyConfig(ctx context.Context, configYAML string) error {
 	}
 
 	_, err = m.dynamicClient.Resource(otelCollectorGVR).Namespace(m.ref.Namespace).Patch(
-		ctx, m.ref.Name, k8stypes.MergePatchType, patchJSON, metav1.PatchOptions{})
+		ctx, m.ref.Name, k8stypes.MergePatchType, patchJSON, opts)
 	if err != nil {
 		return fmt.Errorf("failed to patch CR spec.config: %w", err)
 	}
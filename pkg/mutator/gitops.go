@@ -0,0 +1,315 @@
+package mutator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// GitOps controllers a collector can be managed by.
+const (
+	GitOpsArgoCD = "argocd"
+	GitOpsFlux   = "flux"
+)
+
+// GitOps annotation keys. AnnotationSyncSuspended marks a collector resource
+// whose sync was suspended by a session; AnnotationSuspendedAutomatedSync
+// holds the automated sync policy of an Argo CD Application while it is
+// suspended.
+const (
+	AnnotationArgoTrackingID         = "argocd.argoproj.io/tracking-id"
+	AnnotationFluxReconcile          = "kustomize.toolkit.fluxcd.io/reconcile"
+	AnnotationFluxDriftDetection     = "helm.toolkit.fluxcd.io/driftDetection"
+	AnnotationSyncSuspended          = "mcp.otel.dev/sync-suspended"
+	AnnotationSuspendedAutomatedSync = "mcp.otel.dev/suspended-automated-sync"
+)
+
+// ErrUnknownApplication is returned when the sync of a collector managed by
+// Argo CD is suspended but its Application is not named by a tracking ID.
+var ErrUnknownApplication = errors.New("the Argo CD Application managing the collector is unknown")
+
+var argoApplicationGVR = schema.GroupVersionResource{
+	Group:    "argoproj.io",
+	Version:  "v1alpha1",
+	Resource: "applications",
+}
+
+// GitOpsOwner identifies the GitOps object that manages a collector resource.
+type GitOpsOwner struct {
+	Tool      string `json:"tool"`
+	Kind      string `json:"kind,omitempty"` // Application, Kustomization or HelmRelease
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+func (o *GitOpsOwner) String() string {
+	if o.Name == "" {
+		return o.Tool
+	}
+	return fmt.Sprintf("%s %s %s/%s", o.Tool, o.Kind, o.Namespace, o.Name)
+}
+
+// GitOpsOwnerOf returns the GitOps owner recorded in the labels and
+// annotations of a resource, or nil when it is not GitOps-managed. Argo CD
+// Applications without a namespace in their tracking ID are looked up in
+// argoNamespace.
+func GitOpsOwnerOf(obj metav1.Object, argoNamespace string) *GitOpsOwner {
+	labels, annotations := obj.GetLabels(), obj.GetAnnotations()
+
+	// Argo CD tracking IDs are <app>:<group>/<kind>:<namespace>/<name>, with
+	// <namespace>_<app> for Applications outside the Argo CD namespace
+	if id, ok := annotations[AnnotationArgoTrackingID]; ok {
+		app, _, _ := strings.Cut(id, ":")
+		ns := argoNamespace
+		if before, after, found := strings.Cut(app, "_"); found {
+			ns, app = before, after
+		}
+		return &GitOpsOwner{Tool: GitOpsArgoCD, Kind: "Application", Name: app, Namespace: ns}
+	}
+	if name := labels["kustomize.toolkit.fluxcd.io/name"]; name != "" {
+		return &GitOpsOwner{Tool: GitOpsFlux, Kind: "Kustomization", Name: name, Namespace: labels["kustomize.toolkit.fluxcd.io/namespace"]}
+	}
+	if name := labels["helm.toolkit.fluxcd.io/name"]; name != "" {
+		return &GitOpsOwner{Tool: GitOpsFlux, Kind: "HelmRelease", Name: name, Namespace: labels["helm.toolkit.fluxcd.io/namespace"]}
+	}
+	if _, ok := annotations["argocd.argoproj.io/managed-by"]; ok {
+		return &GitOpsOwner{Tool: GitOpsArgoCD}
+	}
+	if _, ok := annotations["fluxcd.io/automated"]; ok {
+		return &GitOpsOwner{Tool: GitOpsFlux}
+	}
+	return nil
+}
+
+// FindGitOpsOwner returns the GitOps owner of a collector's ConfigMap or
+// OpenTelemetryCollector CR, or nil when it is not GitOps-managed or cannot
+// be read.
+func FindGitOpsOwner(ctx context.Context, clientset kubernetes.Interface, dynamicClient dynamic.Interface, ref CollectorRef, argoNamespace string) *GitOpsOwner {
+	obj, err := collectorObject(ctx, clientset, dynamicClient, ref)
+	if err != nil {
+		return nil
+	}
+	return GitOpsOwnerOf(obj, argoNamespace)
+}
+
+// collectorObject returns the resource holding a collector's config. The
+// ConfigMap of a reference that does not name it is resolved from the
+// collector's pods.
+func collectorObject(ctx context.Context, clientset kubernetes.Interface, dynamicClient dynamic.Interface, ref CollectorRef) (metav1.Object, error) {
+	if ref.DeploymentMode == ModeOperatorCRD {
+		if dynamicClient == nil {
+			return nil, fmt.Errorf("dynamic client not configured for CRD operations")
+		}
+		return dynamicClient.Resource(otelCollectorGVR).Namespace(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	}
+	if ref.ConfigMapName == "" {
+		// Only the ConfigMap is needed here, whether or not the owner resolves
+		ref, _ = ResolveCollectorRef(ctx, clientset, ref)
+		if ref.ConfigMapName == "" {
+			return nil, fmt.Errorf("the ConfigMap of collector %s/%s is not known", ref.Namespace, ref.Name)
+		}
+	}
+	return clientset.CoreV1().ConfigMaps(ref.Namespace).Get(ctx, ref.ConfigMapName, metav1.GetOptions{})
+}
+
+// updateCollectorObject updates the annotations of a collector's resource.
+func updateCollectorObject(ctx context.Context, clientset kubernetes.Interface, dynamicClient dynamic.Interface, ref CollectorRef, update func(annotations map[string]string)) error {
+	obj, err := collectorObject(ctx, clientset, dynamicClient, ref)
+	if err != nil {
+		return fmt.Errorf("failed to get collector resource %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	update(annotations)
+	obj.SetAnnotations(annotations)
+
+	switch o := obj.(type) {
+	case *unstructured.Unstructured:
+		_, err = dynamicClient.Resource(otelCollectorGVR).Namespace(ref.Namespace).Update(ctx, o, metav1.UpdateOptions{})
+	case *corev1.ConfigMap:
+		_, err = clientset.CoreV1().ConfigMaps(ref.Namespace).Update(ctx, o, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to annotate collector resource %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+	return nil
+}
+
+// SyncSuspension suspends the GitOps sync of a collector while a session
+// has ephemeral changes, such as the debug exporter, live on it. Flux is
+// suspended with its reconcile annotation on the collector resource; Argo CD
+// by turning off the automated sync of the collector's Application, whose
+// policy is kept in an annotation until it is resumed.
+type SyncSuspension struct {
+	clientset     kubernetes.Interface
+	dynamicClient dynamic.Interface
+	ref           CollectorRef
+	owner         GitOpsOwner
+	sessionID     string
+	suspended     bool
+}
+
+// NewSyncSuspension creates the SyncSuspension of a collector managed by owner.
+func NewSyncSuspension(clientset kubernetes.Interface, dynamicClient dynamic.Interface, ref CollectorRef, owner GitOpsOwner, sessionID string) *SyncSuspension {
+	return &SyncSuspension{clientset: clientset, dynamicClient: dynamicClient, ref: ref, owner: owner, sessionID: sessionID}
+}
+
+// Suspended reports whether the sync is currently suspended by this session.
+func (s *SyncSuspension) Suspended() bool {
+	return s.suspended
+}
+
+// Suspend stops the GitOps controller from reverting changes to the
+// collector. It is a no-op when already suspended.
+func (s *SyncSuspension) Suspend(ctx context.Context) error {
+	if s.suspended {
+		return nil
+	}
+	switch s.owner.Tool {
+	case GitOpsFlux:
+		key := s.fluxAnnotation()
+		err := updateCollectorObject(ctx, s.clientset, s.dynamicClient, s.ref, func(annotations map[string]string) {
+			// A reconcile already disabled by hand is left to its owner
+			if annotations[key] == "disabled" && annotations[AnnotationSyncSuspended] == "" {
+				return
+			}
+			annotations[key] = "disabled"
+			annotations[AnnotationSyncSuspended] = s.sessionID
+		})
+		if err != nil {
+			return err
+		}
+	case GitOpsArgoCD:
+		if err := s.setArgoAutomatedSync(ctx, false); err != nil {
+			return err
+		}
+		if err := updateCollectorObject(ctx, s.clientset, s.dynamicClient, s.ref, func(annotations map[string]string) {
+			annotations[AnnotationSyncSuspended] = s.sessionID
+		}); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported GitOps controller %q", s.owner.Tool)
+	}
+	s.suspended = true
+	return nil
+}
+
+// Resume restores the GitOps sync suspended by Suspend. It is a no-op when
+// the sync is not suspended.
+func (s *SyncSuspension) Resume(ctx context.Context) error {
+	if !s.suspended {
+		return nil
+	}
+	if s.owner.Tool == GitOpsArgoCD {
+		if err := s.setArgoAutomatedSync(ctx, true); err != nil {
+			return err
+		}
+	}
+	key := s.fluxAnnotation()
+	err := updateCollectorObject(ctx, s.clientset, s.dynamicClient, s.ref, func(annotations map[string]string) {
+		if annotations[AnnotationSyncSuspended] == "" {
+			return
+		}
+		if s.owner.Tool == GitOpsFlux {
+			delete(annotations, key)
+		}
+		delete(annotations, AnnotationSyncSuspended)
+	})
+	if err != nil {
+		return err
+	}
+	s.suspended = false
+	return nil
+}
+
+// ResumeArgoAutomatedSync restores the automated sync policy of the Argo CD
+// Application of a collector whose session ended without resuming it, such
+// as one lost to a server restart.
+func ResumeArgoAutomatedSync(ctx context.Context, dynamicClient dynamic.Interface, ref CollectorRef, owner GitOpsOwner) error {
+	s := &SyncSuspension{dynamicClient: dynamicClient, ref: ref, owner: owner}
+	return s.setArgoAutomatedSync(ctx, true)
+}
+
+// fluxAnnotation returns the annotation that stops Flux from reconciling the
+// collector resource: drift detection for HelmRelease-managed resources,
+// reconciliation otherwise.
+func (s *SyncSuspension) fluxAnnotation() string {
+	if s.owner.Kind == "HelmRelease" {
+		return AnnotationFluxDriftDetection
+	}
+	return AnnotationFluxReconcile
+}
+
+// setArgoAutomatedSync turns the automated sync of the collector's Argo CD
+// Application off, keeping its policy in an annotation, or restores it.
+func (s *SyncSuspension) setArgoAutomatedSync(ctx context.Context, enabled bool) error {
+	if s.owner.Name == "" {
+		return fmt.Errorf("%w: collector %s/%s needs the %s annotation", ErrUnknownApplication, s.ref.Namespace, s.ref.Name, AnnotationArgoTrackingID)
+	}
+	if s.dynamicClient == nil {
+		return fmt.Errorf("dynamic client not configured for Argo CD Applications")
+	}
+	apps := s.dynamicClient.Resource(argoApplicationGVR).Namespace(s.owner.Namespace)
+	app, err := apps.Get(ctx, s.owner.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get Argo CD Application %s/%s: %w", s.owner.Namespace, s.owner.Name, err)
+	}
+	annotations := app.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	if enabled {
+		saved, ok := annotations[AnnotationSuspendedAutomatedSync]
+		if !ok {
+			return nil
+		}
+		if saved != "" {
+			var automated map[string]interface{}
+			if err := json.Unmarshal([]byte(saved), &automated); err != nil {
+				return fmt.Errorf("failed to unmarshal the suspended sync policy of Application %s/%s: %w", s.owner.Namespace, s.owner.Name, err)
+			}
+			if err := unstructured.SetNestedMap(app.Object, automated, "spec", "syncPolicy", "automated"); err != nil {
+				return fmt.Errorf("failed to restore the sync policy of Application %s/%s: %w", s.owner.Namespace, s.owner.Name, err)
+			}
+		}
+		delete(annotations, AnnotationSuspendedAutomatedSync)
+	} else {
+		// An Application already suspended keeps its original policy
+		if _, ok := annotations[AnnotationSuspendedAutomatedSync]; ok {
+			return nil
+		}
+		automated, found, err := unstructured.NestedMap(app.Object, "spec", "syncPolicy", "automated")
+		if err != nil {
+			return fmt.Errorf("failed to read the sync policy of Application %s/%s: %w", s.owner.Namespace, s.owner.Name, err)
+		}
+		saved := ""
+		if found {
+			b, err := json.Marshal(automated)
+			if err != nil {
+				return fmt.Errorf("failed to marshal the sync policy of Application %s/%s: %w", s.owner.Namespace, s.owner.Name, err)
+			}
+			saved = string(b)
+		}
+		annotations[AnnotationSuspendedAutomatedSync] = saved
+		unstructured.RemoveNestedField(app.Object, "spec", "syncPolicy", "automated")
+	}
+
+	app.SetAnnotations(annotations)
+	if _, err := apps.Update(ctx, app, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update Argo CD Application %s/%s: %w", s.owner.Namespace, s.owner.Name, err)
+	}
+	return nil
+}
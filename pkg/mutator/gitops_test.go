@@ -0,0 +1,220 @@
+package mutator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGitOpsOwnerOf(t *testing.T) {
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		annotations map[string]string
+		want        *GitOpsOwner
+	}{
+		{name: "not managed", want: nil},
+		{
+			name:        "Argo CD tracking ID",
+			annotations: map[string]string{AnnotationArgoTrackingID: "observability:/ConfigMap:obs/otel-config"},
+			want:        &GitOpsOwner{Tool: GitOpsArgoCD, Kind: "Application", Name: "observability", Namespace: "argocd"},
+		},
+		{
+			name:        "Argo CD Application in another namespace",
+			annotations: map[string]string{AnnotationArgoTrackingID: "team-a_observability:/ConfigMap:obs/otel-config"},
+			want:        &GitOpsOwner{Tool: GitOpsArgoCD, Kind: "Application", Name: "observability", Namespace: "team-a"},
+		},
+		{
+			name:   "Flux Kustomization",
+			labels: map[string]string{"kustomize.toolkit.fluxcd.io/name": "apps", "kustomize.toolkit.fluxcd.io/namespace": "flux-system"},
+			want:   &GitOpsOwner{Tool: GitOpsFlux, Kind: "Kustomization", Name: "apps", Namespace: "flux-system"},
+		},
+		{
+			name:   "Flux HelmRelease",
+			labels: map[string]string{"helm.toolkit.fluxcd.io/name": "otel", "helm.toolkit.fluxcd.io/namespace": "obs"},
+			want:   &GitOpsOwner{Tool: GitOpsFlux, Kind: "HelmRelease", Name: "otel", Namespace: "obs"},
+		},
+		{
+			name:        "legacy Flux annotation",
+			annotations: map[string]string{"fluxcd.io/automated": "true"},
+			want:        &GitOpsOwner{Tool: GitOpsFlux},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{Labels: tt.labels, Annotations: tt.annotations}
+			got := GitOpsOwnerOf(obj, "argocd")
+			if (got == nil) != (tt.want == nil) || got != nil && *got != *tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFluxSyncSuspension(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "otel-config", Namespace: "obs"},
+		Data:       map[string]string{"relay": testConfig},
+	})
+	ref := CollectorRef{Name: "otel", Namespace: "obs", ConfigMapName: "otel-config"}
+	s := NewSyncSuspension(clientset, nil, ref, GitOpsOwner{Tool: GitOpsFlux, Kind: "Kustomization", Name: "apps"}, "s1")
+
+	if err := s.Suspend(ctx); err != nil {
+		t.Fatal(err)
+	}
+	cm, _ := clientset.CoreV1().ConfigMaps("obs").Get(ctx, "otel-config", metav1.GetOptions{})
+	if cm.Annotations[AnnotationFluxReconcile] != "disabled" || cm.Annotations[AnnotationSyncSuspended] != "s1" {
+		t.Errorf("expected reconciliation disabled by the session, got %v", cm.Annotations)
+	}
+
+	if err := s.Resume(ctx); err != nil {
+		t.Fatal(err)
+	}
+	cm, _ = clientset.CoreV1().ConfigMaps("obs").Get(ctx, "otel-config", metav1.GetOptions{})
+	if len(cm.Annotations) != 0 {
+		t.Errorf("expected the annotations to be removed, got %v", cm.Annotations)
+	}
+}
+
+func TestGitOpsStartAnalysisRef(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewSimpleClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "otel-config", Namespace: "obs",
+				Labels: map[string]string{"kustomize.toolkit.fluxcd.io/name": "apps", "kustomize.toolkit.fluxcd.io/namespace": "flux-system"},
+			},
+			Data: map[string]string{"relay": testConfig},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "otel-1", Namespace: "obs", Labels: map[string]string{"app.kubernetes.io/instance": "otel"}},
+			Spec:       collectorDeployment(1).Spec.Template.Spec,
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+	)
+	// start_analysis only knows the collector's name and namespace
+	ref := CollectorRef{Name: "otel", Namespace: "obs"}
+
+	owner := FindGitOpsOwner(ctx, clientset, nil, ref, "argocd")
+	want := GitOpsOwner{Tool: GitOpsFlux, Kind: "Kustomization", Name: "apps", Namespace: "flux-system"}
+	if owner == nil || *owner != want {
+		t.Fatalf("expected %+v, got %+v", want, owner)
+	}
+
+	s := NewSyncSuspension(clientset, nil, ref, *owner, "s1")
+	if err := s.Suspend(ctx); err != nil {
+		t.Fatal(err)
+	}
+	cm, _ := clientset.CoreV1().ConfigMaps("obs").Get(ctx, "otel-config", metav1.GetOptions{})
+	if cm.Annotations[AnnotationFluxReconcile] != "disabled" {
+		t.Errorf("expected reconciliation disabled on the pod's ConfigMap, got %v", cm.Annotations)
+	}
+	if err := s.Resume(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFluxSyncSuspensionKeepsManualSuspension(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "otel-config", Namespace: "obs", Annotations: map[string]string{AnnotationFluxReconcile: "disabled"}},
+		Data:       map[string]string{"relay": testConfig},
+	})
+	ref := CollectorRef{Name: "otel", Namespace: "obs", ConfigMapName: "otel-config"}
+	s := NewSyncSuspension(clientset, nil, ref, GitOpsOwner{Tool: GitOpsFlux}, "s1")
+
+	if err := s.Suspend(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Resume(ctx); err != nil {
+		t.Fatal(err)
+	}
+	cm, _ := clientset.CoreV1().ConfigMaps("obs").Get(ctx, "otel-config", metav1.GetOptions{})
+	if cm.Annotations[AnnotationFluxReconcile] != "disabled" {
+		t.Errorf("expected the manual suspension to be kept, got %v", cm.Annotations)
+	}
+}
+
+func TestArgoSyncSuspension(t *testing.T) {
+	ctx := context.Background()
+	app := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Application",
+		"metadata":   map[string]interface{}{"name": "observability", "namespace": "argocd"},
+		"spec": map[string]interface{}{
+			"syncPolicy": map[string]interface{}{
+				"automated": map[string]interface{}{"prune": true, "selfHeal": true},
+			},
+		},
+	}}
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), app)
+	clientset := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "otel-config", Namespace: "obs"},
+		Data:       map[string]string{"relay": testConfig},
+	})
+	ref := CollectorRef{Name: "otel", Namespace: "obs", ConfigMapName: "otel-config"}
+	owner := GitOpsOwner{Tool: GitOpsArgoCD, Kind: "Application", Name: "observability", Namespace: "argocd"}
+	s := NewSyncSuspension(clientset, dynamicClient, ref, owner, "s1")
+	apps := dynamicClient.Resource(argoApplicationGVR).Namespace("argocd")
+
+	if err := s.Suspend(ctx); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := apps.Get(ctx, "observability", metav1.GetOptions{})
+	if _, found, _ := unstructured.NestedMap(got.Object, "spec", "syncPolicy", "automated"); found {
+		t.Errorf("expected automated sync to be turned off")
+	}
+	if got.GetAnnotations()[AnnotationSuspendedAutomatedSync] == "" {
+		t.Errorf("expected the sync policy to be kept in an annotation")
+	}
+
+	if err := s.Resume(ctx); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = apps.Get(ctx, "observability", metav1.GetOptions{})
+	selfHeal, _, _ := unstructured.NestedBool(got.Object, "spec", "syncPolicy", "automated", "selfHeal")
+	if !selfHeal {
+		t.Errorf("expected the automated sync policy back, got %v", got.Object["spec"])
+	}
+	if _, ok := got.GetAnnotations()[AnnotationSuspendedAutomatedSync]; ok {
+		t.Errorf("expected the suspension annotation to be removed")
+	}
+	cm, _ := clientset.CoreV1().ConfigMaps("obs").Get(ctx, "otel-config", metav1.GetOptions{})
+	if _, ok := cm.Annotations[AnnotationSyncSuspended]; ok {
+		t.Errorf("expected the collector's suspension marker to be removed")
+	}
+}
+
+func TestArgoSyncSuspensionUnknownApplication(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "otel-config",
+			Namespace:   "obs",
+			Annotations: map[string]string{"argocd.argoproj.io/managed-by": "argocd"},
+		},
+		Data: map[string]string{"relay": testConfig},
+	})
+	ref := CollectorRef{Name: "otel", Namespace: "obs", ConfigMapName: "otel-config"}
+	owner := FindGitOpsOwner(context.Background(), clientset, nil, ref, "argocd")
+	if owner == nil || owner.Tool != GitOpsArgoCD {
+		t.Fatalf("expected an Argo CD owner, got %v", owner)
+	}
+	s := NewSyncSuspension(clientset, nil, ref, *owner, "s1")
+
+	err := s.Suspend(context.Background())
+	if !errors.Is(err, ErrUnknownApplication) {
+		t.Fatalf("expected ErrUnknownApplication, got %v", err)
+	}
+	if s.Suspended() {
+		t.Errorf("expected the sync not to be suspended")
+	}
+}
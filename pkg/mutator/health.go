@@ -89,8 +89,11 @@ func CheckPodHealth(pod *corev1.Pod) PodHealth {
 
 // CheckCollectorHealth checks the health of all pods matching a collector's label selector.
 func CheckCollectorHealth(ctx context.Context, clientset kubernetes.Interface, namespace, name string) (*CollectorHealth, error) {
-	labelSelector := fmt.Sprintf("app.kubernetes.io/instance=%s", name)
+	return checkPodsHealth(ctx, clientset, namespace, fmt.Sprintf("app.kubernetes.io/instance=%s", name))
+}
 
+// checkPodsHealth checks the health of all pods matching a label selector.
+func checkPodsHealth(ctx context.Context, clientset kubernetes.Interface, namespace, labelSelector string) (*CollectorHealth, error) {
 	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
 		LabelSelector: labelSelector,
 	})
@@ -133,6 +136,13 @@ func CheckCollectorHealth(ctx context.Context, clientset kubernetes.Interface, n
 // WaitHealthy polls pod health at 2-second intervals until all pods are healthy
 // or the context deadline is exceeded.
 func WaitHealthy(ctx context.Context, clientset kubernetes.Interface, namespace, name string, timeout time.Duration) error {
+	return waitPodsHealthy(ctx, clientset, namespace, fmt.Sprintf("app.kubernetes.io/instance=%s", name),
+		fmt.Sprintf("collector %s/%s", namespace, name), timeout)
+}
+
+// waitPodsHealthy polls the health of the pods matching a label selector until
+// all are healthy or the timeout expires. what names the pods in errors.
+func waitPodsHealthy(ctx context.Context, clientset kubernetes.Interface, namespace, labelSelector, what string, timeout time.Duration) error {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
@@ -140,7 +150,7 @@ func WaitHealthy(ctx context.Context, clientset kubernetes.Interface, namespace,
 	defer ticker.Stop()
 
 	for {
-		health, err := CheckCollectorHealth(ctx, clientset, namespace, name)
+		health, err := checkPodsHealth(ctx, clientset, namespace, labelSelector)
 		if err != nil {
 			return err
 		}
@@ -150,7 +160,7 @@ func WaitHealthy(ctx context.Context, clientset kubernetes.Interface, namespace,
 
 		select {
 		case <-ctx.Done():
-			return fmt.Errorf("timed out waiting for collector %s/%s to become healthy (last status: %s)", namespace, name, health.Status)
+			return fmt.Errorf("timed out waiting for %s to become healthy (last status: %s)", what, health.Status)
 		case <-ticker.C:
 		}
 	}
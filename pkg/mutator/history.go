@@ -0,0 +1,191 @@
+package mutator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// maxHistoryBytes keeps the history ConfigMap below the 1 MiB object size
+// limit with room for its metadata.
+const maxHistoryBytes = 900 * 1024
+
+// revisionKeyPrefix prefixes the history ConfigMap keys holding revisions.
+const revisionKeyPrefix = "revision-"
+
+// Revision is a collector config as it was applied.
+type Revision struct {
+	Number      int       `json:"number"`
+	CreatedAt   time.Time `json:"created_at"`
+	SessionID   string    `json:"session_id,omitempty"`
+	FixIDs      []string  `json:"fix_ids,omitempty"`
+	Description string    `json:"description"`
+	Diff        string    `json:"diff,omitempty"` // from the previous revision
+	Config      string    `json:"config,omitempty"`
+}
+
+// History is the revision history of a collector, kept in a dedicated
+// ConfigMap next to it so that it outlives sessions and backups.
+type History struct {
+	clientset kubernetes.Interface
+	ref       CollectorRef
+	max       int
+	diff      func(from, to string) string
+}
+
+// NewHistory creates the History of a collector holding at most max
+// revisions. diff renders the change between two configs.
+func NewHistory(clientset kubernetes.Interface, ref CollectorRef, max int, diff func(from, to string) string) *History {
+	return &History{clientset: clientset, ref: ref, max: max, diff: diff}
+}
+
+// ConfigMapName returns the name of the ConfigMap holding the history.
+func (h *History) ConfigMapName() string {
+	return h.ref.Name + "-mcp-history"
+}
+
+// List returns the revisions, oldest first.
+func (h *History) List(ctx context.Context) ([]Revision, error) {
+	cm, err := h.clientset.CoreV1().ConfigMaps(h.ref.Namespace).Get(ctx, h.ConfigMapName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history ConfigMap %s/%s: %w", h.ref.Namespace, h.ConfigMapName(), err)
+	}
+	return revisionsOf(cm)
+}
+
+// Get returns the revision with the given number.
+func (h *History) Get(ctx context.Context, number int) (*Revision, error) {
+	revisions, err := h.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range revisions {
+		if revisions[i].Number == number {
+			return &revisions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("revision %d not found in the history of collector %s/%s", number, h.ref.Namespace, h.ref.Name)
+}
+
+// Record adds the config applied in place of previous as a new revision.
+// When the history does not end with previous, for example because the
+// collector was changed by hand or the history is new, previous is recorded
+// first so that it can be rolled back to. The oldest revisions are dropped
+// beyond the history's bound and the ConfigMap size limit.
+func (h *History) Record(ctx context.Context, previous string, rev Revision) (*Revision, error) {
+	cms := h.clientset.CoreV1().ConfigMaps(h.ref.Namespace)
+	cm, err := cms.Get(ctx, h.ConfigMapName(), metav1.GetOptions{})
+	create := apierrors.IsNotFound(err)
+	if create {
+		cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:      h.ConfigMapName(),
+			Namespace: h.ref.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "otel-collector-mcp",
+				"app.kubernetes.io/instance":   h.ref.Name,
+			},
+		}}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get history ConfigMap %s/%s: %w", h.ref.Namespace, h.ConfigMapName(), err)
+	}
+
+	revisions, err := revisionsOf(cm)
+	if err != nil {
+		return nil, err
+	}
+	next := 1
+	if n := len(revisions); n > 0 {
+		next = revisions[n-1].Number + 1
+	}
+	if n := len(revisions); n == 0 || revisions[n-1].Config != previous {
+		revisions = append(revisions, Revision{
+			Number:      next,
+			CreatedAt:   time.Now().UTC(),
+			Description: "config before the first recorded change",
+			Config:      previous,
+		})
+		if n > 0 {
+			revisions[len(revisions)-1].Description = "config changed outside the history"
+			revisions[len(revisions)-1].Diff = h.diff(revisions[n-1].Config, previous)
+		}
+		next++
+	}
+
+	rev.Number = next
+	if rev.CreatedAt.IsZero() {
+		rev.CreatedAt = time.Now().UTC()
+	}
+	rev.Diff = h.diff(previous, rev.Config)
+	revisions = append(revisions, rev)
+
+	data, err := h.encode(revisions)
+	if err != nil {
+		return nil, err
+	}
+	cm.Data = data
+	if create {
+		_, err = cms.Create(ctx, cm, metav1.CreateOptions{})
+	} else {
+		_, err = cms.Update(ctx, cm, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write history ConfigMap %s/%s: %w", h.ref.Namespace, h.ConfigMapName(), err)
+	}
+	return &rev, nil
+}
+
+// encode keeps the newest revisions that fit the history's bounds. The
+// newest revision is always kept.
+func (h *History) encode(revisions []Revision) (map[string]string, error) {
+	if h.max > 0 && len(revisions) > h.max {
+		revisions = revisions[len(revisions)-h.max:]
+	}
+	data := make(map[string]string, len(revisions))
+	size := 0
+	for i := len(revisions) - 1; i >= 0; i-- {
+		b, err := json.Marshal(revisions[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal revision %d: %w", revisions[i].Number, err)
+		}
+		if size+len(b) > maxHistoryBytes && len(data) > 0 {
+			break
+		}
+		size += len(b)
+		data[revisionKey(revisions[i].Number)] = string(b)
+	}
+	return data, nil
+}
+
+// revisionsOf decodes the revisions held in a history ConfigMap, oldest first.
+func revisionsOf(cm *corev1.ConfigMap) ([]Revision, error) {
+	var revisions []Revision
+	for key, value := range cm.Data {
+		if !strings.HasPrefix(key, revisionKeyPrefix) {
+			continue
+		}
+		var rev Revision
+		if err := json.Unmarshal([]byte(value), &rev); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s of history ConfigMap %s/%s: %w", key, cm.Namespace, cm.Name, err)
+		}
+		revisions = append(revisions, rev)
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Number < revisions[j].Number })
+	return revisions, nil
+}
+
+// revisionKey returns the ConfigMap key of a revision; numbers are padded so
+// the keys sort in order.
+func revisionKey(number int) string {
+	return fmt.Sprintf("%s%06d", revisionKeyPrefix, number)
+}
@@ -0,0 +1,105 @@
+package mutator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testDiff(from, to string) string {
+	return "-" + from + "+" + to
+}
+
+func TestHistoryRecord(t *testing.T) {
+	tests := []struct {
+		name      string
+		max       int
+		changes   [][2]string // previous, applied
+		wantNums  []int
+		wantDescs []string
+	}{
+		{
+			name:      "first change records the original config",
+			max:       10,
+			changes:   [][2]string{{"v1", "v2"}},
+			wantNums:  []int{1, 2},
+			wantDescs: []string{"config before the first recorded change", "fix"},
+		},
+		{
+			name:      "consecutive changes",
+			max:       10,
+			changes:   [][2]string{{"v1", "v2"}, {"v2", "v3"}},
+			wantNums:  []int{1, 2, 3},
+			wantDescs: []string{"config before the first recorded change", "fix", "fix"},
+		},
+		{
+			name:      "change made outside the history",
+			max:       10,
+			changes:   [][2]string{{"v1", "v2"}, {"edited", "v3"}},
+			wantNums:  []int{1, 2, 3, 4},
+			wantDescs: []string{"config before the first recorded change", "fix", "config changed outside the history", "fix"},
+		},
+		{
+			name:      "bounded history keeps the newest revisions",
+			max:       2,
+			changes:   [][2]string{{"v1", "v2"}, {"v2", "v3"}, {"v3", "v4"}},
+			wantNums:  []int{3, 4},
+			wantDescs: []string{"fix", "fix"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			h := NewHistory(fake.NewSimpleClientset(), CollectorRef{Name: "otel", Namespace: "obs"}, tt.max, testDiff)
+
+			for _, c := range tt.changes {
+				rev, err := h.Record(ctx, c[0], Revision{SessionID: "s1", FixIDs: []string{"rule#0"}, Description: "fix", Config: c[1]})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if rev.Diff != testDiff(c[0], c[1]) {
+					t.Errorf("expected the diff from the previous config, got %q", rev.Diff)
+				}
+			}
+
+			revisions, err := h.List(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(revisions) != len(tt.wantNums) {
+				t.Fatalf("expected %d revisions, got %d", len(tt.wantNums), len(revisions))
+			}
+			for i, rev := range revisions {
+				if rev.Number != tt.wantNums[i] || rev.Description != tt.wantDescs[i] {
+					t.Errorf("revision %d: expected %d %q, got %d %q", i, tt.wantNums[i], tt.wantDescs[i], rev.Number, rev.Description)
+				}
+			}
+		})
+	}
+}
+
+func TestHistoryGet(t *testing.T) {
+	ctx := context.Background()
+	h := NewHistory(fake.NewSimpleClientset(), CollectorRef{Name: "otel", Namespace: "obs"}, 10, testDiff)
+
+	if revisions, err := h.List(ctx); err != nil || len(revisions) != 0 {
+		t.Fatalf("expected an empty history, got %v, %v", revisions, err)
+	}
+	if _, err := h.Record(ctx, testConfig, Revision{Config: "receivers: {}\n"}); err != nil {
+		t.Fatal(err)
+	}
+
+	rev, err := h.Get(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rev.Config != testConfig {
+		t.Errorf("expected revision 1 to hold the original config, got %q", rev.Config)
+	}
+	if _, err := h.Get(ctx, 7); err == nil || !strings.Contains(err.Error(), "revision 7 not found") {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
@@ -0,0 +1,442 @@
+package mutator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kinds of files a PatchWriter updates.
+const (
+	PatchConfigMap              = "ConfigMap"
+	PatchOpenTelemetryCollector = "OpenTelemetryCollector"
+	PatchHelmValues             = "HelmValues"
+)
+
+// Patch is a collector config change committed to a GitOps repository.
+type Patch struct {
+	Branch string `json:"branch"`
+	Commit string `json:"commit"`
+	File   string `json:"file"` // relative to the repository root
+	Kind   string `json:"kind"`
+}
+
+// PatchWriter commits collector config changes to a branch of a local Git
+// working tree instead of applying them to the cluster, for collectors whose
+// manifests are reconciled by a GitOps controller. Changes are made in a
+// temporary worktree, so the checkout at the repository path is left as is.
+type PatchWriter struct {
+	repo         string
+	branchPrefix string
+}
+
+// NewPatchWriter creates a PatchWriter for the Git working tree at repo.
+// Branches are named branchPrefix followed by the session ID.
+func NewPatchWriter(repo, branchPrefix string) *PatchWriter {
+	return &PatchWriter{repo: repo, branchPrefix: branchPrefix}
+}
+
+// Branch returns the branch the changes of a session are committed to.
+func (w *PatchWriter) Branch(sessionID string) string {
+	return w.branchPrefix + sessionID
+}
+
+// Write commits configYAML as the config of the collector to the session's
+// branch, creating it from HEAD on the first change. The collector is found
+// as a ConfigMap or OpenTelemetryCollector manifest, else as the config of
+// the opentelemetry-collector Helm chart in a values file.
+func (w *PatchWriter) Write(ctx context.Context, ref CollectorRef, sessionID, configYAML, message string) (patch *Patch, err error) {
+	branch := w.Branch(sessionID)
+	worktree, err := os.MkdirTemp("", "otel-collector-mcp-gitops-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+	defer os.RemoveAll(worktree)
+
+	if _, verr := w.git(ctx, w.repo, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch); verr == nil {
+		if _, err := w.git(ctx, w.repo, "worktree", "add", worktree, branch); err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err := w.git(ctx, w.repo, "worktree", "add", "-b", branch, worktree, "HEAD"); err != nil {
+			return nil, err
+		}
+		// A branch created for a change that could not be committed is removed
+		defer func() {
+			if err != nil {
+				_, _ = w.git(context.Background(), w.repo, "branch", "-D", branch)
+			}
+		}()
+	}
+	defer func() {
+		_, _ = w.git(context.Background(), w.repo, "worktree", "remove", "--force", worktree)
+	}()
+
+	file, kind, err := findCollectorManifest(worktree, ref)
+	if err != nil {
+		return nil, err
+	}
+	if err := updateManifest(filepath.Join(worktree, file), kind, ref, configYAML); err != nil {
+		return nil, err
+	}
+
+	if _, err := w.git(ctx, worktree, "add", "--", file); err != nil {
+		return nil, err
+	}
+	if _, err := w.git(ctx, worktree, "diff", "--cached", "--quiet"); err == nil {
+		return nil, fmt.Errorf("the config in %s on branch %s is already up to date", file, branch)
+	}
+	args := []string{"commit", "-m", message}
+	if name, _ := w.git(ctx, worktree, "config", "user.name"); name == "" {
+		args = append([]string{"-c", "user.name=otel-collector-mcp", "-c", "user.email=otel-collector-mcp@localhost"}, args...)
+	}
+	if _, err := w.git(ctx, worktree, args...); err != nil {
+		return nil, err
+	}
+	commit, err := w.git(ctx, worktree, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Patch{Branch: branch, Commit: commit, File: filepath.ToSlash(file), Kind: kind}, nil
+}
+
+// git runs a git command in dir and returns its trimmed output.
+func (w *PatchWriter) git(ctx context.Context, dir string, args ...string) (string, error) {
+	// #nosec G204 -- the arguments come from server configuration and session IDs
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// findCollectorManifest returns the file under root holding the collector's
+// config, relative to root, and its kind. Manifests of the collector's
+// ConfigMap or CR take precedence over Helm values files.
+func findCollectorManifest(root string, ref CollectorRef) (string, string, error) {
+	var manifests, values []string
+	kinds := map[string]string{}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := filepath.Ext(path)
+		if ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+		docs, err := readYAMLDocuments(path)
+		if err != nil {
+			return nil // not every YAML file is a manifest, e.g. Helm templates
+		}
+		rel, _ := filepath.Rel(root, path)
+		for _, doc := range docs {
+			if kind := manifestKind(doc, ref); kind != "" {
+				manifests = append(manifests, rel)
+				kinds[rel] = kind
+				return nil
+			}
+		}
+		if strings.HasPrefix(d.Name(), "values") && len(docs) == 1 && helmCollectorConfig(docs[0]) != nil {
+			values = append(values, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to search the GitOps repository: %w", err)
+	}
+
+	switch {
+	case len(manifests) == 1:
+		return manifests[0], kinds[manifests[0]], nil
+	case len(manifests) > 1:
+		return "", "", fmt.Errorf("collector %s/%s is defined in several files: %s", ref.Namespace, ref.Name, strings.Join(manifests, ", "))
+	case len(values) == 1:
+		return values[0], PatchHelmValues, nil
+	case len(values) > 1:
+		return "", "", fmt.Errorf("several Helm values files hold a collector config: %s", strings.Join(values, ", "))
+	}
+	return "", "", fmt.Errorf("no manifest or Helm values file for collector %s/%s found in the GitOps repository", ref.Namespace, ref.Name)
+}
+
+// updateManifest sets the collector config in the file at path. Only the
+// lines of the config entry are rewritten; the rest of the file, other
+// documents included, keeps its bytes.
+func updateManifest(path, kind string, ref CollectorRef, configYAML string) error {
+	content, err := os.ReadFile(path) // #nosec G304 -- the path is inside the GitOps repository
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	docs, err := parseYAMLDocuments(content)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var key, config *yaml.Node
+	for _, doc := range docs {
+		switch {
+		case kind == PatchHelmValues:
+			key, config = helmCollectorConfigEntry(doc)
+		case manifestKind(doc, ref) == PatchConfigMap:
+			data := mappingValue(doc, "data")
+			key, config = mappingEntry(data, configMapKey(data, ref))
+		case manifestKind(doc, ref) == PatchOpenTelemetryCollector:
+			key, config = mappingEntry(mappingValue(doc, "spec"), "config")
+		}
+		if config != nil {
+			break
+		}
+	}
+	if config == nil {
+		return fmt.Errorf("no collector config found in %s", path)
+	}
+	if err := setConfigNode(config, configYAML); err != nil {
+		return fmt.Errorf("failed to update the collector config in %s: %w", path, err)
+	}
+
+	out, err := spliceEntry(content, key, config)
+	if err != nil {
+		return fmt.Errorf("failed to update the collector config in %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, out, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// spliceEntry replaces the lines of the mapping entry key: value in content
+// with the entry encoded from the nodes. The entry runs from the key's line
+// to the last line indented deeper than the key.
+func spliceEntry(content []byte, key, value *yaml.Node) ([]byte, error) {
+	lines := strings.SplitAfter(string(content), "\n")
+	start, indent := key.Line-1, key.Column-1
+	if start < 0 || start >= len(lines) || indent > len(lines[start]) {
+		return nil, fmt.Errorf("config entry not found at line %d", key.Line)
+	}
+	end := start + 1
+	for i := end; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r\n")
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" {
+			continue
+		}
+		if len(line)-len(trimmed) <= indent {
+			break
+		}
+		end = i + 1
+	}
+
+	entry := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: key.Tag, Style: key.Style, Value: key.Value, LineComment: key.LineComment},
+		value,
+	}}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(entry); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	// The first line keeps what precedes the key, such as a "- " sequence
+	// marker; the others are indented to the key's column
+	var out strings.Builder
+	out.WriteString(strings.Join(lines[:start], ""))
+	pad := strings.Repeat(" ", indent)
+	for i, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		switch {
+		case i == 0:
+			out.WriteString(lines[start][:indent])
+		case line != "":
+			out.WriteString(pad)
+		}
+		out.WriteString(line)
+		out.WriteString("\n")
+	}
+	out.WriteString(strings.Join(lines[end:], ""))
+	return []byte(out.String()), nil
+}
+
+// readYAMLDocuments parses every document of a YAML file.
+func readYAMLDocuments(path string) ([]*yaml.Node, error) {
+	content, err := os.ReadFile(path) // #nosec G304 -- the path is inside the GitOps repository
+	if err != nil {
+		return nil, err
+	}
+	return parseYAMLDocuments(content)
+}
+
+// parseYAMLDocuments parses every document of a YAML stream. Node lines
+// count from the start of the stream.
+func parseYAMLDocuments(content []byte) ([]*yaml.Node, error) {
+	var docs []*yaml.Node
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(doc.Content) > 0 {
+			docs = append(docs, doc.Content[0])
+		}
+	}
+}
+
+// manifestKind returns the kind of a manifest defining the collector, or "".
+func manifestKind(doc *yaml.Node, ref CollectorRef) string {
+	metadata := mappingValue(doc, "metadata")
+	name := scalarValue(mappingValue(metadata, "name"))
+	if ns := scalarValue(mappingValue(metadata, "namespace")); ns != "" && ns != ref.Namespace {
+		return ""
+	}
+
+	switch scalarValue(mappingValue(doc, "kind")) {
+	case PatchConfigMap:
+		if ref.DeploymentMode == ModeOperatorCRD {
+			return ""
+		}
+		// Without the ConfigMap name, a ConfigMap named after the collector
+		// holding a config key is taken
+		if ref.ConfigMapName != "" && name != ref.ConfigMapName ||
+			ref.ConfigMapName == "" && !collectorConfigMapName(name, ref.Name) {
+			return ""
+		}
+		if configMapKey(mappingValue(doc, "data"), ref) == "" {
+			return ""
+		}
+		return PatchConfigMap
+	case PatchOpenTelemetryCollector:
+		if name != ref.Name || mappingValue(mappingValue(doc, "spec"), "config") == nil {
+			return ""
+		}
+		return PatchOpenTelemetryCollector
+	}
+	return ""
+}
+
+// configMapNameSuffixes are the suffixes a collector's ConfigMap is commonly
+// named with after the collector.
+var configMapNameSuffixes = []string{"", "-config", "-conf", "-configmap", "-collector", "-collector-config"}
+
+// collectorConfigMapName reports whether a ConfigMap name is the collector's
+// name with one of configMapNameSuffixes.
+func collectorConfigMapName(name, collectorName string) bool {
+	for _, suffix := range configMapNameSuffixes {
+		if name == collectorName+suffix {
+			return true
+		}
+	}
+	return false
+}
+
+// configMapKey returns the data key holding the collector config, following
+// ConfigMapMutator.configKey.
+func configMapKey(data *yaml.Node, ref CollectorRef) string {
+	if data == nil || data.Kind != yaml.MappingNode {
+		return ""
+	}
+	values := make(map[string]string, len(data.Content)/2)
+	for i := 0; i+1 < len(data.Content); i += 2 {
+		values[data.Content[i].Value] = data.Content[i+1].Value
+	}
+	m := &ConfigMapMutator{ref: ref}
+	key := m.configKey(values)
+	if _, ok := values[key]; !ok {
+		return ""
+	}
+	return key
+}
+
+// helmCollectorConfig returns the collector config of opentelemetry-collector
+// chart values, at the top level or under an opentelemetry-collector
+// subchart key, or nil.
+func helmCollectorConfig(doc *yaml.Node) *yaml.Node {
+	_, config := helmCollectorConfigEntry(doc)
+	return config
+}
+
+// helmCollectorConfigEntry returns the key and value nodes of the config
+// helmCollectorConfig returns.
+func helmCollectorConfigEntry(doc *yaml.Node) (*yaml.Node, *yaml.Node) {
+	for _, values := range []*yaml.Node{doc, mappingValue(doc, "opentelemetry-collector")} {
+		key, config := mappingEntry(values, "config")
+		if config != nil && config.Kind == yaml.MappingNode &&
+			(mappingValue(config, "receivers") != nil || mappingValue(config, "service") != nil) {
+			return key, config
+		}
+	}
+	return nil, nil
+}
+
+// setConfigNode replaces a config node with configYAML, as a block string
+// when the node is a string and as a mapping otherwise.
+func setConfigNode(node *yaml.Node, configYAML string) error {
+	if node == nil {
+		return fmt.Errorf("no config node")
+	}
+	if node.Kind == yaml.ScalarNode {
+		*node = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Style: yaml.LiteralStyle, Value: configYAML}
+		return nil
+	}
+	var parsed yaml.Node
+	if err := yaml.Unmarshal([]byte(configYAML), &parsed); err != nil {
+		return fmt.Errorf("failed to parse config: %w", err)
+	}
+	if len(parsed.Content) == 0 {
+		return fmt.Errorf("empty config")
+	}
+	*node = *parsed.Content[0]
+	return nil
+}
+
+// mappingValue returns the value of key in a mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	_, value := mappingEntry(node, key)
+	return value
+}
+
+// mappingEntry returns the key and value nodes of key in a mapping node, or
+// nils.
+func mappingEntry(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+func scalarValue(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return node.Value
+}
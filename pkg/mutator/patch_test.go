@@ -0,0 +1,219 @@
+package mutator
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const patchedConfig = "receivers:\n  otlp: {}\nservice:\n  pipelines: {}\n"
+
+// gitRepo creates a Git repository holding files in one commit.
+func gitRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "initial"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	return dir
+}
+
+// show returns a file as committed on a branch.
+func show(t *testing.T, repo, branch, file string) string {
+	t.Helper()
+	out, err := exec.Command("git", "-C", repo, "show", branch+":"+file).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git show: %v: %s", err, out)
+	}
+	return string(out)
+}
+
+func TestPatchWriterWrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		ref      CollectorRef
+		files    map[string]string
+		wantFile string
+		wantKind string
+	}{
+		{
+			name: "ConfigMap manifest",
+			ref:  CollectorRef{Name: "otel", Namespace: "obs", ConfigMapName: "otel-config"},
+			files: map[string]string{
+				"apps/otel/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: otel-config\n  namespace: obs\ndata:\n  relay: |\n    receivers: {}\n",
+				"apps/other.yaml":          "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: other\ndata:\n  relay: x\n",
+			},
+			wantFile: "apps/otel/configmap.yaml",
+			wantKind: PatchConfigMap,
+		},
+		{
+			name: "OpenTelemetryCollector in a multi-document file",
+			ref:  CollectorRef{Name: "otel", Namespace: "obs", DeploymentMode: ModeOperatorCRD},
+			files: map[string]string{
+				"collector.yaml": "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: obs\n---\napiVersion: opentelemetry.io/v1beta1\nkind: OpenTelemetryCollector\nmetadata:\n  name: otel\nspec:\n  config:\n    receivers: {}\n",
+			},
+			wantFile: "collector.yaml",
+			wantKind: PatchOpenTelemetryCollector,
+		},
+		{
+			name: "Helm values",
+			ref:  CollectorRef{Name: "otel", Namespace: "obs"},
+			files: map[string]string{
+				"charts/otel/values.yaml": "mode: deployment\nconfig:\n  receivers: {}\n",
+			},
+			wantFile: "charts/otel/values.yaml",
+			wantKind: PatchHelmValues,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := gitRepo(t, tt.files)
+			w := NewPatchWriter(repo, "otel-collector-mcp/")
+
+			patch, err := w.Write(context.Background(), tt.ref, "s1", patchedConfig, "Apply fix")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if patch.Branch != "otel-collector-mcp/s1" || patch.File != tt.wantFile || patch.Kind != tt.wantKind || patch.Commit == "" {
+				t.Errorf("unexpected patch %+v", patch)
+			}
+
+			committed := show(t, repo, patch.Branch, tt.wantFile)
+			if !strings.Contains(committed, "otlp: {}") {
+				t.Errorf("expected the new config to be committed, got:\n%s", committed)
+			}
+			// The checkout at the repository path is not touched
+			if current := show(t, repo, "main", tt.wantFile); current != tt.files[tt.wantFile] {
+				t.Errorf("expected main to be unchanged, got:\n%s", current)
+			}
+		})
+	}
+}
+
+func TestPatchWriterStacksSessionChanges(t *testing.T) {
+	repo := gitRepo(t, map[string]string{
+		"configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: otel-config\ndata:\n  relay: |\n    receivers: {}\n",
+	})
+	w := NewPatchWriter(repo, "mcp/")
+	ref := CollectorRef{Name: "otel", Namespace: "obs", ConfigMapName: "otel-config"}
+	ctx := context.Background()
+
+	first, err := w.Write(ctx, ref, "s1", patchedConfig, "First fix")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := w.Write(ctx, ref, "s1", patchedConfig+"extensions: {}\n", "Second fix")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Commit == second.Commit {
+		t.Fatalf("expected a second commit")
+	}
+	out, err := exec.Command("git", "-C", repo, "rev-list", "--count", "main..mcp/s1").CombinedOutput()
+	if err != nil || strings.TrimSpace(string(out)) != "2" {
+		t.Errorf("expected 2 commits on the session branch, got %s (%v)", out, err)
+	}
+
+	if _, err := w.Write(ctx, ref, "s1", patchedConfig+"extensions: {}\n", "Same again"); err == nil {
+		t.Errorf("expected an error when the config is unchanged")
+	}
+}
+
+func TestPatchWriterCollectorNotFound(t *testing.T) {
+	repo := gitRepo(t, map[string]string{"README.md": "nothing here\n"})
+	w := NewPatchWriter(repo, "mcp/")
+
+	_, err := w.Write(context.Background(), CollectorRef{Name: "otel", Namespace: "obs", ConfigMapName: "otel-config"}, "s1", patchedConfig, "Apply fix")
+	if err == nil || !strings.Contains(err.Error(), "no manifest or Helm values file") {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+	if out, err := exec.Command("git", "-C", repo, "branch", "--list", "mcp/s1").CombinedOutput(); err != nil || len(out) != 0 {
+		t.Errorf("expected the branch to be removed, got %q (%v)", out, err)
+	}
+}
+
+func TestUpdateManifestKeepsOtherDocuments(t *testing.T) {
+	namespace := "# Observability namespace\napiVersion: v1\nkind: Namespace\nmetadata:\n  name: obs\n  labels: {team: \"platform\"}   # owned by platform\n"
+	service := "apiVersion: v1\nkind: Service\nmetadata:\n  name: otel\nspec:\n  ports:\n  - {name: otlp, port: 4317}\n"
+	tests := []struct {
+		name   string
+		ref    CollectorRef
+		kind   string
+		before string
+		after  string
+	}{
+		{
+			name:   "ConfigMap",
+			ref:    CollectorRef{Name: "otel", Namespace: "obs", ConfigMapName: "otel-config"},
+			kind:   PatchConfigMap,
+			before: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: otel-config\ndata:\n  # collector config\n  relay: |\n    receivers: {}\n\n  other: keep   # untouched\n",
+			after:  "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: otel-config\ndata:\n  # collector config\n  relay: |\n    receivers:\n      otlp: {}\n    service:\n      pipelines: {}\n\n  other: keep   # untouched\n",
+		},
+		{
+			name:   "OpenTelemetryCollector",
+			ref:    CollectorRef{Name: "otel", Namespace: "obs", DeploymentMode: ModeOperatorCRD},
+			kind:   PatchOpenTelemetryCollector,
+			before: "apiVersion: opentelemetry.io/v1beta1\nkind: OpenTelemetryCollector\nmetadata:\n  name: otel\nspec:\n  config:\n    receivers:\n      jaeger: {}\n  mode: deployment\n",
+			after:  "apiVersion: opentelemetry.io/v1beta1\nkind: OpenTelemetryCollector\nmetadata:\n  name: otel\nspec:\n  config:\n    receivers:\n      otlp: {}\n    service:\n      pipelines: {}\n  mode: deployment\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "collector.yaml")
+			if err := os.WriteFile(path, []byte(namespace+"---\n"+tt.before+"---\n"+service), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if err := updateManifest(path, tt.kind, tt.ref, patchedConfig); err != nil {
+				t.Fatal(err)
+			}
+			got, _ := os.ReadFile(path)
+			if want := namespace + "---\n" + tt.after + "---\n" + service; string(got) != want {
+				t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+			}
+		})
+	}
+}
+
+func TestManifestKindConfigMapName(t *testing.T) {
+	ref := CollectorRef{Name: "otel", Namespace: "obs"}
+	tests := map[string]string{
+		"otel":        PatchConfigMap,
+		"otel-config": PatchConfigMap,
+		"otel-agent":  "",
+		"otelcol":     "",
+	}
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			docs, err := parseYAMLDocuments([]byte("kind: ConfigMap\nmetadata:\n  name: " + name + "\ndata:\n  relay: |\n    receivers: {}\n"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := manifestKind(docs[0], ref); got != want {
+				t.Errorf("expected %q, got %q", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package mutator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ResolveCollectorRef completes a reference that only names a collector, as
+// start_analysis builds it, from one of the collector's pods: the ConfigMap
+// its config is mounted from and the workload owning it. Fields already set
+// are kept. On error the returned reference holds what could be resolved.
+func ResolveCollectorRef(ctx context.Context, clientset kubernetes.Interface, ref CollectorRef) (CollectorRef, error) {
+	needConfigMap := ref.ConfigMapName == "" && ref.DeploymentMode != ModeOperatorCRD
+	if !needConfigMap && ref.OwnerKind != "" && ref.OwnerName != "" {
+		return ref, nil
+	}
+	pod, err := collector.FindCollectorPod(ctx, clientset, ref.Namespace, ref.Name, "")
+	if err != nil {
+		return ref, err
+	}
+	if needConfigMap {
+		if name, err := collector.PodConfigMap(ctx, clientset, pod); err == nil {
+			ref.ConfigMapName = name
+		}
+	}
+	if ref.OwnerKind == "" || ref.OwnerName == "" {
+		kind, name, err := podOwner(ctx, clientset, pod)
+		if err != nil {
+			return ref, err
+		}
+		ref.OwnerKind, ref.OwnerName = kind, name
+	}
+	return ref, nil
+}
+
+// podOwner returns the kind and name of the workload owning a pod, looking
+// through the ReplicaSet of a Deployment.
+func podOwner(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod) (string, string, error) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return "", "", fmt.Errorf("no workload owns pod %s/%s", pod.Namespace, pod.Name)
+	}
+	if owner.Kind != "ReplicaSet" {
+		return owner.Kind, owner.Name, nil
+	}
+	rs, err := clientset.AppsV1().ReplicaSets(pod.Namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get ReplicaSet %s: %w", owner.Name, err)
+	}
+	if d := metav1.GetControllerOf(rs); d != nil {
+		return d.Kind, d.Name, nil
+	}
+	return "ReplicaSet", rs.Name, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"k8s.io/client-go/kubernetes"
@@ -16,13 +17,103 @@ type SafeApplyResult struct {
 	HealthOK   bool
 	Error      error
 	Message    string
+
+	// Telemetry is the outcome of the telemetry gate, when one ran.
+	Telemetry *TelemetryCheck
+
+	// Canary is the outcome of the canary stage, when one was requested.
+	Canary *CanaryResult
+
+	// Revision is the history revision recorded for the applied config.
+	Revision *Revision
+}
+
+// SafeApplyOptions configures the optional gates of SafeApply.
+type SafeApplyOptions struct {
+	// Validator checks the config before anything is changed.
+	Validator Validator
+
+	// Telemetry compares the collector's internal metrics before the change
+	// with those of the healthy collector after it.
+	Telemetry *TelemetryGate
+
+	// Canary checks the config on one canary pod before every pod of a
+	// DaemonSet or multi-replica collector gets it.
+	Canary *CanaryOptions
+
+	// History records the applied config as a new revision once the
+	// collector is healthy. Revision carries its fix IDs and description.
+	History  *History
+	Revision Revision
 }
 
 // SafeApply performs a config mutation with automatic health check and rollback.
-// Sequence: backup → apply → rollout → wait healthy → success OR rollback.
-func SafeApply(ctx context.Context, mut Mutator, clientset kubernetes.Interface, ref CollectorRef, sessionID, configYAML string) *SafeApplyResult {
+// Sequence: validate → scrape telemetry → canary → backup → apply → rollout →
+// wait healthy → compare telemetry → success OR rollback. Gates left unset in
+// opts are skipped. A Deployment or StatefulSet canary runs before the backup
+// and leaves the collector untouched when it fails; a DaemonSet canary runs as
+// the first step of the rollout and is rolled back with the config.
+func SafeApply(ctx context.Context, mut Mutator, clientset kubernetes.Interface, ref CollectorRef, sessionID, configYAML string, opts SafeApplyOptions) *SafeApplyResult {
 	result := &SafeApplyResult{}
 
+	// Step 0: Validate
+	if opts.Validator != nil {
+		if err := opts.Validator.Validate(ctx, configYAML); err != nil {
+			result.Error = fmt.Errorf("validation failed, mutation refused: %w", err)
+			result.Message = "Config validation failed — no config change attempted"
+			return result
+		}
+		slog.Info("config validated", "collector", ref.Name)
+	}
+
+	// Internal metrics before the change; the gate is skipped when they cannot be read
+	if opts.Telemetry != nil {
+		result.Telemetry = &TelemetryCheck{}
+		before, err := ScrapeTelemetry(ctx, clientset, ref.Namespace, ref.Name, opts.Telemetry.BeforePort)
+		if err != nil {
+			slog.Warn("telemetry gate skipped", "collector", ref.Name, "error", err)
+			result.Telemetry.Skipped = err.Error()
+		}
+		result.Telemetry.Before = before
+	}
+
+	// Canary: a copy of the workload runs the new config before the collector is changed
+	strategy := ""
+	if opts.Canary != nil {
+		result.Canary = &CanaryResult{}
+		var skipped string
+		strategy, skipped = canaryStrategy(ctx, clientset, &ref)
+		result.Canary.Strategy = strategy
+		if skipped != "" {
+			slog.Info("canary skipped", "collector", ref.Name, "reason", skipped)
+			result.Canary.Skipped = skipped
+		}
+	}
+	if strategy == CanaryWorkload {
+		if err := runWorkloadCanary(ctx, clientset, ref, configYAML, opts, result.Telemetry.before(), result.Canary); err != nil {
+			result.Error = fmt.Errorf("canary could not be started, mutation refused: %w", err)
+			result.Message = "Canary could not be started — no config change attempted"
+			return result
+		}
+		if !result.Canary.Promoted {
+			result.Error = fmt.Errorf("canary failed, mutation refused: %s", result.Canary.Failure)
+			result.Message = "Canary failed — no config change attempted"
+			return result
+		}
+		slog.Info("canary healthy, promoting", "collector", ref.Name, "canary", result.Canary.Workload)
+	}
+
+	// The config being replaced, for the revision history
+	previous := ""
+	if opts.History != nil {
+		current, err := mut.CurrentConfig(ctx)
+		if err != nil {
+			slog.Warn("config revision will not be recorded", "collector", ref.Name, "error", err)
+			opts.History = nil
+		}
+		previous = current
+	}
+
 	// Step 1: Backup
 	if err := mut.Backup(ctx, sessionID); err != nil {
 		result.Error = fmt.Errorf("backup failed, mutation refused: %w", err)
@@ -48,8 +139,12 @@ func SafeApply(ctx context.Context, mut Mutator, clientset kubernetes.Interface,
 	result.Applied = true
 	slog.Info("config applied", "collector", ref.Name)
 
-	// Step 3: Trigger rollout
-	if err := mut.TriggerRollout(ctx); err != nil {
+	// Step 3: Trigger rollout; a DaemonSet canary first rolls a single node
+	if strategy == CanaryPaced {
+		if !pacedRollout(ctx, mut, clientset, ref, opts, result) {
+			return result
+		}
+	} else if err := mut.TriggerRollout(ctx); err != nil {
 		slog.Warn("rollout trigger failed, will still check health", "error", err)
 	}
 
@@ -57,29 +152,118 @@ func SafeApply(ctx context.Context, mut Mutator, clientset kubernetes.Interface,
 	healthErr := WaitHealthy(ctx, clientset, ref.Namespace, ref.Name, 30*time.Second)
 	if healthErr != nil {
 		slog.Warn("health check failed, triggering auto-rollback", "error", healthErr, "collector", ref.Name)
+		rollBack(ctx, mut, clientset, ref, result, fmt.Errorf("health check failed after mutation: %w", healthErr))
+		return result
+	}
 
-		rollbackErr := mut.Rollback(ctx)
-		if rollbackErr != nil {
-			result.Error = fmt.Errorf("health check failed AND rollback failed: health=%w, rollback=%v", healthErr, rollbackErr)
-			result.Message = "CRITICAL: Health check failed and rollback also failed"
+	// Step 5: Compare internal metrics of the healthy collector
+	if check := result.Telemetry; check != nil && check.Before != nil {
+		if err := telemetryRegression(ctx, clientset, ref, opts.Telemetry, check); err != nil {
+			slog.Warn("telemetry regressed, triggering auto-rollback", "error", err, "collector", ref.Name)
+			rollBack(ctx, mut, clientset, ref, result, err)
 			return result
 		}
-
-		// Verify recovery after rollback
-		recoveryErr := WaitHealthy(ctx, clientset, ref.Namespace, ref.Name, 30*time.Second)
-		result.RolledBack = true
-		if recoveryErr != nil {
-			result.Error = fmt.Errorf("auto-rollback completed but recovery verification failed: %w", recoveryErr)
-			result.Message = "Rolled back but recovery not verified"
-		} else {
-			result.Error = fmt.Errorf("health check failed after mutation: %w", healthErr)
-			result.Message = "Auto-rollback successful — collector recovered"
-		}
-		return result
 	}
 
 	result.HealthOK = true
 	result.Message = "Config applied and collector is healthy"
+	if opts.History != nil {
+		rev := opts.Revision
+		rev.SessionID = sessionID
+		rev.Config = configYAML
+		recorded, err := opts.History.Record(ctx, previous, rev)
+		if err != nil {
+			slog.Warn("failed to record config revision", "collector", ref.Name, "error", err)
+		}
+		result.Revision = recorded
+	}
 	slog.Info("mutation successful, collector healthy", "collector", ref.Name)
 	return result
 }
+
+// telemetryRegression observes the collector for the gate's window and
+// returns an error when its internal metrics regressed beyond the thresholds.
+// A collector whose metrics cannot be read after the change is not rolled
+// back for it; the check records why it was skipped.
+func telemetryRegression(ctx context.Context, clientset kubernetes.Interface, ref CollectorRef, gate *TelemetryGate, check *TelemetryCheck) error {
+	select {
+	case <-time.After(gate.Thresholds.Window):
+	case <-ctx.Done():
+		check.Skipped = "telemetry observation was cancelled"
+		return nil
+	}
+
+	after, err := ScrapeTelemetry(ctx, clientset, ref.Namespace, ref.Name, gate.AfterPort)
+	if err != nil {
+		slog.Warn("telemetry gate skipped after the change", "collector", ref.Name, "error", err)
+		check.Skipped = err.Error()
+		return nil
+	}
+	check.After = after
+	check.Regressions = CompareTelemetry(check.Before, after, gate.Thresholds)
+	if len(check.Regressions) > 0 {
+		return fmt.Errorf("telemetry regressed after mutation: %s", strings.Join(check.Regressions, "; "))
+	}
+	return nil
+}
+
+// pacedRollout rolls the new config out to a DaemonSet's pod on one node, then
+// to the remaining nodes at the DaemonSet's own pace once that pod passes the
+// canary gates. It rolls back and returns false when the canary fails.
+func pacedRollout(ctx context.Context, mut Mutator, clientset kubernetes.Interface, ref CollectorRef, opts SafeApplyOptions, result *SafeApplyResult) bool {
+	paced, err := pauseDaemonSet(ctx, clientset, ref)
+	if err != nil {
+		slog.Warn("canary skipped, rolling out all pods", "collector", ref.Name, "error", err)
+		result.Canary.Skipped = err.Error()
+		if err := mut.TriggerRollout(ctx); err != nil {
+			slog.Warn("rollout trigger failed, will still check health", "error", err)
+		}
+		return true
+	}
+
+	canaryErr := mut.TriggerRollout(ctx)
+	if canaryErr == nil {
+		canaryErr = paced.runPacedCanary(ctx, opts, result.Telemetry.before(), result.Canary)
+	}
+	if canaryErr == nil && !result.Canary.Promoted {
+		canaryErr = fmt.Errorf("canary failed: %s", result.Canary.Failure)
+	}
+
+	// The DaemonSet's update strategy is restored either way, so that a
+	// rollback replaces the canary pod as well
+	if err := paced.resume(ctx); err != nil {
+		slog.Error("failed to restore DaemonSet update strategy", "collector", ref.Name, "error", err)
+		if canaryErr == nil {
+			canaryErr = err
+		}
+	}
+	if canaryErr != nil {
+		slog.Warn("canary failed, triggering auto-rollback", "error", canaryErr, "collector", ref.Name)
+		rollBack(ctx, mut, clientset, ref, result, canaryErr)
+		return false
+	}
+	slog.Info("canary healthy, promoting", "collector", ref.Name, "node", result.Canary.Node)
+	return true
+}
+
+// rollBack restores the backup after a failed post-apply check and verifies
+// that the collector recovers.
+func rollBack(ctx context.Context, mut Mutator, clientset kubernetes.Interface, ref CollectorRef, result *SafeApplyResult, cause error) {
+	rollbackErr := mut.Rollback(ctx)
+	if rollbackErr != nil {
+		result.Error = fmt.Errorf("%w AND rollback failed: %v", cause, rollbackErr)
+		result.Message = "CRITICAL: Post-apply check failed and rollback also failed"
+		return
+	}
+
+	// Verify recovery after rollback
+	recoveryErr := WaitHealthy(ctx, clientset, ref.Namespace, ref.Name, 30*time.Second)
+	result.RolledBack = true
+	if recoveryErr != nil {
+		result.Error = fmt.Errorf("auto-rollback completed but recovery verification failed: %w", recoveryErr)
+		result.Message = "Rolled back but recovery not verified"
+	} else {
+		result.Error = cause
+		result.Message = "Auto-rollback successful — collector recovered"
+	}
+}
@@ -0,0 +1,241 @@
+package mutator
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// TelemetryGate compares the collector's internal metrics before and after a
+// mutation. BeforePort and AfterPort are the internal metrics ports of the
+// current and the new config.
+type TelemetryGate struct {
+	BeforePort int
+	AfterPort  int
+	Thresholds TelemetryThresholds
+}
+
+// TelemetryThresholds bound how much a mutation may worsen the collector's
+// internal metrics. Ratio increases are absolute, so 0.05 allows five more
+// failed items in every hundred.
+type TelemetryThresholds struct {
+	Window                  time.Duration // how long the new pods are observed once healthy
+	MaxFailureRatioIncrease float64       // exporter send and enqueue failures over items exported
+	MaxRefusalRatioIncrease float64       // receiver and processor refusals over items received
+	MaxQueueUtilization     float64       // exporter queue size over capacity
+}
+
+// TelemetrySnapshot sums the internal metrics of a collector's ready pods.
+type TelemetrySnapshot struct {
+	Pods          []string  `json:"pods"`
+	ScrapedAt     time.Time `json:"scraped_at"`
+	Sent          float64   `json:"sent"`
+	SendFailed    float64   `json:"send_failed"`
+	Accepted      float64   `json:"accepted"`
+	Refused       float64   `json:"refused"`
+	QueueSize     float64   `json:"queue_size"`
+	QueueCapacity float64   `json:"queue_capacity"`
+}
+
+// FailureRatio is the share of items the exporters failed to send or enqueue.
+func (s *TelemetrySnapshot) FailureRatio() float64 {
+	return ratio(s.SendFailed, s.Sent+s.SendFailed)
+}
+
+// RefusalRatio is the share of received items that were refused.
+func (s *TelemetrySnapshot) RefusalRatio() float64 {
+	return ratio(s.Refused, s.Accepted+s.Refused)
+}
+
+// QueueUtilization is how full the exporter queues are.
+func (s *TelemetrySnapshot) QueueUtilization() float64 {
+	return ratio(s.QueueSize, s.QueueCapacity)
+}
+
+// TelemetryCheck is the outcome of a telemetry gate.
+type TelemetryCheck struct {
+	Before      *TelemetrySnapshot `json:"before,omitempty"`
+	After       *TelemetrySnapshot `json:"after,omitempty"`
+	Regressions []string           `json:"regressions,omitempty"`
+	Skipped     string             `json:"skipped,omitempty"`
+}
+
+// before returns the snapshot taken before the change, if any.
+func (c *TelemetryCheck) before() *TelemetrySnapshot {
+	if c == nil {
+		return nil
+	}
+	return c.Before
+}
+
+// ScrapeTelemetry reads the internal metrics of every ready collector pod
+// through the API server's pod proxy and sums them.
+func ScrapeTelemetry(ctx context.Context, clientset kubernetes.Interface, namespace, name string, port int) (*TelemetrySnapshot, error) {
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app.kubernetes.io/instance=%s", name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	return scrapePods(ctx, clientset, namespace, pods.Items, port)
+}
+
+// scrapePods reads and sums the internal metrics of the ready pods among pods.
+func scrapePods(ctx context.Context, clientset kubernetes.Interface, namespace string, pods []corev1.Pod, port int) (*TelemetrySnapshot, error) {
+	snapshot := &TelemetrySnapshot{ScrapedAt: time.Now()}
+	var lastErr error
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil || CheckPodHealth(pod).Status != StatusHealthy {
+			continue
+		}
+		body, err := clientset.CoreV1().Pods(namespace).ProxyGet("http", pod.Name, strconv.Itoa(port), "metrics", nil).DoRaw(ctx)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		snapshot.add(parseTelemetry(string(body)))
+		snapshot.Pods = append(snapshot.Pods, pod.Name)
+	}
+
+	if len(snapshot.Pods) == 0 {
+		if lastErr != nil {
+			return nil, fmt.Errorf("failed to scrape internal metrics on port %d: %w", port, lastErr)
+		}
+		return nil, fmt.Errorf("no ready pods to scrape in %s", namespace)
+	}
+	return snapshot, nil
+}
+
+// CompareTelemetry lists how after regresses from before. Counters are
+// cumulative per pod, so when the same pods were scraped twice the
+// before values are subtracted to get what happened in between.
+func CompareTelemetry(before, after *TelemetrySnapshot, th TelemetryThresholds) []string {
+	window := *after
+	if samePods(before.Pods, after.Pods) {
+		window.Sent -= before.Sent
+		window.SendFailed -= before.SendFailed
+		window.Accepted -= before.Accepted
+		window.Refused -= before.Refused
+	}
+
+	var regressions []string
+	if increase := window.FailureRatio() - before.FailureRatio(); increase > th.MaxFailureRatioIncrease {
+		regressions = append(regressions, fmt.Sprintf("exporter failure ratio rose from %.1f%% to %.1f%%",
+			100*before.FailureRatio(), 100*window.FailureRatio()))
+	}
+	if increase := window.RefusalRatio() - before.RefusalRatio(); increase > th.MaxRefusalRatioIncrease {
+		regressions = append(regressions, fmt.Sprintf("refusal ratio rose from %.1f%% to %.1f%%",
+			100*before.RefusalRatio(), 100*window.RefusalRatio()))
+	}
+	if window.QueueUtilization() > th.MaxQueueUtilization && window.QueueUtilization() > before.QueueUtilization() {
+		regressions = append(regressions, fmt.Sprintf("exporter queues are %.0f%% full, up from %.0f%%",
+			100*window.QueueUtilization(), 100*before.QueueUtilization()))
+	}
+	if before.Sent > 0 && window.Accepted > 0 && window.Sent == 0 && window.SendFailed == 0 {
+		regressions = append(regressions, "exporters sent nothing after the change while receivers accepted data")
+	}
+	return regressions
+}
+
+// parseTelemetry sums the internal metrics the gate watches from a
+// Prometheus text exposition. Metric names are matched without the _total
+// suffix newer collectors add to counters.
+func parseTelemetry(text string) *TelemetrySnapshot {
+	s := &TelemetrySnapshot{}
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := parseSample(line)
+		if !ok {
+			continue
+		}
+		name = strings.TrimSuffix(name, "_total")
+		switch {
+		case strings.HasPrefix(name, "otelcol_exporter_sent_"):
+			s.Sent += value
+		case strings.HasPrefix(name, "otelcol_exporter_send_failed_"),
+			strings.HasPrefix(name, "otelcol_exporter_enqueue_failed_"):
+			s.SendFailed += value
+		case strings.HasPrefix(name, "otelcol_receiver_accepted_"):
+			s.Accepted += value
+		case strings.HasPrefix(name, "otelcol_receiver_refused_"),
+			strings.HasPrefix(name, "otelcol_processor_refused_"):
+			s.Refused += value
+		case name == "otelcol_exporter_queue_size":
+			s.QueueSize += value
+		case name == "otelcol_exporter_queue_capacity":
+			s.QueueCapacity += value
+		}
+	}
+	return s
+}
+
+// parseSample splits a sample line into its metric name and value.
+func parseSample(line string) (string, float64, bool) {
+	name := line
+	rest := ""
+	if i := strings.IndexAny(line, "{ "); i >= 0 {
+		name = line[:i]
+		rest = line[i:]
+	}
+	if strings.HasPrefix(rest, "{") {
+		end := strings.LastIndex(rest, "}")
+		if end < 0 {
+			return "", 0, false
+		}
+		rest = rest[end+1:]
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return "", 0, false
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return name, value, true
+}
+
+func (s *TelemetrySnapshot) add(o *TelemetrySnapshot) {
+	s.Sent += o.Sent
+	s.SendFailed += o.SendFailed
+	s.Accepted += o.Accepted
+	s.Refused += o.Refused
+	s.QueueSize += o.QueueSize
+	s.QueueCapacity += o.QueueCapacity
+}
+
+func samePods(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]bool, len(a))
+	for _, p := range a {
+		seen[p] = true
+	}
+	for _, p := range b {
+		if !seen[p] {
+			return false
+		}
+	}
+	return true
+}
+
+func ratio(part, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return part / total
+}
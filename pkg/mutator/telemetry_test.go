@@ -0,0 +1,81 @@
+package mutator
+
+import (
+	"testing"
+)
+
+const testMetrics = `# HELP otelcol_exporter_sent_spans_total Number of spans successfully sent to destination.
+# TYPE otelcol_exporter_sent_spans_total counter
+otelcol_exporter_sent_spans_total{exporter="otlp",service_instance_id="abc"} 900
+otelcol_exporter_send_failed_spans_total{exporter="otlp",service_instance_id="abc"} 100
+otelcol_exporter_enqueue_failed_spans{exporter="otlp"} 0
+otelcol_receiver_accepted_spans{receiver="otlp",transport="grpc"} 1000
+otelcol_receiver_refused_spans{receiver="otlp",transport="grpc"} 10
+otelcol_processor_refused_spans{processor="memory_limiter"} 5
+otelcol_exporter_queue_size{exporter="otlp"} 200
+otelcol_exporter_queue_capacity{exporter="otlp"} 1000
+otelcol_process_uptime 12.5
+`
+
+func TestParseTelemetry(t *testing.T) {
+	s := parseTelemetry(testMetrics)
+
+	if s.Sent != 900 || s.SendFailed != 100 {
+		t.Errorf("expected 900 sent and 100 failed, got %v and %v", s.Sent, s.SendFailed)
+	}
+	if s.Accepted != 1000 || s.Refused != 15 {
+		t.Errorf("expected 1000 accepted and 15 refused, got %v and %v", s.Accepted, s.Refused)
+	}
+	if s.QueueSize != 200 || s.QueueCapacity != 1000 {
+		t.Errorf("expected queue 200/1000, got %v/%v", s.QueueSize, s.QueueCapacity)
+	}
+	if s.FailureRatio() != 0.1 {
+		t.Errorf("expected failure ratio 0.1, got %v", s.FailureRatio())
+	}
+}
+
+func TestCompareTelemetry(t *testing.T) {
+	th := TelemetryThresholds{MaxFailureRatioIncrease: 0.05, MaxRefusalRatioIncrease: 0.05, MaxQueueUtilization: 0.8}
+	before := &TelemetrySnapshot{Pods: []string{"otel-a"}, Sent: 1000, Accepted: 1000, QueueCapacity: 1000}
+
+	tests := []struct {
+		name  string
+		after *TelemetrySnapshot
+		want  int
+	}{
+		{
+			name:  "steady on the same pods",
+			after: &TelemetrySnapshot{Pods: []string{"otel-a"}, Sent: 2000, SendFailed: 10, Accepted: 2000, QueueSize: 100, QueueCapacity: 1000},
+			want:  0,
+		},
+		{
+			name:  "failures since the change on the same pods",
+			after: &TelemetrySnapshot{Pods: []string{"otel-a"}, Sent: 1500, SendFailed: 500, Accepted: 2000, QueueCapacity: 1000},
+			want:  1,
+		},
+		{
+			name:  "refusals on new pods",
+			after: &TelemetrySnapshot{Pods: []string{"otel-b"}, Sent: 900, Accepted: 900, Refused: 100, QueueCapacity: 1000},
+			want:  1,
+		},
+		{
+			name:  "queues filling up",
+			after: &TelemetrySnapshot{Pods: []string{"otel-b"}, Sent: 1000, Accepted: 1000, QueueSize: 900, QueueCapacity: 1000},
+			want:  1,
+		},
+		{
+			name:  "exporters stopped sending",
+			after: &TelemetrySnapshot{Pods: []string{"otel-b"}, Accepted: 500, QueueCapacity: 1000},
+			want:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompareTelemetry(before, tt.after, th)
+			if len(got) != tt.want {
+				t.Errorf("expected %d regressions, got %v", tt.want, got)
+			}
+		})
+	}
+}
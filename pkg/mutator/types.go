@@ -8,16 +8,28 @@ type Mutator interface {
 	// Backup stores the current config for later rollback.
 	Backup(ctx context.Context, sessionID string) error
 
+	// CurrentConfig returns the collector YAML config currently stored.
+	CurrentConfig(ctx context.Context) (string, error)
+
+	// BackedUpConfig returns the collector YAML config held in the backup.
+	BackedUpConfig(ctx context.Context) (string, error)
+
 	// ApplyConfig applies new YAML config to the collector.
 	ApplyConfig(ctx context.Context, configYAML string) error
 
+	// DryRunConfig submits the update ApplyConfig would make as a
+	// server-side dry run. Nothing is persisted; validation and admission
+	// webhook rejections are returned as errors.
+	DryRunConfig(ctx context.Context, configYAML string) error
+
 	// Rollback restores the backed-up config and triggers a rollout.
 	Rollback(ctx context.Context) error
 
 	// TriggerRollout restarts the collector workload to pick up config changes.
 	TriggerRollout(ctx context.Context) error
 
-	// Cleanup removes backup annotations and session metadata.
+	// Cleanup removes backup annotations and session metadata. The
+	// collector's revision history is kept.
 	Cleanup(ctx context.Context) error
 
 	// DetectGitOps checks for ArgoCD or Flux annotations and returns a warning if found.
@@ -0,0 +1,210 @@
+package mutator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Validator checks a candidate collector config before it is applied.
+type Validator interface {
+	// Validate returns a *ValidationError when the collector rejects the
+	// config, and another error when the validation itself could not run.
+	Validate(ctx context.Context, configYAML string) error
+}
+
+// ValidationError carries the output of a collector that rejected a config.
+type ValidationError struct {
+	Output string
+}
+
+func (e *ValidationError) Error() string {
+	return "collector rejected the config: " + e.Output
+}
+
+// BinaryValidator runs the validate subcommand of a local otelcol or
+// otelcol-contrib binary.
+type BinaryValidator struct {
+	binary  string
+	timeout time.Duration
+}
+
+// NewBinaryValidator creates a BinaryValidator for the binary at path.
+func NewBinaryValidator(binary string, timeout time.Duration) *BinaryValidator {
+	return &BinaryValidator{binary: binary, timeout: timeout}
+}
+
+func (v *BinaryValidator) Validate(ctx context.Context, configYAML string) error {
+	f, err := os.CreateTemp("", "otelcol-config-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to create config file for validation: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(configYAML); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write config file for validation: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write config file for validation: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, v.timeout)
+	defer cancel()
+
+	// #nosec G204 -- the binary path comes from server configuration
+	out, err := exec.CommandContext(ctx, v.binary, "validate", "--config="+f.Name()).CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("validator %s timed out after %s", v.binary, v.timeout)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return &ValidationError{Output: strings.TrimSpace(string(out))}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run validator %s: %w", v.binary, err)
+	}
+	return nil
+}
+
+// JobValidator runs the validate subcommand in a Kubernetes Job that uses
+// the collector's own image, so the config is checked against the
+// components the collector was built with.
+type JobValidator struct {
+	clientset kubernetes.Interface
+	ref       CollectorRef
+	timeout   time.Duration
+}
+
+// NewJobValidator creates a JobValidator for the given collector reference.
+func NewJobValidator(clientset kubernetes.Interface, ref CollectorRef, timeout time.Duration) *JobValidator {
+	return &JobValidator{clientset: clientset, ref: ref, timeout: timeout}
+}
+
+func (v *JobValidator) Validate(ctx context.Context, configYAML string) error {
+	ctx, cancel := context.WithTimeout(ctx, v.timeout)
+	defer cancel()
+
+	image, err := v.collectorImage(ctx)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-validate-%d", v.ref.Name, time.Now().Unix())
+	labels := map[string]string{"app.kubernetes.io/managed-by": "otel-collector-mcp"}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: v.ref.Namespace, Labels: labels},
+		Data:       map[string]string{"config.yaml": configYAML},
+	}
+	if _, err := v.clientset.CoreV1().ConfigMaps(v.ref.Namespace).Create(ctx, cm, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create validation ConfigMap: %w", err)
+	}
+	defer v.cleanup(name)
+
+	backoffLimit := int32(0)
+	ttl := int32(300)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: v.ref.Namespace, Labels: labels},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{{
+						Name:         "validate",
+						Image:        image,
+						Args:         []string{"validate", "--config=/conf/config.yaml"},
+						VolumeMounts: []corev1.VolumeMount{{Name: "config", MountPath: "/conf"}},
+					}},
+					Volumes: []corev1.Volume{{
+						Name: "config",
+						VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: name}},
+						},
+					}},
+				},
+			},
+		},
+	}
+	if _, err := v.clientset.BatchV1().Jobs(v.ref.Namespace).Create(ctx, job, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create validation Job: %w", err)
+	}
+
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	for {
+		current, err := v.clientset.BatchV1().Jobs(v.ref.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get validation Job: %w", err)
+		}
+		if current.Status.Succeeded > 0 {
+			return nil
+		}
+		if current.Status.Failed > 0 {
+			return &ValidationError{Output: v.jobOutput(ctx, name)}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("validation Job %s/%s did not finish within %s", v.ref.Namespace, name, v.timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// cleanup deletes the validation Job, its pod and its ConfigMap.
+func (v *JobValidator) cleanup(name string) {
+	ctx := context.Background()
+	propagation := metav1.DeletePropagationBackground
+	err := v.clientset.BatchV1().Jobs(v.ref.Namespace).Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil && !apierrors.IsNotFound(err) {
+		slog.Warn("failed to delete validation Job", "job", name, "error", err)
+	}
+	if err := v.clientset.CoreV1().ConfigMaps(v.ref.Namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		slog.Warn("failed to delete validation ConfigMap", "configmap", name, "error", err)
+	}
+}
+
+// collectorImage returns the image of the collector's first pod.
+func (v *JobValidator) collectorImage(ctx context.Context) (string, error) {
+	pods, err := v.clientset.CoreV1().Pods(v.ref.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app.kubernetes.io/instance=%s", v.ref.Name),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list collector pods: %w", err)
+	}
+	for _, pod := range pods.Items {
+		if len(pod.Spec.Containers) > 0 {
+			return pod.Spec.Containers[0].Image, nil
+		}
+	}
+	return "", fmt.Errorf("no collector pod found for %s/%s to take the image from", v.ref.Namespace, v.ref.Name)
+}
+
+// jobOutput returns the logs of the validation Job's pod.
+func (v *JobValidator) jobOutput(ctx context.Context, jobName string) string {
+	pods, err := v.clientset.CoreV1().Pods(v.ref.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "job-name=" + jobName,
+	})
+	if err != nil || len(pods.Items) == 0 {
+		return "validation Job failed; its pod logs are not available"
+	}
+	out, err := v.clientset.CoreV1().Pods(v.ref.Namespace).GetLogs(pods.Items[0].Name, &corev1.PodLogOptions{}).DoRaw(ctx)
+	if err != nil {
+		return fmt.Sprintf("validation Job failed; reading its logs failed: %v", err)
+	}
+	return strings.TrimSpace(string(out))
+}
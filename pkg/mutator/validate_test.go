@@ -0,0 +1,117 @@
+package mutator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// stubCollector is a stand-in for otelcol's validate subcommand: it rejects
+// configs that mention an unknown component.
+const stubCollector = `#!/bin/sh
+[ "$1" = "validate" ] || exit 2
+config="${2#--config=}"
+if grep -q unknowncomponent "$config"; then
+  echo "Error: failed to get config: 'exporters' unknown type: \"unknowncomponent\""
+  exit 1
+fi
+`
+
+func TestBinaryValidator(t *testing.T) {
+	binary := filepath.Join(t.TempDir(), "otelcol")
+	if err := os.WriteFile(binary, []byte(stubCollector), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		binary         string
+		config         string
+		wantValidation bool
+		wantErr        bool
+	}{
+		{name: "valid config", binary: binary, config: testConfig},
+		{name: "rejected config", binary: binary, config: "exporters:\n  unknowncomponent: {}\n", wantValidation: true, wantErr: true},
+		{name: "missing binary", binary: filepath.Join(t.TempDir(), "absent"), config: testConfig, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewBinaryValidator(tt.binary, 10*time.Second).Validate(context.Background(), tt.config)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) != tt.wantValidation {
+				t.Fatalf("expected validation error %v, got %v", tt.wantValidation, err)
+			}
+			if tt.wantValidation && !strings.Contains(validationErr.Output, "unknown type") {
+				t.Errorf("expected the validator output in the error, got %q", validationErr.Output)
+			}
+		})
+	}
+}
+
+func TestJobValidator(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  batchv1.JobStatus
+		wantErr bool
+	}{
+		{name: "succeeded", status: batchv1.JobStatus{Succeeded: 1}},
+		{name: "failed", status: batchv1.JobStatus{Failed: 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "otel-abc", Namespace: "obs", Labels: map[string]string{"app.kubernetes.io/instance": "otel"}},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "otc", Image: "otel/opentelemetry-collector-contrib:0.120.0"}}},
+			})
+			var created *batchv1.Job
+			clientset.PrependReactor("create", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				created = action.(k8stesting.CreateAction).GetObject().(*batchv1.Job)
+				return false, nil, nil
+			})
+			clientset.PrependReactor("get", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				job := created.DeepCopy()
+				job.Status = tt.status
+				return true, job, nil
+			})
+
+			err := NewJobValidator(clientset, CollectorRef{Name: "otel", Namespace: "obs"}, 10*time.Second).Validate(context.Background(), testConfig)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				var validationErr *ValidationError
+				if !errors.As(err, &validationErr) {
+					t.Errorf("expected a validation error, got %v", err)
+				}
+			}
+
+			if created == nil {
+				t.Fatal("expected a validation Job to be created")
+			}
+			if image := created.Spec.Template.Spec.Containers[0].Image; image != "otel/opentelemetry-collector-contrib:0.120.0" {
+				t.Errorf("expected the collector's image, got %s", image)
+			}
+			jobs, _ := clientset.BatchV1().Jobs("obs").List(context.Background(), metav1.ListOptions{})
+			cms, _ := clientset.CoreV1().ConfigMaps("obs").List(context.Background(), metav1.ListOptions{})
+			if len(jobs.Items) != 0 || len(cms.Items) != 0 {
+				t.Errorf("expected the Job and ConfigMap to be cleaned up, got %d jobs and %d configmaps", len(jobs.Items), len(cms.Items))
+			}
+		})
+	}
+}
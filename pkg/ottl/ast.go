@@ -0,0 +1,107 @@
+package ottl
+
+// Statement is an editor invocation with an optional where clause.
+type Statement struct {
+	Editor *Call
+	Where  *BoolExpr
+}
+
+// Call invokes an editor (lowercase) or converter (uppercase). Keys index the
+// converter's result, as in ParseJSON(body)["level"].
+type Call struct {
+	Name string
+	Args []Arg
+	Keys []Value
+	Pos  int
+}
+
+// Arg is a positional or named function argument.
+type Arg struct {
+	Name  string
+	Value Value
+}
+
+// Field is one segment of a path with its map or slice keys.
+type Field struct {
+	Name string
+	Keys []Value
+}
+
+// Path is a reference to telemetry data, such as resource.attributes["k"].
+type Path struct {
+	Fields []Field
+	Pos    int
+}
+
+// String renders the path without keys, e.g. resource.attributes.
+func (p *Path) String() string {
+	s := ""
+	for i, f := range p.Fields {
+		if i > 0 {
+			s += "."
+		}
+		s += f.Name
+	}
+	return s
+}
+
+// LiteralKind is the type of a literal value.
+type LiteralKind int
+
+const (
+	LitString LiteralKind = iota
+	LitInt
+	LitFloat
+	LitBool
+	LitNil
+	LitBytes
+)
+
+// Literal is a constant value.
+type Literal struct {
+	Kind LiteralKind
+	Raw  string
+}
+
+// Value is one OTTL value. Exactly one field is set.
+type Value struct {
+	Literal *Literal
+	Path    *Path
+	Call    *Call
+	Enum    string // an uppercase identifier: an enum or a function reference
+	Map     []MapEntry
+	List    []Value
+	IsMap   bool
+	IsList  bool
+	Math    *MathExpr
+	Pos     int
+}
+
+// MapEntry is a key-value pair of a map literal.
+type MapEntry struct {
+	Key   string
+	Value Value
+}
+
+// MathExpr is a binary arithmetic expression.
+type MathExpr struct {
+	Op          string
+	Left, Right Value
+}
+
+// BoolExpr is a boolean expression tree. Exactly one of Or, And, Not,
+// Comparison or Value (a boolean literal or converter) is set.
+type BoolExpr struct {
+	Or         []*BoolExpr
+	And        []*BoolExpr
+	Not        *BoolExpr
+	Comparison *Comparison
+	Value      *Value
+	Pos        int
+}
+
+// Comparison compares two values.
+type Comparison struct {
+	Op          string
+	Left, Right Value
+}
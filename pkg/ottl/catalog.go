@@ -0,0 +1,296 @@
+package ottl
+
+import "strings"
+
+// Context is an OTTL evaluation context.
+type Context string
+
+const (
+	ContextResource  Context = "resource"
+	ContextScope     Context = "scope"
+	ContextSpan      Context = "span"
+	ContextSpanEvent Context = "spanevent"
+	ContextMetric    Context = "metric"
+	ContextDataPoint Context = "datapoint"
+	ContextLog       Context = "log"
+)
+
+// contextInfo describes the paths available in a context.
+type contextInfo struct {
+	// fields maps each top-level field to its allowed sub-fields; a nil
+	// slice means the field takes keys only (or nothing).
+	fields map[string][]string
+	// parents maps path prefixes to the higher contexts they reach.
+	parents map[string]Context
+}
+
+var commonParents = map[string]Context{
+	"resource":              ContextResource,
+	"instrumentation_scope": ContextScope,
+	"scope":                 ContextScope,
+}
+
+var contexts = map[Context]contextInfo{
+	ContextResource: {
+		fields: fieldSet("attributes", "dropped_attributes_count", "cache"),
+	},
+	ContextScope: {
+		fields:  fieldSet("name", "version", "attributes", "dropped_attributes_count", "cache"),
+		parents: map[string]Context{"resource": ContextResource},
+	},
+	ContextSpan: {
+		fields: withSubfields(fieldSet(
+			"trace_state", "name", "start_time_unix_nano", "end_time_unix_nano", "start_time", "end_time",
+			"attributes", "dropped_attributes_count", "events", "dropped_events_count", "links",
+			"dropped_links_count", "cache",
+		), map[string][]string{
+			"trace_id":       {"string"},
+			"span_id":        {"string"},
+			"parent_span_id": {"string"},
+			"kind":           {"string", "deprecated_string"},
+			"status":         {"code", "message"},
+		}),
+		parents: commonParents,
+	},
+	ContextSpanEvent: {
+		fields:  fieldSet("time_unix_nano", "time", "name", "attributes", "dropped_attributes_count", "event_index", "cache"),
+		parents: merge(commonParents, map[string]Context{"span": ContextSpan}),
+	},
+	ContextMetric: {
+		fields: fieldSet("name", "description", "unit", "type", "aggregation_temporality", "is_monotonic",
+			"data_points", "metadata", "cache"),
+		parents: commonParents,
+	},
+	ContextDataPoint: {
+		fields: withSubfields(fieldSet(
+			"attributes", "start_time_unix_nano", "time_unix_nano", "start_time", "time", "value_double",
+			"value_int", "exemplars", "flags", "count", "sum", "bucket_counts", "explicit_bounds", "scale",
+			"zero_count", "quantile_values", "cache",
+		), map[string][]string{
+			"positive": {"offset", "bucket_counts"},
+			"negative": {"offset", "bucket_counts"},
+		}),
+		parents: merge(commonParents, map[string]Context{"metric": ContextMetric}),
+	},
+	ContextLog: {
+		fields: withSubfields(fieldSet(
+			"time_unix_nano", "observed_time_unix_nano", "time", "observed_time", "severity_number",
+			"severity_text", "attributes", "dropped_attributes_count", "flags", "event_name", "cache",
+		), map[string][]string{
+			"body":     {"string"},
+			"trace_id": {"string"},
+			"span_id":  {"string"},
+		}),
+		parents: commonParents,
+	},
+}
+
+// signalContexts lists the contexts valid for each signal, lowest first.
+var signalContexts = map[string][]Context{
+	"traces":  {ContextSpanEvent, ContextSpan, ContextScope, ContextResource},
+	"metrics": {ContextDataPoint, ContextMetric, ContextScope, ContextResource},
+	"logs":    {ContextLog, ContextScope, ContextResource},
+}
+
+// ContextsForSignal returns the contexts valid for a signal, lowest first.
+func ContextsForSignal(signal string) []Context {
+	return signalContexts[signal]
+}
+
+// TransformStatementSignals maps transform processor statement keys to signals.
+var TransformStatementSignals = map[string]string{
+	"trace_statements":  "traces",
+	"metric_statements": "metrics",
+	"log_statements":    "logs",
+}
+
+// FilterConditionContexts maps filter processor condition keys, per signal, to
+// the OTTL context the conditions are evaluated in.
+var FilterConditionContexts = map[string]map[string]Context{
+	"traces":  {"span": ContextSpan, "spanevent": ContextSpanEvent},
+	"metrics": {"metric": ContextMetric, "datapoint": ContextDataPoint},
+	"logs":    {"log_record": ContextLog},
+}
+
+// ValidContext reports whether name is a known context.
+func ValidContext(name string) bool {
+	_, ok := contexts[Context(name)]
+	return ok
+}
+
+// function describes an editor or converter signature.
+type function struct {
+	min, max int
+	contexts []Context // nil means every context
+}
+
+var metricOnly = []Context{ContextMetric}
+
+var editors = map[string]function{
+	"set":                              {min: 2, max: 2},
+	"delete_key":                       {min: 2, max: 2},
+	"delete_matching_keys":             {min: 2, max: 2},
+	"keep_keys":                        {min: 2, max: 2},
+	"keep_matching_keys":               {min: 2, max: 2},
+	"limit":                            {min: 3, max: 3},
+	"merge_maps":                       {min: 3, max: 3},
+	"replace_all_matches":              {min: 3, max: 4},
+	"replace_all_patterns":             {min: 4, max: 5},
+	"replace_match":                    {min: 3, max: 4},
+	"replace_pattern":                  {min: 3, max: 4},
+	"truncate_all":                     {min: 2, max: 2},
+	"flatten":                          {min: 1, max: 3},
+	"append":                           {min: 1, max: 3},
+	"convert_sum_to_gauge":             {min: 0, max: 0, contexts: metricOnly},
+	"convert_gauge_to_sum":             {min: 2, max: 2, contexts: metricOnly},
+	"convert_summary_count_val_to_sum": {min: 2, max: 3, contexts: metricOnly},
+	"convert_summary_sum_val_to_sum":   {min: 2, max: 3, contexts: metricOnly},
+	"convert_exponential_histogram_to_histogram": {min: 2, max: 2, contexts: metricOnly},
+	"extract_sum_metric":                         {min: 1, max: 1, contexts: metricOnly},
+	"extract_count_metric":                       {min: 1, max: 1, contexts: metricOnly},
+	"copy_metric":                                {min: 0, max: 2, contexts: metricOnly},
+	"scale_metric":                               {min: 1, max: 2, contexts: metricOnly},
+	"aggregate_on_attributes":                    {min: 1, max: 2, contexts: metricOnly},
+	"aggregate_on_attribute_value":               {min: 4, max: 4, contexts: metricOnly},
+}
+
+var converters = map[string]function{
+	"Base64Decode":        {min: 1, max: 1},
+	"Concat":              {min: 2, max: 2},
+	"ContainsValue":       {min: 2, max: 2},
+	"ConvertCase":         {min: 2, max: 2},
+	"Day":                 {min: 1, max: 1},
+	"Decode":              {min: 2, max: 2},
+	"Double":              {min: 1, max: 1},
+	"Duration":            {min: 1, max: 1},
+	"ExtractGrokPatterns": {min: 2, max: 4},
+	"ExtractPatterns":     {min: 2, max: 2},
+	"FNV":                 {min: 1, max: 1},
+	"Format":              {min: 2, max: 2},
+	"FormatTime":          {min: 2, max: 2},
+	"GetXML":              {min: 2, max: 2},
+	"Hex":                 {min: 1, max: 1},
+	"Hour":                {min: 1, max: 1},
+	"Hours":               {min: 1, max: 1},
+	"Index":               {min: 2, max: 2},
+	"InsertXML":           {min: 3, max: 3},
+	"Int":                 {min: 1, max: 1},
+	"IsBool":              {min: 1, max: 1},
+	"IsDouble":            {min: 1, max: 1},
+	"IsInt":               {min: 1, max: 1},
+	"IsList":              {min: 1, max: 1},
+	"IsMap":               {min: 1, max: 1},
+	"IsMatch":             {min: 2, max: 2},
+	"IsRootSpan":          {min: 0, max: 0, contexts: []Context{ContextSpan, ContextSpanEvent}},
+	"IsString":            {min: 1, max: 1},
+	"IsValidLuhn":         {min: 1, max: 1},
+	"Keys":                {min: 1, max: 1},
+	"Len":                 {min: 1, max: 1},
+	"Log":                 {min: 1, max: 1},
+	"MD5":                 {min: 1, max: 1},
+	"Microseconds":        {min: 1, max: 1},
+	"Milliseconds":        {min: 1, max: 1},
+	"Minute":              {min: 1, max: 1},
+	"Minutes":             {min: 1, max: 1},
+	"Month":               {min: 1, max: 1},
+	"Murmur3Hash":         {min: 1, max: 1},
+	"Murmur3Hash128":      {min: 1, max: 1},
+	"Nanosecond":          {min: 1, max: 1},
+	"Nanoseconds":         {min: 1, max: 1},
+	"Now":                 {min: 0, max: 0},
+	"ParseCSV":            {min: 2, max: 5},
+	"ParseInt":            {min: 2, max: 2},
+	"ParseJSON":           {min: 1, max: 1},
+	"ParseKeyValue":       {min: 1, max: 3},
+	"ParseSimplifiedXML":  {min: 1, max: 1},
+	"ParseXML":            {min: 1, max: 1},
+	"RemoveXML":           {min: 2, max: 2},
+	"SHA1":                {min: 1, max: 1},
+	"SHA256":              {min: 1, max: 1},
+	"SHA512":              {min: 1, max: 1},
+	"Second":              {min: 1, max: 1},
+	"Seconds":             {min: 1, max: 1},
+	"SliceToMap":          {min: 1, max: 3},
+	"Sort":                {min: 1, max: 2},
+	"SpanID":              {min: 1, max: 1},
+	"Split":               {min: 2, max: 2},
+	"String":              {min: 1, max: 1},
+	"Substring":           {min: 3, max: 3},
+	"Time":                {min: 2, max: 4},
+	"ToCamelCase":         {min: 1, max: 1},
+	"ToKeyValueString":    {min: 1, max: 4},
+	"ToLowerCase":         {min: 1, max: 1},
+	"ToSnakeCase":         {min: 1, max: 1},
+	"ToUpperCase":         {min: 1, max: 1},
+	"TraceID":             {min: 1, max: 1},
+	"TruncateTime":        {min: 2, max: 2},
+	"URL":                 {min: 1, max: 1},
+	"UUID":                {min: 0, max: 0},
+	"Unix":                {min: 1, max: 2},
+	"UnixMicro":           {min: 1, max: 1},
+	"UnixMilli":           {min: 1, max: 1},
+	"UnixNano":            {min: 1, max: 1},
+	"UnixSeconds":         {min: 1, max: 1},
+	"UserAgent":           {min: 1, max: 1},
+	"Values":              {min: 1, max: 1},
+	"Weekday":             {min: 1, max: 1},
+	"XXH128":              {min: 1, max: 1},
+	"XXH3":                {min: 1, max: 1},
+	"Year":                {min: 1, max: 1},
+}
+
+// enumPrefixes lists the prefixes of the enums OTTL defines.
+var enumPrefixes = []string{
+	"STATUS_CODE_",
+	"SPAN_KIND_",
+	"SEVERITY_NUMBER_",
+	"AGGREGATION_TEMPORALITY_",
+	"METRIC_DATA_TYPE_",
+}
+
+// IsEditor reports whether name is a known editor.
+func IsEditor(name string) bool {
+	_, ok := editors[name]
+	return ok
+}
+
+// IsConverter reports whether name is a known converter.
+func IsConverter(name string) bool {
+	_, ok := converters[name]
+	return ok
+}
+
+func isKnownEnum(name string) bool {
+	for _, prefix := range enumPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func fieldSet(names ...string) map[string][]string {
+	m := make(map[string][]string, len(names))
+	for _, n := range names {
+		m[n] = nil
+	}
+	return m
+}
+
+func withSubfields(fields map[string][]string, sub map[string][]string) map[string][]string {
+	for k, v := range sub {
+		fields[k] = v
+	}
+	return fields
+}
+
+func merge(a, b map[string]Context) map[string]Context {
+	m := make(map[string]Context, len(a)+len(b))
+	for k, v := range a {
+		m[k] = v
+	}
+	for k, v := range b {
+		m[k] = v
+	}
+	return m
+}
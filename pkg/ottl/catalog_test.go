@@ -0,0 +1,36 @@
+package ottl
+
+import "testing"
+
+func TestCatalogArity(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantArity bool
+	}{
+		{name: "ParseCSV without header", input: `set(attributes["csv"], ParseCSV(body))`, wantArity: true},
+		{name: "ParseCSV with header", input: `set(attributes["csv"], ParseCSV(body, "a,b"))`},
+		{name: "ParseCSV with every option", input: `set(attributes["csv"], ParseCSV(body, "a;b", ";", ";", "strict"))`},
+		{name: "ParseCSV with too many arguments", input: `set(attributes["csv"], ParseCSV(body, "a", ",", ",", "strict", "x"))`, wantArity: true},
+		{name: "append with target only", input: `append(attributes["tags"])`},
+		{name: "append with value", input: `append(attributes["tags"], "a")`},
+		{name: "append with value and values", input: `append(attributes["tags"], "a", ["b", "c"])`},
+		{name: "append without target", input: `append()`, wantArity: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotArity := false
+			for _, p := range CheckStatement(ContextLog, tt.input) {
+				if p.Kind == ProblemArity {
+					gotArity = true
+				} else {
+					t.Errorf("unexpected %s problem: %s", p.Kind, p.Message)
+				}
+			}
+			if gotArity != tt.wantArity {
+				t.Errorf("expected arity problem %v, got %v", tt.wantArity, gotArity)
+			}
+		})
+	}
+}
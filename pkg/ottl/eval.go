@@ -0,0 +1,869 @@
+package ottl
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// record is one telemetry item evaluated in a context. Higher-context paths
+// such as resource. or metric. resolve through up.
+type record struct {
+	ctx    Context
+	fields map[string]interface{}
+	up     map[Context]*record
+}
+
+// UnsupportedError reports a function the evaluator cannot run. Statements
+// using it are validated but not previewed.
+type UnsupportedError struct {
+	Name string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s is not supported by the preview evaluator", e.Name)
+}
+
+// evaluator runs parsed statements and conditions against records.
+type evaluator struct {
+	regexps map[string]*regexp.Regexp
+}
+
+func newEvaluator() *evaluator {
+	return &evaluator{regexps: make(map[string]*regexp.Regexp)}
+}
+
+func (e *evaluator) regexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := e.regexps[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	e.regexps[pattern] = re
+	return re, nil
+}
+
+// execStatement runs one statement and reports whether its where clause held.
+func (e *evaluator) execStatement(stmt *Statement, r *record) (bool, error) {
+	if stmt.Where != nil {
+		ok, err := e.evalBool(stmt.Where, r)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, e.execEditor(stmt.Editor, r)
+}
+
+func (e *evaluator) evalBool(b *BoolExpr, r *record) (bool, error) {
+	switch {
+	case b.Or != nil:
+		for _, sub := range b.Or {
+			ok, err := e.evalBool(sub, r)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	case b.And != nil:
+		for _, sub := range b.And {
+			ok, err := e.evalBool(sub, r)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case b.Not != nil:
+		ok, err := e.evalBool(b.Not, r)
+		return !ok, err
+	case b.Comparison != nil:
+		left, err := e.eval(b.Comparison.Left, r)
+		if err != nil {
+			return false, err
+		}
+		right, err := e.eval(b.Comparison.Right, r)
+		if err != nil {
+			return false, err
+		}
+		return compare(b.Comparison.Op, left, right), nil
+	case b.Value != nil:
+		v, err := e.eval(*b.Value, r)
+		if err != nil {
+			return false, err
+		}
+		ok, isBool := v.(bool)
+		if !isBool {
+			return false, fmt.Errorf("condition value %v is not a boolean", v)
+		}
+		return ok, nil
+	}
+	return false, nil
+}
+
+// eval computes the value of v for a record.
+func (e *evaluator) eval(v Value, r *record) (interface{}, error) {
+	switch {
+	case v.Literal != nil:
+		return literalValue(v.Literal)
+	case v.Path != nil:
+		return e.getPath(v.Path.Fields, r)
+	case v.Call != nil:
+		out, err := e.execConverter(v.Call, r)
+		if err != nil {
+			return nil, err
+		}
+		return e.index(out, v.Call.Keys, r)
+	case v.Enum != "":
+		if n, ok := enumValues[v.Enum]; ok {
+			return n, nil
+		}
+		return nil, &UnsupportedError{Name: v.Enum}
+	case v.IsMap:
+		m := make(map[string]interface{}, len(v.Map))
+		for _, entry := range v.Map {
+			val, err := e.eval(entry.Value, r)
+			if err != nil {
+				return nil, err
+			}
+			m[unescape(entry.Key)] = val
+		}
+		return m, nil
+	case v.IsList:
+		list := make([]interface{}, 0, len(v.List))
+		for _, item := range v.List {
+			val, err := e.eval(item, r)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, val)
+		}
+		return list, nil
+	case v.Math != nil:
+		left, err := e.eval(v.Math.Left, r)
+		if err != nil {
+			return nil, err
+		}
+		right, err := e.eval(v.Math.Right, r)
+		if err != nil {
+			return nil, err
+		}
+		return arithmetic(v.Math.Op, left, right)
+	}
+	return nil, nil
+}
+
+// enumValues holds the numeric values of the enums the evaluator knows.
+var enumValues = map[string]interface{}{
+	"STATUS_CODE_UNSET":                   int64(0),
+	"STATUS_CODE_OK":                      int64(1),
+	"STATUS_CODE_ERROR":                   int64(2),
+	"SPAN_KIND_UNSPECIFIED":               int64(0),
+	"SPAN_KIND_INTERNAL":                  int64(1),
+	"SPAN_KIND_SERVER":                    int64(2),
+	"SPAN_KIND_CLIENT":                    int64(3),
+	"SPAN_KIND_PRODUCER":                  int64(4),
+	"SPAN_KIND_CONSUMER":                  int64(5),
+	"SEVERITY_NUMBER_UNSPECIFIED":         int64(0),
+	"SEVERITY_NUMBER_TRACE":               int64(1),
+	"SEVERITY_NUMBER_DEBUG":               int64(5),
+	"SEVERITY_NUMBER_INFO":                int64(9),
+	"SEVERITY_NUMBER_WARN":                int64(13),
+	"SEVERITY_NUMBER_ERROR":               int64(17),
+	"SEVERITY_NUMBER_FATAL":               int64(21),
+	"AGGREGATION_TEMPORALITY_UNSPECIFIED": int64(0),
+	"AGGREGATION_TEMPORALITY_DELTA":       int64(1),
+	"AGGREGATION_TEMPORALITY_CUMULATIVE":  int64(2),
+}
+
+// resolve follows context prefixes to the record a path refers to and
+// returns the remaining fields.
+func (r *record) resolve(fields []Field) (*record, []Field, error) {
+	info := contexts[r.ctx]
+	name := fields[0].Name
+	if _, isField := info.fields[name]; !isField && len(fields) > 1 {
+		if name == string(r.ctx) {
+			return r.resolve(fields[1:])
+		}
+		if parent, ok := info.parents[name]; ok {
+			up := r.up[parent]
+			if up == nil {
+				return nil, nil, fmt.Errorf("no %s data was captured", parent)
+			}
+			return up.resolve(fields[1:])
+		}
+	}
+	return r, fields, nil
+}
+
+// fieldKey returns the record key for a field and its optional sub-field,
+// with the keys that index into it.
+func fieldKey(fields []Field) (string, []Value) {
+	if len(fields) > 1 {
+		return fields[0].Name + "." + fields[1].Name, fields[1].Keys
+	}
+	return fields[0].Name, fields[0].Keys
+}
+
+func (e *evaluator) getPath(fields []Field, r *record) (interface{}, error) {
+	rec, rest, err := r.resolve(fields)
+	if err != nil {
+		return nil, err
+	}
+	key, keys := fieldKey(rest)
+	v, ok := rec.fields[key]
+	if !ok && len(rest) > 1 {
+		// Sub-fields such as trace_id.string read the same captured value.
+		v = rec.fields[rest[0].Name]
+	}
+	if len(rest) > 1 && len(rest[0].Keys) > 0 {
+		return nil, fmt.Errorf("path %s cannot index %s", (&Path{Fields: fields}).String(), rest[0].Name)
+	}
+	return e.index(v, keys, r)
+}
+
+func (e *evaluator) index(v interface{}, keys []Value, r *record) (interface{}, error) {
+	for _, k := range keys {
+		key, err := e.eval(k, r)
+		if err != nil {
+			return nil, err
+		}
+		switch c := v.(type) {
+		case map[string]interface{}:
+			s, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("map key %v is not a string", key)
+			}
+			v = c[s]
+		case []interface{}:
+			i, ok := key.(int64)
+			if !ok || i < 0 || int(i) >= len(c) {
+				return nil, fmt.Errorf("invalid slice index %v", key)
+			}
+			v = c[i]
+		case nil:
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("cannot index a %T", v)
+		}
+	}
+	return v, nil
+}
+
+func (e *evaluator) setPath(fields []Field, value interface{}, r *record) error {
+	rec, rest, err := r.resolve(fields)
+	if err != nil {
+		return err
+	}
+	key, keys := fieldKey(rest)
+	if len(keys) == 0 {
+		rec.fields[key] = value
+		return nil
+	}
+	m, _ := rec.fields[key].(map[string]interface{})
+	if m == nil {
+		m = make(map[string]interface{})
+		rec.fields[key] = m
+	}
+	for i, k := range keys {
+		kv, err := e.eval(k, r)
+		if err != nil {
+			return err
+		}
+		s, ok := kv.(string)
+		if !ok {
+			return &UnsupportedError{Name: "setting a slice index"}
+		}
+		if i == len(keys)-1 {
+			m[s] = value
+			break
+		}
+		sub, ok := m[s].(map[string]interface{})
+		if !ok {
+			sub = make(map[string]interface{})
+			m[s] = sub
+		}
+		m = sub
+	}
+	return nil
+}
+
+// args evaluates a call's arguments positionally.
+func (e *evaluator) args(c *Call, r *record) ([]interface{}, error) {
+	out := make([]interface{}, len(c.Args))
+	for i, a := range c.Args {
+		v, err := e.eval(a.Value, r)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+func (e *evaluator) execEditor(c *Call, r *record) error {
+	switch c.Name {
+	case "set":
+		if len(c.Args) != 2 || c.Args[0].Value.Path == nil {
+			return fmt.Errorf("set needs a path target")
+		}
+		v, err := e.eval(c.Args[1].Value, r)
+		if err != nil || v == nil {
+			return err
+		}
+		return e.setPath(c.Args[0].Value.Path.Fields, copyValue(v), r)
+	case "replace_pattern", "replace_match":
+		if len(c.Args) == 4 {
+			return &UnsupportedError{Name: c.Name + " with a hash function"}
+		}
+		target := c.Args[0].Value.Path
+		if target == nil {
+			return fmt.Errorf("%s needs a path target", c.Name)
+		}
+		args, err := e.args(c, r)
+		if err != nil {
+			return err
+		}
+		s, ok := args[0].(string)
+		if !ok {
+			return nil
+		}
+		pattern, replacement := toString(args[1]), toString(args[2])
+		var out string
+		if c.Name == "replace_pattern" {
+			re, err := e.regexp(pattern)
+			if err != nil {
+				return err
+			}
+			out = re.ReplaceAllString(s, replacement)
+		} else {
+			out = s
+			if ok, _ := path.Match(pattern, s); ok {
+				out = replacement
+			}
+		}
+		return e.setPath(target.Fields, out, r)
+	}
+
+	// The remaining editors modify a map in place.
+	if !mapEditors[c.Name] {
+		return &UnsupportedError{Name: c.Name}
+	}
+	args, err := e.args(c, r)
+	if err != nil {
+		return err
+	}
+	m, isMap := args[0].(map[string]interface{})
+	if !isMap {
+		if args[0] == nil {
+			return nil
+		}
+		return fmt.Errorf("%s target is a %T, not a map", c.Name, args[0])
+	}
+
+	switch c.Name {
+	case "delete_key":
+		delete(m, toString(args[1]))
+	case "delete_matching_keys", "keep_matching_keys":
+		re, err := e.regexp(toString(args[1]))
+		if err != nil {
+			return err
+		}
+		for k := range m {
+			if re.MatchString(k) == (c.Name == "delete_matching_keys") {
+				delete(m, k)
+			}
+		}
+	case "keep_keys":
+		keep := map[string]bool{}
+		list, _ := args[1].([]interface{})
+		for _, k := range list {
+			keep[toString(k)] = true
+		}
+		for k := range m {
+			if !keep[k] {
+				delete(m, k)
+			}
+		}
+	case "limit":
+		n, ok := args[1].(int64)
+		if !ok {
+			return fmt.Errorf("limit needs an integer")
+		}
+		keys := sortedMapKeys(m)
+		priority := map[string]bool{}
+		list, _ := args[2].([]interface{})
+		for _, k := range list {
+			priority[toString(k)] = true
+		}
+		sort.SliceStable(keys, func(i, j int) bool { return priority[keys[i]] && !priority[keys[j]] })
+		for i, k := range keys {
+			if int64(i) >= n {
+				delete(m, k)
+			}
+		}
+	case "merge_maps":
+		src, ok := args[1].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		strategy := toString(args[2])
+		for k, v := range src {
+			_, exists := m[k]
+			if (strategy == "insert" && !exists) || (strategy == "update" && exists) || strategy == "upsert" {
+				m[k] = copyValue(v)
+			}
+		}
+	case "replace_all_patterns":
+		if len(args) == 5 {
+			return &UnsupportedError{Name: c.Name + " with a hash function"}
+		}
+		re, err := e.regexp(toString(args[2]))
+		if err != nil {
+			return err
+		}
+		replacement := toString(args[3])
+		for k, v := range m {
+			switch toString(args[1]) {
+			case "key":
+				if nk := re.ReplaceAllString(k, replacement); nk != k {
+					delete(m, k)
+					m[nk] = v
+				}
+			case "value":
+				if s, ok := v.(string); ok {
+					m[k] = re.ReplaceAllString(s, replacement)
+				}
+			}
+		}
+	case "replace_all_matches":
+		if len(args) == 4 {
+			return &UnsupportedError{Name: c.Name + " with a hash function"}
+		}
+		for k, v := range m {
+			if s, ok := v.(string); ok {
+				if match, _ := path.Match(toString(args[1]), s); match {
+					m[k] = toString(args[2])
+				}
+			}
+		}
+	case "truncate_all":
+		n, ok := args[1].(int64)
+		if !ok || n < 0 {
+			return fmt.Errorf("truncate_all needs a non-negative integer")
+		}
+		for k, v := range m {
+			if s, ok := v.(string); ok && int64(len(s)) > n {
+				m[k] = s[:n]
+			}
+		}
+	}
+	return nil
+}
+
+// mapEditors are the editors that modify a map in place.
+var mapEditors = map[string]bool{
+	"delete_key":           true,
+	"delete_matching_keys": true,
+	"keep_keys":            true,
+	"keep_matching_keys":   true,
+	"limit":                true,
+	"merge_maps":           true,
+	"replace_all_patterns": true,
+	"replace_all_matches":  true,
+	"truncate_all":         true,
+}
+
+func (e *evaluator) execConverter(c *Call, r *record) (interface{}, error) {
+	if c.Name == "IsRootSpan" {
+		span := r
+		if r.ctx == ContextSpanEvent {
+			span = r.up[ContextSpan]
+		}
+		if span == nil || span.ctx != ContextSpan {
+			return nil, fmt.Errorf("IsRootSpan needs span data")
+		}
+		return toString(span.fields["parent_span_id"]) == "", nil
+	}
+
+	args, err := e.args(c, r)
+	if err != nil {
+		return nil, err
+	}
+	switch c.Name {
+	case "IsString", "IsMap", "IsList", "IsInt", "IsDouble", "IsBool":
+		var ok bool
+		switch args[0].(type) {
+		case string:
+			ok = c.Name == "IsString"
+		case map[string]interface{}:
+			ok = c.Name == "IsMap"
+		case []interface{}:
+			ok = c.Name == "IsList"
+		case int64:
+			ok = c.Name == "IsInt"
+		case float64:
+			ok = c.Name == "IsDouble"
+		case bool:
+			ok = c.Name == "IsBool"
+		}
+		return ok, nil
+	case "IsMatch":
+		if args[0] == nil {
+			return false, nil
+		}
+		re, err := e.regexp(toString(args[1]))
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString(toString(args[0])), nil
+	case "Concat":
+		list, _ := args[0].([]interface{})
+		parts := make([]string, len(list))
+		for i, v := range list {
+			parts[i] = toString(v)
+		}
+		return strings.Join(parts, toString(args[1])), nil
+	case "ToLowerCase":
+		return strings.ToLower(toString(args[0])), nil
+	case "ToUpperCase":
+		return strings.ToUpper(toString(args[0])), nil
+	case "ConvertCase":
+		s := toString(args[0])
+		switch toString(args[1]) {
+		case "lower":
+			return strings.ToLower(s), nil
+		case "upper":
+			return strings.ToUpper(s), nil
+		case "snake":
+			return snakeCase(s), nil
+		}
+		return nil, &UnsupportedError{Name: "ConvertCase " + toString(args[1])}
+	case "Len":
+		switch v := args[0].(type) {
+		case string:
+			return int64(len(v)), nil
+		case map[string]interface{}:
+			return int64(len(v)), nil
+		case []interface{}:
+			return int64(len(v)), nil
+		}
+		return nil, fmt.Errorf("Len of a %T", args[0])
+	case "Int":
+		switch v := args[0].(type) {
+		case int64:
+			return v, nil
+		case float64:
+			return int64(v), nil
+		case bool:
+			if v {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		case string:
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, nil
+			}
+			return n, nil
+		}
+		return nil, nil
+	case "Double":
+		if f, ok := toFloat(args[0]); ok {
+			return f, nil
+		}
+		if s, ok := args[0].(string); ok {
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				return f, nil
+			}
+		}
+		return nil, nil
+	case "String":
+		if args[0] == nil {
+			return nil, nil
+		}
+		return toString(args[0]), nil
+	case "Split":
+		if args[0] == nil {
+			return nil, nil
+		}
+		parts := strings.Split(toString(args[0]), toString(args[1]))
+		out := make([]interface{}, len(parts))
+		for i, p := range parts {
+			out[i] = p
+		}
+		return out, nil
+	case "Substring":
+		s := toString(args[0])
+		start, ok1 := args[1].(int64)
+		length, ok2 := args[2].(int64)
+		if !ok1 || !ok2 || start < 0 || length < 0 || start+length > int64(len(s)) {
+			return nil, fmt.Errorf("invalid Substring range")
+		}
+		return s[start : start+length], nil
+	case "ParseJSON":
+		var out interface{}
+		if err := json.Unmarshal([]byte(toString(args[0])), &out); err != nil {
+			return nil, fmt.Errorf("ParseJSON: %v", err)
+		}
+		return normalizeJSON(out), nil
+	case "ExtractPatterns":
+		re, err := e.regexp(toString(args[1]))
+		if err != nil {
+			return nil, err
+		}
+		out := map[string]interface{}{}
+		match := re.FindStringSubmatch(toString(args[0]))
+		for i, name := range re.SubexpNames() {
+			if name != "" && match != nil {
+				out[name] = match[i]
+			}
+		}
+		return out, nil
+	case "SHA256":
+		sum := sha256.Sum256([]byte(toString(args[0])))
+		return hex.EncodeToString(sum[:]), nil
+	case "SHA1":
+		sum := sha1.Sum([]byte(toString(args[0])))
+		return hex.EncodeToString(sum[:]), nil
+	case "MD5":
+		sum := md5.Sum([]byte(toString(args[0])))
+		return hex.EncodeToString(sum[:]), nil
+	case "Keys", "Values":
+		m, _ := args[0].(map[string]interface{})
+		out := make([]interface{}, 0, len(m))
+		for _, k := range sortedMapKeys(m) {
+			if c.Name == "Keys" {
+				out = append(out, k)
+			} else {
+				out = append(out, m[k])
+			}
+		}
+		return out, nil
+	case "ContainsValue":
+		list, _ := args[0].([]interface{})
+		for _, v := range list {
+			if compare("==", v, args[1]) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return nil, &UnsupportedError{Name: c.Name}
+}
+
+func literalValue(l *Literal) (interface{}, error) {
+	switch l.Kind {
+	case LitString:
+		return unescape(l.Raw), nil
+	case LitInt:
+		return strconv.ParseInt(l.Raw, 10, 64)
+	case LitFloat:
+		return strconv.ParseFloat(l.Raw, 64)
+	case LitBool:
+		return l.Raw == "true", nil
+	case LitBytes:
+		return l.Raw, nil
+	}
+	return nil, nil
+}
+
+// unescape resolves the escape sequences of an OTTL string literal.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case '\\', '"':
+			b.WriteByte(s[i])
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+func compare(op string, a, b interface{}) bool {
+	if fa, ok := toFloat(a); ok {
+		if fb, ok := toFloat(b); ok {
+			switch op {
+			case "==":
+				return fa == fb
+			case "!=":
+				return fa != fb
+			case "<":
+				return fa < fb
+			case "<=":
+				return fa <= fb
+			case ">":
+				return fa > fb
+			case ">=":
+				return fa >= fb
+			}
+		}
+	}
+	if sa, ok := a.(string); ok {
+		if sb, ok := b.(string); ok {
+			switch op {
+			case "<":
+				return sa < sb
+			case "<=":
+				return sa <= sb
+			case ">":
+				return sa > sb
+			case ">=":
+				return sa >= sb
+			}
+		}
+	}
+	switch op {
+	case "==":
+		return reflect.DeepEqual(a, b)
+	case "!=":
+		return !reflect.DeepEqual(a, b)
+	}
+	return false
+}
+
+func arithmetic(op string, a, b interface{}) (interface{}, error) {
+	ia, aInt := a.(int64)
+	ib, bInt := b.(int64)
+	if aInt && bInt {
+		switch op {
+		case "+":
+			return ia + ib, nil
+		case "-":
+			return ia - ib, nil
+		case "*":
+			return ia * ib, nil
+		case "/":
+			if ib == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			return ia / ib, nil
+		}
+	}
+	fa, ok1 := toFloat(a)
+	fb, ok2 := toFloat(b)
+	if !ok1 || !ok2 {
+		return nil, fmt.Errorf("cannot apply %s to %T and %T", op, a, b)
+	}
+	switch op {
+	case "+":
+		return fa + fb, nil
+	case "-":
+		return fa - fb, nil
+	case "*":
+		return fa * fb, nil
+	}
+	if fb == 0 {
+		return nil, fmt.Errorf("division by zero")
+	}
+	return fa / fb, nil
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func toString(v interface{}) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	case map[string]interface{}, []interface{}:
+		out, _ := json.Marshal(s)
+		return string(out)
+	}
+	return fmt.Sprint(v)
+}
+
+// normalizeJSON converts decoded JSON numbers to int64 where they are whole.
+func normalizeJSON(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			t[k] = normalizeJSON(val)
+		}
+	case []interface{}:
+		for i, val := range t {
+			t[i] = normalizeJSON(val)
+		}
+	case float64:
+		if t == float64(int64(t)) {
+			return int64(t)
+		}
+	}
+	return v
+}
+
+// copyValue deep-copies maps and slices so records never share state.
+func copyValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			m[k] = copyValue(val)
+		}
+		return m
+	case []interface{}:
+		list := make([]interface{}, len(t))
+		for i, val := range t {
+			list[i] = copyValue(val)
+		}
+		return list
+	}
+	return v
+}
+
+func sortedMapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,138 @@
+// Package ottl parses and validates OpenTelemetry Transformation Language
+// statements and conditions as used by the transform and filter processors.
+package ottl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tokenKind classifies a lexical token.
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokBytes
+	tokPunct // ( ) [ ] { } , . : =
+	tokOp    // == != < <= > >= + - * /
+)
+
+// token is a lexical token with its byte offset in the input.
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of input"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// SyntaxError reports where a statement or condition fails to parse.
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at offset %d: %s", e.Pos, e.Msg)
+}
+
+// lex splits an OTTL input into tokens.
+func lex(input string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(input) {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"':
+			start := i
+			i++
+			var b strings.Builder
+			closed := false
+			for i < len(input) {
+				if input[i] == '\\' && i+1 < len(input) {
+					b.WriteByte(input[i])
+					b.WriteByte(input[i+1])
+					i += 2
+					continue
+				}
+				if input[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+				b.WriteByte(input[i])
+				i++
+			}
+			if !closed {
+				return nil, &SyntaxError{Pos: start, Msg: "unterminated string"}
+			}
+			tokens = append(tokens, token{tokString, b.String(), start})
+		case c == '0' && i+1 < len(input) && (input[i+1] == 'x' || input[i+1] == 'X'):
+			start := i
+			i += 2
+			for i < len(input) && isHex(input[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokBytes, input[start:i], start})
+		case isDigit(c):
+			start := i
+			for i < len(input) && (isDigit(input[i]) || input[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokNumber, input[start:i], start})
+		case isIdentStart(c):
+			start := i
+			for i < len(input) && isIdentPart(input[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokIdent, input[start:i], start})
+		case strings.ContainsRune("()[]{},.:", rune(c)):
+			tokens = append(tokens, token{tokPunct, string(c), i})
+			i++
+		case c == '=' || c == '!' || c == '<' || c == '>':
+			start := i
+			if i+1 < len(input) && input[i+1] == '=' {
+				tokens = append(tokens, token{tokOp, input[i : i+2], start})
+				i += 2
+				continue
+			}
+			switch c {
+			case '=':
+				tokens = append(tokens, token{tokPunct, "=", start})
+			case '!':
+				return nil, &SyntaxError{Pos: start, Msg: `unexpected "!", use "not" or "!="`}
+			default:
+				tokens = append(tokens, token{tokOp, string(c), start})
+			}
+			i++
+		case c == '+' || c == '-' || c == '*' || c == '/':
+			tokens = append(tokens, token{tokOp, string(c), i})
+			i++
+		default:
+			return nil, &SyntaxError{Pos: i, Msg: fmt.Sprintf("unexpected character %q", c)}
+		}
+	}
+	return append(tokens, token{tokEOF, "", len(input)}), nil
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isHex(c byte) bool {
+	return isDigit(c) || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isIdentPart(c byte) bool { return isIdentStart(c) || isDigit(c) }
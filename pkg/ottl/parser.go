@@ -0,0 +1,432 @@
+package ottl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parser is a recursive-descent parser over the token stream.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// ParseStatement parses an editor invocation with an optional where clause.
+func ParseStatement(input string) (*Statement, error) {
+	p, err := newParser(input)
+	if err != nil {
+		return nil, err
+	}
+	call, err := p.parseCall()
+	if err != nil {
+		return nil, err
+	}
+	stmt := &Statement{Editor: call}
+	if p.peekIdent("where") {
+		p.next()
+		if stmt.Where, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expectEOF(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// ParseCondition parses a boolean expression, as used by filter processor
+// conditions and transform processor statement-group conditions.
+func ParseCondition(input string) (*BoolExpr, error) {
+	p, err := newParser(input)
+	if err != nil {
+		return nil, err
+	}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectEOF(); err != nil {
+		return nil, err
+	}
+	return expr, nil
+}
+
+func newParser(input string) (*parser, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	return &parser{tokens: tokens}, nil
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) peekAt(n int) token {
+	if p.pos+n < len(p.tokens) {
+		return p.tokens[p.pos+n]
+	}
+	return p.tokens[len(p.tokens)-1]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) peekPunct(s string) bool {
+	t := p.peek()
+	return t.kind == tokPunct && t.text == s
+}
+
+func (p *parser) peekIdent(s string) bool {
+	t := p.peek()
+	return t.kind == tokIdent && t.text == s
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) expect(s string) error {
+	t := p.next()
+	if (t.kind != tokPunct && t.kind != tokOp) || t.text != s {
+		return p.errorf(t, "expected %q, found %s", s, t)
+	}
+	return nil
+}
+
+func (p *parser) expectEOF() error {
+	if t := p.peek(); t.kind != tokEOF {
+		return p.errorf(t, "unexpected %s", t)
+	}
+	return nil
+}
+
+// parseCall parses name(args) followed by optional keys.
+func (p *parser) parseCall() (*Call, error) {
+	t := p.next()
+	if t.kind != tokIdent {
+		return nil, p.errorf(t, "expected function name, found %s", t)
+	}
+	call := &Call{Name: t.text, Pos: t.pos}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	for !p.peekPunct(")") {
+		var arg Arg
+		if p.peek().kind == tokIdent && p.peekAt(1).kind == tokPunct && p.peekAt(1).text == "=" {
+			arg.Name = p.next().text
+			p.next()
+		}
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		arg.Value = v
+		call.Args = append(call.Args, arg)
+		if !p.peekPunct(",") {
+			break
+		}
+		p.next()
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	keys, err := p.parseKeys()
+	if err != nil {
+		return nil, err
+	}
+	call.Keys = keys
+	return call, nil
+}
+
+// parseKeys parses zero or more [key] indexes.
+func (p *parser) parseKeys() ([]Value, error) {
+	var keys []Value
+	for p.peekPunct("[") {
+		p.next()
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, v)
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// parseValue parses an additive math expression or a single value.
+func (p *parser) parseValue() (Value, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return Value{}, err
+	}
+	for t := p.peek(); t.kind == tokOp && (t.text == "+" || t.text == "-"); t = p.peek() {
+		p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return Value{}, err
+		}
+		left = Value{Math: &MathExpr{Op: t.text, Left: left, Right: right}, Pos: left.Pos}
+	}
+	return left, nil
+}
+
+func (p *parser) parseTerm() (Value, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return Value{}, err
+	}
+	for t := p.peek(); t.kind == tokOp && (t.text == "*" || t.text == "/"); t = p.peek() {
+		p.next()
+		right, err := p.parseFactor()
+		if err != nil {
+			return Value{}, err
+		}
+		left = Value{Math: &MathExpr{Op: t.text, Left: left, Right: right}, Pos: left.Pos}
+	}
+	return left, nil
+}
+
+func (p *parser) parseFactor() (Value, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokPunct && t.text == "(":
+		p.next()
+		v, err := p.parseValue()
+		if err != nil {
+			return Value{}, err
+		}
+		return v, p.expect(")")
+	case t.kind == tokOp && t.text == "-" && p.peekAt(1).kind == tokNumber:
+		p.next()
+		n := p.next()
+		return numberValue("-"+n.text, t.pos), nil
+	}
+	return p.parsePrimary()
+}
+
+func numberValue(raw string, pos int) Value {
+	kind := LitInt
+	if strings.Contains(raw, ".") {
+		kind = LitFloat
+	}
+	return Value{Literal: &Literal{Kind: kind, Raw: raw}, Pos: pos}
+}
+
+// parsePrimary parses a literal, path, converter call, enum, map or list.
+func (p *parser) parsePrimary() (Value, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokString:
+		p.next()
+		return Value{Literal: &Literal{Kind: LitString, Raw: t.text}, Pos: t.pos}, nil
+	case tokNumber:
+		p.next()
+		if strings.Count(t.text, ".") > 1 {
+			return Value{}, p.errorf(t, "invalid number %s", t)
+		}
+		return numberValue(t.text, t.pos), nil
+	case tokBytes:
+		p.next()
+		return Value{Literal: &Literal{Kind: LitBytes, Raw: t.text}, Pos: t.pos}, nil
+	case tokPunct:
+		switch t.text {
+		case "{":
+			return p.parseMap()
+		case "[":
+			return p.parseList()
+		}
+	case tokIdent:
+		switch t.text {
+		case "true", "false":
+			p.next()
+			return Value{Literal: &Literal{Kind: LitBool, Raw: t.text}, Pos: t.pos}, nil
+		case "nil":
+			p.next()
+			return Value{Literal: &Literal{Kind: LitNil, Raw: t.text}, Pos: t.pos}, nil
+		}
+		if next := p.peekAt(1); next.kind == tokPunct && next.text == "(" {
+			call, err := p.parseCall()
+			if err != nil {
+				return Value{}, err
+			}
+			return Value{Call: call, Pos: t.pos}, nil
+		}
+		if isUpper(t.text[0]) {
+			p.next()
+			return Value{Enum: t.text, Pos: t.pos}, nil
+		}
+		return p.parsePath()
+	}
+	return Value{}, p.errorf(t, "expected a value, found %s", t)
+}
+
+func (p *parser) parsePath() (Value, error) {
+	start := p.peek()
+	path := &Path{Pos: start.pos}
+	for {
+		t := p.next()
+		if t.kind != tokIdent {
+			return Value{}, p.errorf(t, "expected path field, found %s", t)
+		}
+		keys, err := p.parseKeys()
+		if err != nil {
+			return Value{}, err
+		}
+		path.Fields = append(path.Fields, Field{Name: t.text, Keys: keys})
+		if !p.peekPunct(".") {
+			break
+		}
+		p.next()
+	}
+	return Value{Path: path, Pos: start.pos}, nil
+}
+
+func (p *parser) parseMap() (Value, error) {
+	start := p.next()
+	v := Value{IsMap: true, Pos: start.pos}
+	for !p.peekPunct("}") {
+		k := p.next()
+		if k.kind != tokString {
+			return Value{}, p.errorf(k, "expected string map key, found %s", k)
+		}
+		if err := p.expect(":"); err != nil {
+			return Value{}, err
+		}
+		val, err := p.parseValue()
+		if err != nil {
+			return Value{}, err
+		}
+		v.Map = append(v.Map, MapEntry{Key: k.text, Value: val})
+		if !p.peekPunct(",") {
+			break
+		}
+		p.next()
+	}
+	return v, p.expect("}")
+}
+
+func (p *parser) parseList() (Value, error) {
+	start := p.next()
+	v := Value{IsList: true, Pos: start.pos}
+	for !p.peekPunct("]") {
+		val, err := p.parseValue()
+		if err != nil {
+			return Value{}, err
+		}
+		v.List = append(v.List, val)
+		if !p.peekPunct(",") {
+			break
+		}
+		p.next()
+	}
+	return v, p.expect("]")
+}
+
+// parseOr parses "a or b or c".
+func (p *parser) parseOr() (*BoolExpr, error) {
+	pos := p.peek().pos
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	if !p.peekIdent("or") {
+		return left, nil
+	}
+	expr := &BoolExpr{Or: []*BoolExpr{left}, Pos: pos}
+	for p.peekIdent("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		expr.Or = append(expr.Or, right)
+	}
+	return expr, nil
+}
+
+func (p *parser) parseAnd() (*BoolExpr, error) {
+	pos := p.peek().pos
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	if !p.peekIdent("and") {
+		return left, nil
+	}
+	expr := &BoolExpr{And: []*BoolExpr{left}, Pos: pos}
+	for p.peekIdent("and") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		expr.And = append(expr.And, right)
+	}
+	return expr, nil
+}
+
+func (p *parser) parseNot() (*BoolExpr, error) {
+	t := p.peek()
+	if t.kind == tokIdent && t.text == "not" {
+		p.next()
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &BoolExpr{Not: inner, Pos: t.pos}, nil
+	}
+
+	// A parenthesis opens either a nested boolean expression or a math
+	// expression on the left of a comparison; try the former first.
+	if t.kind == tokPunct && t.text == "(" {
+		saved := p.pos
+		p.next()
+		if inner, err := p.parseOr(); err == nil && p.peekPunct(")") {
+			p.next()
+			if next := p.peek(); next.kind != tokOp {
+				return inner, nil
+			}
+		}
+		p.pos = saved
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (*BoolExpr, error) {
+	t := p.peek()
+	left, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	op := p.peek()
+	if op.kind != tokOp || !isComparisonOp(op.text) {
+		// A bare value must be a boolean literal or a converter call
+		if (left.Literal != nil && left.Literal.Kind == LitBool) || left.Call != nil {
+			return &BoolExpr{Value: &left, Pos: t.pos}, nil
+		}
+		return nil, p.errorf(op, "expected comparison operator, found %s", op)
+	}
+	p.next()
+	right, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	return &BoolExpr{Comparison: &Comparison{Op: op.text, Left: left, Right: right}, Pos: t.pos}, nil
+}
+
+func isComparisonOp(s string) bool {
+	switch s {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return true
+	}
+	return false
+}
+
+func isUpper(c byte) bool { return c >= 'A' && c <= 'Z' }
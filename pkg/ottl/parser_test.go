@@ -0,0 +1,46 @@
+package ottl
+
+import "testing"
+
+func TestParseStatement(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "set with key", input: `set(attributes["env"], "prod")`},
+		{name: "where clause", input: `set(severity_text, attributes["level"]) where attributes["level"] != nil`},
+		{name: "converter with keys", input: `set(attributes["lvl"], ParseJSON(body)["level"])`},
+		{name: "named arguments", input: `replace_pattern(attributes["url"], "token=\\w+", "token=***", function=SHA256)`},
+		{name: "map and list literals", input: `merge_maps(attributes, {"a": [1, -2.5, true]}, "upsert")`},
+		{name: "math and enums", input: `set(attributes["ms"], (end_time_unix_nano - start_time_unix_nano) / 1000000) where status.code == STATUS_CODE_ERROR`},
+		{name: "nested boolean", input: `set(name, "x") where not (IsMatch(name, "^GET") or kind == 2) and resource.attributes["a"] == 0x0a`},
+		{name: "missing closing paren", input: `set(attributes["a"], "b"`, wantErr: true},
+		{name: "bang operator", input: `set(name, "x") where !IsRootSpan()`, wantErr: true},
+		{name: "unterminated string", input: `set(name, "x)`, wantErr: true},
+		{name: "trailing tokens", input: `set(name, "x") "y"`, wantErr: true},
+		{name: "bare path condition", input: `set(name, "x") where attributes["a"]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseStatement(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseStatement(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseCondition(t *testing.T) {
+	expr, err := ParseCondition(`attributes["a"] == "b" or (IsRootSpan() and duration > 5)`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(expr.Or) != 2 || len(expr.Or[1].And) != 2 {
+		t.Fatalf("unexpected tree: %+v", expr)
+	}
+	if expr.Or[0].Comparison == nil || expr.Or[0].Comparison.Left.Path.String() != "attributes" {
+		t.Errorf("expected comparison on attributes, got %+v", expr.Or[0])
+	}
+}
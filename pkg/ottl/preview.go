@@ -0,0 +1,335 @@
+package ottl
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/signals"
+)
+
+// maxSamples bounds the before/after samples kept per statement.
+const maxSamples = 3
+
+// Change is one field a statement modified on a record.
+type Change struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// StatementEffect is the measured effect of one statement.
+type StatementEffect struct {
+	Statement   string     `json:"statement"`
+	Problems    []Problem  `json:"problems,omitempty"`
+	Unsupported string     `json:"unsupported,omitempty"`
+	Matched     int        `json:"matched"`
+	Modified    int        `json:"modified"`
+	Errors      int        `json:"errors,omitempty"`
+	FirstError  string     `json:"first_error,omitempty"`
+	Samples     [][]Change `json:"samples,omitempty"`
+}
+
+// TransformPreview reports what a list of transform statements does to the
+// captured records of one context.
+type TransformPreview struct {
+	Context    Context           `json:"context"`
+	Records    int               `json:"records"`
+	Modified   int               `json:"modified"`
+	Statements []StatementEffect `json:"statements"`
+}
+
+// ConditionEffect is the measured effect of one filter condition.
+type ConditionEffect struct {
+	Condition   string    `json:"condition"`
+	Problems    []Problem `json:"problems,omitempty"`
+	Unsupported string    `json:"unsupported,omitempty"`
+	Matched     int       `json:"matched"`
+	Errors      int       `json:"errors,omitempty"`
+	FirstError  string    `json:"first_error,omitempty"`
+}
+
+// FilterPreview reports how many captured records a set of filter
+// conditions would drop. A record is dropped when any condition matches.
+type FilterPreview struct {
+	Context    Context                  `json:"context"`
+	Records    int                      `json:"records"`
+	Dropped    int                      `json:"dropped"`
+	DropRatio  float64                  `json:"drop_ratio"`
+	Conditions []ConditionEffect        `json:"conditions"`
+	Samples    []map[string]interface{} `json:"samples,omitempty"`
+}
+
+// PreviewTransform runs statements in order against copies of the captured
+// records of ctx, the way the transform processor applies a statement group.
+// Invalid statements are reported and skipped; captured data is not modified.
+func PreviewTransform(ctx Context, statements []string, captured *signals.CapturedSignals) *TransformPreview {
+	records := buildRecords(ctx, captured)
+	preview := &TransformPreview{Context: ctx, Records: len(records)}
+
+	parsed := make([]*Statement, len(statements))
+	for i, s := range statements {
+		effect := StatementEffect{Statement: s}
+		if problems := CheckStatement(ctx, s); len(problems) > 0 {
+			effect.Problems = problems
+		} else {
+			parsed[i], _ = ParseStatement(s)
+		}
+		preview.Statements = append(preview.Statements, effect)
+	}
+
+	e := newEvaluator()
+	for _, r := range records {
+		recordModified := false
+		for i, stmt := range parsed {
+			effect := &preview.Statements[i]
+			if stmt == nil || effect.Unsupported != "" {
+				continue
+			}
+			before := snapshot(r)
+			matched, err := e.execStatement(stmt, r)
+			if err != nil {
+				var unsupported *UnsupportedError
+				if errors.As(err, &unsupported) {
+					effect.Unsupported = unsupported.Error()
+					continue
+				}
+				effect.Errors++
+				if effect.FirstError == "" {
+					effect.FirstError = err.Error()
+				}
+				continue
+			}
+			if !matched {
+				continue
+			}
+			effect.Matched++
+			if changes := diff(before, snapshot(r)); len(changes) > 0 {
+				effect.Modified++
+				recordModified = true
+				if len(effect.Samples) < maxSamples {
+					effect.Samples = append(effect.Samples, changes)
+				}
+			}
+		}
+		if recordModified {
+			preview.Modified++
+		}
+	}
+	return preview
+}
+
+// PreviewFilter evaluates filter conditions against the captured records of
+// ctx and counts the records the filter processor would drop.
+func PreviewFilter(ctx Context, conditions []string, captured *signals.CapturedSignals) *FilterPreview {
+	records := buildRecords(ctx, captured)
+	preview := &FilterPreview{Context: ctx, Records: len(records)}
+
+	parsed := make([]*BoolExpr, len(conditions))
+	for i, c := range conditions {
+		effect := ConditionEffect{Condition: c}
+		if problems := CheckCondition(ctx, c); len(problems) > 0 {
+			effect.Problems = problems
+		} else {
+			parsed[i], _ = ParseCondition(c)
+		}
+		preview.Conditions = append(preview.Conditions, effect)
+	}
+
+	e := newEvaluator()
+	for _, r := range records {
+		dropped := false
+		for i, cond := range parsed {
+			effect := &preview.Conditions[i]
+			if cond == nil || effect.Unsupported != "" {
+				continue
+			}
+			matched, err := e.evalBool(cond, r)
+			if err != nil {
+				var unsupported *UnsupportedError
+				if errors.As(err, &unsupported) {
+					effect.Unsupported = unsupported.Error()
+					continue
+				}
+				effect.Errors++
+				if effect.FirstError == "" {
+					effect.FirstError = err.Error()
+				}
+				continue
+			}
+			if matched {
+				effect.Matched++
+				dropped = true
+			}
+		}
+		if dropped {
+			preview.Dropped++
+			if len(preview.Samples) < maxSamples {
+				preview.Samples = append(preview.Samples, snapshot(r))
+			}
+		}
+	}
+	if preview.Records > 0 {
+		preview.DropRatio = float64(preview.Dropped) / float64(preview.Records)
+	}
+	return preview
+}
+
+// buildRecords converts captured signals into evaluation records for ctx. Each
+// record is a fresh copy, so previews never modify the capture.
+func buildRecords(ctx Context, captured *signals.CapturedSignals) []*record {
+	if captured == nil {
+		return nil
+	}
+	var records []*record
+	switch ctx {
+	case ContextLog:
+		for _, l := range captured.Logs {
+			res := newRecord(ContextResource, map[string]interface{}{"attributes": attrMap(l.ResourceAttributes)})
+			records = append(records, newRecord(ContextLog, map[string]interface{}{
+				"body":          l.Body,
+				"severity_text": l.Severity,
+				"time":          l.Timestamp,
+				"attributes":    attrMap(l.Attributes),
+			}, res))
+		}
+	case ContextSpan:
+		for _, s := range captured.Traces {
+			records = append(records, spanRecord(s))
+		}
+	case ContextSpanEvent:
+		for _, s := range captured.Traces {
+			span := spanRecord(s)
+			for _, ev := range s.Events {
+				records = append(records, newRecord(ContextSpanEvent, map[string]interface{}{
+					"name":       ev.Name,
+					"time":       ev.Timestamp,
+					"attributes": attrMap(ev.Attributes),
+				}, span, span.up[ContextResource]))
+			}
+		}
+	case ContextMetric:
+		seen := map[string]bool{}
+		for _, m := range captured.Metrics {
+			if seen[m.Name] {
+				continue
+			}
+			seen[m.Name] = true
+			records = append(records, metricRecord(m))
+		}
+	case ContextDataPoint:
+		for _, m := range captured.Metrics {
+			metric := metricRecord(m)
+			records = append(records, newRecord(ContextDataPoint, map[string]interface{}{
+				"attributes":   attrMap(m.Labels),
+				"value_double": m.Value,
+			}, metric, metric.up[ContextResource]))
+		}
+	case ContextResource:
+		seen := map[string]bool{}
+		for _, l := range captured.Logs {
+			key := fmt.Sprint(sortedAttrs(l.ResourceAttributes))
+			if len(l.ResourceAttributes) == 0 || seen[key] {
+				continue
+			}
+			seen[key] = true
+			records = append(records, newRecord(ContextResource, map[string]interface{}{"attributes": attrMap(l.ResourceAttributes)}))
+		}
+	}
+	return records
+}
+
+func newRecord(ctx Context, fields map[string]interface{}, up ...*record) *record {
+	fields["cache"] = map[string]interface{}{}
+	r := &record{ctx: ctx, fields: fields, up: map[Context]*record{}}
+	for _, u := range up {
+		if u != nil {
+			r.up[u.ctx] = u
+		}
+	}
+	if r.up[ContextResource] == nil && ctx != ContextResource {
+		r.up[ContextResource] = &record{ctx: ContextResource, fields: map[string]interface{}{"attributes": map[string]interface{}{}}}
+	}
+	return r
+}
+
+func spanRecord(s signals.SpanData) *record {
+	return newRecord(ContextSpan, map[string]interface{}{
+		"trace_id":       s.TraceID,
+		"span_id":        s.SpanID,
+		"parent_span_id": s.ParentSpanID,
+		"name":           s.Name,
+		"start_time":     s.StartTime,
+		"end_time":       s.StartTime.Add(s.Duration),
+		"attributes":     attrMap(s.Attributes),
+	})
+}
+
+func metricRecord(m signals.MetricDataPoint) *record {
+	return newRecord(ContextMetric, map[string]interface{}{
+		"name": m.Name,
+		"type": m.Type,
+	})
+}
+
+func attrMap(attrs map[string]string) map[string]interface{} {
+	m := make(map[string]interface{}, len(attrs))
+	for k, v := range attrs {
+		m[k] = v
+	}
+	return m
+}
+
+func sortedAttrs(attrs map[string]string) []string {
+	out := make([]string, 0, len(attrs))
+	for k, v := range attrs {
+		out = append(out, k+"="+v)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// snapshot flattens a record and its higher contexts into path -> value,
+// expanding attribute maps so changes are reported per key.
+func snapshot(r *record) map[string]interface{} {
+	out := map[string]interface{}{}
+	flatten(out, "", r.fields)
+	for ctx, up := range r.up {
+		flatten(out, string(ctx)+".", up.fields)
+	}
+	delete(out, "cache")
+	return out
+}
+
+func flatten(out map[string]interface{}, prefix string, fields map[string]interface{}) {
+	for k, v := range fields {
+		if m, ok := v.(map[string]interface{}); ok && k != "cache" {
+			for mk, mv := range m {
+				out[fmt.Sprintf("%s%s[%q]", prefix, k, mk)] = copyValue(mv)
+			}
+			continue
+		}
+		if k == "cache" {
+			continue
+		}
+		out[prefix+k] = copyValue(v)
+	}
+}
+
+// diff lists the fields whose values differ between two snapshots.
+func diff(before, after map[string]interface{}) []Change {
+	var changes []Change
+	for k, a := range after {
+		if b, ok := before[k]; !ok || !reflect.DeepEqual(a, b) {
+			changes = append(changes, Change{Field: k, Before: before[k], After: a})
+		}
+	}
+	for k, b := range before {
+		if _, ok := after[k]; !ok {
+			changes = append(changes, Change{Field: k, Before: b})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
@@ -0,0 +1,90 @@
+package ottl
+
+import (
+	"testing"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/signals"
+)
+
+func testSignals() *signals.CapturedSignals {
+	return &signals.CapturedSignals{
+		Logs: []signals.LogRecord{
+			{Body: `{"level":"error","user":"a@example.com"}`, Attributes: map[string]string{"env": "prod"}, ResourceAttributes: map[string]string{"service.name": "checkout"}},
+			{Body: `{"level":"info"}`, Attributes: map[string]string{"env": "dev"}, ResourceAttributes: map[string]string{"service.name": "cart"}},
+			{Body: "plain text", Attributes: map[string]string{"env": "prod", "token": "secret"}},
+		},
+		Traces: []signals.SpanData{
+			{TraceID: "t1", SpanID: "s1", Name: "GET /health", Attributes: map[string]string{"http.route": "/health"}},
+			{TraceID: "t1", SpanID: "s2", ParentSpanID: "s1", Name: "SELECT", Attributes: map[string]string{"db.system": "postgresql"}},
+		},
+		Metrics: []signals.MetricDataPoint{
+			{Name: "http.requests", Labels: map[string]string{"user_id": "42", "route": "/a"}, Value: 1},
+			{Name: "http.requests", Labels: map[string]string{"user_id": "43", "route": "/b"}, Value: 2},
+		},
+	}
+}
+
+func TestPreviewTransform(t *testing.T) {
+	captured := testSignals()
+	preview := PreviewTransform(ContextLog, []string{
+		`set(attributes["level"], ParseJSON(body)["level"]) where IsMatch(body, "^\\{")`,
+		`set(severity_text, "ERROR") where attributes["level"] == "error"`,
+		`delete_key(attributes, "token")`,
+		`set(attributes["svc"], resource.attributes["service.name"])`,
+		`flatten(attributes)`,
+		`rename(attributes, "a")`,
+	}, captured)
+
+	if preview.Records != 3 {
+		t.Fatalf("expected 3 records, got %d", preview.Records)
+	}
+	want := []struct{ matched, modified int }{{2, 2}, {1, 1}, {3, 1}, {3, 2}, {0, 0}, {0, 0}}
+	for i, w := range want {
+		got := preview.Statements[i]
+		if got.Matched != w.matched || got.Modified != w.modified {
+			t.Errorf("statement %d: expected matched=%d modified=%d, got %+v", i, w.matched, w.modified, got)
+		}
+	}
+	if preview.Statements[4].Unsupported == "" {
+		t.Error("expected flatten to be reported as unsupported")
+	}
+	if len(preview.Statements[5].Problems) == 0 {
+		t.Error("expected unknown editor to be reported as a problem")
+	}
+	sample := preview.Statements[1].Samples[0][0]
+	if sample.Field != "severity_text" || sample.Before != "" || sample.After != "ERROR" {
+		t.Errorf("unexpected sample %+v", sample)
+	}
+	if captured.Logs[2].Attributes["token"] != "secret" {
+		t.Error("preview must not modify captured signals")
+	}
+	if preview.Modified != 3 {
+		t.Errorf("expected 3 modified records, got %d", preview.Modified)
+	}
+}
+
+func TestPreviewTransformDataPoints(t *testing.T) {
+	preview := PreviewTransform(ContextDataPoint, []string{
+		`delete_key(attributes, "user_id") where metric.name == "http.requests"`,
+		`set(attributes["scaled"], value_double * 1000)`,
+	}, testSignals())
+	if got := preview.Statements[0]; got.Matched != 2 || got.Modified != 2 {
+		t.Errorf("unexpected delete_key effect %+v", got)
+	}
+	if got := preview.Statements[1].Samples[0][0]; got.After != float64(1000) {
+		t.Errorf("unexpected math result %+v", got)
+	}
+}
+
+func TestPreviewFilter(t *testing.T) {
+	preview := PreviewFilter(ContextSpan, []string{
+		`attributes["http.route"] == "/health"`,
+		`IsRootSpan() and name == "nothing"`,
+	}, testSignals())
+	if preview.Records != 2 || preview.Dropped != 1 || preview.DropRatio != 0.5 {
+		t.Fatalf("unexpected filter preview %+v", preview)
+	}
+	if preview.Conditions[0].Matched != 1 || preview.Conditions[1].Matched != 0 {
+		t.Errorf("unexpected condition counts %+v", preview.Conditions)
+	}
+}
@@ -0,0 +1,286 @@
+package ottl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Problem kinds reported by the validator.
+const (
+	ProblemSyntax   = "syntax"
+	ProblemFunction = "unknown_function"
+	ProblemArity    = "arity"
+	ProblemContext  = "context"
+	ProblemPath     = "path"
+	ProblemEnum     = "enum"
+)
+
+// Problem is one validation failure in a statement or condition.
+type Problem struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// CheckStatement parses a statement and validates it against a context.
+func CheckStatement(ctx Context, input string) []Problem {
+	if _, ok := contexts[ctx]; !ok {
+		return []Problem{{Kind: ProblemContext, Message: fmt.Sprintf("unknown context %q", ctx)}}
+	}
+	stmt, err := ParseStatement(input)
+	if err != nil {
+		return []Problem{{Kind: ProblemSyntax, Message: err.Error()}}
+	}
+	v := &validator{ctx: ctx}
+	v.call(stmt.Editor, true)
+	if stmt.Where != nil {
+		v.boolExpr(stmt.Where)
+	}
+	return v.problems
+}
+
+// CheckCondition parses a condition and validates it against a context.
+func CheckCondition(ctx Context, input string) []Problem {
+	if _, ok := contexts[ctx]; !ok {
+		return []Problem{{Kind: ProblemContext, Message: fmt.Sprintf("unknown context %q", ctx)}}
+	}
+	expr, err := ParseCondition(input)
+	if err != nil {
+		return []Problem{{Kind: ProblemSyntax, Message: err.Error()}}
+	}
+	v := &validator{ctx: ctx}
+	v.boolExpr(expr)
+	return v.problems
+}
+
+// InferContext picks the lowest context of a signal named by the path
+// prefixes in a statement, as the transform processor does for statements
+// listed without an explicit context. It returns "" when nothing matches.
+func InferContext(signal, input string) Context {
+	stmt, err := ParseStatement(input)
+	if err != nil {
+		return ""
+	}
+	prefixes := map[string]bool{}
+	collectPrefixes(&Value{Call: stmt.Editor}, prefixes)
+	if stmt.Where != nil {
+		walkBool(stmt.Where, func(v *Value) { collectPrefixes(v, prefixes) })
+	}
+	for _, ctx := range signalContexts[signal] {
+		if prefixes[string(ctx)] || (ctx == ContextScope && prefixes["instrumentation_scope"]) {
+			return ctx
+		}
+	}
+	return ""
+}
+
+// CheckInferred validates a statement whose context is inferred from its
+// path prefixes.
+func CheckInferred(signal, input string) []Problem {
+	if _, err := ParseStatement(input); err != nil {
+		return []Problem{{Kind: ProblemSyntax, Message: err.Error()}}
+	}
+	ctx := InferContext(signal, input)
+	if ctx == "" {
+		return []Problem{{Kind: ProblemContext, Message: fmt.Sprintf("cannot infer the context of %q; prefix paths with one of %s", input, contextList(signal))}}
+	}
+	return CheckStatement(ctx, input)
+}
+
+func contextList(signal string) string {
+	names := make([]string, 0, len(signalContexts[signal]))
+	for _, ctx := range signalContexts[signal] {
+		names = append(names, string(ctx)+".")
+	}
+	return strings.Join(names, ", ")
+}
+
+func collectPrefixes(v *Value, prefixes map[string]bool) {
+	walkValue(v, func(v *Value) {
+		if v.Path != nil && len(v.Path.Fields) > 1 {
+			prefixes[v.Path.Fields[0].Name] = true
+		}
+	})
+}
+
+// walkValue calls fn for v and every value nested within it.
+func walkValue(v *Value, fn func(*Value)) {
+	fn(v)
+	switch {
+	case v.Path != nil:
+		for _, f := range v.Path.Fields {
+			for i := range f.Keys {
+				walkValue(&f.Keys[i], fn)
+			}
+		}
+	case v.Call != nil:
+		for i := range v.Call.Args {
+			walkValue(&v.Call.Args[i].Value, fn)
+		}
+		for i := range v.Call.Keys {
+			walkValue(&v.Call.Keys[i], fn)
+		}
+	case v.Math != nil:
+		walkValue(&v.Math.Left, fn)
+		walkValue(&v.Math.Right, fn)
+	case v.IsMap:
+		for i := range v.Map {
+			walkValue(&v.Map[i].Value, fn)
+		}
+	case v.IsList:
+		for i := range v.List {
+			walkValue(&v.List[i], fn)
+		}
+	}
+}
+
+// walkBool calls fn for every value in a boolean expression.
+func walkBool(e *BoolExpr, fn func(*Value)) {
+	switch {
+	case e.Or != nil:
+		for _, sub := range e.Or {
+			walkBool(sub, fn)
+		}
+	case e.And != nil:
+		for _, sub := range e.And {
+			walkBool(sub, fn)
+		}
+	case e.Not != nil:
+		walkBool(e.Not, fn)
+	case e.Comparison != nil:
+		walkValue(&e.Comparison.Left, fn)
+		walkValue(&e.Comparison.Right, fn)
+	case e.Value != nil:
+		walkValue(e.Value, fn)
+	}
+}
+
+type validator struct {
+	ctx      Context
+	problems []Problem
+}
+
+func (v *validator) addf(kind, format string, args ...interface{}) {
+	v.problems = append(v.problems, Problem{Kind: kind, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) boolExpr(e *BoolExpr) {
+	walkBool(e, v.value)
+}
+
+// value checks a single value; nested values are visited by walkValue.
+func (v *validator) value(val *Value) {
+	switch {
+	case val.Path != nil:
+		v.path(v.ctx, val.Path.Fields, val.Path.String())
+	case val.Call != nil:
+		v.call(val.Call, false)
+	case val.Enum != "":
+		if !isKnownEnum(val.Enum) && !IsConverter(val.Enum) {
+			v.addf(ProblemEnum, "unknown enum %s", val.Enum)
+		}
+	}
+}
+
+// call checks an editor (statement) or converter (value) invocation and
+// its arguments.
+func (v *validator) call(c *Call, editor bool) {
+	var fn function
+	var ok bool
+	if editor {
+		fn, ok = editors[c.Name]
+		switch {
+		case !ok && IsConverter(c.Name):
+			v.addf(ProblemFunction, "%s is a converter and cannot be used as a statement; wrap it in an editor such as set()", c.Name)
+		case !ok:
+			v.addf(ProblemFunction, "unknown editor %s", c.Name)
+		}
+	} else {
+		fn, ok = converters[c.Name]
+		switch {
+		case !ok && IsEditor(c.Name):
+			v.addf(ProblemFunction, "%s is an editor and cannot be used as a value", c.Name)
+		case !ok:
+			v.addf(ProblemFunction, "unknown converter %s", c.Name)
+		}
+	}
+	if ok {
+		if n := len(c.Args); n < fn.min || n > fn.max {
+			v.addf(ProblemArity, "%s expects %s, got %d", c.Name, arity(fn), n)
+		}
+		if fn.contexts != nil && !containsContext(fn.contexts, v.ctx) {
+			v.addf(ProblemContext, "%s is only available in the %s context, not %s", c.Name, joinContexts(fn.contexts), v.ctx)
+		}
+	}
+
+	// Editor arguments are not visited by walkValue, so walk them here.
+	if editor {
+		for i := range c.Args {
+			walkValue(&c.Args[i].Value, v.value)
+		}
+	}
+}
+
+// path checks that fields resolve in ctx, following higher-context
+// prefixes such as resource. or metric.
+func (v *validator) path(ctx Context, fields []Field, full string) {
+	info := contexts[ctx]
+	name := fields[0].Name
+	rest := fields[1:]
+
+	if sub, ok := info.fields[name]; ok {
+		if len(rest) == 0 {
+			return
+		}
+		for _, s := range sub {
+			if rest[0].Name == s && len(rest) == 1 {
+				return
+			}
+		}
+		v.addf(ProblemPath, "path %s is not valid: %s has no field %q in the %s context", full, name, rest[0].Name, ctx)
+		return
+	}
+
+	if len(rest) > 0 {
+		if name == string(ctx) {
+			v.path(ctx, rest, full)
+			return
+		}
+		if parent, ok := info.parents[name]; ok {
+			v.path(parent, rest, full)
+			return
+		}
+	}
+
+	if ValidContext(name) && Context(name) != ctx {
+		v.addf(ProblemContext, "path %s refers to the %s context, which is not accessible from the %s context", full, name, ctx)
+		return
+	}
+	v.addf(ProblemPath, "path %s is not available in the %s context", full, ctx)
+}
+
+func arity(fn function) string {
+	if fn.min == fn.max {
+		if fn.min == 1 {
+			return "1 argument"
+		}
+		return fmt.Sprintf("%d arguments", fn.min)
+	}
+	return fmt.Sprintf("%d to %d arguments", fn.min, fn.max)
+}
+
+func containsContext(list []Context, ctx Context) bool {
+	for _, c := range list {
+		if c == ctx {
+			return true
+		}
+	}
+	return false
+}
+
+func joinContexts(list []Context) string {
+	names := make([]string, len(list))
+	for i, c := range list {
+		names[i] = string(c)
+	}
+	return strings.Join(names, " or ")
+}
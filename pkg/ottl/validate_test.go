@@ -0,0 +1,68 @@
+package ottl
+
+import "testing"
+
+func TestCheckStatement(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        Context
+		input      string
+		expectKind []string
+	}{
+		{name: "valid log statement", ctx: ContextLog, input: `set(severity_text, attributes["level"]) where attributes["level"] != nil`},
+		{name: "higher context path", ctx: ContextDataPoint, input: `set(attributes["svc"], resource.attributes["service.name"]) where metric.name == "http.requests"`},
+		{name: "prefixed own context", ctx: ContextSpan, input: `set(span.attributes["n"], span.name)`},
+		{name: "syntax error", ctx: ContextSpan, input: `set(name,`, expectKind: []string{ProblemSyntax}},
+		{name: "unknown editor", ctx: ContextSpan, input: `rename(attributes["a"], "b")`, expectKind: []string{ProblemFunction}},
+		{name: "converter as statement", ctx: ContextLog, input: `ParseJSON(body)`, expectKind: []string{ProblemFunction}},
+		{name: "unknown converter", ctx: ContextLog, input: `set(attributes["a"], ToLower(body))`, expectKind: []string{ProblemFunction}},
+		{name: "editor arity", ctx: ContextSpan, input: `set(attributes["a"])`, expectKind: []string{ProblemArity}},
+		{name: "converter arity", ctx: ContextLog, input: `set(attributes["a"], Concat(["a", "b"]))`, expectKind: []string{ProblemArity}},
+		{name: "log path in span context", ctx: ContextSpan, input: `set(attributes["b"], body)`, expectKind: []string{ProblemPath}},
+		{name: "lower context path", ctx: ContextMetric, input: `set(description, "x") where datapoint.attributes["a"] == "b"`, expectKind: []string{ProblemContext}},
+		{name: "metric editor in datapoint context", ctx: ContextDataPoint, input: `convert_sum_to_gauge() where metric.name == "x"`, expectKind: []string{ProblemContext}},
+		{name: "unknown sub-field", ctx: ContextSpan, input: `set(status.text, "x")`, expectKind: []string{ProblemPath}},
+		{name: "unknown enum", ctx: ContextSpan, input: `set(status.code, STATUS_ERROR)`, expectKind: []string{ProblemEnum}},
+		{name: "unknown context", ctx: Context("trace"), input: `set(name, "x")`, expectKind: []string{ProblemContext}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := CheckStatement(tt.ctx, tt.input)
+			if len(problems) != len(tt.expectKind) {
+				t.Fatalf("expected %d problems, got %d: %+v", len(tt.expectKind), len(problems), problems)
+			}
+			for i, p := range problems {
+				if p.Kind != tt.expectKind[i] {
+					t.Errorf("problem %d: expected kind %s, got %s (%s)", i, tt.expectKind[i], p.Kind, p.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckCondition(t *testing.T) {
+	if problems := CheckCondition(ContextSpan, `attributes["http.route"] == "/health" and IsRootSpan()`); len(problems) != 0 {
+		t.Errorf("expected no problems, got %+v", problems)
+	}
+	if problems := CheckCondition(ContextLog, `delete_key(attributes, "a")`); len(problems) != 1 || problems[0].Kind != ProblemFunction {
+		t.Errorf("expected editor rejected in condition, got %+v", problems)
+	}
+}
+
+func TestInferContext(t *testing.T) {
+	tests := []struct {
+		signal, input string
+		want          Context
+	}{
+		{"traces", `set(span.attributes["svc"], resource.attributes["service.name"])`, ContextSpan},
+		{"metrics", `set(metric.description, "x")`, ContextMetric},
+		{"metrics", `set(datapoint.attributes["a"], "b") where metric.name == "m"`, ContextDataPoint},
+		{"logs", `set(attributes["a"], "b")`, ""},
+	}
+	for _, tt := range tests {
+		if got := InferContext(tt.signal, tt.input); got != tt.want {
+			t.Errorf("InferContext(%q, %q) = %q, want %q", tt.signal, tt.input, got, tt.want)
+		}
+	}
+}
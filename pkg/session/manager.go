@@ -139,6 +139,13 @@ func (m *Manager) cleanupExpired(ctx context.Context) {
 					slog.Warn("failed to cleanup expired session", "session_id", sess.ID, "error", err)
 				}
 			}
+
+			// Resume the GitOps sync suspended for the session
+			if sess.SyncSuspension != nil {
+				if err := sess.SyncSuspension.Resume(ctx); err != nil {
+					slog.Warn("failed to resume gitops sync of expired session", "session_id", sess.ID, "error", err)
+				}
+			}
 		}
 		return true
 	})
@@ -10,12 +10,15 @@ import (
 
 type mockMutator struct{}
 
-func (m *mockMutator) Backup(_ context.Context, _ string) error     { return nil }
-func (m *mockMutator) ApplyConfig(_ context.Context, _ string) error { return nil }
-func (m *mockMutator) Rollback(_ context.Context) error              { return nil }
-func (m *mockMutator) TriggerRollout(_ context.Context) error        { return nil }
-func (m *mockMutator) Cleanup(_ context.Context) error               { return nil }
-func (m *mockMutator) DetectGitOps(_ context.Context) (bool, string) { return false, "" }
+func (m *mockMutator) Backup(_ context.Context, _ string) error         { return nil }
+func (m *mockMutator) CurrentConfig(_ context.Context) (string, error)  { return "", nil }
+func (m *mockMutator) BackedUpConfig(_ context.Context) (string, error) { return "", nil }
+func (m *mockMutator) ApplyConfig(_ context.Context, _ string) error    { return nil }
+func (m *mockMutator) DryRunConfig(_ context.Context, _ string) error   { return nil }
+func (m *mockMutator) Rollback(_ context.Context) error                 { return nil }
+func (m *mockMutator) TriggerRollout(_ context.Context) error           { return nil }
+func (m *mockMutator) Cleanup(_ context.Context) error                  { return nil }
+func (m *mockMutator) DetectGitOps(_ context.Context) (bool, string)    { return false, "" }
 
 // Compile-time check: mockMutator satisfies mutator.Mutator.
 var _ mutator.Mutator = (*mockMutator)(nil)
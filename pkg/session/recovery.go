@@ -5,14 +5,18 @@ import (
 	"log/slog"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/hrexed/otel-collector-mcp/pkg/mutator"
 )
 
 // RecoverOrphanedSessions scans ConfigMaps and CRDs for orphaned session annotations
-// and cleans them up. Called on server startup.
-func RecoverOrphanedSessions(ctx context.Context, clientset kubernetes.Interface) {
+// and cleans them up. Called on server startup. The automated sync of an Argo
+// CD Application suspended by an orphaned session is restored from the policy
+// kept in its annotation; Applications not named by a tracking ID are looked
+// up in argoNamespace.
+func RecoverOrphanedSessions(ctx context.Context, clientset kubernetes.Interface, dynamicClient dynamic.Interface, argoNamespace string) {
 	// Scan all namespaces for ConfigMaps with session annotations
 	namespaces, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
@@ -30,8 +34,9 @@ func RecoverOrphanedSessions(ctx context.Context, clientset kubernetes.Interface
 		for _, cm := range configMaps.Items {
 			sessionID, hasSession := cm.Annotations[mutator.AnnotationSessionID]
 			_, hasBackup := cm.Annotations[mutator.AnnotationConfigBackup]
+			_, hasSuspension := cm.Annotations[mutator.AnnotationSyncSuspended]
 
-			if hasSession || hasBackup {
+			if hasSession || hasBackup || hasSuspension {
 				slog.Warn("recovering orphaned session",
 					"namespace", ns.Name,
 					"configmap", cm.Name,
@@ -43,6 +48,22 @@ func RecoverOrphanedSessions(ctx context.Context, clientset kubernetes.Interface
 				delete(cmCopy.Annotations, mutator.AnnotationSessionID)
 				delete(cmCopy.Annotations, mutator.AnnotationConfigBackup)
 
+				// Resume the GitOps sync suspended by the session: Flux through
+				// the collector's annotations, Argo CD by restoring the sync
+				// policy kept on its Application
+				if hasSuspension {
+					if owner := mutator.GitOpsOwnerOf(&cm, argoNamespace); owner != nil && owner.Tool == mutator.GitOpsArgoCD {
+						ref := mutator.CollectorRef{Name: cm.Name, Namespace: ns.Name, ConfigMapName: cm.Name}
+						if err := mutator.ResumeArgoAutomatedSync(ctx, dynamicClient, ref, *owner); err != nil {
+							slog.Warn("gitops sync of orphaned session left suspended; restore the automated sync of its Argo CD Application",
+								"configmap", cm.Name, "annotation", mutator.AnnotationSuspendedAutomatedSync, "error", err)
+						}
+					}
+					delete(cmCopy.Annotations, mutator.AnnotationFluxReconcile)
+					delete(cmCopy.Annotations, mutator.AnnotationFluxDriftDetection)
+					delete(cmCopy.Annotations, mutator.AnnotationSyncSuspended)
+				}
+
 				if _, err := clientset.CoreV1().ConfigMaps(ns.Name).Update(ctx, cmCopy, metav1.UpdateOptions{}); err != nil {
 					slog.Error("failed to cleanup orphaned session", "configmap", cm.Name, "error", err)
 				} else {
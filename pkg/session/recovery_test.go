@@ -0,0 +1,60 @@
+package session
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/mutator"
+)
+
+func TestRecoverOrphanedSessionsResumesArgoSync(t *testing.T) {
+	ctx := context.Background()
+	app := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Application",
+		"metadata": map[string]interface{}{
+			"name":        "observability",
+			"namespace":   "argocd",
+			"annotations": map[string]interface{}{mutator.AnnotationSuspendedAutomatedSync: `{"selfHeal":true}`},
+		},
+		"spec": map[string]interface{}{"syncPolicy": map[string]interface{}{}},
+	}}
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), app)
+	clientset := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "obs"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:      "otel-config",
+			Namespace: "obs",
+			Annotations: map[string]string{
+				mutator.AnnotationArgoTrackingID: "observability:/ConfigMap:obs/otel-config",
+				mutator.AnnotationSyncSuspended:  "s1",
+			},
+		}},
+	)
+
+	RecoverOrphanedSessions(ctx, clientset, dynamicClient, "argocd")
+
+	gvr := schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"}
+	got, err := dynamicClient.Resource(gvr).Namespace("argocd").Get(ctx, "observability", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if selfHeal, _, _ := unstructured.NestedBool(got.Object, "spec", "syncPolicy", "automated", "selfHeal"); !selfHeal {
+		t.Errorf("expected the automated sync policy back, got %v", got.Object["spec"])
+	}
+	if _, ok := got.GetAnnotations()[mutator.AnnotationSuspendedAutomatedSync]; ok {
+		t.Errorf("expected the suspension annotation to be removed from the Application")
+	}
+	cm, _ := clientset.CoreV1().ConfigMaps("obs").Get(ctx, "otel-config", metav1.GetOptions{})
+	if _, ok := cm.Annotations[mutator.AnnotationSyncSuspended]; ok {
+		t.Errorf("expected the collector's suspension marker to be removed")
+	}
+}
@@ -36,6 +36,17 @@ type Session struct {
 	CapturedSignals interface{}
 	Findings        interface{}
 	SuggestedFixes  interface{}
+	Verifications   interface{} // verdicts of fix verifications, oldest first
+	Plan            interface{} // change plan from plan_changes awaiting apply_plan
+
+	// GitOps state for a GitOps-managed collector. In patch mode fixes are
+	// committed to the GitOps repository and PatchedConfig is the config
+	// last committed; SyncSuspension holds the sync suspended while
+	// ephemeral changes are live on the collector.
+	GitOps         *mutator.GitOpsOwner
+	PatchMode      bool
+	PatchedConfig  string
+	SyncSuspension *mutator.SyncSuspension
 
 	// Mutator for this session
 	Mutator mutator.Mutator
@@ -0,0 +1,392 @@
+package skills
+
+import (
+	"bufio"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fbSection is one [SECTION] of a Fluent Bit config, or one list entry of
+// the YAML format. Keys are lowercased; a key may repeat.
+type fbSection struct {
+	kind  string
+	props []fbProp
+	used  map[string]bool
+}
+
+type fbProp struct {
+	key   string
+	value string
+}
+
+// get returns the first value of key and marks the key as converted.
+func (s *fbSection) get(key string) string {
+	s.used[key] = true
+	for _, p := range s.props {
+		if p.key == key {
+			return p.value
+		}
+	}
+	return ""
+}
+
+// all returns every value of key and marks the key as converted.
+func (s *fbSection) all(key string) []string {
+	s.used[key] = true
+	var values []string
+	for _, p := range s.props {
+		if p.key == key {
+			values = append(values, p.value)
+		}
+	}
+	return values
+}
+
+func (s *fbSection) on(key string) bool {
+	switch strings.ToLower(s.get(key)) {
+	case "on", "true", "yes", "1":
+		return true
+	}
+	return false
+}
+
+func (s *fbSection) name() string {
+	return strings.ToLower(s.get("name"))
+}
+
+// label identifies the section in unmapped constructs and notes.
+func (s *fbSection) label() string {
+	if alias := s.get("alias"); alias != "" {
+		return fmt.Sprintf("[%s] %s (%s)", strings.ToUpper(s.kind), s.name(), alias)
+	}
+	return fmt.Sprintf("[%s] %s", strings.ToUpper(s.kind), s.name())
+}
+
+// fbCommonKeys are accepted by every plugin. Buffering and scheduling are
+// handled by the memory_limiter, batch and exporter queues instead.
+var fbCommonKeys = map[string]bool{
+	"name": true, "alias": true, "tag": true, "match": true, "match_regex": true,
+	"mem_buf_limit": true, "buffer_chunk_size": true, "buffer_max_size": true,
+	"storage.type": true, "storage.total_limit_size": true, "storage.pause_on_chunks_overlimit": true,
+	"threaded": true, "workers": true, "log_level": true, "retry_limit": true,
+}
+
+// unmapUnused reports the section's settings the converter did not consume.
+func (c *conversion) unmapUnused(s *fbSection, target string) {
+	for _, p := range s.props {
+		if s.used[p.key] || fbCommonKeys[p.key] {
+			continue
+		}
+		c.unmap(fmt.Sprintf("%s %s %s", s.label(), p.key, p.value), "no equivalent setting on "+target)
+		s.used[p.key] = true
+	}
+}
+
+// fbConfig is a parsed Fluent Bit configuration.
+type fbConfig struct {
+	inputs, filters, outputs []*fbSection
+	parsers                  map[string]*fbSection
+}
+
+var fbVariable = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// parseFluentBit reads the classic or the YAML Fluent Bit format.
+func parseFluentBit(content string, c *conversion) (*fbConfig, error) {
+	var sections []*fbSection
+	var err error
+	if regexp.MustCompile(`(?m)^pipeline:`).MatchString(content) {
+		sections, err = parseFluentBitYAML(content, c)
+	} else {
+		sections, err = parseFluentBitClassic(content, c)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fb := &fbConfig{parsers: map[string]*fbSection{}}
+	for _, s := range sections {
+		switch s.kind {
+		case "input":
+			fb.inputs = append(fb.inputs, s)
+		case "filter":
+			fb.filters = append(fb.filters, s)
+		case "output":
+			fb.outputs = append(fb.outputs, s)
+		case "parser":
+			fb.parsers[s.get("name")] = s
+		case "service":
+			c.note("[SERVICE] settings (flush interval, HTTP server, storage) map to the batch processor, service.telemetry and exporter queues rather than one section")
+		case "multiline_parser":
+			c.unmap("[MULTILINE_PARSER] "+s.get("name"), "rewrite its rules as a recombine operator on the filelog receiver")
+		default:
+			c.unmap("["+strings.ToUpper(s.kind)+"]", "unknown section")
+		}
+	}
+	if len(fb.inputs) == 0 {
+		return nil, fmt.Errorf("no [INPUT] section found")
+	}
+	return fb, nil
+}
+
+func parseFluentBitClassic(content string, c *conversion) ([]*fbSection, error) {
+	vars := map[string]string{}
+	var sections []*fbSection
+	var current *fbSection
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			current = &fbSection{kind: strings.ToLower(strings.Trim(line, "[] ")), used: map[string]bool{}}
+			sections = append(sections, current)
+			continue
+		case strings.HasPrefix(strings.ToUpper(line), "@INCLUDE"):
+			c.unmap(line, "included files were not provided; convert them together with this file")
+			continue
+		case strings.HasPrefix(strings.ToUpper(line), "@SET"):
+			k, v, _ := strings.Cut(strings.TrimSpace(line[len("@SET"):]), "=")
+			vars[strings.TrimSpace(k)] = strings.TrimSpace(v)
+			continue
+		}
+		if current == nil {
+			return nil, fmt.Errorf("line %d: setting outside of a section", n)
+		}
+		fields := strings.Fields(line)
+		value := strings.TrimSpace(strings.TrimPrefix(line, fields[0]))
+		current.props = append(current.props, fbProp{key: strings.ToLower(fields[0]), value: expandFluentBitVars(value, vars)})
+	}
+	return sections, scanner.Err()
+}
+
+func parseFluentBitYAML(content string, c *conversion) ([]*fbSection, error) {
+	var doc struct {
+		Env      map[string]string        `yaml:"env"`
+		Includes []string                 `yaml:"includes"`
+		Service  map[string]interface{}   `yaml:"service"`
+		Parsers  []map[string]interface{} `yaml:"parsers"`
+		Pipeline struct {
+			Inputs  []map[string]interface{} `yaml:"inputs"`
+			Filters []map[string]interface{} `yaml:"filters"`
+			Outputs []map[string]interface{} `yaml:"outputs"`
+		} `yaml:"pipeline"`
+	}
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+		return nil, err
+	}
+	for _, inc := range doc.Includes {
+		c.unmap("includes "+inc, "included files were not provided; convert them together with this file")
+	}
+
+	section := func(kind string, entry map[string]interface{}) *fbSection {
+		s := &fbSection{kind: kind, used: map[string]bool{}}
+		keys := make([]string, 0, len(entry))
+		for k := range entry {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			values, ok := entry[k].([]interface{})
+			if !ok {
+				values = []interface{}{entry[k]}
+			}
+			for _, v := range values {
+				s.props = append(s.props, fbProp{key: strings.ToLower(k), value: expandFluentBitVars(fmt.Sprint(v), doc.Env)})
+			}
+		}
+		return s
+	}
+
+	var sections []*fbSection
+	if len(doc.Service) > 0 {
+		sections = append(sections, section("service", doc.Service))
+	}
+	for _, p := range doc.Parsers {
+		sections = append(sections, section("parser", p))
+	}
+	for _, group := range []struct {
+		kind    string
+		entries []map[string]interface{}
+	}{
+		{"input", doc.Pipeline.Inputs},
+		{"filter", doc.Pipeline.Filters},
+		{"output", doc.Pipeline.Outputs},
+	} {
+		for _, e := range group.entries {
+			sections = append(sections, section(group.kind, e))
+		}
+	}
+	return sections, nil
+}
+
+// expandFluentBitVars replaces ${VAR} with @SET variables; other references
+// are environment variables and become collector ${env:VAR} references.
+func expandFluentBitVars(value string, vars map[string]string) string {
+	return fbVariable.ReplaceAllStringFunc(value, func(ref string) string {
+		name := fbVariable.FindStringSubmatch(ref)[1]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return "${env:" + name + "}"
+	})
+}
+
+// fbMatcher returns whether a section's Match or Match_Regex selects a tag.
+func fbMatcher(s *fbSection) func(tag string) bool {
+	if expr := s.get("match_regex"); expr != "" {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return func(string) bool { return false }
+		}
+		return re.MatchString
+	}
+	pattern := s.get("match")
+	return func(tag string) bool {
+		ok, _ := path.Match(pattern, tag)
+		return ok
+	}
+}
+
+// fbInput is a converted input and the tag Fluent Bit routes it by.
+type fbInput struct {
+	tag      string
+	receiver string
+	signals  []string
+}
+
+// fbOutput is a converted output and the tags it selects.
+type fbOutput struct {
+	exporter string
+	signals  []string
+	matches  func(tag string) bool
+}
+
+// fbStage is a converted filter, applied to the inputs it selects.
+type fbStage struct {
+	processor string
+	label     string
+	matches   func(tag string) bool
+}
+
+// convertFluentBit maps inputs to receivers, filters to processors and
+// outputs to exporters, then rebuilds Fluent Bit's tag routing as pipelines.
+func convertFluentBit(content string, c *conversion) error {
+	fb, err := parseFluentBit(content, c)
+	if err != nil {
+		return err
+	}
+	c.note("Fluent Bit record keys are mapped to log attributes; the raw log line is the log body")
+
+	var inputs []fbInput
+	for i, in := range fb.inputs {
+		tag := in.get("tag")
+		if tag == "" {
+			tag = fmt.Sprintf("%s.%d", in.name(), i)
+		}
+		receiver, signals := c.fluentBitInput(in, fb.parsers)
+		if receiver == "" {
+			continue
+		}
+		inputs = append(inputs, fbInput{tag: tag, receiver: receiver, signals: signals})
+	}
+
+	var stages []fbStage
+	for _, f := range fb.filters {
+		for _, processor := range c.fluentBitFilter(f, fb.parsers) {
+			stages = append(stages, fbStage{processor: processor, label: f.label(), matches: fbMatcher(f)})
+		}
+	}
+
+	var outputs []fbOutput
+	for _, out := range fb.outputs {
+		matches := fbMatcher(out)
+		exporter, signals := c.fluentBitOutput(out)
+		if exporter == "" {
+			// Keep the records flowing to the destination instead of dropping them
+			exporter, signals = c.destinationExporter(), allSignals
+		}
+		outputs = append(outputs, fbOutput{exporter: exporter, signals: signals, matches: matches})
+	}
+	if len(outputs) == 0 {
+		outputs = append(outputs, fbOutput{exporter: c.destinationExporter(), signals: allSignals, matches: func(string) bool { return true }})
+	}
+
+	routed := map[string]bool{}
+	for _, signal := range []string{"logs", "metrics", "traces"} {
+		// Inputs sent to the same outputs share a pipeline
+		var groups []string
+		exporters := map[string][]string{}
+		receivers := map[string][]fbInput{}
+		for _, out := range outputs {
+			if !containsString(out.signals, signal) {
+				continue
+			}
+			var selected []fbInput
+			var ids []string
+			for _, in := range inputs {
+				if containsString(in.signals, signal) && out.matches(in.tag) {
+					selected = append(selected, in)
+					ids = append(ids, in.receiver)
+					routed[in.receiver] = true
+				}
+			}
+			if len(selected) == 0 {
+				continue
+			}
+			key := strings.Join(ids, ",")
+			if _, ok := exporters[key]; !ok {
+				groups = append(groups, key)
+				receivers[key] = selected
+			}
+			if !containsString(exporters[key], out.exporter) {
+				exporters[key] = append(exporters[key], out.exporter)
+			}
+		}
+
+		for _, key := range groups {
+			var processors []string
+			if signal == "logs" {
+				processors = c.fluentBitStages(stages, receivers[key])
+			}
+			ids := strings.Split(key, ",")
+			c.addPipeline(signal, ids, processors, exporters[key])
+		}
+	}
+
+	for _, in := range inputs {
+		if !routed[in.receiver] {
+			c.note("Input tagged %s matches no output, so Fluent Bit discards it; receiver %s is not used in any pipeline", in.tag, in.receiver)
+			delete(c.cfg.Receivers, in.receiver)
+		}
+	}
+	return nil
+}
+
+// fluentBitStages returns the processors whose Match selects any of the
+// pipeline's inputs, flagging filters that now apply to more inputs.
+func (c *conversion) fluentBitStages(stages []fbStage, inputs []fbInput) []string {
+	var processors []string
+	for _, st := range stages {
+		matched := 0
+		for _, in := range inputs {
+			if st.matches(in.tag) {
+				matched++
+			}
+		}
+		if matched == 0 || containsString(processors, st.processor) {
+			continue
+		}
+		if matched < len(inputs) {
+			c.note("%s matches only some inputs of a pipeline it was added to; processor %s now applies to all of them, split the pipeline if that matters", st.label, st.processor)
+		}
+		processors = append(processors, st.processor)
+	}
+	return processors
+}
@@ -0,0 +1,639 @@
+package skills
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// hostEndpoint joins a Listen/Host and Port setting with defaults.
+func hostEndpoint(s *fbSection, hostKey, defHost, defPort string) string {
+	host, port := s.get(hostKey), s.get("port")
+	if host == "" {
+		host = defHost
+	}
+	if port == "" {
+		port = defPort
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// seconds turns a Fluent Bit interval in seconds into a duration.
+func seconds(v string) string {
+	if v == "" || strings.TrimLeft(v, "0123456789") != "" {
+		return v
+	}
+	return v + "s"
+}
+
+// fbScrapers maps Fluent Bit host metric inputs to hostmetrics scrapers.
+var fbScrapers = map[string][]string{
+	"cpu":                   {"cpu"},
+	"mem":                   {"memory"},
+	"disk":                  {"disk"},
+	"netif":                 {"network"},
+	"node_exporter_metrics": {"cpu", "disk", "filesystem", "load", "memory", "network", "paging"},
+}
+
+// fluentBitInput converts an [INPUT] to a receiver and returns its ID and
+// signals, or "" when the plugin has no collector equivalent.
+func (c *conversion) fluentBitInput(in *fbSection, parsers map[string]*fbSection) (string, []string) {
+	name := in.name()
+	alias := sanitizeID(in.get("alias"))
+	var typ string
+	cfg := map[string]interface{}{}
+	signals := []string{"logs"}
+
+	switch name {
+	case "tail":
+		typ = "filelog"
+		cfg["include"] = splitList(in.get("path"))
+		if exclude := splitList(in.get("exclude_path")); len(exclude) > 0 {
+			cfg["exclude"] = exclude
+		}
+		cfg["start_at"] = "end"
+		if in.on("read_from_head") {
+			cfg["start_at"] = "beginning"
+		}
+		if key := in.get("path_key"); key != "" {
+			cfg["include_file_path"] = true
+			c.note("%s: the file path is stored in the log.file.path attribute instead of %s", in.label(), key)
+		}
+		c.fluentBitStorage(in, cfg)
+		if ops := c.fluentBitOperators(in, parsers); len(ops) > 0 {
+			cfg["operators"] = ops
+		}
+	case "systemd":
+		typ = "journald"
+		if dir := in.get("path"); dir != "" {
+			cfg["directory"] = dir
+		}
+		var matches []interface{}
+		for _, f := range in.all("systemd_filter") {
+			k, v, ok := strings.Cut(f, "=")
+			if ok {
+				matches = append(matches, map[string]interface{}{k: v})
+			}
+		}
+		if len(matches) > 0 {
+			cfg["matches"] = matches
+		}
+		cfg["start_at"] = "beginning"
+		if in.on("read_from_tail") {
+			cfg["start_at"] = "end"
+		}
+		c.fluentBitStorage(in, cfg)
+	case "forward":
+		typ = "fluentforward"
+		cfg["endpoint"] = hostEndpoint(in, "listen", "0.0.0.0", "24224")
+	case "syslog":
+		mode := strings.ToLower(in.get("mode"))
+		if mode != "tcp" && mode != "udp" {
+			c.unmap(in.label()+" mode "+mode, "the syslog receiver listens on TCP or UDP, not Unix sockets")
+			return "", nil
+		}
+		typ = "syslog"
+		cfg[mode] = map[string]interface{}{"listen_address": hostEndpoint(in, "listen", "0.0.0.0", "5140")}
+		cfg["protocol"] = "rfc5424"
+		if strings.Contains(in.get("parser"), "3164") {
+			cfg["protocol"] = "rfc3164"
+		}
+	case "tcp", "udp":
+		typ = name + "log"
+		cfg["listen_address"] = hostEndpoint(in, "listen", "0.0.0.0", "5170")
+		if strings.ToLower(in.get("format")) != "none" {
+			cfg["operators"] = []interface{}{map[string]interface{}{"type": "json_parser"}}
+		}
+	case "opentelemetry":
+		typ = "otlp"
+		cfg["protocols"] = map[string]interface{}{
+			"http": map[string]interface{}{"endpoint": hostEndpoint(in, "listen", "0.0.0.0", "4318")},
+		}
+		signals = allSignals
+	case "cpu", "mem", "disk", "netif", "node_exporter_metrics":
+		typ = "hostmetrics"
+		interval := in.get("interval_sec")
+		if name == "node_exporter_metrics" {
+			interval = in.get("scrape_interval")
+		}
+		if interval != "" {
+			cfg["collection_interval"] = seconds(interval)
+		}
+		scrapers := map[string]interface{}{}
+		for _, s := range fbScrapers[name] {
+			scrapers[s] = map[string]interface{}{}
+		}
+		cfg["scrapers"] = scrapers
+		cfg["root_path"] = "/hostfs"
+		c.note("%s: mount the host root filesystem at /hostfs so hostmetrics reports the node rather than the container", in.label())
+		signals = []string{"metrics"}
+	case "prometheus_scrape":
+		typ = "prometheus"
+		job := map[string]interface{}{
+			"job_name":       "fluentbit_" + sanitizeID(in.get("host")),
+			"static_configs": []interface{}{map[string]interface{}{"targets": []interface{}{hostEndpoint(in, "host", "127.0.0.1", "9100")}}},
+		}
+		if p := in.get("metrics_path"); p != "" {
+			job["metrics_path"] = p
+		}
+		if interval := in.get("scrape_interval"); interval != "" {
+			job["scrape_interval"] = seconds(interval)
+		}
+		cfg["config"] = map[string]interface{}{"scrape_configs": []interface{}{job}}
+		signals = []string{"metrics"}
+	case "kubernetes_events":
+		typ = "k8sobjects"
+		cfg["objects"] = []interface{}{map[string]interface{}{"name": "events", "mode": "watch"}}
+		c.note("%s: run k8sobjects in a single-replica Deployment, or every agent reports every event", in.label())
+	case "fluentbit_metrics":
+		c.unmap(in.label(), "the collector reports its own metrics through service.telemetry.metrics")
+		return "", nil
+	default:
+		c.unmap(in.label(), "no collector receiver equivalent")
+		return "", nil
+	}
+
+	id := componentID(c.cfg.Receivers, typ, alias)
+	c.cfg.Receivers[id] = cfg
+	c.unmapUnused(in, "receiver "+id)
+	return id, signals
+}
+
+// fluentBitStorage maps the offset database to the file_storage extension.
+func (c *conversion) fluentBitStorage(in *fbSection, cfg map[string]interface{}) {
+	db := in.get("db")
+	if db == "" {
+		return
+	}
+	c.cfg.Extensions["file_storage"] = map[string]interface{}{"directory": filepath.Dir(db)}
+	cfg["storage"] = "file_storage"
+	c.note("%s: offsets are persisted by the file_storage extension in %s, which must be a hostPath volume", in.label(), filepath.Dir(db))
+}
+
+// fluentBitOperators builds filelog operators from the tail input's parsers.
+func (c *conversion) fluentBitOperators(in *fbSection, parsers map[string]*fbSection) []interface{} {
+	var ops []interface{}
+	container := in.on("docker_mode")
+	var names []string
+	names = append(names, splitList(in.get("multiline.parser"))...)
+	names = append(names, splitList(in.get("parser"))...)
+	for _, name := range names {
+		switch name {
+		case "docker", "cri":
+			container = true
+		case "go", "java", "python", "ruby":
+			c.unmap(in.label()+" multiline.parser "+name, "add a recombine operator whose is_first_entry matches the first line of a "+name+" log entry")
+		default:
+			def, ok := parsers[name]
+			if !ok {
+				c.unmap(in.label()+" parser "+name, "parser definition not found in the provided config")
+				continue
+			}
+			if op := c.parserOperator(def); op != nil {
+				ops = append(ops, op)
+			}
+		}
+	}
+	if in.on("multiline") || in.get("parser_firstline") != "" {
+		c.unmap(in.label()+" multiline", "add a recombine operator with is_first_entry set to the Parser_Firstline pattern")
+	}
+	if container {
+		ops = append([]interface{}{map[string]interface{}{"type": "container"}}, ops...)
+	}
+	return ops
+}
+
+// parserOperator converts a [PARSER] definition to a filelog operator.
+func (c *conversion) parserOperator(def *fbSection) map[string]interface{} {
+	label := "[PARSER] " + def.get("name")
+	var op map[string]interface{}
+	switch format := strings.ToLower(def.get("format")); format {
+	case "json":
+		op = map[string]interface{}{"type": "json_parser"}
+	case "regex":
+		pattern := def.get("regex")
+		if _, err := regexp.Compile(pattern); err != nil {
+			c.unmap(label, fmt.Sprintf("regex is not RE2-compatible (%v); rewrite it without lookarounds or backreferences", err))
+			return nil
+		}
+		op = map[string]interface{}{"type": "regex_parser", "regex": pattern}
+	case "logfmt":
+		op = map[string]interface{}{"type": "key_value_parser"}
+		c.note("%s: logfmt is parsed as key=value pairs; quoted values containing spaces are not supported", label)
+	case "ltsv":
+		op = map[string]interface{}{"type": "key_value_parser", "delimiter": ":", "pair_delimiter": "\t"}
+	default:
+		c.unmap(label, fmt.Sprintf("format %q has no operator equivalent", format))
+		return nil
+	}
+	if key := def.get("time_key"); key != "" {
+		ts := map[string]interface{}{"parse_from": "attributes." + key}
+		if layout := def.get("time_format"); layout != "" {
+			ts["layout_type"] = "strptime"
+			ts["layout"] = layout
+		}
+		op["timestamp"] = ts
+	}
+	def.get("time_keep")
+	c.unmapUnused(def, "the "+op["type"].(string)+" operator")
+	return op
+}
+
+// fluentBitFilter converts a [FILTER] to processors, in order.
+func (c *conversion) fluentBitFilter(f *fbSection, parsers map[string]*fbSection) []string {
+	alias := sanitizeID(f.get("alias"))
+	var processors []string
+	var statements, conditions []string
+
+	switch name := f.name(); name {
+	case "kubernetes":
+		processors = append(processors, c.k8sAttributes(f))
+		if f.on("merge_log") {
+			stmts, _ := genParseJSON(&ottlSpec{Action: "parse_json", Target: f.get("merge_log_key")})
+			statements = append(statements, stmts...)
+		}
+		f.get("keep_log")
+	case "modify":
+		statements = c.modifyStatements(f)
+	case "record_modifier":
+		for _, r := range f.all("record") {
+			k, v, _ := strings.Cut(r, " ")
+			statements = append(statements, c.ottlStatements(f.label()+" record "+r, &ottlSpec{Action: "set_attribute", Attribute: k, Value: strings.TrimSpace(v)})...)
+		}
+		for _, k := range f.all("remove_key") {
+			statements = append(statements, c.ottlStatements(f.label()+" remove_key "+k, &ottlSpec{Action: "delete_attribute", Attribute: k})...)
+		}
+		keep := append(f.all("allowlist_key"), f.all("whitelist_key")...)
+		if len(keep) > 0 {
+			quoted := make([]string, len(keep))
+			for i, k := range keep {
+				quoted[i] = ottlString(k)
+			}
+			statements = append(statements, fmt.Sprintf("keep_keys(attributes, [%s])", strings.Join(quoted, ", ")))
+		}
+	case "grep":
+		conditions = c.grepConditions(f)
+	case "parser":
+		statements = c.parserStatements(f, parsers)
+	default:
+		c.unmap(f.label(), "no processor equivalent; rewrite it as transform or filter processor statements")
+		return nil
+	}
+
+	if len(statements) > 0 {
+		id := componentID(c.cfg.Processors, "transform", orDefault(alias, f.name()))
+		c.cfg.Processors[id] = map[string]interface{}{
+			"error_mode":     "ignore",
+			"log_statements": []interface{}{map[string]interface{}{"context": "log", "statements": statements}},
+		}
+		processors = append(processors, id)
+	}
+	if len(conditions) > 0 {
+		id := componentID(c.cfg.Processors, "filter", orDefault(alias, f.name()))
+		c.cfg.Processors[id] = map[string]interface{}{
+			"error_mode": "ignore",
+			"logs":       map[string]interface{}{"log_record": conditions},
+		}
+		processors = append(processors, id)
+	}
+	c.unmapUnused(f, strings.Join(processors, ", "))
+	return processors
+}
+
+// k8sAttributes adds the k8sattributes processor replacing the kubernetes
+// filter. Pods are associated by the k8s.pod.uid the container operator sets.
+func (c *conversion) k8sAttributes(f *fbSection) string {
+	for _, k := range []string{"kube_url", "kube_ca_file", "kube_token_file", "kube_tag_prefix", "use_kubelet", "kubelet_port", "buffer_size", "tls.verify"} {
+		f.get(k)
+	}
+	extract := map[string]interface{}{
+		"metadata": []interface{}{"k8s.namespace.name", "k8s.pod.name", "k8s.pod.uid", "k8s.deployment.name", "k8s.node.name", "container.image.name", "container.image.tag"},
+	}
+	if f.get("labels") == "" || f.on("labels") {
+		extract["labels"] = []interface{}{map[string]interface{}{"tag_name": "k8s.pod.labels.$$1", "key_regex": "(.*)", "from": "pod"}}
+	}
+	if f.get("annotations") == "" || f.on("annotations") {
+		extract["annotations"] = []interface{}{map[string]interface{}{"tag_name": "k8s.pod.annotations.$$1", "key_regex": "(.*)", "from": "pod"}}
+	}
+	if _, ok := c.cfg.Processors["k8sattributes"]; !ok {
+		c.cfg.Processors["k8sattributes"] = map[string]interface{}{
+			"auth_type": "serviceAccount",
+			"extract":   extract,
+			"pod_association": []interface{}{
+				map[string]interface{}{"sources": []interface{}{map[string]interface{}{"from": "resource_attribute", "name": "k8s.pod.uid"}}},
+				map[string]interface{}{"sources": []interface{}{map[string]interface{}{"from": "connection"}}},
+			},
+		}
+		c.note("k8sattributes replaces the kubernetes filter: metadata lands in resource attributes (k8s.pod.name, ...) rather than a kubernetes record key, and the ServiceAccount needs get/watch/list on pods, namespaces and replicasets")
+	}
+	for _, k := range []string{"k8s-logging.parser", "k8s-logging.exclude"} {
+		if f.on(k) {
+			c.unmap(f.label()+" "+k, "pod annotations cannot select parsers or exclusion; use a filter processor on resource.attributes")
+		}
+	}
+	return "k8sattributes"
+}
+
+// modifyStatements converts modify filter rules, in order.
+func (c *conversion) modifyStatements(f *fbSection) []string {
+	var statements []string
+	for _, cond := range f.all("condition") {
+		c.unmap(f.label()+" condition "+cond, "rules were converted without the condition; add it as a where clause")
+	}
+	for _, p := range f.props {
+		k, v, _ := strings.Cut(p.value, " ")
+		v = strings.TrimSpace(v)
+		switch p.key {
+		case "set":
+			statements = append(statements, c.ottlStatements(f.label()+" "+p.key+" "+p.value, &ottlSpec{Action: "set_attribute", Attribute: k, Value: v})...)
+		case "add":
+			statements = append(statements, c.ottlStatements(f.label()+" "+p.key+" "+p.value, &ottlSpec{Action: "set_attribute", Attribute: k, Value: v, Where: attrPath(k) + " == nil"})...)
+		case "remove":
+			statements = append(statements, c.ottlStatements(f.label()+" "+p.key+" "+p.value, &ottlSpec{Action: "delete_attribute", Attribute: k})...)
+		case "remove_wildcard":
+			statements = append(statements, fmt.Sprintf("delete_matching_keys(attributes, %s)", ottlString("^"+regexp.QuoteMeta(k))))
+		case "remove_regex":
+			statements = append(statements, fmt.Sprintf("delete_matching_keys(attributes, %s)", ottlString(k)))
+		case "rename", "hard_rename":
+			statements = append(statements, c.ottlStatements(f.label()+" "+p.key+" "+p.value, &ottlSpec{Action: "rename_attribute", From: k, To: v})...)
+		case "copy":
+			statements = append(statements, fmt.Sprintf("set(%s, %s) where %s == nil", attrPath(v), attrPath(k), attrPath(v)))
+		case "hard_copy":
+			statements = append(statements, fmt.Sprintf("set(%s, %s) where %s != nil", attrPath(v), attrPath(k), attrPath(k)))
+		default:
+			continue
+		}
+		f.used[p.key] = true
+	}
+	return statements
+}
+
+// grepConditions converts grep rules to filter processor conditions, which
+// drop a record when any condition is true.
+func (c *conversion) grepConditions(f *fbSection) []string {
+	match := func(rule string) (string, bool) {
+		key, pattern, _ := strings.Cut(rule, " ")
+		field, ok := fbFieldPath(key)
+		if !ok {
+			c.unmap(f.label()+" "+rule, "record accessor could not be converted; Kubernetes metadata lives in resource attributes")
+			return "", false
+		}
+		return fmt.Sprintf("IsMatch(%s, %s)", field, ottlString(strings.TrimSpace(pattern))), true
+	}
+
+	op := strings.ToLower(f.get("logical_op"))
+	var keep, drop []string
+	for _, r := range f.all("regex") {
+		if m, ok := match(r); ok {
+			keep = append(keep, m)
+		}
+	}
+	for _, r := range f.all("exclude") {
+		if m, ok := match(r); ok {
+			drop = append(drop, m)
+		}
+	}
+
+	var conditions []string
+	switch {
+	case len(keep) == 0:
+	case op == "or":
+		conditions = append(conditions, "not ("+strings.Join(keep, " or ")+")")
+	default:
+		for _, k := range keep {
+			conditions = append(conditions, "not "+k)
+		}
+	}
+	if op == "and" && len(drop) > 1 {
+		conditions = append(conditions, strings.Join(drop, " and "))
+	} else {
+		conditions = append(conditions, drop...)
+	}
+	return conditions
+}
+
+// parserStatements converts the parser filter to OTTL parsing statements.
+func (c *conversion) parserStatements(f *fbSection, parsers map[string]*fbSection) []string {
+	field, ok := fbFieldPath(f.get("key_name"))
+	if !ok {
+		c.unmap(f.label()+" key_name", "record accessor could not be converted")
+		return nil
+	}
+	if !f.on("reserve_data") {
+		c.note("%s: Reserve_Data is off, so Fluent Bit drops the other record keys; the converted statements keep them", f.label())
+	}
+	f.get("preserve_key")
+
+	var statements []string
+	for _, name := range f.all("parser") {
+		def, ok := parsers[name]
+		if !ok {
+			c.unmap(f.label()+" parser "+name, "parser definition not found in the provided config")
+			continue
+		}
+		spec := &ottlSpec{Source: field}
+		switch strings.ToLower(def.get("format")) {
+		case "json":
+			spec.Action = "parse_json"
+		case "regex":
+			spec.Action, spec.Pattern = "parse_regex", def.get("regex")
+		case "logfmt":
+			spec.Action = "parse_key_value"
+		default:
+			c.unmap(f.label()+" parser "+name, "format has no OTTL equivalent")
+			continue
+		}
+		statements = append(statements, c.ottlStatements(f.label()+" parser "+name, spec)...)
+	}
+	return statements
+}
+
+// fbRecordAccessor matches $key['sub']['subsub'] record accessors.
+var fbRecordAccessor = regexp.MustCompile(`^\$([A-Za-z0-9_.-]+)((?:\['[^']+'\])*)$`)
+
+// fbFieldPath converts a record key or record accessor to an OTTL path. The
+// log key holds the raw line, which is the log body.
+func fbFieldPath(key string) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+	if !strings.HasPrefix(key, "$") {
+		if key == "log" {
+			return "body", true
+		}
+		return attrPath(key), true
+	}
+	m := fbRecordAccessor.FindStringSubmatch(key)
+	if m == nil || m[1] == "kubernetes" {
+		return "", false
+	}
+	p := attrPath(m[1])
+	for _, sub := range regexp.MustCompile(`\['([^']+)'\]`).FindAllStringSubmatch(m[2], -1) {
+		p += "[" + ottlString(sub[1]) + "]"
+	}
+	return p, true
+}
+
+// fluentBitOutput converts an [OUTPUT] to an exporter and returns its ID and
+// signals, or "" when the plugin has no collector equivalent.
+func (c *conversion) fluentBitOutput(out *fbSection) (string, []string) {
+	name := out.name()
+	alias := sanitizeID(out.get("alias"))
+	scheme := "http"
+	if out.on("tls") {
+		scheme = "https"
+	}
+	out.get("tls")
+	var typ string
+	cfg := map[string]interface{}{}
+	signals := []string{"logs"}
+
+	switch name {
+	case "stdout":
+		typ = "debug"
+		cfg["verbosity"] = "detailed"
+		out.get("format")
+		signals = allSignals
+	case "null":
+		typ = "nop"
+		signals = allSignals
+	case "opentelemetry":
+		typ = "otlphttp"
+		cfg["endpoint"] = scheme + "://" + hostEndpoint(out, "host", "127.0.0.1", "80")
+		for _, u := range []struct{ key, setting, def string }{
+			{"logs_uri", "logs_endpoint", "/v1/logs"},
+			{"metrics_uri", "metrics_endpoint", "/v1/metrics"},
+			{"traces_uri", "traces_endpoint", "/v1/traces"},
+		} {
+			if uri := out.get(u.key); uri != "" && uri != u.def {
+				cfg[u.setting] = cfg["endpoint"].(string) + uri
+			}
+		}
+		if headers := fbHeaders(out); len(headers) > 0 {
+			cfg["headers"] = headers
+		}
+		signals = allSignals
+	case "es":
+		typ = "elasticsearch"
+		if cloudID := out.get("cloud_id"); cloudID != "" {
+			cfg["cloudid"] = cloudID
+		} else {
+			cfg["endpoints"] = []interface{}{scheme + "://" + hostEndpoint(out, "host", "127.0.0.1", "9200")}
+		}
+		cfg["logs_index"] = orDefault(out.get("index"), "fluent-bit")
+		if user := out.get("http_user"); user != "" {
+			cfg["user"] = user
+			cfg["password"] = "${env:ELASTICSEARCH_PASSWORD}"
+			out.get("http_passwd")
+			c.note("%s: the password is read from the ELASTICSEARCH_PASSWORD environment variable; mount it from a Secret", out.label())
+		}
+		if out.on("logstash_format") {
+			c.unmap(out.label()+" logstash_format", "date-suffixed indices are configured with logs_dynamic_index or an ingest pipeline")
+		}
+	case "loki":
+		typ = "otlphttp"
+		alias = orDefault(alias, "loki")
+		cfg["endpoint"] = scheme + "://" + hostEndpoint(out, "host", "127.0.0.1", "3100") + "/otlp"
+		if tenant := out.get("tenant_id"); tenant != "" {
+			cfg["headers"] = map[string]interface{}{"X-Scope-OrgID": tenant}
+		}
+		c.note("%s: logs are sent to Loki's native OTLP endpoint (Loki 3.0+); index labels come from resource attributes configured in Loki's otlp_config", out.label())
+		for _, k := range []string{"labels", "label_keys", "auto_kubernetes_labels", "remove_keys", "line_format"} {
+			if v := out.get(k); v != "" {
+				c.unmap(out.label()+" "+k+" "+v, "Loki derives labels from OTLP resource attributes; configure them in Loki's limits_config.otlp_config")
+			}
+		}
+	case "kafka":
+		typ = "kafka"
+		cfg["brokers"] = toInterfaces(splitList(out.get("brokers")))
+		topics := splitList(out.get("topics"))
+		if len(topics) > 0 {
+			cfg["topic"] = topics[0]
+		}
+		if len(topics) > 1 {
+			c.unmap(out.label()+" topics", "the kafka exporter writes to one topic; add one exporter per topic")
+		}
+		cfg["encoding"] = "otlp_json"
+		out.get("format")
+	case "s3":
+		typ = "awss3"
+		uploader := map[string]interface{}{"s3_bucket": out.get("bucket"), "region": out.get("region")}
+		cfg["s3uploader"] = uploader
+	case "splunk":
+		typ = "splunk_hec"
+		cfg["endpoint"] = scheme + "://" + hostEndpoint(out, "host", "127.0.0.1", "8088") + "/services/collector"
+		if out.get("splunk_token") != "" {
+			cfg["token"] = "${env:SPLUNK_HEC_TOKEN}"
+			c.note("%s: the HEC token is read from the SPLUNK_HEC_TOKEN environment variable; mount it from a Secret", out.label())
+		}
+	case "prometheus_exporter":
+		typ = "prometheus"
+		cfg["endpoint"] = hostEndpoint(out, "host", "0.0.0.0", "2021")
+		signals = []string{"metrics"}
+	case "prometheus_remote_write":
+		typ = "prometheusremotewrite"
+		cfg["endpoint"] = scheme + "://" + hostEndpoint(out, "host", "127.0.0.1", "80") + orDefault(out.get("uri"), "/api/v1/write")
+		if headers := fbHeaders(out); len(headers) > 0 {
+			cfg["headers"] = headers
+		}
+		signals = []string{"metrics"}
+	case "forward":
+		c.unmap(out.label(), "the collector has no Fluent Forward exporter; records are sent to the destination over OTLP instead")
+		return "", nil
+	default:
+		c.unmap(out.label(), "no collector exporter equivalent; records are sent to the destination over OTLP instead")
+		return "", nil
+	}
+
+	id := componentID(c.cfg.Exporters, typ, alias)
+	c.cfg.Exporters[id] = cfg
+	c.unmapUnused(out, "exporter "+id)
+	return id, signals
+}
+
+func fbHeaders(s *fbSection) map[string]interface{} {
+	headers := map[string]interface{}{}
+	for _, h := range s.all("header") {
+		k, v, _ := strings.Cut(h, " ")
+		headers[k] = strings.TrimSpace(v)
+	}
+	return headers
+}
+
+// ottlStatements renders a log spec, reporting rules that lack a required
+// field as unmapped.
+func (c *conversion) ottlStatements(label string, spec *ottlSpec) []string {
+	statements, _, err := generateFromSpec("logs", spec)
+	if err != nil {
+		c.unmap(label, err.Error())
+		return nil
+	}
+	return statements
+}
+
+func splitList(v string) []string {
+	var out []string
+	for _, item := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func toInterfaces(items []string) []interface{} {
+	out := make([]interface{}, len(items))
+	for i, item := range items {
+		out[i] = item
+	}
+	return out
+}
+
+func orDefault(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,281 @@
+package skills
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// jaegerThriftSettings maps Jaeger agent UDP server flag suffixes to jaeger
+// receiver thrift protocol settings.
+var jaegerThriftSettings = map[string]string{
+	"server-host-port":          "endpoint",
+	"server-queue-size":         "queue_size",
+	"server-max-packet-size":    "max_packet_size",
+	"workers":                   "workers",
+	"server-socket-buffer-size": "socket_buffer_size",
+}
+
+// jaegerUnmapped lists agent flags with no collector equivalent.
+var jaegerUnmapped = map[string]string{
+	"reporter.grpc.discovery.min-peers": "peer discovery is not supported; put the collectors behind a Service or use the loadbalancing exporter",
+	"reporter.grpc.discovery.ttl":       "peer discovery is not supported; put the collectors behind a Service or use the loadbalancing exporter",
+	"reporter.type":                     "the collector always exports with the configured exporter; only gRPC reporting maps to OTLP",
+	"metrics-backend":                   "the collector exposes its own metrics through service.telemetry.metrics",
+	"metrics-http-route":                "the collector exposes its own metrics through service.telemetry.metrics",
+	"log-level":                         "set service.telemetry.logs.level instead",
+	"config-file":                       "the converted flags replace the config file",
+	"reporter.grpc.retry.max":           "the otlp exporter retries by elapsed time; tune retry_on_failure.max_elapsed_time",
+}
+
+// jaegerEnvRef matches ${ENV} and ${ENV:default} references in agent tags.
+var jaegerEnvRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::([^}]*))?\}`)
+
+// convertJaegerAgent maps Jaeger agent flags onto a jaeger receiver for the
+// agent's UDP ports, an OTLP receiver for migrated SDKs, and an OTLP exporter
+// pointed at the Jaeger collector, which accepts OTLP natively.
+func convertJaegerAgent(content string, c *conversion) error {
+	flags, err := parseJaegerFlags(content)
+	if err != nil {
+		return err
+	}
+
+	protocols := map[string]interface{}{}
+	for _, proto := range []struct{ flag, name, port string }{
+		{"processor.jaeger-compact.", "thrift_compact", "6831"},
+		{"processor.jaeger-binary.", "thrift_binary", "6832"},
+	} {
+		settings := map[string]interface{}{"endpoint": "0.0.0.0:" + proto.port}
+		for suffix, key := range jaegerThriftSettings {
+			if v, ok := flags[proto.flag+suffix]; ok {
+				if key == "endpoint" {
+					settings[key] = listenEndpoint(v)
+					continue
+				}
+				settings[key] = typedValue(v)
+			}
+		}
+		protocols[proto.name] = settings
+	}
+	c.cfg.Receivers["jaeger"] = map[string]interface{}{"protocols": protocols}
+	c.cfg.Receivers["otlp"] = map[string]interface{}{
+		"protocols": map[string]interface{}{
+			"grpc": map[string]interface{}{"endpoint": "0.0.0.0:4317"},
+			"http": map[string]interface{}{"endpoint": "0.0.0.0:4318"},
+		},
+	}
+	c.note("An otlp receiver was added so services can move from Jaeger clients to OpenTelemetry SDKs without another config change")
+
+	var processors []string
+	if tags := flags["agent.tags"]; tags != "" {
+		var actions []interface{}
+		for _, pair := range strings.Split(tags, ",") {
+			k, v, ok := strings.Cut(pair, "=")
+			if !ok || strings.TrimSpace(k) == "" {
+				c.unmap("agent.tags "+pair, "not a key=value pair")
+				continue
+			}
+			v = jaegerEnvRef.ReplaceAllStringFunc(strings.TrimSpace(v), func(ref string) string {
+				m := jaegerEnvRef.FindStringSubmatch(ref)
+				if m[2] != "" {
+					return fmt.Sprintf("${env:%s:-%s}", m[1], m[2])
+				}
+				return fmt.Sprintf("${env:%s}", m[1])
+			})
+			actions = append(actions, map[string]interface{}{"key": strings.TrimSpace(k), "value": v, "action": "upsert"})
+		}
+		if len(actions) > 0 {
+			c.cfg.Processors["resource/agent_tags"] = map[string]interface{}{"attributes": actions}
+			processors = append(processors, "resource/agent_tags")
+		}
+	}
+
+	exporter := c.destinationExporterFor(flags)
+
+	if sampling, ok := flags["http-server.host-port"]; ok || flags["reporter.grpc.host-port"] != "" {
+		if !ok {
+			sampling = "0.0.0.0:5778"
+		}
+		if hosts := flags["reporter.grpc.host-port"]; hosts != "" {
+			c.cfg.Extensions["jaegerremotesampling"] = map[string]interface{}{
+				"source": map[string]interface{}{
+					"remote": map[string]interface{}{"endpoint": strings.Split(hosts, ",")[0]},
+				},
+				"http": map[string]interface{}{"endpoint": listenEndpoint(sampling)},
+			}
+			c.note("The jaegerremotesampling extension serves sampling strategies from the Jaeger collector on the agent's sampling port")
+		} else {
+			c.unmap("http-server.host-port", "remote sampling needs a Jaeger collector to proxy; configure the jaegerremotesampling extension with a file or remote source")
+		}
+	}
+	if admin, ok := flags["admin.http.host-port"]; ok {
+		c.cfg.Extensions["health_check"] = map[string]interface{}{"endpoint": listenEndpoint(admin)}
+	}
+
+	for _, key := range sortedFlagKeys(flags) {
+		if reason, ok := jaegerUnmapped[key]; ok {
+			c.unmap(key, reason)
+			continue
+		}
+		if strings.HasPrefix(key, "processor.zipkin-compact.") {
+			c.unmap(key, "the collector has no receiver for Zipkin Thrift over compact UDP; move those clients to Zipkin HTTP or OTLP")
+			continue
+		}
+		if !jaegerFlagHandled(key) {
+			c.unmap(key, "unknown or unsupported Jaeger agent flag")
+		}
+	}
+
+	c.addPipeline("traces", []string{"jaeger", "otlp"}, processors, []string{exporter})
+	return nil
+}
+
+// destinationExporterFor builds the OTLP exporter towards the Jaeger
+// collector from the reporter flags, unless a destination was given.
+func (c *conversion) destinationExporterFor(flags map[string]string) string {
+	hosts := flags["reporter.grpc.host-port"]
+	if c.destination != "" && hosts != "" {
+		c.note("Traces are exported to the destination %s instead of the agent's reporter %s", c.destination, hosts)
+	}
+	if c.destination != "" || hosts == "" {
+		return c.destinationExporter()
+	}
+
+	list := strings.Split(hosts, ",")
+	host, port, err := net.SplitHostPort(strings.TrimSpace(list[0]))
+	if err != nil {
+		host, port = strings.TrimSpace(list[0]), "14250"
+	}
+	if port == "14250" {
+		port = "4317"
+		c.note("Port 14250 (Jaeger gRPC) was replaced with 4317, the Jaeger collector's OTLP port; enable OTLP on the Jaeger collector if it predates 1.35")
+	}
+	if len(list) > 1 {
+		c.note("The agent reported to %d collectors; only %s is used, point the exporter at a Service that fronts them all", len(list), list[0])
+	}
+
+	exporter := map[string]interface{}{"endpoint": net.JoinHostPort(host, port)}
+	tls := map[string]interface{}{}
+	if flags["reporter.grpc.tls.enabled"] != "true" {
+		tls["insecure"] = true
+	}
+	for flag, key := range map[string]string{
+		"reporter.grpc.tls.ca":               "ca_file",
+		"reporter.grpc.tls.cert":             "cert_file",
+		"reporter.grpc.tls.key":              "key_file",
+		"reporter.grpc.tls.server-name":      "server_name_override",
+		"reporter.grpc.tls.skip-host-verify": "insecure_skip_verify",
+	} {
+		if v, ok := flags[flag]; ok {
+			tls[key] = typedValue(v)
+		}
+	}
+	exporter["tls"] = tls
+	c.cfg.Exporters["otlp/jaeger"] = exporter
+	return "otlp/jaeger"
+}
+
+// jaegerFlagHandled reports whether convertJaegerAgent consumed the flag.
+func jaegerFlagHandled(key string) bool {
+	switch key {
+	case "agent.tags", "http-server.host-port", "admin.http.host-port", "reporter.grpc.host-port":
+		return true
+	}
+	if strings.HasPrefix(key, "reporter.grpc.tls.") {
+		return true
+	}
+	for _, prefix := range []string{"processor.jaeger-compact.", "processor.jaeger-binary."} {
+		if _, ok := jaegerThriftSettings[strings.TrimPrefix(key, prefix)]; ok && strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseJaegerFlags reads agent settings from command-line flags, a container
+// args list or a YAML config file, keyed by flag name.
+func parseJaegerFlags(content string) (map[string]string, error) {
+	flags := map[string]string{}
+	if !strings.Contains(content, "--") {
+		var doc map[string]interface{}
+		if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+			return nil, err
+		}
+		flattenFlags(flags, "", doc)
+		if len(flags) == 0 {
+			return nil, fmt.Errorf("no agent flags found")
+		}
+		return flags, nil
+	}
+
+	tokens := strings.FieldsFunc(content, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\\'
+	})
+	for i := 0; i < len(tokens); i++ {
+		tok := strings.Trim(tokens[i], `"',[]`)
+		if !strings.HasPrefix(tok, "--") {
+			continue
+		}
+		key, value, hasValue := strings.Cut(strings.TrimPrefix(tok, "--"), "=")
+		if !hasValue {
+			value = "true"
+			if i+1 < len(tokens) {
+				next := strings.Trim(tokens[i+1], `"',[]`)
+				if next != "" && !strings.HasPrefix(next, "-") {
+					value = next
+					i++
+				}
+			}
+		}
+		flags[key] = value
+	}
+	if len(flags) == 0 {
+		return nil, fmt.Errorf("no agent flags found")
+	}
+	return flags, nil
+}
+
+func flattenFlags(flags map[string]string, prefix string, doc map[string]interface{}) {
+	for k, v := range doc {
+		if nested, ok := v.(map[string]interface{}); ok {
+			flattenFlags(flags, prefix+k+".", nested)
+			continue
+		}
+		flags[prefix+k] = fmt.Sprint(v)
+	}
+}
+
+func sortedFlagKeys(flags map[string]string) []string {
+	keys := make([]string, 0, len(flags))
+	for k := range flags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// listenEndpoint turns a host-port with an empty host, such as :6831, into
+// an explicit wildcard endpoint.
+func listenEndpoint(hostPort string) string {
+	if strings.HasPrefix(hostPort, ":") {
+		return "0.0.0.0" + hostPort
+	}
+	return hostPort
+}
+
+// typedValue converts a flag value to a bool or integer when it is one, so it
+// renders unquoted in YAML.
+func typedValue(v string) interface{} {
+	if b, err := strconv.ParseBool(v); err == nil {
+		return b
+	}
+	if n, err := strconv.Atoi(v); err == nil {
+		return n
+	}
+	return v
+}
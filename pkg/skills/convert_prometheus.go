@@ -0,0 +1,202 @@
+package skills
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// prometheusUnmapped lists top-level prometheus.yml sections with no
+// collector equivalent.
+var prometheusUnmapped = map[string]string{
+	"rule_files":          "recording and alerting rules are evaluated by Prometheus, not the collector; keep them in the backend",
+	"alerting":            "alert routing to Alertmanager is not a collector feature",
+	"remote_read":         "the collector does not serve queries",
+	"storage":             "the collector does not store samples",
+	"tracing":             "Prometheus self-tracing has no collector equivalent; use service.telemetry",
+	"otlp":                "Prometheus OTLP ingestion settings do not apply to the collector",
+	"runtime":             "Prometheus runtime settings do not apply to the collector",
+	"scrape_config_files": "the prometheus receiver does not load scrape config files; inline them into scrape_configs",
+}
+
+// remoteWriteUnmapped lists remote_write settings the prometheusremotewrite
+// exporter cannot express.
+var remoteWriteUnmapped = map[string]string{
+	"write_relabel_configs": "the exporter does not relabel; drop or rename series with a filter or transform processor",
+	"basic_auth":            "configure a basicauth extension and reference it from the exporter's auth.authenticator",
+	"authorization":         "set the Authorization header in the exporter's headers from an environment variable",
+	"bearer_token":          "set the Authorization header in the exporter's headers from an environment variable",
+	"bearer_token_file":     "configure a bearertokenauth extension with filename and reference it from auth.authenticator",
+	"sigv4":                 "configure a sigv4auth extension and reference it from auth.authenticator",
+	"oauth2":                "configure an oauth2client extension and reference it from auth.authenticator",
+	"azuread":               "configure an azureauth extension and reference it from auth.authenticator",
+	"metadata_config":       "the exporter does not send metric metadata",
+	"queue_config":          "use the exporter's remote_write_queue and sending_queue settings",
+}
+
+// convertPrometheus maps prometheus.yml onto a prometheus receiver whose
+// scrape_configs are copied verbatim, and remote_write targets onto
+// prometheusremotewrite exporters.
+func convertPrometheus(content string, c *conversion) error {
+	var prom map[string]interface{}
+	if err := yaml.Unmarshal([]byte(content), &prom); err != nil {
+		return err
+	}
+	if prom == nil {
+		return fmt.Errorf("empty configuration")
+	}
+
+	for _, key := range sortedKeys(prom) {
+		if reason, ok := prometheusUnmapped[key]; ok {
+			c.unmap(key, reason)
+		}
+	}
+
+	promConfig := map[string]interface{}{}
+	var externalLabels map[string]interface{}
+	if global, ok := prom["global"].(map[string]interface{}); ok {
+		externalLabels, _ = global["external_labels"].(map[string]interface{})
+		g := map[string]interface{}{}
+		for _, key := range sortedKeys(global) {
+			switch key {
+			case "scrape_interval", "scrape_timeout", "scrape_protocols", "body_size_limit", "sample_limit", "label_limit", "label_name_length_limit", "label_value_length_limit", "target_limit", "keep_dropped_targets":
+				g[key] = global[key]
+			case "external_labels":
+			case "evaluation_interval":
+				c.unmap("global.evaluation_interval", "rule evaluation is not a collector feature")
+			default:
+				c.unmap("global."+key, "not supported by the prometheus receiver")
+			}
+		}
+		if len(g) > 0 {
+			promConfig["global"] = g
+		}
+	}
+
+	scrapeConfigs, _ := prom["scrape_configs"].([]interface{})
+	if len(scrapeConfigs) == 0 {
+		return fmt.Errorf("no scrape_configs found")
+	}
+	for _, sc := range scrapeConfigs {
+		job, _ := sc.(map[string]interface{})
+		name, _ := job["job_name"].(string)
+		if _, ok := job["file_sd_configs"]; ok {
+			c.note("Job %s uses file_sd_configs; mount the target files into the collector pod at the same paths", name)
+		}
+		for _, key := range []string{"bearer_token_file", "authorization", "basic_auth"} {
+			if _, ok := job[key]; ok {
+				c.note("Job %s reads credentials (%s); mount the referenced secret files into the collector pod", name, key)
+			}
+		}
+	}
+	promConfig["scrape_configs"] = escapeDollars(scrapeConfigs)
+	if strings.Contains(fmt.Sprint(scrapeConfigs), "$") {
+		c.note("Every $ in scrape_configs was escaped as $$ so the collector does not expand relabel references such as $1 as environment variables")
+	}
+
+	receiver := componentID(c.cfg.Receivers, "prometheus", "")
+	c.cfg.Receivers[receiver] = map[string]interface{}{"config": promConfig}
+	c.note("Scale the prometheus receiver with the Target Allocator (StatefulSet) when running more than one replica, or every replica scrapes every target")
+
+	var exporters []string
+	remoteWrites, _ := prom["remote_write"].([]interface{})
+	for i, rw := range remoteWrites {
+		rwMap, _ := rw.(map[string]interface{})
+		url, _ := rwMap["url"].(string)
+		if url == "" {
+			c.unmap(fmt.Sprintf("remote_write[%d]", i), "no url set")
+			continue
+		}
+		name, _ := rwMap["name"].(string)
+		exporter := map[string]interface{}{"endpoint": url}
+		if len(externalLabels) > 0 {
+			exporter["external_labels"] = externalLabels
+		}
+		if headers, ok := rwMap["headers"].(map[string]interface{}); ok {
+			exporter["headers"] = headers
+		}
+		if tls, ok := rwMap["tls_config"].(map[string]interface{}); ok {
+			exporter["tls"] = convertPromTLS(tls)
+		}
+		if timeout, ok := rwMap["remote_timeout"]; ok {
+			exporter["timeout"] = timeout
+		}
+		for _, key := range sortedKeys(rwMap) {
+			if reason, ok := remoteWriteUnmapped[key]; ok {
+				c.unmap(fmt.Sprintf("remote_write[%d].%s", i, key), reason)
+			}
+		}
+		id := componentID(c.cfg.Exporters, "prometheusremotewrite", sanitizeID(name))
+		c.cfg.Exporters[id] = exporter
+		exporters = append(exporters, id)
+	}
+	if len(exporters) == 0 {
+		if len(externalLabels) > 0 {
+			c.unmap("global.external_labels", "only applied by prometheusremotewrite exporters; add them with a resource or transform processor")
+		}
+		exporters = append(exporters, c.destinationExporter())
+	}
+
+	c.addPipeline("metrics", []string{receiver}, nil, exporters)
+	return nil
+}
+
+// convertPromTLS renames Prometheus tls_config keys to collector TLS settings.
+func convertPromTLS(tls map[string]interface{}) map[string]interface{} {
+	renames := map[string]string{
+		"ca_file":              "ca_file",
+		"cert_file":            "cert_file",
+		"key_file":             "key_file",
+		"server_name":          "server_name_override",
+		"insecure_skip_verify": "insecure_skip_verify",
+		"min_version":          "min_version",
+	}
+	out := map[string]interface{}{}
+	for k, v := range tls {
+		if name, ok := renames[k]; ok {
+			if k == "min_version" {
+				// Prometheus spells versions TLS12, the collector 1.2
+				v = "1." + strings.TrimPrefix(fmt.Sprint(v), "TLS1")
+			}
+			out[name] = v
+		}
+	}
+	return out
+}
+
+// escapeDollars doubles every $ in string values, since the collector
+// expands $VAR and ${VAR} in its config.
+func escapeDollars(v interface{}) interface{} {
+	switch val := v.(type) {
+	case string:
+		return strings.ReplaceAll(val, "$", "$$")
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = escapeDollars(item)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = escapeDollars(item)
+		}
+		return out
+	}
+	return v
+}
+
+// sanitizeID turns a free-form name into a component ID suffix.
+func sanitizeID(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '-':
+			b.WriteRune(r)
+		case r == '.', r == ' ', r == '/':
+			b.WriteRune('_')
+		}
+	}
+	return strings.Trim(b.String(), "_")
+}
@@ -0,0 +1,512 @@
+package skills
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/ottl"
+)
+
+// ottlSpec is a structured description of one OTTL transformation.
+type ottlSpec struct {
+	Action        string   `json:"action"`
+	Source        string   `json:"source,omitempty"`    // OTTL path to read, e.g. body
+	Target        string   `json:"target,omitempty"`    // attribute to write results to
+	Attribute     string   `json:"attribute,omitempty"` // attribute the action applies to
+	From          string   `json:"from,omitempty"`
+	To            string   `json:"to,omitempty"`
+	Value         string   `json:"value,omitempty"`
+	Pattern       string   `json:"pattern,omitempty"`
+	Replacement   string   `json:"replacement,omitempty"`
+	Delimiter     string   `json:"delimiter,omitempty"`
+	PairDelimiter string   `json:"pair_delimiter,omitempty"`
+	Limit         int      `json:"limit,omitempty"`
+	Factor        float64  `json:"factor,omitempty"`
+	Unit          string   `json:"unit,omitempty"`
+	Function      string   `json:"function,omitempty"`
+	Attributes    []string `json:"attributes,omitempty"`
+	Metric        string   `json:"metric,omitempty"` // restrict metric actions to this metric name
+	Where         string   `json:"where,omitempty"`  // extra OTTL condition
+}
+
+// ottlAction describes a generator action.
+type ottlAction struct {
+	signals     []string
+	context     func(signal string) ottl.Context
+	generate    func(s *ottlSpec) ([]string, error)
+	description string
+}
+
+// ottlActions is the catalog of supported generator actions.
+var ottlActions = map[string]ottlAction{
+	"parse_json": {
+		signals: []string{"logs"}, context: signalContext, generate: genParseJSON,
+		description: "Parse a JSON string (default: body) and merge its fields into attributes, or into target",
+	},
+	"parse_regex": {
+		signals: []string{"logs"}, context: signalContext, generate: genParseRegex,
+		description: "Extract the named capture groups of pattern from source into attributes",
+	},
+	"parse_key_value": {
+		signals: []string{"logs"}, context: signalContext, generate: genParseKeyValue,
+		description: "Parse key=value pairs (delimiter, pair_delimiter) from source into attributes",
+	},
+	"map_severity": {
+		signals: []string{"logs"}, context: signalContext, generate: genMapSeverity,
+		description: "Set severity_text and severity_number from a level attribute (source, default attributes[\"level\"])",
+	},
+	"set_attribute": {
+		signals: allSignals, context: signalContext, generate: genSetAttribute,
+		description: "Set attribute to value",
+	},
+	"delete_attribute": {
+		signals: allSignals, context: signalContext, generate: genDeleteAttribute,
+		description: "Delete attribute",
+	},
+	"rename_attribute": {
+		signals: allSignals, context: signalContext, generate: genRenameAttribute,
+		description: "Rename attribute from -> to",
+	},
+	"hash_attribute": {
+		signals: allSignals, context: signalContext, generate: genHashAttribute,
+		description: "Replace attribute with its SHA256 hash",
+	},
+	"truncate_attributes": {
+		signals: allSignals, context: signalContext, generate: genTruncate,
+		description: "Truncate all attribute values to limit characters (default 256)",
+	},
+	"limit_attributes": {
+		signals: allSignals, context: signalContext, generate: genLimit,
+		description: "Keep at most limit attributes (default 64), preferring attributes",
+	},
+	"normalize_span_name": {
+		signals: []string{"traces"}, context: signalContext, generate: genNormalizeSpanName,
+		description: "Replace IDs in span names (pattern/replacement, default UUIDs and numeric path segments)",
+	},
+	"convert_sum_to_gauge": {
+		signals: []string{"metrics"}, context: metricContext, generate: genMetricEditor("convert_sum_to_gauge()"),
+		description: "Convert a sum metric to a gauge",
+	},
+	"convert_gauge_to_sum": {
+		signals: []string{"metrics"}, context: metricContext, generate: genMetricEditor(`convert_gauge_to_sum("cumulative", false)`),
+		description: "Convert a gauge metric to a cumulative, non-monotonic sum",
+	},
+	"extract_count_metric": {
+		signals: []string{"metrics"}, context: metricContext, generate: genMetricEditor("extract_count_metric(true)"),
+		description: "Create a <metric>_count sum from a histogram or summary",
+	},
+	"extract_sum_metric": {
+		signals: []string{"metrics"}, context: metricContext, generate: genMetricEditor("extract_sum_metric(true)"),
+		description: "Create a <metric>_sum sum from a histogram or summary",
+	},
+	"scale_metric": {
+		signals: []string{"metrics"}, context: metricContext, generate: genScaleMetric,
+		description: "Multiply metric values by factor, optionally setting unit",
+	},
+	"aggregate_on_attributes": {
+		signals: []string{"metrics"}, context: metricContext, generate: genAggregate,
+		description: "Aggregate data points keeping only attributes, with function (default sum)",
+	},
+}
+
+var allSignals = []string{"logs", "traces", "metrics"}
+
+// signalContext returns the record-level context of a signal.
+func signalContext(signal string) ottl.Context {
+	switch signal {
+	case "traces":
+		return ottl.ContextSpan
+	case "metrics":
+		return ottl.ContextDataPoint
+	}
+	return ottl.ContextLog
+}
+
+func metricContext(string) ottl.Context { return ottl.ContextMetric }
+
+// ottlActionNames returns the supported actions, sorted.
+func ottlActionNames() []string {
+	names := make([]string, 0, len(ottlActions))
+	for name := range ottlActions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// generateFromSpec renders the statements of a spec and their context.
+func generateFromSpec(signal string, spec *ottlSpec) ([]string, ottl.Context, error) {
+	action, ok := ottlActions[spec.Action]
+	if !ok {
+		return nil, "", fmt.Errorf("unknown action %q; supported actions: %s", spec.Action, strings.Join(ottlActionNames(), ", "))
+	}
+	if !containsString(action.signals, signal) {
+		return nil, "", fmt.Errorf("action %q does not apply to %s; it supports %s", spec.Action, signal, strings.Join(action.signals, ", "))
+	}
+	statements, err := action.generate(spec)
+	if err != nil {
+		return nil, "", err
+	}
+	if spec.Where != "" {
+		for i, stmt := range statements {
+			statements[i] = addWhere(stmt, spec.Where)
+		}
+	}
+	return statements, action.context(signal), nil
+}
+
+// addWhere adds a condition to a statement, combining it with an existing
+// where clause.
+func addWhere(stmt, cond string) string {
+	if i := whereIndex(stmt); i >= 0 {
+		return fmt.Sprintf("%s where (%s) and (%s)", stmt[:i], stmt[i+len(" where "):], cond)
+	}
+	return stmt + " where " + cond
+}
+
+// whereIndex finds the " where " keyword outside string literals.
+func whereIndex(stmt string) int {
+	inString := false
+	for i := 0; i < len(stmt); i++ {
+		switch {
+		case inString && stmt[i] == '\\':
+			i++
+		case stmt[i] == '"':
+			inString = !inString
+		case !inString && strings.HasPrefix(stmt[i:], " where "):
+			return i
+		}
+	}
+	return -1
+}
+
+// ottlString quotes s as an OTTL string literal.
+func ottlString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func attrPath(key string) string {
+	return "attributes[" + ottlString(key) + "]"
+}
+
+func sourceOr(s *ottlSpec, def string) string {
+	if s.Source != "" {
+		return s.Source
+	}
+	return def
+}
+
+func require(value, field, action string) error {
+	if value == "" {
+		return fmt.Errorf("action %s requires %q", action, field)
+	}
+	return nil
+}
+
+// mergeInto merges a parsed map into attributes, or stores it in target.
+func mergeInto(s *ottlSpec, parsed string) string {
+	if s.Target != "" {
+		return fmt.Sprintf("set(%s, %s)", attrPath(s.Target), parsed)
+	}
+	return fmt.Sprintf(`merge_maps(attributes, %s, "upsert")`, parsed)
+}
+
+func genParseJSON(s *ottlSpec) ([]string, error) {
+	src := sourceOr(s, "body")
+	return []string{mergeInto(s, "ParseJSON("+src+")") + fmt.Sprintf(` where IsMatch(%s, "^\\s*\\{")`, src)}, nil
+}
+
+func genParseRegex(s *ottlSpec) ([]string, error) {
+	if err := require(s.Pattern, "pattern", s.Action); err != nil {
+		return nil, err
+	}
+	re, err := regexp.Compile(s.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %v", err)
+	}
+	named := false
+	for _, name := range re.SubexpNames() {
+		named = named || name != ""
+	}
+	if !named {
+		return nil, fmt.Errorf("pattern must contain named capture groups such as (?P<status>\\d+)")
+	}
+	src := sourceOr(s, "body")
+	return []string{mergeInto(s, fmt.Sprintf("ExtractPatterns(%s, %s)", src, ottlString(s.Pattern))) +
+		fmt.Sprintf(" where IsMatch(%s, %s)", src, ottlString(s.Pattern))}, nil
+}
+
+func genParseKeyValue(s *ottlSpec) ([]string, error) {
+	delim, pair := s.Delimiter, s.PairDelimiter
+	if delim == "" {
+		delim = "="
+	}
+	if pair == "" {
+		pair = " "
+	}
+	src := sourceOr(s, "body")
+	return []string{mergeInto(s, fmt.Sprintf("ParseKeyValue(%s, %s, %s)", src, ottlString(delim), ottlString(pair))) +
+		fmt.Sprintf(" where IsString(%s)", src)}, nil
+}
+
+// severityLevels maps severity numbers to the level names that select them.
+var severityLevels = []struct {
+	number string
+	names  string
+}{
+	{"SEVERITY_NUMBER_TRACE", "trace"},
+	{"SEVERITY_NUMBER_DEBUG", "debug"},
+	{"SEVERITY_NUMBER_INFO", "info|information|notice"},
+	{"SEVERITY_NUMBER_WARN", "warn|warning"},
+	{"SEVERITY_NUMBER_ERROR", "err|error"},
+	{"SEVERITY_NUMBER_FATAL", "fatal|critical|crit|panic|emerg"},
+}
+
+func genMapSeverity(s *ottlSpec) ([]string, error) {
+	src := sourceOr(s, `attributes["level"]`)
+	statements := []string{fmt.Sprintf("set(severity_text, %s) where %s != nil", src, src)}
+	for _, l := range severityLevels {
+		statements = append(statements, fmt.Sprintf("set(severity_number, %s) where IsMatch(severity_text, %s)",
+			l.number, ottlString("(?i)^("+l.names+")$")))
+	}
+	return statements, nil
+}
+
+func genSetAttribute(s *ottlSpec) ([]string, error) {
+	if err := require(s.Attribute, "attribute", s.Action); err != nil {
+		return nil, err
+	}
+	return []string{fmt.Sprintf("set(%s, %s)", attrPath(s.Attribute), ottlString(s.Value))}, nil
+}
+
+func genDeleteAttribute(s *ottlSpec) ([]string, error) {
+	if err := require(s.Attribute, "attribute", s.Action); err != nil {
+		return nil, err
+	}
+	return []string{fmt.Sprintf("delete_key(attributes, %s)", ottlString(s.Attribute))}, nil
+}
+
+func genRenameAttribute(s *ottlSpec) ([]string, error) {
+	if err := require(s.From, "from", s.Action); err != nil {
+		return nil, err
+	}
+	if err := require(s.To, "to", s.Action); err != nil {
+		return nil, err
+	}
+	return []string{
+		fmt.Sprintf("set(%s, %s) where %s != nil", attrPath(s.To), attrPath(s.From), attrPath(s.From)),
+		fmt.Sprintf("delete_key(attributes, %s)", ottlString(s.From)),
+	}, nil
+}
+
+func genHashAttribute(s *ottlSpec) ([]string, error) {
+	if err := require(s.Attribute, "attribute", s.Action); err != nil {
+		return nil, err
+	}
+	p := attrPath(s.Attribute)
+	return []string{fmt.Sprintf("set(%s, SHA256(%s)) where %s != nil", p, p, p)}, nil
+}
+
+func genTruncate(s *ottlSpec) ([]string, error) {
+	limit := s.Limit
+	if limit <= 0 {
+		limit = 256
+	}
+	return []string{fmt.Sprintf("truncate_all(attributes, %d)", limit)}, nil
+}
+
+func genLimit(s *ottlSpec) ([]string, error) {
+	limit := s.Limit
+	if limit <= 0 {
+		limit = 64
+	}
+	keys := make([]string, len(s.Attributes))
+	for i, a := range s.Attributes {
+		keys[i] = ottlString(a)
+	}
+	return []string{fmt.Sprintf("limit(attributes, %d, [%s])", limit, strings.Join(keys, ", "))}, nil
+}
+
+func genNormalizeSpanName(s *ottlSpec) ([]string, error) {
+	if s.Pattern != "" {
+		if _, err := regexp.Compile(s.Pattern); err != nil {
+			return nil, fmt.Errorf("invalid pattern: %v", err)
+		}
+		replacement := s.Replacement
+		if replacement == "" {
+			replacement = "{id}"
+		}
+		return []string{fmt.Sprintf("replace_pattern(name, %s, %s)", ottlString(s.Pattern), ottlString(replacement))}, nil
+	}
+	return []string{
+		fmt.Sprintf("replace_pattern(name, %s, %s)", ottlString(`/[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`), ottlString("/{uuid}")),
+		fmt.Sprintf("replace_pattern(name, %s, %s)", ottlString(`/\d+`), ottlString("/{id}")),
+	}, nil
+}
+
+// genMetricEditor returns a generator for a metric-context editor, scoped to
+// spec.Metric when set.
+func genMetricEditor(call string) func(*ottlSpec) ([]string, error) {
+	return func(s *ottlSpec) ([]string, error) {
+		return []string{metricScoped(call, s)}, nil
+	}
+}
+
+func metricScoped(call string, s *ottlSpec) string {
+	if s.Metric == "" {
+		return call
+	}
+	return fmt.Sprintf("%s where name == %s", call, ottlString(s.Metric))
+}
+
+func genScaleMetric(s *ottlSpec) ([]string, error) {
+	if s.Factor == 0 {
+		return nil, fmt.Errorf("action %s requires a non-zero \"factor\"", s.Action)
+	}
+	factor := strconv.FormatFloat(s.Factor, 'f', -1, 64)
+	if !strings.Contains(factor, ".") {
+		factor += ".0"
+	}
+	call := fmt.Sprintf("scale_metric(%s)", factor)
+	if s.Unit != "" {
+		call = fmt.Sprintf("scale_metric(%s, %s)", factor, ottlString(s.Unit))
+	}
+	return []string{metricScoped(call, s)}, nil
+}
+
+func genAggregate(s *ottlSpec) ([]string, error) {
+	function := s.Function
+	if function == "" {
+		function = "sum"
+	}
+	keys := make([]string, len(s.Attributes))
+	for i, a := range s.Attributes {
+		keys[i] = ottlString(a)
+	}
+	return []string{metricScoped(fmt.Sprintf("aggregate_on_attributes(%s, [%s])", ottlString(function), strings.Join(keys, ", ")), s)}, nil
+}
+
+// Free-text operation patterns. They run against the original text so
+// attribute names keep their case.
+var (
+	renamePattern = regexp.MustCompile(`(?i)rename\s+(?:the\s+)?(?:attribute\s+|label\s+)?([\w.\-/]+)\s+(?:to|as|into)\s+([\w.\-/]+)`)
+	targetPattern = regexp.MustCompile(`(?i)(?:hash|delete|remove|drop|redact)\s+(?:the\s+)?(?:attribute\s+|label\s+|key\s+)?([\w.\-/]+)`)
+	numberPattern = regexp.MustCompile(`\b(\d+)\b`)
+	factorPattern = regexp.MustCompile(`(?i)(?:by|factor)\s+([0-9.]+)`)
+	metricPattern = regexp.MustCompile(`(?i)metric\s+([\w.\-/]+)`)
+	setPattern    = regexp.MustCompile(`(?i)(?:add|set)\s+(?:the\s+)?(?:attribute\s+)?([\w.\-/]+)\s*(?:=|to)\s*"?([^"\s]+)"?`)
+)
+
+// specFromText maps a natural-language operation to a spec. The second
+// return value lists placeholders the user should replace.
+func specFromText(signal, operation string) (*ottlSpec, []string) {
+	lower := strings.ToLower(operation)
+	spec := &ottlSpec{}
+	var notes []string
+
+	word := func(re *regexp.Regexp, group int, placeholder string) string {
+		if m := re.FindStringSubmatch(operation); m != nil && !isFillerWord(m[group]) {
+			return m[group]
+		}
+		notes = append(notes, fmt.Sprintf("Replace %s with the real name", placeholder))
+		return placeholder
+	}
+	number := func() int {
+		if m := numberPattern.FindStringSubmatch(operation); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			return n
+		}
+		return 0
+	}
+
+	switch {
+	case signal == "logs" && strings.Contains(lower, "json"):
+		spec.Action = "parse_json"
+	case signal == "logs" && (strings.Contains(lower, "key value") || strings.Contains(lower, "key=value") ||
+		strings.Contains(lower, "key-value") || strings.Contains(lower, "logfmt")):
+		spec.Action = "parse_key_value"
+	case signal == "logs" && (strings.Contains(lower, "regex") || strings.Contains(lower, "extract") || strings.Contains(lower, "pattern")):
+		spec.Action = "parse_regex"
+		spec.Pattern = `(?P<field>pattern)`
+		notes = append(notes, "Replace (?P<field>pattern) with your regex; every named group becomes an attribute")
+	case signal == "logs" && (strings.Contains(lower, "severity") || strings.Contains(lower, "log level")):
+		spec.Action = "map_severity"
+	case strings.Contains(lower, "rename"):
+		spec.Action = "rename_attribute"
+		spec.From = word(renamePattern, 1, "old.name")
+		spec.To = word(renamePattern, 2, "new.name")
+	case strings.Contains(lower, "hash") || strings.Contains(lower, "sha256"):
+		spec.Action = "hash_attribute"
+		spec.Attribute = word(targetPattern, 1, "attribute.to.hash")
+	case strings.Contains(lower, "truncate"):
+		spec.Action = "truncate_attributes"
+		spec.Limit = number()
+	case strings.Contains(lower, "limit"):
+		spec.Action = "limit_attributes"
+		spec.Limit = number()
+	case signal == "traces" && (strings.Contains(lower, "normali") || strings.Contains(lower, "span name")):
+		spec.Action = "normalize_span_name"
+	case signal == "metrics" && strings.Contains(lower, "gauge") && strings.Contains(lower, "sum to"):
+		spec.Action = "convert_sum_to_gauge"
+	case signal == "metrics" && strings.Contains(lower, "gauge"):
+		spec.Action = "convert_gauge_to_sum"
+	case signal == "metrics" && strings.Contains(lower, "scale"):
+		spec.Action = "scale_metric"
+		spec.Factor = 1
+		if m := factorPattern.FindStringSubmatch(operation); m != nil {
+			spec.Factor, _ = strconv.ParseFloat(m[1], 64)
+		}
+		if spec.Factor == 1 {
+			notes = append(notes, "Set the scale factor")
+		}
+	case signal == "metrics" && strings.Contains(lower, "count"):
+		spec.Action = "extract_count_metric"
+	case signal == "metrics" && (strings.Contains(lower, "aggregate") || strings.Contains(lower, "sum")):
+		spec.Action = "aggregate_on_attributes"
+		spec.Attributes = []string{"attribute.to.keep"}
+		notes = append(notes, "Replace attribute.to.keep with the attributes to keep")
+	case strings.Contains(lower, "delete") || strings.Contains(lower, "remove") || strings.Contains(lower, "drop"):
+		spec.Action = "delete_attribute"
+		spec.Attribute = word(targetPattern, 1, "attribute.to.remove")
+	case strings.Contains(lower, "add") || strings.Contains(lower, "set"):
+		spec.Action = "set_attribute"
+		spec.Attribute = word(setPattern, 1, "custom.attribute")
+		spec.Value = "value"
+		if m := setPattern.FindStringSubmatch(operation); m != nil {
+			spec.Value = m[2]
+		}
+	default:
+		spec.Action = "set_attribute"
+		spec.Attribute = "custom.attribute"
+		spec.Value = "value"
+		notes = append(notes, fmt.Sprintf("No action matched %q; pass a spec with one of: %s", operation, strings.Join(ottlActionNames(), ", ")))
+	}
+
+	if ottlActions[spec.Action].context(signal) == ottl.ContextMetric {
+		if m := metricPattern.FindStringSubmatch(operation); m != nil {
+			spec.Metric = m[1]
+		}
+	}
+	return spec, notes
+}
+
+// isFillerWord rejects words a pattern captured that are not names.
+func isFillerWord(w string) bool {
+	switch strings.ToLower(w) {
+	case "attribute", "attributes", "label", "labels", "key", "keys", "the", "a", "an", "to":
+		return true
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
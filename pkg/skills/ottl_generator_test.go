@@ -0,0 +1,162 @@
+package skills
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/ottl"
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateOTTL(t *testing.T) {
+	tests := []struct {
+		name        string
+		signal      string
+		operation   string
+		spec        *ottlSpec
+		wantContext ottl.Context
+		want        []string
+		wantCount   int
+	}{
+		{
+			name:        "parse_json",
+			signal:      "logs",
+			spec:        &ottlSpec{Action: "parse_json"},
+			wantContext: ottl.ContextLog,
+			want:        []string{`merge_maps(attributes, ParseJSON(body), "upsert") where IsMatch(body, "^\\s*\\{")`},
+		},
+		{
+			name:        "parse_regex",
+			signal:      "logs",
+			spec:        &ottlSpec{Action: "parse_regex", Pattern: `(?P<status>\d{3}) (?P<path>\S+)`},
+			wantContext: ottl.ContextLog,
+			want:        []string{`merge_maps(attributes, ExtractPatterns(body, "(?P<status>\\d{3}) (?P<path>\\S+)"), "upsert") where IsMatch(body, "(?P<status>\\d{3}) (?P<path>\\S+)")`},
+		},
+		{
+			name:        "parse_key_value into a target",
+			signal:      "logs",
+			spec:        &ottlSpec{Action: "parse_key_value", Target: "kv", PairDelimiter: ","},
+			wantContext: ottl.ContextLog,
+			want:        []string{`set(attributes["kv"], ParseKeyValue(body, "=", ",")) where IsString(body)`},
+		},
+		{
+			name:        "map_severity",
+			signal:      "logs",
+			spec:        &ottlSpec{Action: "map_severity"},
+			wantContext: ottl.ContextLog,
+			wantCount:   1 + len(severityLevels),
+		},
+		{
+			name:        "rename_attribute",
+			signal:      "traces",
+			spec:        &ottlSpec{Action: "rename_attribute", From: "http.url", To: "url.full"},
+			wantContext: ottl.ContextSpan,
+			want: []string{
+				`set(attributes["url.full"], attributes["http.url"]) where attributes["http.url"] != nil`,
+				`delete_key(attributes, "http.url")`,
+			},
+		},
+		{
+			name:        "hash_attribute",
+			signal:      "logs",
+			spec:        &ottlSpec{Action: "hash_attribute", Attribute: "user.email"},
+			wantContext: ottl.ContextLog,
+			want:        []string{`set(attributes["user.email"], SHA256(attributes["user.email"])) where attributes["user.email"] != nil`},
+		},
+		{
+			name:        "scale_metric",
+			signal:      "metrics",
+			spec:        &ottlSpec{Action: "scale_metric", Factor: 1000, Unit: "ms", Metric: "http.server.duration"},
+			wantContext: ottl.ContextMetric,
+			want:        []string{`scale_metric(1000.0, "ms") where name == "http.server.duration"`},
+		},
+		{
+			name:        "limit_attributes",
+			signal:      "metrics",
+			spec:        &ottlSpec{Action: "limit_attributes", Limit: 10, Attributes: []string{"service.name"}},
+			wantContext: ottl.ContextDataPoint,
+			want:        []string{`limit(attributes, 10, ["service.name"])`},
+		},
+		{
+			name:        "text mode",
+			signal:      "traces",
+			operation:   "rename the attribute http.method to http.request.method",
+			wantContext: ottl.ContextSpan,
+			want: []string{
+				`set(attributes["http.request.method"], attributes["http.method"]) where attributes["http.method"] != nil`,
+				`delete_key(attributes, "http.method")`,
+			},
+		},
+		{
+			name:   "quotes and where keywords in literals are escaped",
+			signal: "logs",
+			spec: &ottlSpec{
+				Action: "set_attribute", Attribute: `team's "owner"`, Value: `say "hi" \ now`,
+				Where: `attributes["note"] == "a where b"`,
+			},
+			wantContext: ottl.ContextLog,
+			want:        []string{`set(attributes["team's \"owner\""], "say \"hi\" \\ now") where attributes["note"] == "a where b"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := generateOTTL(tt.signal, tt.operation, tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rec := result.Recommendation.(map[string]interface{})
+			statements := rec["statements"].([]string)
+			if ctx := ottl.Context(rec["context"].(string)); ctx != tt.wantContext {
+				t.Errorf("expected context %s, got %s", tt.wantContext, ctx)
+			}
+			if tt.want != nil && strings.Join(statements, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("expected:\n%s\ngot:\n%s", strings.Join(tt.want, "\n"), strings.Join(statements, "\n"))
+			}
+			if tt.wantCount > 0 && len(statements) != tt.wantCount {
+				t.Errorf("expected %d statements, got %d", tt.wantCount, len(statements))
+			}
+			for _, stmt := range statements {
+				if problems := ottl.CheckStatement(tt.wantContext, stmt); len(problems) > 0 {
+					t.Errorf("statement %q fails validation: %+v", stmt, problems)
+				}
+			}
+
+			// The config snippet must carry the statements through YAML unchanged
+			var doc struct {
+				Processors map[string]map[string][]struct {
+					Statements []string `yaml:"statements"`
+				} `yaml:"processors"`
+			}
+			if err := yaml.Unmarshal([]byte(result.ConfigSnippet), &doc); err != nil {
+				t.Fatalf("invalid config snippet: %v\n%s", err, result.ConfigSnippet)
+			}
+			groups := doc.Processors["transform/"+tt.signal+"_transform"][transformStatementKeys[tt.signal]]
+			if len(groups) != 1 || strings.Join(groups[0].Statements, "\n") != strings.Join(statements, "\n") {
+				t.Errorf("config snippet does not round-trip the statements:\n%s", result.ConfigSnippet)
+			}
+		})
+	}
+}
+
+func TestGenerateOTTLErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		signal string
+		spec   *ottlSpec
+	}{
+		{name: "unknown action", signal: "logs", spec: &ottlSpec{Action: "explode"}},
+		{name: "action not valid for the signal", signal: "metrics", spec: &ottlSpec{Action: "parse_json"}},
+		{name: "regex without named groups", signal: "logs", spec: &ottlSpec{Action: "parse_regex", Pattern: `\d+`}},
+		{name: "scale without factor", signal: "metrics", spec: &ottlSpec{Action: "scale_metric"}},
+		{name: "unknown signal", signal: "profiles", spec: &ottlSpec{Action: "set_attribute", Attribute: "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := generateOTTL(tt.signal, "", tt.spec); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package skills
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/analysis"
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/config"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+)
+
+// ConvertConfigSkill migrates Fluent Bit, Prometheus and Jaeger agent
+// configurations to an equivalent collector configuration.
+type ConvertConfigSkill struct {
+	Cfg *config.Config
+}
+
+// configConverters maps each supported source format to its converter.
+var configConverters = map[string]func(content string, c *conversion) error{
+	"fluentbit":    convertFluentBit,
+	"prometheus":   convertPrometheus,
+	"jaeger_agent": convertJaegerAgent,
+}
+
+// defaultDeployModes is the workload a converted config usually runs as,
+// used when linting the output.
+var defaultDeployModes = map[string]collector.DeploymentMode{
+	"fluentbit":    collector.ModeDaemonSet,
+	"prometheus":   collector.ModeStatefulSet,
+	"jaeger_agent": collector.ModeDaemonSet,
+}
+
+func (s *ConvertConfigSkill) Definition() SkillDefinition {
+	return SkillDefinition{
+		Name:        "convert_config",
+		Description: "Convert a Fluent Bit config, a Prometheus prometheus.yml or Jaeger agent flags into an equivalent OpenTelemetry Collector config, listing constructs that have no collector equivalent and linting the result with the configuration analyzers",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"format": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"fluentbit", "prometheus", "jaeger_agent"},
+					"description": "Format of content: fluentbit (classic or YAML), prometheus (prometheus.yml) or jaeger_agent (command-line flags or YAML config file)",
+				},
+				"content": map[string]interface{}{
+					"type":        "string",
+					"description": "The configuration to convert",
+				},
+				"destination": map[string]interface{}{
+					"type":        "string",
+					"description": "OTLP endpoint to export to when the source config has no output with a collector equivalent, e.g. otel-gateway.observability:4317",
+				},
+				"deployment_mode": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"DaemonSet", "Deployment", "StatefulSet"},
+					"description": "Workload the converted collector will run as, used when linting (default: DaemonSet for fluentbit and jaeger_agent, StatefulSet for prometheus)",
+				},
+			},
+			"required": []string{"format", "content"},
+		},
+	}
+}
+
+func (s *ConvertConfigSkill) Execute(ctx context.Context, args map[string]interface{}) (*types.StandardResponse, error) {
+	format, _ := args["format"].(string)
+	content, _ := args["content"].(string)
+	destination, _ := args["destination"].(string)
+	mode, _ := args["deployment_mode"].(string)
+
+	if _, ok := configConverters[format]; !ok {
+		return nil, types.NewMCPError(types.ErrCodeInvalidArgument, fmt.Sprintf("unknown format %q; supported formats: fluentbit, prometheus, jaeger_agent", format))
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, types.NewMCPError(types.ErrCodeInvalidArgument, "content is required")
+	}
+
+	result, err := convertConfig(ctx, format, content, destination, collector.DeploymentMode(mode))
+	if err != nil {
+		return nil, types.NewMCPError(types.ErrCodeConfigParseFailed, err.Error())
+	}
+
+	meta := s.Cfg.ClusterMetadata()
+	return types.NewStandardResponse(meta, "convert_config", result), nil
+}
+
+// unmappedConstruct is a source construct with no collector equivalent.
+type unmappedConstruct struct {
+	Construct string `json:"construct"`
+	Reason    string `json:"reason"`
+}
+
+// conversion accumulates the collector config built from a source config.
+type conversion struct {
+	cfg         *collector.CollectorConfig
+	destination string
+	unmapped    []unmappedConstruct
+	notes       []string
+}
+
+func newConversion(destination string) *conversion {
+	return &conversion{
+		cfg: &collector.CollectorConfig{
+			Receivers:  map[string]interface{}{},
+			Processors: map[string]interface{}{},
+			Exporters:  map[string]interface{}{},
+			Extensions: map[string]interface{}{},
+			Service:    collector.ServiceConfig{Pipelines: map[string]collector.PipelineConfig{}},
+		},
+		destination: destination,
+	}
+}
+
+func (c *conversion) unmap(construct, reason string) {
+	c.unmapped = append(c.unmapped, unmappedConstruct{Construct: construct, Reason: reason})
+}
+
+func (c *conversion) note(format string, args ...interface{}) {
+	c.notes = append(c.notes, fmt.Sprintf(format, args...))
+}
+
+// componentID returns a free component ID of the given type, using name as
+// the ID suffix when set.
+func componentID(section map[string]interface{}, typ, name string) string {
+	id := typ
+	if name != "" {
+		id = typ + "/" + name
+	}
+	if _, taken := section[id]; !taken {
+		return id
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s/%d", id, i)
+		if _, taken := section[candidate]; !taken {
+			return candidate
+		}
+	}
+}
+
+// destinationExporter adds the OTLP exporter used when the source config has
+// no mappable output, and returns its ID.
+func (c *conversion) destinationExporter() string {
+	if _, ok := c.cfg.Exporters["otlp"]; ok {
+		return "otlp"
+	}
+	endpoint := c.destination
+	if endpoint == "" {
+		endpoint = "<configure-endpoint>:4317"
+		c.note("No destination was given; set the endpoint of the otlp exporter")
+	}
+	c.cfg.Exporters["otlp"] = map[string]interface{}{"endpoint": endpoint}
+	return "otlp"
+}
+
+// addPipeline adds a pipeline wrapping processors with memory_limiter and
+// batch, which every converted pipeline gets.
+func (c *conversion) addPipeline(signal string, receivers, processors, exporters []string) string {
+	if _, ok := c.cfg.Processors["memory_limiter"]; !ok {
+		c.cfg.Processors["memory_limiter"] = map[string]interface{}{
+			"check_interval":         "1s",
+			"limit_percentage":       80,
+			"spike_limit_percentage": 25,
+		}
+		c.cfg.Processors["batch"] = map[string]interface{}{}
+	}
+	id := signal
+	for i := 2; ; i++ {
+		if _, taken := c.cfg.Service.Pipelines[id]; !taken {
+			break
+		}
+		id = fmt.Sprintf("%s/%d", signal, i)
+	}
+	all := append([]string{"memory_limiter"}, processors...)
+	c.cfg.Service.Pipelines[id] = collector.PipelineConfig{
+		Receivers:  receivers,
+		Processors: append(all, "batch"),
+		Exporters:  exporters,
+	}
+	return id
+}
+
+// resilientExporters are the push exporter types that get retries and a
+// sending queue, replacing the retries and buffering of the source agent.
+// elasticsearch retries through retry and prometheusremotewrite queues through
+// remote_write_queue; analysis.ExporterResilienceKeys names the settings.
+var resilientExporters = map[string]bool{
+	"otlp":                  true,
+	"otlphttp":              true,
+	"kafka":                 true,
+	"splunk_hec":            true,
+	"elasticsearch":         true,
+	"prometheusremotewrite": true,
+}
+
+func (c *conversion) addResilience() {
+	for id, exporter := range c.cfg.Exporters {
+		cfg, isMap := exporter.(map[string]interface{})
+		if !resilientExporters[strings.SplitN(id, "/", 2)[0]] || !isMap {
+			continue
+		}
+		retry, queue := analysis.ExporterResilienceKeys(id)
+		cfg[retry] = map[string]interface{}{"enabled": true}
+		cfg[queue] = map[string]interface{}{"enabled": true}
+	}
+}
+
+// convertConfig converts content in the given format, renders the collector
+// config and lints it with the configuration analyzers.
+func convertConfig(ctx context.Context, format, content, destination string, mode collector.DeploymentMode) (*SkillResult, error) {
+	convert, ok := configConverters[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q; supported formats: fluentbit, prometheus, jaeger_agent", format)
+	}
+
+	c := newConversion(destination)
+	if err := convert(content, c); err != nil {
+		return nil, fmt.Errorf("parsing %s config: %w", format, err)
+	}
+	if len(c.cfg.Service.Pipelines) == 0 {
+		return nil, fmt.Errorf("no %s input could be converted to a collector receiver", format)
+	}
+	c.addResilience()
+	for _, id := range sortedKeys(c.cfg.Extensions) {
+		c.cfg.Service.Extensions = append(c.cfg.Service.Extensions, id)
+	}
+
+	rendered, err := renderCollectorConfig(c.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if mode == "" {
+		mode = defaultDeployModes[format]
+	}
+	findings := lintConfig(ctx, rendered, mode)
+
+	unmapped := c.unmapped
+	if unmapped == nil {
+		unmapped = []unmappedConstruct{}
+	}
+	return &SkillResult{
+		Skill: "convert_config",
+		Recommendation: map[string]interface{}{
+			"format":         format,
+			"deploymentMode": string(mode),
+			"unmapped":       unmapped,
+			"notes":          c.notes,
+			"lint":           findings,
+		},
+		ConfigSnippet: rendered,
+	}, nil
+}
+
+// renderCollectorConfig marshals the config in the conventional section order.
+func renderCollectorConfig(cfg *collector.CollectorConfig) (string, error) {
+	var b strings.Builder
+	sections := []struct {
+		name       string
+		components map[string]interface{}
+	}{
+		{"extensions", cfg.Extensions},
+		{"receivers", cfg.Receivers},
+		{"processors", cfg.Processors},
+		{"exporters", cfg.Exporters},
+	}
+	for _, sec := range sections {
+		if len(sec.components) == 0 {
+			continue
+		}
+		data, err := yaml.Marshal(map[string]interface{}{sec.name: sec.components})
+		if err != nil {
+			return "", fmt.Errorf("rendering %s: %w", sec.name, err)
+		}
+		b.Write(data)
+		b.WriteString("\n")
+	}
+
+	service := map[string]interface{}{"pipelines": cfg.Service.Pipelines}
+	if len(cfg.Service.Extensions) > 0 {
+		service["extensions"] = cfg.Service.Extensions
+	}
+	data, err := yaml.Marshal(map[string]interface{}{"service": service})
+	if err != nil {
+		return "", fmt.Errorf("rendering service: %w", err)
+	}
+	b.Write(data)
+	return b.String(), nil
+}
+
+// lintConfig re-parses the rendered config, so analyzers see exactly what the
+// collector would load, and runs the configuration analyzers on it.
+func lintConfig(ctx context.Context, rendered string, mode collector.DeploymentMode) []types.DiagnosticFinding {
+	cfg, err := collector.ParseConfig([]byte(rendered))
+	if err != nil {
+		return []types.DiagnosticFinding{{
+			Severity: types.SeverityCritical,
+			Category: types.CategoryConfig,
+			Summary:  "Converted config does not parse",
+			Detail:   err.Error(),
+		}}
+	}
+
+	input := &analysis.AnalysisInput{Config: cfg, DeployMode: mode}
+	findings := []types.DiagnosticFinding{}
+	for _, analyzer := range analysis.AllAnalyzers() {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					slog.Error("analyzer panicked while linting converted config", "error", r)
+				}
+			}()
+			findings = append(findings, analyzer(ctx, input)...)
+		}()
+	}
+	return findings
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
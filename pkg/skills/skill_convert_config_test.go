@@ -0,0 +1,209 @@
+package skills
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+)
+
+func TestConvertConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		format       string
+		content      string
+		destination  string
+		wantSnippet  []string
+		wantUnmapped []string
+	}{
+		{
+			name:   "fluentbit classic tail to elasticsearch",
+			format: "fluentbit",
+			content: `[INPUT]
+    Name tail
+    Path /var/log/containers/*.log
+    Tag  kube.*
+    multiline.parser cri
+
+[FILTER]
+    Name  kubernetes
+    Match kube.*
+
+[FILTER]
+    Name   modify
+    Match  kube.*
+    Rename log message
+    Add    cluster prod
+
+[FILTER]
+    Name    grep
+    Match   kube.*
+    Exclude level debug
+
+[OUTPUT]
+    Name  es
+    Match kube.*
+    Host  es.logging
+    Port  9200
+    Index app-logs
+`,
+			wantSnippet: []string{
+				"filelog:",
+				"- type: container",
+				"k8sattributes:",
+				`- set(attributes["message"], attributes["log"]) where attributes["log"] != nil`,
+				`- IsMatch(attributes["level"], "debug")`,
+				"elasticsearch:",
+				"- http://es.logging:9200",
+				"logs_index: app-logs",
+				"retry:\n            enabled: true",
+				"sending_queue:\n            enabled: true",
+			},
+		},
+		{
+			name:   "fluentbit YAML systemd and node metrics",
+			format: "fluentbit",
+			content: `pipeline:
+  inputs:
+    - name: systemd
+      tag: host.*
+    - name: node_exporter_metrics
+      tag: node
+  outputs:
+    - name: prometheus_remote_write
+      match: node
+      host: mimir.monitoring
+      port: 9009
+      uri: /api/v1/push
+    - name: loki
+      match: host.*
+      host: loki.logging
+      tenant_id: ops
+`,
+			wantSnippet: []string{
+				"journald:",
+				"hostmetrics:",
+				"otlphttp/loki:",
+				"endpoint: http://loki.logging:3100/otlp",
+				"X-Scope-OrgID: ops",
+				"prometheusremotewrite:",
+				"endpoint: http://mimir.monitoring:9009/api/v1/push",
+				"remote_write_queue:\n            enabled: true",
+			},
+		},
+		{
+			name:   "prometheus scrape configs and remote_write",
+			format: "prometheus",
+			content: `global:
+  scrape_interval: 30s
+  evaluation_interval: 30s
+  external_labels:
+    cluster: prod
+rule_files: [rules.yml]
+scrape_configs:
+  - job_name: pods
+    kubernetes_sd_configs:
+      - role: pod
+    relabel_configs:
+      - source_labels: [__meta_kubernetes_pod_name]
+        target_label: pod
+        replacement: $1
+remote_write:
+  - url: https://mimir.example.com/api/v1/push
+    name: mimir
+    queue_config:
+      capacity: 10000
+`,
+			wantSnippet: []string{
+				"prometheus:",
+				"scrape_interval: 30s",
+				"replacement: $$1",
+				"prometheusremotewrite/mimir:",
+				"cluster: prod",
+				"remote_write_queue:\n            enabled: true",
+				"retry_on_failure:\n            enabled: true",
+			},
+			wantUnmapped: []string{"rule_files", "global.evaluation_interval", "remote_write[0].queue_config"},
+		},
+		{
+			name:        "prometheus without remote_write exports to the destination",
+			format:      "prometheus",
+			content:     "scrape_configs:\n  - job_name: self\n    static_configs:\n      - targets: [localhost:9090]\n",
+			destination: "gateway.obs:4317",
+			wantSnippet: []string{"otlp:\n        endpoint: gateway.obs:4317", "sending_queue:\n            enabled: true"},
+		},
+		{
+			name:   "jaeger agent flags",
+			format: "jaeger_agent",
+			content: `--reporter.grpc.host-port=jaeger-collector.tracing:14250
+--processor.jaeger-compact.server-queue-size=2000
+--agent.tags=cluster=prod,zone=${ZONE:eu-1}
+--log-level=debug
+`,
+			destination: "gateway.obs:4317",
+			wantSnippet: []string{
+				"jaeger:",
+				"queue_size: 2000",
+				"endpoint: 0.0.0.0:6832",
+				"resource/agent_tags:",
+				"value: ${env:ZONE:-eu-1}",
+				"jaegerremotesampling:",
+				"endpoint: jaeger-collector.tracing:14250",
+				"endpoint: gateway.obs:4317",
+			},
+			wantUnmapped: []string{"log-level"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := convertConfig(context.Background(), tt.format, tt.content, tt.destination, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.wantSnippet {
+				if !strings.Contains(result.ConfigSnippet, want) {
+					t.Errorf("expected the config to contain %q:\n%s", want, result.ConfigSnippet)
+				}
+			}
+
+			rec := result.Recommendation.(map[string]interface{})
+			var unmapped []string
+			for _, u := range rec["unmapped"].([]unmappedConstruct) {
+				unmapped = append(unmapped, u.Construct)
+			}
+			if strings.Join(unmapped, ",") != strings.Join(tt.wantUnmapped, ",") {
+				t.Errorf("expected unmapped %v, got %v", tt.wantUnmapped, unmapped)
+			}
+
+			// The converter's own output passes its resilience lint
+			for _, f := range rec["lint"].([]types.DiagnosticFinding) {
+				if f.RuleID == types.RuleMissingRetry || f.RuleID == types.RuleMissingQueue {
+					t.Errorf("unexpected lint finding %s: %s", f.RuleID, f.Summary)
+				}
+			}
+		})
+	}
+}
+
+func TestConvertConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		content string
+	}{
+		{name: "unknown format", format: "logstash", content: "input {}"},
+		{name: "prometheus without scrape configs", format: "prometheus", content: "global:\n  scrape_interval: 15s\n"},
+		{name: "prometheus YAML error", format: "prometheus", content: "scrape_configs: ["},
+		{name: "fluentbit without inputs", format: "fluentbit", content: "[OUTPUT]\n    Name stdout\n    Match *\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := convertConfig(context.Background(), tt.format, tt.content, "", ""); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
@@ -2,10 +2,12 @@ package skills
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/hrexed/otel-collector-mcp/pkg/config"
+	"github.com/hrexed/otel-collector-mcp/pkg/ottl"
 	"github.com/hrexed/otel-collector-mcp/pkg/types"
 )
 
@@ -17,7 +19,7 @@ type OTTLSkill struct {
 func (s *OTTLSkill) Definition() SkillDefinition {
 	return SkillDefinition{
 		Name:        "generate_ottl",
-		Description: "Generate OTTL transform processor statements for log parsing, span manipulation, or metric operations",
+		Description: "Generate validated OTTL transform processor statements for log parsing, severity mapping, attribute rename/hash/truncate/limit, span name normalisation, or metric conversions, from a structured spec or a natural-language description",
 		Parameters: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -28,123 +30,150 @@ func (s *OTTLSkill) Definition() SkillDefinition {
 				},
 				"operation": map[string]interface{}{
 					"type":        "string",
-					"description": "Natural language description of the desired transformation",
+					"description": "Natural language description of the desired transformation, used when spec is not given",
 				},
+				"spec": ottlSpecSchema(),
 			},
-			"required": []string{"signal_type", "operation"},
+			"required": []string{"signal_type"},
 		},
 	}
 }
 
+// ottlSpecSchema describes the spec argument, listing every action.
+func ottlSpecSchema() map[string]interface{} {
+	var actions []string
+	for _, name := range ottlActionNames() {
+		actions = append(actions, fmt.Sprintf("%s: %s", name, ottlActions[name].description))
+	}
+	str := func(desc string) map[string]interface{} {
+		return map[string]interface{}{"type": "string", "description": desc}
+	}
+	return map[string]interface{}{
+		"type":        "object",
+		"description": "Structured transformation. Actions:\n" + strings.Join(actions, "\n"),
+		"properties": map[string]interface{}{
+			"action":         map[string]interface{}{"type": "string", "enum": ottlActionNames()},
+			"source":         str("OTTL path to read, e.g. body or attributes[\"message\"]"),
+			"target":         str("Attribute to store parsed results in instead of merging into attributes"),
+			"attribute":      str("Attribute the action applies to"),
+			"from":           str("Attribute to rename"),
+			"to":             str("New attribute name"),
+			"value":          str("Value for set_attribute"),
+			"pattern":        str("Regex; parse_regex needs named capture groups"),
+			"replacement":    str("Replacement for normalize_span_name"),
+			"delimiter":      str("Key-value delimiter (default =)"),
+			"pair_delimiter": str("Pair delimiter (default space)"),
+			"limit":          map[string]interface{}{"type": "integer", "description": "Length for truncate_attributes, count for limit_attributes"},
+			"factor":         map[string]interface{}{"type": "number", "description": "Multiplier for scale_metric"},
+			"unit":           str("New unit for scale_metric"),
+			"function":       str("Aggregation function for aggregate_on_attributes (sum, mean, min, max, count, median)"),
+			"attributes": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+				"description": "Attributes to keep (aggregate_on_attributes) or prefer (limit_attributes)",
+			},
+			"metric": str("Restrict metric actions to this metric name"),
+			"where":  str("Extra OTTL condition added to every statement"),
+		},
+		"required": []string{"action"},
+	}
+}
+
 func (s *OTTLSkill) Execute(_ context.Context, args map[string]interface{}) (*types.StandardResponse, error) {
 	signalType, _ := args["signal_type"].(string)
 	operation, _ := args["operation"].(string)
 
-	result := generateOTTL(signalType, operation)
+	var spec *ottlSpec
+	if raw, ok := args["spec"].(map[string]interface{}); ok {
+		spec = &ottlSpec{}
+		data, _ := json.Marshal(raw)
+		if err := json.Unmarshal(data, spec); err != nil {
+			return nil, types.NewMCPError(types.ErrCodeInvalidArgument, fmt.Sprintf("invalid spec: %v", err))
+		}
+	}
+	if spec == nil && operation == "" {
+		return nil, types.NewMCPError(types.ErrCodeInvalidArgument, "provide an operation or a spec")
+	}
+
+	result, err := generateOTTL(signalType, operation, spec)
+	if err != nil {
+		return nil, types.NewMCPError(types.ErrCodeInvalidArgument, err.Error())
+	}
 
 	meta := s.Cfg.ClusterMetadata()
 	return types.NewStandardResponse(meta, "generate_ottl", result), nil
 }
 
-func generateOTTL(signalType, operation string) *SkillResult {
-	var statements []string
-	var contextStr string
-
-	lower := strings.ToLower(operation)
-
-	switch signalType {
-	case "logs":
-		contextStr = "log"
-		statements = generateLogOTTL(lower)
-	case "traces":
-		contextStr = "span"
-		statements = generateTraceOTTL(lower)
-	case "metrics":
-		contextStr = "datapoint"
-		statements = generateMetricOTTL(lower)
+// generateOTTL renders a spec, or one derived from the operation text, into
+// validated statements and a transform processor config.
+func generateOTTL(signalType, operation string, spec *ottlSpec) (*SkillResult, error) {
+	if _, ok := transformStatementKeys[signalType]; !ok {
+		return nil, fmt.Errorf("unknown signal_type %q", signalType)
 	}
 
-	configBlock := buildTransformConfig(signalType, contextStr, statements)
+	var notes []string
+	if spec == nil {
+		spec, notes = specFromText(signalType, operation)
+	}
+
+	statements, ctx, err := generateFromSpec(signalType, spec)
+	if err != nil {
+		return nil, err
+	}
 
 	return &SkillResult{
 		Skill: "generate_ottl",
 		Recommendation: map[string]interface{}{
 			"signalType": signalType,
 			"operation":  operation,
+			"spec":       spec,
 			"statements": statements,
-			"context":    contextStr,
+			"context":    string(ctx),
+			"validation": validateStatements(ctx, statements),
+			"notes":      notes,
 		},
-		ConfigSnippet: configBlock,
-	}
+		ConfigSnippet: buildTransformConfig(signalType, string(ctx), statements),
+	}, nil
 }
 
-func generateLogOTTL(operation string) []string {
-	var statements []string
-
-	switch {
-	case strings.Contains(operation, "parse json") || strings.Contains(operation, "json parse"):
-		statements = append(statements, `merge_maps(cache, ParseJSON(body), "insert")`)
-		statements = append(statements, `set(attributes["parsed"], cache)`)
-	case strings.Contains(operation, "severity") || strings.Contains(operation, "log level"):
-		statements = append(statements, `set(severity_text, attributes["level"]) where attributes["level"] != nil`)
-	case strings.Contains(operation, "extract") || strings.Contains(operation, "regex"):
-		statements = append(statements, `# Replace <pattern> with your regex:`)
-		statements = append(statements, `merge_maps(attributes, ExtractPatterns(body, "(?P<field>pattern)"), "insert")`)
-	default:
-		statements = append(statements, fmt.Sprintf(`# Custom log transform for: %s`, operation))
-		statements = append(statements, `set(attributes["custom_field"], "value") where body != nil`)
-	}
-
-	return statements
+// statementValidation reports the problems found in one generated statement.
+type statementValidation struct {
+	Statement string         `json:"statement"`
+	Problems  []ottl.Problem `json:"problems"`
 }
 
-func generateTraceOTTL(operation string) []string {
-	var statements []string
-
-	switch {
-	case strings.Contains(operation, "add attribute") || strings.Contains(operation, "set attribute"):
-		statements = append(statements, `set(attributes["custom.attribute"], "value")`)
-	case strings.Contains(operation, "delete") || strings.Contains(operation, "remove"):
-		statements = append(statements, `delete_key(attributes, "attribute.to.remove")`)
-	case strings.Contains(operation, "rename"):
-		statements = append(statements, `set(attributes["new.name"], attributes["old.name"]) where attributes["old.name"] != nil`)
-		statements = append(statements, `delete_key(attributes, "old.name")`)
-	default:
-		statements = append(statements, fmt.Sprintf(`# Custom span transform for: %s`, operation))
-		statements = append(statements, `set(attributes["custom"], "value")`)
+// validateStatements runs every generated statement through the OTTL
+// validator.
+func validateStatements(ctx ottl.Context, statements []string) map[string]interface{} {
+	invalid := []statementValidation{}
+	for _, stmt := range statements {
+		if problems := ottl.CheckStatement(ctx, stmt); len(problems) > 0 {
+			invalid = append(invalid, statementValidation{Statement: stmt, Problems: problems})
+		}
 	}
-
-	return statements
-}
-
-func generateMetricOTTL(operation string) []string {
-	var statements []string
-
-	switch {
-	case strings.Contains(operation, "rename") || strings.Contains(operation, "label"):
-		statements = append(statements, `set(attributes["new_label"], attributes["old_label"]) where attributes["old_label"] != nil`)
-		statements = append(statements, `delete_key(attributes, "old_label")`)
-	case strings.Contains(operation, "drop") || strings.Contains(operation, "delete"):
-		statements = append(statements, `delete_key(attributes, "high_cardinality_label")`)
-	case strings.Contains(operation, "aggregate") || strings.Contains(operation, "sum"):
-		statements = append(statements, `# Use metricstransform processor for aggregation instead of OTTL`)
-	default:
-		statements = append(statements, fmt.Sprintf(`# Custom metric transform for: %s`, operation))
-		statements = append(statements, `set(attributes["custom"], "value")`)
+	return map[string]interface{}{
+		"valid":   len(invalid) == 0,
+		"invalid": invalid,
 	}
+}
 
-	return statements
+// transformStatementKeys maps signal types to transform processor keys.
+var transformStatementKeys = map[string]string{
+	"logs":    "log_statements",
+	"traces":  "trace_statements",
+	"metrics": "metric_statements",
 }
 
 func buildTransformConfig(signalType, context string, statements []string) string {
 	var b strings.Builder
 	b.WriteString("processors:\n")
 	fmt.Fprintf(&b, "  transform/%s_transform:\n", signalType)
-	fmt.Fprintf(&b, "    %s_statements:\n", context)
+	fmt.Fprintf(&b, "    %s:\n", transformStatementKeys[signalType])
 	b.WriteString("      - context: " + context + "\n")
 	b.WriteString("        statements:\n")
 	for _, stmt := range statements {
-		fmt.Fprintf(&b, "          - '%s'\n", stmt)
+		// Single-quoted YAML escapes a quote by doubling it
+		fmt.Fprintf(&b, "          - '%s'\n", strings.ReplaceAll(stmt, "'", "''"))
 	}
 
 	fmt.Fprintf(&b, "\nservice:\n  pipelines:\n    %s:\n      processors: [transform/%s_transform]\n", signalType, signalType)
@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/fixes"
+	"github.com/hrexed/otel-collector-mcp/pkg/mutator"
+)
+
+// dryRunArgSchema is the input schema of the dry_run argument shared by the
+// mutating tools.
+var dryRunArgSchema = map[string]interface{}{
+	"type":        "boolean",
+	"description": "Return the resulting config, a diff against the live config and a server-side dry-run result without persisting anything",
+}
+
+// Server-side dry-run outcomes.
+const (
+	serverDryRunAccepted = "accepted"
+	serverDryRunRejected = "rejected"
+)
+
+// dryRunResult describes the config a mutating tool would write.
+type dryRunResult struct {
+	ResultingConfig string `json:"resulting_config"`
+	Diff            string `json:"diff"`
+	ServerDryRun    string `json:"server_dry_run"`
+	ServerMessage   string `json:"server_message,omitempty"`
+}
+
+// dryRun diffs resulting against the live config and submits it to the API
+// server as a dry run, so validation and admission webhooks see the exact
+// update without it being persisted.
+func dryRun(ctx context.Context, mut mutator.Mutator, live, resulting string) *dryRunResult {
+	result := &dryRunResult{
+		ResultingConfig: resulting,
+		Diff:            configDiff(live, resulting, "live/config.yaml", "dry-run/config.yaml"),
+		ServerDryRun:    serverDryRunAccepted,
+	}
+
+	if err := mut.DryRunConfig(ctx, resulting); err != nil {
+		result.ServerDryRun = serverDryRunRejected
+		result.ServerMessage = err.Error()
+	}
+	return result
+}
+
+// configDiff renders a unified diff between two collector configs,
+// normalized so that key order and comments do not show up as changes.
+func configDiff(a, b, fromName, toName string) string {
+	from, err := fixes.NormalizeConfig(a)
+	if err != nil {
+		from = a
+	}
+	to, err := fixes.NormalizeConfig(b)
+	if err != nil {
+		to = b
+	}
+	return fixes.UnifiedDiff(from, to, fromName, toName)
+}
+
+// dryRunRequested reports whether the dry_run argument is set.
+func dryRunRequested(args map[string]interface{}) bool {
+	v, _ := args["dry_run"].(bool)
+	return v
+}
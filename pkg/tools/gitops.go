@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/mutator"
+	"github.com/hrexed/otel-collector-mcp/pkg/session"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+)
+
+// patchCommittedMessage tells the user how a committed patch reaches the collector.
+const patchCommittedMessage = "The change was committed to the GitOps repository and not applied to the cluster. Push the branch and merge it to roll it out through the GitOps controller."
+
+// sessionConfig returns the collector config the session's changes build on:
+// the live config, or in GitOps patch mode the config last committed to the
+// session's branch.
+func sessionConfig(ctx context.Context, sess *session.Session) (string, error) {
+	if sess.PatchMode && sess.PatchedConfig != "" {
+		return sess.PatchedConfig, nil
+	}
+	return sess.Mutator.CurrentConfig(ctx)
+}
+
+// commitPatch validates a config for a collector in GitOps patch mode and
+// commits it to the session's branch of the GitOps repository instead of
+// applying it.
+func (b *BaseTool) commitPatch(ctx context.Context, sess *session.Session, configYAML, summary string, fixIDs []string) (*mutator.Patch, error) {
+	if validator := b.configValidator(sess.Collector); validator != nil {
+		if err := validator.Validate(ctx, configYAML); err != nil {
+			var validationErr *mutator.ValidationError
+			if errors.As(err, &validationErr) {
+				return nil, types.NewMCPError(types.ErrCodeValidationFailed, err.Error())
+			}
+			return nil, types.NewMCPError(types.ErrCodeMutationFailed, "config validation failed: "+err.Error())
+		}
+	}
+
+	message := fmt.Sprintf("%s in collector %s/%s\n\nSession: %s\n", summary, sess.Collector.Namespace, sess.Collector.Name, sess.ID)
+	if len(fixIDs) > 0 {
+		message += "Fixes: " + strings.Join(fixIDs, ", ") + "\n"
+	}
+
+	writer := mutator.NewPatchWriter(b.Cfg.GitOpsRepo, b.Cfg.GitOpsBranchPrefix)
+	patch, err := writer.Write(ctx, sess.Collector, sess.ID, configYAML, message)
+	if err != nil {
+		return nil, types.NewMCPError(types.ErrCodeMutationFailed, err.Error())
+	}
+	sess.PatchedConfig = configYAML
+	return patch, nil
+}
+
+// suspendSync suspends the GitOps sync of a GitOps-managed session collector
+// so that ephemeral changes are not reverted. It reports whether the sync
+// is suspended; the sync of a collector whose Argo CD Application is unknown
+// is left running with a warning.
+func (b *BaseTool) suspendSync(ctx context.Context, sess *session.Session) (bool, error) {
+	if sess.GitOps == nil {
+		return false, nil
+	}
+	if sess.SyncSuspension == nil {
+		sess.SyncSuspension = mutator.NewSyncSuspension(b.Clients.Clientset, b.Clients.DynamicClient, sess.Collector, *sess.GitOps, sess.ID)
+	}
+	err := sess.SyncSuspension.Suspend(ctx)
+	if errors.Is(err, mutator.ErrUnknownApplication) {
+		slog.Warn("gitops sync not suspended", "session_id", sess.ID, "error", err)
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to suspend %s sync: %w", sess.GitOps, err)
+	}
+	return true, nil
+}
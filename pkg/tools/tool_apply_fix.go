@@ -2,15 +2,28 @@ package tools
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
+	"github.com/hrexed/otel-collector-mcp/pkg/analysis"
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
 	"github.com/hrexed/otel-collector-mcp/pkg/fixes"
+	"github.com/hrexed/otel-collector-mcp/pkg/mutator"
 	"github.com/hrexed/otel-collector-mcp/pkg/session"
+	"github.com/hrexed/otel-collector-mcp/pkg/signals"
 	"github.com/hrexed/otel-collector-mcp/pkg/types"
+	"k8s.io/client-go/kubernetes"
 )
 
-// ApplyFixTool applies a single user-approved fix with automatic health checking.
+// defaultVerifySeconds is how long apply_fix captures signals after applying
+// a fix for a runtime finding.
+const defaultVerifySeconds = 30
+
+// ApplyFixTool applies a single user-approved fix with automatic health checking
+// and verifies that the fix resolves its finding.
 type ApplyFixTool struct {
 	BaseTool
 	SessionMgr *session.Manager
@@ -19,7 +32,7 @@ type ApplyFixTool struct {
 func (t *ApplyFixTool) Name() string { return "apply_fix" }
 
 func (t *ApplyFixTool) Description() string {
-	return "Apply a suggested fix to the collector configuration with safety checks and backup."
+	return "Apply a suggested fix to the collector configuration with safety checks and backup. The patched config is re-analysed first and the fix is refused if its finding remains or new critical findings appear; for runtime findings, signals are captured again after the apply to confirm the finding is gone."
 }
 
 func (t *ApplyFixTool) InputSchema() map[string]interface{} {
@@ -28,6 +41,8 @@ func (t *ApplyFixTool) InputSchema() map[string]interface{} {
 		"properties": map[string]interface{}{
 			"session_id":       map[string]interface{}{"type": "string", "description": "Active session ID"},
 			"suggestion_index": map[string]interface{}{"type": "integer", "description": "Index of the fix suggestion to apply"},
+			"verify_seconds":   map[string]interface{}{"type": "integer", "description": "Seconds of signals to capture after applying a fix for a runtime finding (0-120, default 30, 0 skips live verification)"},
+			"dry_run":          dryRunArgSchema,
 		},
 		"required": []string{"session_id", "suggestion_index"},
 	}
@@ -60,18 +75,230 @@ func (t *ApplyFixTool) Run(ctx context.Context, args map[string]interface{}) (*t
 	}
 
 	fix := suggestions[suggestionIdx]
+	if fix.Advisory {
+		return nil, types.NewMCPError(types.ErrCodeMutationFailed,
+			fmt.Sprintf("suggestion %d changes more than the collector config and cannot be applied; carry out its pipeline_changes and manifest by hand", suggestionIdx))
+	}
+	findings, _ := sess.Findings.([]types.DiagnosticFinding)
+	if fix.FindingIndex < 0 || fix.FindingIndex >= len(findings) {
+		return nil, types.NewMCPError(types.ErrCodeMutationFailed, "the finding behind this suggestion is no longer in the session. Run suggest_fixes again.")
+	}
+	finding := findings[fix.FindingIndex]
+
+	verifySeconds := defaultVerifySeconds
+	if v, ok := args["verify_seconds"].(float64); ok {
+		verifySeconds = int(v)
+	}
+	if verifySeconds < 0 || verifySeconds > 120 {
+		return nil, types.NewMCPError(types.ErrCodeInvalidArgument, "verify_seconds must be between 0 and 120")
+	}
+
+	if sess.Mutator == nil {
+		return nil, types.NewMCPError(types.ErrCodeMutationFailed, "no mutator available for this session")
+	}
+	current, err := sessionConfig(ctx, sess)
+	if err != nil {
+		return nil, types.NewMCPError(types.ErrCodeMutationFailed, err.Error())
+	}
+	patched, err := fixes.ApplyToConfig(current, fix)
+	if err != nil {
+		return nil, types.NewMCPError(types.ErrCodeMutationFailed, err.Error())
+	}
+
 	slog.Info("applying fix", "session_id", sessionID, "fix_type", fix.FixType, "index", suggestionIdx)
 
-	// In a full implementation, this would:
-	// 1. Merge the processor config into the collector config
-	// 2. Apply via mutator with safety chain
-	// 3. Health check and auto-rollback if needed
+	// Re-analyse the patched config before touching the collector
+	static := fixes.VerifyStatic(ctx, fixes.VerificationInput(t.verificationBase(ctx, sess), fix), current, patched, finding)
+	static.FindingIndex = fix.FindingIndex
+	verifications := []*fixes.Verification{static}
+
+	if dryRunRequested(args) {
+		return types.NewStandardResponse(t.ClusterMeta(), t.Name(), map[string]interface{}{
+			"session_id":    sessionID,
+			"fix_type":      fix.FixType,
+			"fix_index":     suggestionIdx,
+			"status":        "dry_run",
+			"risk":          fix.Risk,
+			"verifications": verifications,
+			"dry_run":       dryRun(ctx, sess.Mutator, current, patched),
+		}), nil
+	}
+	recordVerification(sess, static)
+
+	if !static.Passed() {
+		return types.NewStandardResponse(t.ClusterMeta(), t.Name(), map[string]interface{}{
+			"session_id":    sessionID,
+			"fix_type":      fix.FixType,
+			"fix_index":     suggestionIdx,
+			"status":        "verification_failed",
+			"risk":          fix.Risk,
+			"verifications": verifications,
+		}), nil
+	}
+
+	// GitOps-managed collectors in patch mode get the fix as a commit to
+	// the GitOps repository; live verification waits for the rollout
+	if sess.PatchMode {
+		patch, err := t.commitPatch(ctx, sess, patched, fix.Description, []string{fixID(suggestionIdx, finding)})
+		if err != nil {
+			return nil, err
+		}
+		return types.NewStandardResponse(t.ClusterMeta(), t.Name(), map[string]interface{}{
+			"session_id":    sessionID,
+			"fix_type":      fix.FixType,
+			"fix_index":     suggestionIdx,
+			"status":        "patch_committed",
+			"risk":          fix.Risk,
+			"message":       patchCommittedMessage,
+			"patch":         patch,
+			"verifications": verifications,
+		}), nil
+	}
+
+	result := mutator.SafeApply(ctx, sess.Mutator, t.Clients.Clientset, sess.Collector, sess.ID, patched, t.SafeApplyOptions(sess.Collector, current, patched, mutator.Revision{
+		FixIDs:      []string{fixID(suggestionIdx, finding)},
+		Description: fix.Description,
+	}))
+	if result.Error != nil {
+		return nil, safeApplyError(result)
+	}
+
+	// Runtime findings are confirmed on signals captured after the apply
+	if strings.HasPrefix(finding.RuleID, "runtime.") && verifySeconds > 0 {
+		live := t.verifyLive(ctx, sess, patched, finding, time.Duration(verifySeconds)*time.Second)
+		live.FindingIndex = fix.FindingIndex
+		recordVerification(sess, live)
+		verifications = append(verifications, live)
+	}
 
 	return types.NewStandardResponse(t.ClusterMeta(), t.Name(), map[string]interface{}{
-		"session_id": sessionID,
-		"fix_type":   fix.FixType,
-		"fix_index":  suggestionIdx,
-		"status":     "fix_applied",
-		"risk":       fix.Risk,
+		"session_id":    sessionID,
+		"fix_type":      fix.FixType,
+		"fix_index":     suggestionIdx,
+		"status":        "fix_applied",
+		"risk":          fix.Risk,
+		"message":       result.Message,
+		"telemetry":     result.Telemetry,
+		"canary":        result.Canary,
+		"revision":      revisionNumber(result.Revision),
+		"verifications": verifications,
 	}), nil
 }
+
+// verifyLive confirms a runtime finding on signals captured after the apply.
+func (t *ApplyFixTool) verifyLive(ctx context.Context, sess *session.Session, configYAML string, finding types.DiagnosticFinding, window time.Duration) *fixes.Verification {
+	captured, err := captureAfterApply(ctx, t.Clients.Clientset, sess, window)
+	if err != nil {
+		return liveInconclusive(finding, "Live verification was not possible: "+err.Error())
+	}
+	return fixes.VerifyLive(ctx, captured, configYAML, string(sess.Collector.DeploymentMode), finding)
+}
+
+// verificationBase is the analysis input static verification runs the
+// session's collector config with. It is built like the input of
+// triage_scan, so workload-aware rules see the same collector.
+func (b *BaseTool) verificationBase(ctx context.Context, sess *session.Session) analysis.AnalysisInput {
+	ref := sess.Collector
+	mode := collector.DeploymentMode(ref.DeploymentMode)
+	if mode == "" {
+		detected, err := collector.DetectDeploymentMode(ctx, b.Clients.Clientset, ref.Namespace, ref.Name)
+		if err != nil {
+			slog.Debug("could not detect deployment mode for verification", "error", err)
+		}
+		mode = detected
+	}
+	podInfo, err := collector.FindCollectorPod(ctx, b.Clients.Clientset, ref.Namespace, ref.Name, "")
+	if err != nil {
+		slog.Debug("could not find collector pod for verification", "error", err)
+	}
+	replicas, err := collector.WorkloadReplicas(ctx, b.Clients.Clientset, ref.Namespace, ref.Name)
+	if err != nil {
+		slog.Debug("could not read workload replicas for verification", "error", err)
+	}
+
+	// The CR is named by the session for operator-managed collectors, and
+	// otherwise read from the pod's operator labels
+	crName := ""
+	if ref.DeploymentMode == mutator.ModeOperatorCRD {
+		crName = ref.Name
+	}
+	return analysis.AnalysisInput{
+		DeployMode:  mode,
+		PodInfo:     podInfo,
+		Environment: sess.Environment,
+		Replicas:    replicas,
+		OperatorCR:  operatorCR(ctx, b.Clients.DynamicClient, ref.Namespace, crName, podInfo, b.Clients.DynamicClient != nil),
+		Clientset:   b.Clients.Clientset,
+		Namespace:   ref.Namespace,
+		Metadata:    b.Clients.Metadata,
+	}
+}
+
+// captureAfterApply waits for the capture window and parses the debug
+// exporter output the collector logged during it.
+func captureAfterApply(ctx context.Context, clientset kubernetes.Interface, sess *session.Session, window time.Duration) (*signals.CapturedSignals, error) {
+	start := time.Now()
+	select {
+	case <-time.After(window):
+	case <-ctx.Done():
+		return nil, fmt.Errorf("verification capture was cancelled")
+	}
+
+	pod, err := collector.FindCollectorPod(ctx, clientset, sess.Collector.Namespace, sess.Collector.Name, "")
+	if err != nil {
+		return nil, fmt.Errorf("no collector pod to capture from: %w", err)
+	}
+	lines, err := collector.FetchPodLogsSince(ctx, clientset, sess.Collector.Namespace, pod.Name, start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read collector logs: %w", err)
+	}
+	return signals.Parse(strings.Join(lines, "\n"), start, window), nil
+}
+
+func liveInconclusive(finding types.DiagnosticFinding, msg string) *fixes.Verification {
+	return &fixes.Verification{RuleID: finding.RuleID, Stage: fixes.StageLive, Verdict: fixes.VerdictInconclusive, Message: msg, VerifiedAt: time.Now()}
+}
+
+// safeApplyError maps a failed SafeApply to the error code of the step that failed.
+func safeApplyError(result *mutator.SafeApplyResult) error {
+	var validationErr *mutator.ValidationError
+	code := types.ErrCodeMutationFailed
+	switch {
+	case errors.As(result.Error, &validationErr):
+		code = types.ErrCodeValidationFailed
+	case result.RolledBack, result.Canary != nil && result.Canary.Failure != "":
+		code = types.ErrCodeHealthCheckFailed
+	}
+	msg := fmt.Sprintf("%s: %v", result.Message, result.Error)
+	if result.Telemetry != nil && len(result.Telemetry.Regressions) > 0 {
+		msg += fmt.Sprintf(" (internal metrics before: %s; after: %s)", describeTelemetry(result.Telemetry.Before), describeTelemetry(result.Telemetry.After))
+	}
+	return types.NewMCPError(code, msg)
+}
+
+// fixID identifies an applied fix suggestion in the revision history by its
+// index and the rule of the finding it fixes.
+func fixID(index int, finding types.DiagnosticFinding) string {
+	return fmt.Sprintf("%s#%d", finding.RuleID, index)
+}
+
+// revisionNumber returns the number of a recorded revision, or nil when none
+// was recorded.
+func revisionNumber(rev *mutator.Revision) interface{} {
+	if rev == nil {
+		return nil
+	}
+	return rev.Number
+}
+
+// describeTelemetry summarises a telemetry snapshot for error messages.
+func describeTelemetry(s *mutator.TelemetrySnapshot) string {
+	return fmt.Sprintf("%.1f%% failed, %.1f%% refused, queues %.0f%% full",
+		100*s.FailureRatio(), 100*s.RefusalRatio(), 100*s.QueueUtilization())
+}
+
+// recordVerification appends a verdict to the session's verification history.
+func recordVerification(sess *session.Session, v *fixes.Verification) {
+	history, _ := sess.Verifications.([]fixes.Verification)
+	sess.Verifications = append(history, *v)
+}
@@ -0,0 +1,153 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/fixes"
+	"github.com/hrexed/otel-collector-mcp/pkg/mutator"
+	"github.com/hrexed/otel-collector-mcp/pkg/session"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+)
+
+// ApplyPlanTool applies the session's change plan as a single transaction.
+type ApplyPlanTool struct {
+	BaseTool
+	SessionMgr *session.Manager
+}
+
+func (t *ApplyPlanTool) Name() string { return "apply_plan" }
+
+func (t *ApplyPlanTool) Description() string {
+	return "Apply the change plan built by plan_changes in one rollout with a single backup and rollback point. The plan is refused if it has conflicts, failed verification, or the collector config changed since it was built; runtime findings are confirmed on one capture after the apply."
+}
+
+func (t *ApplyPlanTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"session_id":     map[string]interface{}{"type": "string", "description": "Active session ID"},
+			"verify_seconds": map[string]interface{}{"type": "integer", "description": "Seconds of signals to capture after the apply when the plan fixes runtime findings (0-120, default 30, 0 skips live verification)"},
+		},
+		"required": []string{"session_id"},
+	}
+}
+
+func (t *ApplyPlanTool) Run(ctx context.Context, args map[string]interface{}) (*types.StandardResponse, error) {
+	sessionID, _ := args["session_id"].(string)
+	if sessionID == "" {
+		return nil, types.NewMCPError(types.ErrCodeSessionNotFound, "session_id is required")
+	}
+
+	verifySeconds := defaultVerifySeconds
+	if v, ok := args["verify_seconds"].(float64); ok {
+		verifySeconds = int(v)
+	}
+	if verifySeconds < 0 || verifySeconds > 120 {
+		return nil, types.NewMCPError(types.ErrCodeInvalidArgument, "verify_seconds must be between 0 and 120")
+	}
+
+	sess, err := t.SessionMgr.Get(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, _ := sess.Plan.(*fixes.Plan)
+	if plan == nil {
+		return nil, types.NewMCPError(types.ErrCodeMutationFailed, "no change plan available. Run plan_changes first.")
+	}
+	if len(plan.Conflicts) > 0 {
+		return nil, types.NewMCPError(types.ErrCodeMutationFailed,
+			fmt.Sprintf("the plan has %d conflicts. Run plan_changes with a compatible selection.", len(plan.Conflicts)))
+	}
+	if !plan.Applicable() {
+		return nil, types.NewMCPError(types.ErrCodeMutationFailed, "the plan failed verification. Run plan_changes without the failing suggestions.")
+	}
+
+	if sess.Mutator == nil {
+		return nil, types.NewMCPError(types.ErrCodeMutationFailed, "no mutator available for this session")
+	}
+	current, err := sessionConfig(ctx, sess)
+	if err != nil {
+		return nil, types.NewMCPError(types.ErrCodeMutationFailed, err.Error())
+	}
+	if current != plan.Base {
+		return nil, types.NewMCPError(types.ErrCodeMutationFailed, "the collector config changed since the plan was built. Run plan_changes again.")
+	}
+
+	slog.Info("applying change plan", "session_id", sessionID, "suggestions", plan.Suggestions)
+
+	findings, _ := sess.Findings.([]types.DiagnosticFinding)
+	rev := mutator.Revision{Description: fmt.Sprintf("change plan of %d suggestions", len(plan.Suggestions))}
+	for i, fix := range plan.Fixes {
+		if i < len(plan.Suggestions) && fix.FindingIndex < len(findings) {
+			rev.FixIDs = append(rev.FixIDs, fixID(plan.Suggestions[i], findings[fix.FindingIndex]))
+		}
+	}
+
+	// GitOps-managed collectors in patch mode get the plan as one commit to
+	// the GitOps repository; live verification waits for the rollout
+	if sess.PatchMode {
+		patch, err := t.commitPatch(ctx, sess, plan.Patched, rev.Description, rev.FixIDs)
+		if err != nil {
+			return nil, err
+		}
+		sess.Plan = nil
+		return types.NewStandardResponse(t.ClusterMeta(), t.Name(), map[string]interface{}{
+			"session_id":    sessionID,
+			"suggestions":   plan.Suggestions,
+			"status":        "patch_committed",
+			"risk":          plan.Risk,
+			"message":       patchCommittedMessage,
+			"patch":         patch,
+			"manifests":     plan.Manifests,
+			"verifications": plan.Verifications,
+		}), nil
+	}
+
+	result := mutator.SafeApply(ctx, sess.Mutator, t.Clients.Clientset, sess.Collector, sess.ID, plan.Patched, t.SafeApplyOptions(sess.Collector, current, plan.Patched, rev))
+	if result.Error != nil {
+		return nil, safeApplyError(result)
+	}
+	sess.Plan = nil
+
+	verifications := append([]*fixes.Verification(nil), plan.Verifications...)
+
+	// Runtime findings are confirmed together on one capture after the apply
+	var runtimeFindings []int
+	for _, fix := range plan.Fixes {
+		if fix.FindingIndex < len(findings) && strings.HasPrefix(findings[fix.FindingIndex].RuleID, "runtime.") {
+			runtimeFindings = append(runtimeFindings, fix.FindingIndex)
+		}
+	}
+	if len(runtimeFindings) > 0 && verifySeconds > 0 {
+		captured, err := captureAfterApply(ctx, t.Clients.Clientset, sess, time.Duration(verifySeconds)*time.Second)
+		for _, idx := range runtimeFindings {
+			var live *fixes.Verification
+			if err != nil {
+				live = liveInconclusive(findings[idx], "Live verification was not possible: "+err.Error())
+			} else {
+				live = fixes.VerifyLive(ctx, captured, plan.Patched, string(sess.Collector.DeploymentMode), findings[idx])
+			}
+			live.FindingIndex = idx
+			recordVerification(sess, live)
+			verifications = append(verifications, live)
+		}
+	}
+
+	return types.NewStandardResponse(t.ClusterMeta(), t.Name(), map[string]interface{}{
+		"session_id":    sessionID,
+		"suggestions":   plan.Suggestions,
+		"status":        "plan_applied",
+		"risk":          plan.Risk,
+		"message":       result.Message,
+		"telemetry":     result.Telemetry,
+		"canary":        result.Canary,
+		"revision":      revisionNumber(result.Revision),
+		"manifests":     plan.Manifests,
+		"verifications": verifications,
+	}), nil
+}
@@ -3,9 +3,10 @@ package tools
 import (
 	"context"
 	"log/slog"
+	"time"
 
+	"github.com/hrexed/otel-collector-mcp/pkg/mutator"
 	"github.com/hrexed/otel-collector-mcp/pkg/session"
-	"github.com/hrexed/otel-collector-mcp/pkg/signals"
 	"github.com/hrexed/otel-collector-mcp/pkg/types"
 )
 
@@ -27,6 +28,7 @@ func (t *CaptureSignalsTool) InputSchema() map[string]interface{} {
 		"properties": map[string]interface{}{
 			"session_id":       map[string]interface{}{"type": "string", "description": "Active session ID"},
 			"duration_seconds": map[string]interface{}{"type": "integer", "description": "Capture duration in seconds (30-120, default 60)"},
+			"dry_run":          dryRunArgSchema,
 		},
 		"required": []string{"session_id"},
 	}
@@ -51,17 +53,72 @@ func (t *CaptureSignalsTool) Run(ctx context.Context, args map[string]interface{
 		return nil, err
 	}
 
+	if sess.Mutator == nil {
+		return nil, types.NewMCPError(types.ErrCodeCaptureFailed, "no mutator available for this session")
+	}
+	live, err := sess.Mutator.CurrentConfig(ctx)
+	if err != nil {
+		return nil, types.NewMCPError(types.ErrCodeCaptureFailed, err.Error())
+	}
+	injected, pipelines, err := mutator.InjectDebugExporter(live, nil)
+	if err != nil {
+		return nil, types.NewMCPError(types.ErrCodeCaptureFailed, err.Error())
+	}
+
+	if dryRunRequested(args) {
+		return types.NewStandardResponse(t.ClusterMeta(), t.Name(), map[string]interface{}{
+			"session_id": sessionID,
+			"status":     "dry_run",
+			"pipelines":  pipelines,
+			"dry_run":    dryRun(ctx, sess.Mutator, live, injected),
+		}), nil
+	}
+
 	slog.Info("capturing signals", "session_id", sessionID, "duration", durationSec)
+
+	// The debug exporter goes through the same gates as a fix; a collector
+	// that already has one is captured as is. It is an ephemeral change: the
+	// GitOps sync of a GitOps-managed collector is suspended before it is
+	// applied and until cleanup_debug or session expiry, so that the
+	// controller does not revert it mid-capture
+	suspended := false
+	var revision *mutator.Revision
+	if len(pipelines) > 0 {
+		suspended, err = t.suspendSync(ctx, sess)
+		if err != nil {
+			return nil, types.NewMCPError(types.ErrCodeCaptureFailed, err.Error())
+		}
+		result := mutator.SafeApply(ctx, sess.Mutator, t.Clients.Clientset, sess.Collector, sess.ID, injected, t.SafeApplyOptions(sess.Collector, live, injected, mutator.Revision{
+			Description: "Inject debug exporter for signal capture",
+		}))
+		if result.Error != nil {
+			if suspended {
+				if err := sess.SyncSuspension.Resume(ctx); err != nil {
+					slog.Warn("failed to resume gitops sync", "session_id", sessionID, "error", err)
+				}
+			}
+			return nil, safeApplyError(result)
+		}
+		revision = result.Revision
+	}
 	sess.SetState(session.StateCapturing)
 
-	// In a real implementation, this would stream pod logs for durationSec seconds.
-	// For now, store empty captured signals to complete the pipeline.
-	captured := &signals.CapturedSignals{}
+	captured, err := captureAfterApply(ctx, t.Clients.Clientset, sess, time.Duration(durationSec)*time.Second)
+	if err != nil {
+		return nil, types.NewMCPError(types.ErrCodeCaptureFailed, err.Error())
+	}
 	sess.CapturedSignals = captured
 
 	summary := captured.Summary()
 	summary["status"] = "capture_complete"
 	summary["duration_seconds"] = durationSec
+	summary["pipelines"] = pipelines
+	summary["revision"] = revisionNumber(revision)
+	if suspended {
+		summary["gitops_sync"] = "suspended until cleanup_debug"
+	} else if sess.GitOps != nil && len(pipelines) > 0 {
+		summary["gitops_sync"] = "not suspended: the GitOps controller may revert the debug exporter"
+	}
 
 	return types.NewStandardResponse(t.ClusterMeta(), t.Name(), summary), nil
 }
@@ -41,6 +41,11 @@ func (t *CheckConfigTool) InputSchema() map[string]interface{} {
 				"type":        "string",
 				"description": "Name of the ConfigMap containing collector configuration (fallback if no CRD)",
 			},
+			"environment": map[string]interface{}{
+				"type":        "string",
+				"description": "Environment type: dev, staging, production (optional — enables environment-specific rules)",
+				"enum":        []string{"dev", "staging", "production"},
+			},
 		},
 		"required": []string{"namespace", "name"},
 	}
@@ -51,6 +56,7 @@ func (t *CheckConfigTool) Run(ctx context.Context, args map[string]interface{})
 	name, _ := args["name"].(string)
 	configmap, _ := args["configmap"].(string)
 	collectorName, _ := args["collector_name"].(string)
+	environment, _ := args["environment"].(string)
 
 	slog.Info("running config check", "namespace", namespace, "name", name, "collector_name", collectorName)
 
@@ -110,10 +116,27 @@ func (t *CheckConfigTool) Run(ctx context.Context, args map[string]interface{})
 		}), nil
 	}
 
+	// Pod spec and replica count let analyzers compare the config with the workload
+	podInfo, err := collector.FindCollectorPod(ctx, t.Clients.Clientset, namespace, name, "")
+	if err != nil {
+		slog.Warn("could not find collector pod", "error", err)
+	}
+	replicas, err := collector.WorkloadReplicas(ctx, t.Clients.Clientset, namespace, name)
+	if err != nil {
+		slog.Debug("could not read workload replicas", "error", err)
+	}
+
 	// Run config-only analyzers (not log-based)
 	input := &analysis.AnalysisInput{
-		Config:     cfg,
-		DeployMode: mode,
+		Config:      cfg,
+		DeployMode:  mode,
+		PodInfo:     podInfo,
+		Environment: environment,
+		Replicas:    replicas,
+		OperatorCR:  operatorCR(ctx, t.Clients.DynamicClient, namespace, collectorName, podInfo, hasOperator),
+		Clientset:   t.Clients.Clientset,
+		Namespace:   namespace,
+		Metadata:    t.Clients.Metadata,
 	}
 
 	analyzers := analysis.AllAnalyzers()
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/hrexed/otel-collector-mcp/pkg/mutator"
 	"github.com/hrexed/otel-collector-mcp/pkg/session"
 	"github.com/hrexed/otel-collector-mcp/pkg/types"
 )
@@ -27,6 +28,7 @@ func (t *CleanupDebugTool) InputSchema() map[string]interface{} {
 		"type": "object",
 		"properties": map[string]interface{}{
 			"session_id": map[string]interface{}{"type": "string", "description": "Active session ID"},
+			"dry_run":    dryRunArgSchema,
 		},
 		"required": []string{"session_id"},
 	}
@@ -47,15 +49,73 @@ func (t *CleanupDebugTool) Run(ctx context.Context, args map[string]interface{})
 		return nil, types.NewMCPError(types.ErrCodeSessionExpired, "session is already closed")
 	}
 
+	// A dry run shows the config without the debug exporter and keeps the session open
+	if dryRunRequested(args) {
+		if sess.Mutator == nil {
+			return nil, types.NewMCPError(types.ErrCodeMutationFailed, "no mutator available for this session")
+		}
+		live, err := sess.Mutator.CurrentConfig(ctx)
+		if err != nil {
+			return nil, types.NewMCPError(types.ErrCodeMutationFailed, err.Error())
+		}
+		cleaned, pipelines, err := mutator.RemoveDebugExporter(live)
+		if err != nil {
+			return nil, types.NewMCPError(types.ErrCodeMutationFailed, err.Error())
+		}
+		return types.NewStandardResponse(t.ClusterMeta(), t.Name(), map[string]interface{}{
+			"session_id": sessionID,
+			"status":     "dry_run",
+			"pipelines":  pipelines,
+			"dry_run":    dryRun(ctx, sess.Mutator, live, cleaned),
+		}), nil
+	}
+
 	slog.Info("cleaning up debug exporter", "session_id", sessionID, "collector", sess.Collector.Name)
 
-	// Cleanup mutator resources
+	// Remove the debug exporter from the live config, keeping the fixes
+	// applied during the session, as the dry run shows. The removal goes
+	// through the same gates as a fix and is rolled back when it fails
+	var pipelines []string
+	var revision *mutator.Revision
+	if sess.Mutator != nil {
+		live, err := sess.Mutator.CurrentConfig(ctx)
+		if err != nil {
+			return nil, types.NewMCPError(types.ErrCodeMutationFailed, err.Error())
+		}
+		var cleaned string
+		cleaned, pipelines, err = mutator.RemoveDebugExporter(live)
+		if err != nil {
+			return nil, types.NewMCPError(types.ErrCodeMutationFailed, err.Error())
+		}
+		if len(pipelines) > 0 {
+			result := mutator.SafeApply(ctx, sess.Mutator, t.Clients.Clientset, sess.Collector, sess.ID, cleaned, t.SafeApplyOptions(sess.Collector, live, cleaned, mutator.Revision{
+				Description: "Remove debug exporter after signal capture",
+			}))
+			if result.Error != nil {
+				return nil, safeApplyError(result)
+			}
+			revision = result.Revision
+		}
+	}
+
+	// Cleanup mutator resources; the collector's revision history is kept, so
+	// list_config_revisions and rollbacks to a revision outlive the session
 	if sess.Mutator != nil {
 		if err := sess.Mutator.Cleanup(ctx); err != nil {
 			slog.Warn("cleanup error", "error", err)
 		}
 	}
 
+	// Resume the GitOps sync suspended while the debug exporter was live
+	syncResumed := false
+	if sess.SyncSuspension != nil && sess.SyncSuspension.Suspended() {
+		if err := sess.SyncSuspension.Resume(ctx); err != nil {
+			slog.Warn("failed to resume gitops sync", "session_id", sessionID, "error", err)
+		} else {
+			syncResumed = true
+		}
+	}
+
 	duration := time.Since(sess.CreatedAt).Seconds()
 
 	// Free signal data (thread-safe)
@@ -64,9 +124,21 @@ func (t *CleanupDebugTool) Run(ctx context.Context, args map[string]interface{})
 	// Close session
 	t.SessionMgr.Close(sessionID)
 
-	return types.NewStandardResponse(t.ClusterMeta(), t.Name(), map[string]interface{}{
+	data := map[string]interface{}{
 		"session_id":       sessionID,
 		"status":           "cleanup_complete",
+		"pipelines":        pipelines,
+		"revision":         revisionNumber(revision),
 		"duration_seconds": fmt.Sprintf("%.0f", duration),
-	}), nil
+	}
+	if history := t.ConfigHistory(sess.Collector); history != nil {
+		data["revision_history"] = history.ConfigMapName()
+	}
+	if sess.GitOps != nil {
+		data["gitops_sync_resumed"] = syncResumed
+	}
+	if sess.PatchMode && sess.PatchedConfig != "" {
+		data["patch_branch"] = mutator.NewPatchWriter(t.Cfg.GitOpsRepo, t.Cfg.GitOpsBranchPrefix).Branch(sessionID)
+	}
+	return types.NewStandardResponse(t.ClusterMeta(), t.Name(), data), nil
 }
@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/mutator"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+)
+
+// ListConfigRevisionsTool lists the revision history of a collector's config.
+type ListConfigRevisionsTool struct {
+	BaseTool
+}
+
+func (t *ListConfigRevisionsTool) Name() string { return "list_config_revisions" }
+
+func (t *ListConfigRevisionsTool) Description() string {
+	return "List the revision history of a collector's config: every config applied through v2 tools with its timestamp, session, applied fixes and diff. Pass revision to get the full config of one revision."
+}
+
+func (t *ListConfigRevisionsTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name":      map[string]interface{}{"type": "string", "description": "Collector name"},
+			"namespace": map[string]interface{}{"type": "string", "description": "Kubernetes namespace"},
+			"revision":  map[string]interface{}{"type": "integer", "description": "Revision number to return with its full config"},
+		},
+		"required": []string{"name", "namespace"},
+	}
+}
+
+func (t *ListConfigRevisionsTool) Run(ctx context.Context, args map[string]interface{}) (*types.StandardResponse, error) {
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+
+	if name == "" || namespace == "" {
+		return nil, types.NewMCPError(types.ErrCodeCollectorNotFound, "name and namespace are required")
+	}
+
+	history := t.ConfigHistory(mutator.CollectorRef{Name: name, Namespace: namespace})
+	if history == nil {
+		return nil, types.NewMCPError(types.ErrCodeInvalidArgument, "the revision history is disabled")
+	}
+
+	slog.Info("listing config revisions", "name", name, "namespace", namespace)
+
+	if v, ok := args["revision"].(float64); ok {
+		rev, err := history.Get(ctx, int(v))
+		if err != nil {
+			return nil, types.NewMCPError(types.ErrCodeInvalidArgument, err.Error())
+		}
+		return types.NewStandardResponse(t.ClusterMeta(), t.Name(), map[string]interface{}{
+			"collector": fmt.Sprintf("%s/%s", namespace, name),
+			"revision":  rev,
+		}), nil
+	}
+
+	revisions, err := history.List(ctx)
+	if err != nil {
+		return nil, types.NewMCPError(types.ErrCodeCollectorNotFound, err.Error())
+	}
+
+	// Newest first; configs are left out of the listing
+	listed := make([]mutator.Revision, 0, len(revisions))
+	for i := len(revisions) - 1; i >= 0; i-- {
+		rev := revisions[i]
+		rev.Config = ""
+		listed = append(listed, rev)
+	}
+
+	return types.NewStandardResponse(t.ClusterMeta(), t.Name(), map[string]interface{}{
+		"collector":      fmt.Sprintf("%s/%s", namespace, name),
+		"history":        history.ConfigMapName(),
+		"revision_count": len(listed),
+		"revisions":      listed,
+	}), nil
+}
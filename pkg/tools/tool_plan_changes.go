@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/fixes"
+	"github.com/hrexed/otel-collector-mcp/pkg/session"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+)
+
+// PlanChangesTool combines several fix suggestions into one change plan.
+type PlanChangesTool struct {
+	BaseTool
+	SessionMgr *session.Manager
+}
+
+func (t *PlanChangesTool) Name() string { return "plan_changes" }
+
+func (t *PlanChangesTool) Description() string {
+	return "Combine selected fix suggestions into one merged collector config. Reports conflicts between the suggestions, shows a unified diff of the change and verifies every finding against the merged config. Apply the plan with apply_plan."
+}
+
+func (t *PlanChangesTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"session_id": map[string]interface{}{"type": "string", "description": "Active session ID"},
+			"suggestion_indexes": map[string]interface{}{
+				"type":        "array",
+				"description": "Indexes of the fix suggestions to combine",
+				"items":       map[string]interface{}{"type": "integer"},
+			},
+		},
+		"required": []string{"session_id", "suggestion_indexes"},
+	}
+}
+
+func (t *PlanChangesTool) Run(ctx context.Context, args map[string]interface{}) (*types.StandardResponse, error) {
+	sessionID, _ := args["session_id"].(string)
+	if sessionID == "" {
+		return nil, types.NewMCPError(types.ErrCodeSessionNotFound, "session_id is required")
+	}
+
+	raw, _ := args["suggestion_indexes"].([]interface{})
+	var indexes []int
+	for _, v := range raw {
+		idx, ok := v.(float64)
+		if !ok {
+			return nil, types.NewMCPError(types.ErrCodeInvalidArgument, "suggestion_indexes must be a list of integers")
+		}
+		indexes = append(indexes, int(idx))
+	}
+	if len(indexes) == 0 {
+		return nil, types.NewMCPError(types.ErrCodeInvalidArgument, "suggestion_indexes must select at least one suggestion")
+	}
+
+	sess, err := t.SessionMgr.Get(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions, _ := sess.SuggestedFixes.([]fixes.FixSuggestion)
+	if len(suggestions) == 0 {
+		return nil, types.NewMCPError(types.ErrCodeMutationFailed, "no fix suggestions available. Run suggest_fixes first.")
+	}
+	if sess.Mutator == nil {
+		return nil, types.NewMCPError(types.ErrCodeMutationFailed, "no mutator available for this session")
+	}
+	current, err := sessionConfig(ctx, sess)
+	if err != nil {
+		return nil, types.NewMCPError(types.ErrCodeMutationFailed, err.Error())
+	}
+
+	plan, err := fixes.BuildPlan(current, suggestions, indexes)
+	if err != nil {
+		return nil, types.NewMCPError(types.ErrCodeInvalidArgument, err.Error())
+	}
+
+	// Verify every finding against the merged config
+	findings, _ := sess.Findings.([]types.DiagnosticFinding)
+	base := fixes.VerificationInput(t.verificationBase(ctx, sess), plan.Fixes...)
+	for _, fix := range plan.Fixes {
+		if fix.FindingIndex < 0 || fix.FindingIndex >= len(findings) {
+			return nil, types.NewMCPError(types.ErrCodeMutationFailed, "the findings behind these suggestions are no longer in the session. Run suggest_fixes again.")
+		}
+		v := fixes.VerifyStatic(ctx, base, current, plan.Patched, findings[fix.FindingIndex])
+		v.FindingIndex = fix.FindingIndex
+		recordVerification(sess, v)
+		plan.Verifications = append(plan.Verifications, v)
+	}
+	sess.Plan = plan
+
+	status := "plan_ready"
+	switch {
+	case len(plan.Conflicts) > 0:
+		status = "conflicts"
+	case !plan.Applicable():
+		status = "verification_failed"
+	}
+	slog.Info("change plan built", "session_id", sessionID, "suggestions", len(plan.Suggestions), "conflicts", len(plan.Conflicts), "status", status)
+
+	return types.NewStandardResponse(t.ClusterMeta(), t.Name(), map[string]interface{}{
+		"session_id": sessionID,
+		"status":     status,
+		"plan":       plan,
+	}), nil
+}
@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/ottl"
+	"github.com/hrexed/otel-collector-mcp/pkg/session"
+	"github.com/hrexed/otel-collector-mcp/pkg/signals"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+)
+
+// PreviewTransformTool dry-runs OTTL statements and filter conditions
+// against a session's captured signals.
+type PreviewTransformTool struct {
+	BaseTool
+	SessionMgr *session.Manager
+}
+
+func (t *PreviewTransformTool) Name() string { return "preview_transform" }
+
+func (t *PreviewTransformTool) Description() string {
+	return "Dry-run OTTL transform statements and filter conditions against the signals captured in a session: per-statement match counts, before/after samples of modified fields, and how many records a filter would drop."
+}
+
+func (t *PreviewTransformTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"session_id": map[string]interface{}{"type": "string", "description": "Active session ID"},
+			"context": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"resource", "span", "spanevent", "metric", "datapoint", "log"},
+				"description": "OTTL context the statements and conditions run in",
+			},
+			"statements": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+				"description": "Transform processor statements, applied in order",
+			},
+			"conditions": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+				"description": "Filter processor conditions; a record is dropped when any condition matches",
+			},
+		},
+		"required": []string{"session_id", "context"},
+	}
+}
+
+func (t *PreviewTransformTool) Run(ctx context.Context, args map[string]interface{}) (*types.StandardResponse, error) {
+	sessionID, _ := args["session_id"].(string)
+	if sessionID == "" {
+		return nil, types.NewMCPError(types.ErrCodeInvalidArgument, "session_id is required")
+	}
+
+	contextName, _ := args["context"].(string)
+	if !ottl.ValidContext(contextName) {
+		return nil, types.NewMCPError(types.ErrCodeInvalidArgument, fmt.Sprintf("unknown OTTL context %q", contextName))
+	}
+	statements := stringArgs(args, "statements")
+	conditions := stringArgs(args, "conditions")
+	if len(statements) == 0 && len(conditions) == 0 {
+		return nil, types.NewMCPError(types.ErrCodeInvalidArgument, "provide statements, conditions, or both")
+	}
+
+	sess, err := t.SessionMgr.Get(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	captured, _ := sess.CapturedSignals.(*signals.CapturedSignals)
+	if captured == nil {
+		captured = &signals.CapturedSignals{}
+	}
+
+	result := map[string]interface{}{
+		"session_id": sessionID,
+		"context":    contextName,
+	}
+	if len(statements) > 0 {
+		result["transform"] = ottl.PreviewTransform(ottl.Context(contextName), statements, captured)
+	}
+	if len(conditions) > 0 {
+		result["filter"] = ottl.PreviewFilter(ottl.Context(contextName), conditions, captured)
+	}
+
+	slog.Info("transform previewed", "session_id", sessionID, "context", contextName,
+		"statements", len(statements), "conditions", len(conditions))
+
+	return types.NewStandardResponse(t.ClusterMeta(), t.Name(), result), nil
+}
+
+// stringArgs reads an array-of-strings argument, skipping non-string items.
+func stringArgs(args map[string]interface{}, key string) []string {
+	list, _ := args[key].([]interface{})
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
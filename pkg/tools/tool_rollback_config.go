@@ -2,8 +2,10 @@ package tools
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 
+	"github.com/hrexed/otel-collector-mcp/pkg/mutator"
 	"github.com/hrexed/otel-collector-mcp/pkg/session"
 	"github.com/hrexed/otel-collector-mcp/pkg/types"
 )
@@ -17,7 +19,7 @@ type RollbackConfigTool struct {
 func (t *RollbackConfigTool) Name() string { return "rollback_config" }
 
 func (t *RollbackConfigTool) Description() string {
-	return "Rollback a collector's configuration to the pre-mutation backup."
+	return "Rollback a collector's configuration to the pre-mutation backup, or to any revision from list_config_revisions."
 }
 
 func (t *RollbackConfigTool) InputSchema() map[string]interface{} {
@@ -25,6 +27,8 @@ func (t *RollbackConfigTool) InputSchema() map[string]interface{} {
 		"type": "object",
 		"properties": map[string]interface{}{
 			"session_id": map[string]interface{}{"type": "string", "description": "Active session ID"},
+			"revision":   map[string]interface{}{"type": "integer", "description": "Revision number to restore, from list_config_revisions. Defaults to the session's pre-mutation backup."},
+			"dry_run":    dryRunArgSchema,
 		},
 		"required": []string{"session_id"},
 	}
@@ -47,13 +51,112 @@ func (t *RollbackConfigTool) Run(ctx context.Context, args map[string]interface{
 		return nil, types.NewMCPError(types.ErrCodeRollbackFailed, "no mutator available for this session")
 	}
 
+	if v, ok := args["revision"].(float64); ok {
+		return t.rollbackToRevision(ctx, sess, int(v), dryRunRequested(args))
+	}
+
+	// In GitOps patch mode fixes are only committed to the session's branch
+	if sess.PatchMode {
+		return nil, types.NewMCPError(types.ErrCodeRollbackFailed,
+			fmt.Sprintf("nothing was applied to the collector in GitOps patch mode; discard or revert the commits on branch %s",
+				mutator.NewPatchWriter(t.Cfg.GitOpsRepo, t.Cfg.GitOpsBranchPrefix).Branch(sess.ID)))
+	}
+
+	live, err := sess.Mutator.CurrentConfig(ctx)
+	if err != nil {
+		return nil, types.NewMCPError(types.ErrCodeRollbackFailed, err.Error())
+	}
+	backup, err := sess.Mutator.BackedUpConfig(ctx)
+	if err != nil {
+		return nil, types.NewMCPError(types.ErrCodeRollbackFailed, err.Error())
+	}
+
+	if dryRunRequested(args) {
+		return types.NewStandardResponse(t.ClusterMeta(), t.Name(), map[string]interface{}{
+			"session_id": sessionID,
+			"status":     "dry_run",
+			"dry_run":    dryRun(ctx, sess.Mutator, live, backup),
+		}), nil
+	}
+
 	if err := sess.Mutator.Rollback(ctx); err != nil {
 		return nil, types.NewMCPError(types.ErrCodeRollbackFailed, err.Error())
 	}
 
+	var revision interface{}
+	if history := t.ConfigHistory(sess.Collector); history != nil {
+		rev, err := history.Record(ctx, live, mutator.Revision{
+			SessionID:   sessionID,
+			Description: "rolled back to the session backup",
+			Config:      backup,
+		})
+		if err != nil {
+			slog.Warn("failed to record config revision", "session_id", sessionID, "error", err)
+		}
+		revision = revisionNumber(rev)
+	}
+
 	return types.NewStandardResponse(t.ClusterMeta(), t.Name(), map[string]interface{}{
 		"session_id":    sessionID,
 		"status":        "rollback_complete",
 		"restored_from": "backup annotation",
+		"revision":      revision,
+	}), nil
+}
+
+// rollbackToRevision applies the config of a history revision through the
+// same safety chain as a fix.
+func (t *RollbackConfigTool) rollbackToRevision(ctx context.Context, sess *session.Session, number int, dryRunOnly bool) (*types.StandardResponse, error) {
+	history := t.ConfigHistory(sess.Collector)
+	if history == nil {
+		return nil, types.NewMCPError(types.ErrCodeRollbackFailed, "the revision history is disabled")
+	}
+	target, err := history.Get(ctx, number)
+	if err != nil {
+		return nil, types.NewMCPError(types.ErrCodeRollbackFailed, err.Error())
+	}
+	live, err := sessionConfig(ctx, sess)
+	if err != nil {
+		return nil, types.NewMCPError(types.ErrCodeRollbackFailed, err.Error())
+	}
+
+	if dryRunOnly {
+		return types.NewStandardResponse(t.ClusterMeta(), t.Name(), map[string]interface{}{
+			"session_id": sess.ID,
+			"status":     "dry_run",
+			"dry_run":    dryRun(ctx, sess.Mutator, live, target.Config),
+		}), nil
+	}
+
+	if sess.PatchMode {
+		patch, err := t.commitPatch(ctx, sess, target.Config, fmt.Sprintf("Roll back to revision %d", number), nil)
+		if err != nil {
+			return nil, err
+		}
+		return types.NewStandardResponse(t.ClusterMeta(), t.Name(), map[string]interface{}{
+			"session_id":    sess.ID,
+			"status":        "patch_committed",
+			"restored_from": fmt.Sprintf("revision %d", number),
+			"message":       patchCommittedMessage,
+			"patch":         patch,
+		}), nil
+	}
+
+	result := mutator.SafeApply(ctx, sess.Mutator, t.Clients.Clientset, sess.Collector, sess.ID, target.Config,
+		t.SafeApplyOptions(sess.Collector, live, target.Config, mutator.Revision{
+			Description: fmt.Sprintf("rolled back to revision %d", number),
+		}))
+	if result.Error != nil {
+		return nil, safeApplyError(result)
+	}
+
+	return types.NewStandardResponse(t.ClusterMeta(), t.Name(), map[string]interface{}{
+		"session_id":    sess.ID,
+		"status":        "rollback_complete",
+		"restored_from": fmt.Sprintf("revision %d", number),
+		"message":       result.Message,
+		"telemetry":     result.Telemetry,
+		"canary":        result.Canary,
+		"revision":      revisionNumber(result.Revision),
 	}), nil
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/hrexed/otel-collector-mcp/pkg/mutator"
 	"github.com/hrexed/otel-collector-mcp/pkg/session"
 	"github.com/hrexed/otel-collector-mcp/pkg/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 // StartAnalysisTool initiates a safe analysis session on a collector.
@@ -19,7 +20,7 @@ type StartAnalysisTool struct {
 func (t *StartAnalysisTool) Name() string { return "start_analysis" }
 
 func (t *StartAnalysisTool) Description() string {
-	return "Start a v2 analysis session for a collector, enabling dynamic signal capture and mutation operations."
+	return "Start a v2 analysis session for a collector, enabling dynamic signal capture and mutation operations. For collectors managed by Argo CD or Flux, fixes are committed to the configured GitOps repository instead of being applied live."
 }
 
 func (t *StartAnalysisTool) InputSchema() map[string]interface{} {
@@ -29,6 +30,7 @@ func (t *StartAnalysisTool) InputSchema() map[string]interface{} {
 			"collector_name": map[string]interface{}{"type": "string", "description": "Collector name"},
 			"namespace":      map[string]interface{}{"type": "string", "description": "Kubernetes namespace"},
 			"environment":    map[string]interface{}{"type": "string", "description": "Environment type: dev, staging, production", "enum": []string{"dev", "staging", "production"}},
+			"dry_run":        dryRunArgSchema,
 		},
 		"required": []string{"collector_name", "namespace", "environment"},
 	}
@@ -56,12 +58,54 @@ func (t *StartAnalysisTool) Run(ctx context.Context, args map[string]interface{}
 		Namespace: namespace,
 	}
 
+	// The ConfigMap and owning workload come from the collector's pods; the
+	// mutator, canary and GitOps detection work on those resources. Missing
+	// RBAC fails the session: without the owner no rollout can be triggered
+	ref, err := mutator.ResolveCollectorRef(ctx, t.Clients.Clientset, ref)
+	if apierrors.IsForbidden(err) {
+		return nil, types.NewMCPError(types.ErrCodeMutationFailed,
+			fmt.Sprintf("cannot resolve the resources of collector %s/%s: %v", namespace, collectorName, err))
+	}
+	if err != nil {
+		slog.Warn("failed to resolve collector resources", "collector", collectorName, "namespace", namespace, "error", err)
+	}
+
 	// Create mutator
 	mut := mutator.NewMutator(t.Clients.Clientset, ref)
 
-	// Check for GitOps conflicts
-	if isGitOps, warning := mut.DetectGitOps(ctx); isGitOps {
-		slog.Warn("gitops conflict detected", "warning", warning)
+	// Check for GitOps conflicts. With a GitOps repository configured, fixes
+	// to a GitOps-managed collector are committed to it as patches instead
+	// of being applied live, where the GitOps controller would revert them
+	owner := mutator.FindGitOpsOwner(ctx, t.Clients.Clientset, t.Clients.DynamicClient, ref, t.Cfg.ArgoCDNamespace)
+	patchMode := owner != nil && t.Cfg.GitOpsRepo != ""
+	gitops := map[string]interface{}{"managed": owner != nil}
+	if owner != nil {
+		gitops["owner"] = owner
+		if patchMode {
+			gitops["mode"] = "patch"
+			gitops["repository"] = t.Cfg.GitOpsRepo
+			gitops["branch_prefix"] = t.Cfg.GitOpsBranchPrefix
+		} else {
+			gitops["mode"] = "live"
+			gitops["warning"] = fmt.Sprintf("collector is managed by %s; fixes applied live may be reverted by the GitOps controller. Set V2_GITOPS_REPO to commit them to the GitOps repository instead.", owner)
+			slog.Warn("gitops conflict detected", "owner", owner.String())
+		}
+	}
+
+	// A dry run checks that the collector's config can be read and updated
+	// without opening a session; starting a session does not change the config
+	if dryRunRequested(args) {
+		live, err := mut.CurrentConfig(ctx)
+		if err != nil {
+			return nil, types.NewMCPError(types.ErrCodeMutationFailed, err.Error())
+		}
+		return types.NewStandardResponse(t.ClusterMeta(), t.Name(), map[string]interface{}{
+			"environment": environment,
+			"collector":   fmt.Sprintf("%s/%s", namespace, collectorName),
+			"status":      "dry_run",
+			"gitops":      gitops,
+			"dry_run":     dryRun(ctx, mut, live, live),
+		}), nil
 	}
 
 	// Create session
@@ -69,11 +113,18 @@ func (t *StartAnalysisTool) Run(ctx context.Context, args map[string]interface{}
 	if err != nil {
 		return nil, err
 	}
+	sess.GitOps = owner
+	sess.PatchMode = patchMode
+	if patchMode {
+		delete(gitops, "branch_prefix")
+		gitops["branch"] = mutator.NewPatchWriter(t.Cfg.GitOpsRepo, t.Cfg.GitOpsBranchPrefix).Branch(sess.ID)
+	}
 
 	return types.NewStandardResponse(t.ClusterMeta(), t.Name(), map[string]interface{}{
 		"session_id":  sess.ID,
 		"environment": environment,
 		"collector":   fmt.Sprintf("%s/%s", namespace, collectorName),
 		"status":      "ready_for_capture",
+		"gitops":      gitops,
 	}), nil
 }
@@ -2,10 +2,12 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
 	"log/slog"
 
 	"github.com/hrexed/otel-collector-mcp/pkg/fixes"
 	"github.com/hrexed/otel-collector-mcp/pkg/session"
+	"github.com/hrexed/otel-collector-mcp/pkg/signals"
 	"github.com/hrexed/otel-collector-mcp/pkg/types"
 )
 
@@ -18,32 +20,52 @@ type SuggestFixesTool struct {
 func (t *SuggestFixesTool) Name() string { return "suggest_fixes" }
 
 func (t *SuggestFixesTool) Description() string {
-	return "Generate fix suggestions for detected runtime issues."
+	return "Generate fix suggestions for the findings of detect_issues, triage_scan or check_config. Suggestions for a session's captured signals are annotated with their measured effect. Advisory suggestions change more than the collector config and are carried out by hand."
 }
 
 func (t *SuggestFixesTool) InputSchema() map[string]interface{} {
 	return map[string]interface{}{
 		"type": "object",
 		"properties": map[string]interface{}{
-			"session_id": map[string]interface{}{"type": "string", "description": "Active session ID"},
+			"session_id": map[string]interface{}{"type": "string", "description": "Active session ID; its findings are used when findings is not given"},
+			"findings": map[string]interface{}{
+				"type":        "array",
+				"description": "Findings returned by triage_scan or check_config",
+				"items":       map[string]interface{}{"type": "object"},
+			},
 		},
-		"required": []string{"session_id"},
 	}
 }
 
 func (t *SuggestFixesTool) Run(ctx context.Context, args map[string]interface{}) (*types.StandardResponse, error) {
 	sessionID, _ := args["session_id"].(string)
-	if sessionID == "" {
-		return nil, types.NewMCPError(types.ErrCodeSessionNotFound, "session_id is required")
-	}
-
-	sess, err := t.SessionMgr.Get(sessionID)
+	findings, err := findingsArg(args)
 	if err != nil {
 		return nil, err
 	}
+	if sessionID == "" && findings == nil {
+		return nil, types.NewMCPError(types.ErrCodeInvalidArgument, "session_id or findings is required")
+	}
+
+	var sess *session.Session
+	if sessionID != "" {
+		sess, err = t.SessionMgr.Get(sessionID)
+		if err != nil {
+			return nil, err
+		}
+		// Suggestions point into the findings by index, so findings passed
+		// in replace the session's for apply_fix and plan_changes
+		if findings == nil {
+			findings, _ = sess.Findings.([]types.DiagnosticFinding)
+		} else {
+			sess.Findings = findings
+		}
+	}
 
-	findings, _ := sess.Findings.([]types.DiagnosticFinding)
 	if len(findings) == 0 {
+		if sess != nil {
+			sess.SuggestedFixes = nil
+		}
 		return types.NewStandardResponse(t.ClusterMeta(), t.Name(), map[string]interface{}{
 			"session_id":  sessionID,
 			"suggestions": []interface{}{},
@@ -51,21 +73,32 @@ func (t *SuggestFixesTool) Run(ctx context.Context, args map[string]interface{})
 		}), nil
 	}
 
+	// Annotate each suggestion with its measured effect on captured signals
+	var captured *signals.CapturedSignals
+	if sess != nil {
+		captured, _ = sess.CapturedSignals.(*signals.CapturedSignals)
+	}
+
 	generators := fixes.AllFixGenerators()
 	var suggestions []fixes.FixSuggestion
 
 	for i, finding := range findings {
-		gen, ok := generators[finding.Category]
+		gen, ok := generators[finding.RuleID]
 		if !ok {
 			continue
 		}
 		suggestion := gen(finding, i)
 		if suggestion != nil {
+			if captured != nil {
+				suggestion.Effect = fixes.MeasureEffect(suggestion.ProcessorConfig, captured)
+			}
 			suggestions = append(suggestions, *suggestion)
 		}
 	}
 
-	sess.SuggestedFixes = suggestions
+	if sess != nil {
+		sess.SuggestedFixes = suggestions
+	}
 	slog.Info("fixes suggested", "session_id", sessionID, "count", len(suggestions))
 
 	return types.NewStandardResponse(t.ClusterMeta(), t.Name(), map[string]interface{}{
@@ -75,3 +108,21 @@ func (t *SuggestFixesTool) Run(ctx context.Context, args map[string]interface{})
 		"status":      "suggestions_ready",
 	}), nil
 }
+
+// findingsArg decodes the findings argument, passed as the JSON objects
+// triage_scan and check_config return. It returns nil when the argument is absent.
+func findingsArg(args map[string]interface{}) ([]types.DiagnosticFinding, error) {
+	raw, ok := args["findings"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, types.NewMCPError(types.ErrCodeInvalidArgument, "findings must be a list of finding objects")
+	}
+	findings := []types.DiagnosticFinding{}
+	if err := json.Unmarshal(data, &findings); err != nil {
+		return nil, types.NewMCPError(types.ErrCodeInvalidArgument, "findings must be a list of finding objects: "+err.Error())
+	}
+	return findings, nil
+}
@@ -0,0 +1,135 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/topology"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TopologyTool maps how collectors export to each other and to backends.
+type TopologyTool struct {
+	BaseTool
+	HasOperator func() bool
+}
+
+// topologyResult is the map_topology payload. It is rendered as JSON.
+type topologyResult struct {
+	Graph   *topology.Graph `json:"graph"`
+	Mermaid string          `json:"mermaid,omitempty"`
+	DOT     string          `json:"dot,omitempty"`
+}
+
+func (t *TopologyTool) Name() string { return "map_topology" }
+
+func (t *TopologyTool) Description() string {
+	return "Map the collector topology: resolve every exporter endpoint to in-cluster Services and the collectors behind them, and return the graph per signal as JSON, Mermaid and DOT with cycles, missing hops and dropped signals flagged"
+}
+
+func (t *TopologyTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"namespace": map[string]interface{}{
+				"type":        "string",
+				"description": "Kubernetes namespace to map (empty for all namespaces)",
+			},
+			"format": map[string]interface{}{
+				"type":        "string",
+				"description": "Rendering to include besides the JSON graph (default: all)",
+				"enum":        []string{"all", "json", "mermaid", "dot"},
+			},
+		},
+	}
+}
+
+func (t *TopologyTool) Run(ctx context.Context, args map[string]interface{}) (*types.StandardResponse, error) {
+	namespace, _ := args["namespace"].(string)
+	format, _ := args["format"].(string)
+	if format == "" {
+		format = "all"
+	}
+
+	slog.Info("mapping collector topology", "namespace", namespace)
+
+	hasOperator := false
+	if t.HasOperator != nil {
+		hasOperator = t.HasOperator()
+	}
+
+	instances, err := collector.ListCollectors(ctx, t.Clients.Clientset, t.Clients.DynamicClient, namespace, hasOperator)
+	if err != nil {
+		return nil, err
+	}
+
+	var unreadable []types.DiagnosticFinding
+	collectors := make([]topology.Collector, 0, len(instances))
+	for _, inst := range instances {
+		c := topology.Collector{Instance: inst}
+		workload := inst.Name
+		if inst.ManagedWorkload != "" {
+			workload = inst.ManagedWorkload
+		}
+		pod, podErr := collector.FindCollectorPod(ctx, t.Clients.Clientset, inst.Namespace, workload, "")
+		if podErr == nil {
+			c.PodLabels = pod.Labels
+		}
+
+		// Prefer the CRD spec, then the ConfigMap mounted into the pod
+		if inst.OperatorCRDName != "" {
+			if raw, err := collector.GetConfigFromCRD(ctx, t.Clients.DynamicClient, inst.Namespace, inst.OperatorCRDName); err == nil {
+				c.Config, _ = collector.ParseConfig(raw)
+			}
+		}
+		if c.Config == nil && podErr == nil {
+			c.Config, err = collector.PodConfig(ctx, t.Clients.Clientset, pod)
+			if err != nil {
+				slog.Debug("could not read collector config", "collector", inst.Name, "error", err)
+			}
+		}
+		if c.Config == nil {
+			unreadable = append(unreadable, types.DiagnosticFinding{
+				Severity:   types.SeverityInfo,
+				Category:   types.CategoryConfig,
+				Resource:   &types.ResourceRef{Kind: "Collector", Namespace: inst.Namespace, Name: inst.Name},
+				Summary:    fmt.Sprintf("Configuration of %s/%s could not be read; its outgoing edges are missing", inst.Namespace, inst.Name),
+				Detail:     "The collector is shown without exporters because neither an OpenTelemetryCollector spec nor a config ConfigMap mounted into its pods was found.",
+				Suggestion: "Check the collector's pods and config volume",
+			})
+		}
+		collectors = append(collectors, c)
+	}
+
+	// A namespaced map still resolves endpoints into other namespaces, so
+	// Services are listed in every namespace an endpoint references.
+	listNamespaces := []string{metav1.NamespaceAll}
+	if namespace != "" {
+		listNamespaces = topology.ServiceNamespaces(collectors)
+	}
+	var services []corev1.Service
+	for _, ns := range listNamespaces {
+		list, err := t.Clients.Clientset.CoreV1().Services(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list services: %w", err)
+		}
+		services = append(services, list.Items...)
+	}
+
+	graph := topology.Build(collectors, services)
+	graph.Findings = append(graph.Findings, unreadable...)
+	sortFindings(graph.Findings)
+
+	result := &topologyResult{Graph: graph}
+	if format == "all" || format == "mermaid" {
+		result.Mermaid = graph.Mermaid()
+	}
+	if format == "all" || format == "dot" {
+		result.DOT = graph.DOT()
+	}
+	return types.NewStandardResponse(t.ClusterMeta(), t.Name(), result), nil
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/hrexed/otel-collector-mcp/pkg/analysis"
 	"github.com/hrexed/otel-collector-mcp/pkg/collector"
 	"github.com/hrexed/otel-collector-mcp/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/dynamic"
 )
 
 // TriageScanTool runs all detection rules against a collector and returns prioritized findings.
@@ -42,6 +44,11 @@ func (t *TriageScanTool) InputSchema() map[string]interface{} {
 				"type":        "string",
 				"description": "Pod name for log analysis (optional — auto-discovered if not provided)",
 			},
+			"environment": map[string]interface{}{
+				"type":        "string",
+				"description": "Environment type: dev, staging, production (optional — enables environment-specific rules)",
+				"enum":        []string{"dev", "staging", "production"},
+			},
 		},
 		"required": []string{"namespace", "name", "configmap"},
 	}
@@ -52,6 +59,7 @@ func (t *TriageScanTool) Run(ctx context.Context, args map[string]interface{}) (
 	name, _ := args["name"].(string)
 	configmap, _ := args["configmap"].(string)
 	pod, _ := args["pod"].(string)
+	environment, _ := args["environment"].(string)
 
 	slog.Info("running triage scan", "namespace", namespace, "name", name)
 
@@ -79,7 +87,20 @@ func (t *TriageScanTool) Run(ctx context.Context, args map[string]interface{}) (
 		}
 	}
 
-	// 3. Get logs if pod name available
+	// 3. Find a collector pod and the replica count for workload-aware rules
+	podInfo, err := collector.FindCollectorPod(ctx, t.Clients.Clientset, namespace, name, pod)
+	if err != nil {
+		slog.Warn("could not find collector pod", "error", err)
+	}
+	replicas, err := collector.WorkloadReplicas(ctx, t.Clients.Clientset, namespace, name)
+	if err != nil {
+		slog.Debug("could not read workload replicas", "error", err)
+	}
+
+	// 4. Get logs if pod name available
+	if pod == "" && podInfo != nil {
+		pod = podInfo.Name
+	}
 	var logs []string
 	if pod != "" {
 		logs, err = collector.FetchPodLogs(ctx, t.Clients.Clientset, namespace, pod, collector.DefaultTailLines)
@@ -88,14 +109,21 @@ func (t *TriageScanTool) Run(ctx context.Context, args map[string]interface{}) (
 		}
 	}
 
-	// 4. Build analysis input
+	// 5. Build analysis input
 	input := &analysis.AnalysisInput{
-		Config:     cfg,
-		DeployMode: mode,
-		Logs:       logs,
+		Config:      cfg,
+		DeployMode:  mode,
+		Logs:        logs,
+		PodInfo:     podInfo,
+		Environment: environment,
+		Replicas:    replicas,
+		OperatorCR:  operatorCR(ctx, t.Clients.DynamicClient, namespace, "", podInfo, hasOperator),
+		Clientset:   t.Clients.Clientset,
+		Namespace:   namespace,
+		Metadata:    t.Clients.Metadata,
 	}
 
-	// 5. Run all analyzers
+	// 6. Run all analyzers
 	analyzers := analysis.AllAnalyzersIncludingLogs()
 	var allFindings []types.DiagnosticFinding
 
@@ -117,7 +145,7 @@ func (t *TriageScanTool) Run(ctx context.Context, args map[string]interface{}) (
 		}()
 	}
 
-	// 6. Sort by severity
+	// 7. Sort by severity
 	sortFindings(allFindings)
 
 	return types.NewStandardResponse(t.ClusterMeta(), t.Name(), &types.ToolResult{
@@ -142,3 +170,23 @@ func sortFindings(findings []types.DiagnosticFinding) {
 		return severityOrder[findings[i].Severity] < severityOrder[findings[j].Severity]
 	})
 }
+
+// operatorCR returns the OpenTelemetryCollector CR managing the collector, given
+// by name or read from the pod's operator labels, or nil when there is none.
+func operatorCR(ctx context.Context, dynClient dynamic.Interface, namespace, name string, pod *corev1.Pod, hasOperator bool) map[string]interface{} {
+	if !hasOperator {
+		return nil
+	}
+	if name == "" {
+		name = collector.OperatorCRName(pod)
+	}
+	if name == "" {
+		return nil
+	}
+	cr, err := collector.GetCollectorCR(ctx, dynClient, namespace, name)
+	if err != nil {
+		slog.Debug("could not read OpenTelemetryCollector", "name", name, "error", err)
+		return nil
+	}
+	return cr
+}
@@ -3,8 +3,11 @@ package tools
 import (
 	"context"
 
+	"github.com/hrexed/otel-collector-mcp/pkg/analysis"
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
 	"github.com/hrexed/otel-collector-mcp/pkg/config"
 	"github.com/hrexed/otel-collector-mcp/pkg/k8s"
+	"github.com/hrexed/otel-collector-mcp/pkg/mutator"
 	"github.com/hrexed/otel-collector-mcp/pkg/types"
 )
 
@@ -26,3 +29,59 @@ type BaseTool struct {
 func (b *BaseTool) ClusterMeta() types.ClusterMetadata {
 	return b.Cfg.ClusterMetadata()
 }
+
+// SafeApplyOptions returns the gates a mutation of a collector from current
+// to patched goes through: the configured validator and, unless disabled,
+// the telemetry gate, and the canary stage when enabled. The applied config
+// is recorded in the collector's revision history as rev.
+func (b *BaseTool) SafeApplyOptions(ref mutator.CollectorRef, current, patched string, rev mutator.Revision) mutator.SafeApplyOptions {
+	opts := mutator.SafeApplyOptions{Validator: b.configValidator(ref), History: b.ConfigHistory(ref), Revision: rev}
+	if b.Cfg.TelemetryGate {
+		opts.Telemetry = &mutator.TelemetryGate{
+			BeforePort: telemetryPort(current),
+			AfterPort:  telemetryPort(patched),
+			Thresholds: mutator.TelemetryThresholds{
+				Window:                  b.Cfg.TelemetryWindow,
+				MaxFailureRatioIncrease: b.Cfg.TelemetryMaxFailureIncrease,
+				MaxRefusalRatioIncrease: b.Cfg.TelemetryMaxRefusalIncrease,
+				MaxQueueUtilization:     b.Cfg.TelemetryMaxQueueUtilization,
+			},
+		}
+	}
+	if b.Cfg.Canary {
+		opts.Canary = &mutator.CanaryOptions{Timeout: b.Cfg.CanaryTimeout}
+	}
+	return opts
+}
+
+// ConfigHistory returns the revision history of a collector, or nil when
+// the history is disabled.
+func (b *BaseTool) ConfigHistory(ref mutator.CollectorRef) *mutator.History {
+	if b.Cfg.HistoryRevisions <= 0 {
+		return nil
+	}
+	return mutator.NewHistory(b.Clients.Clientset, ref, b.Cfg.HistoryRevisions, func(from, to string) string {
+		return configDiff(from, to, "previous/config.yaml", "applied/config.yaml")
+	})
+}
+
+// configValidator returns the validator candidate configs for a collector are
+// checked with before they are applied, or nil when none is configured.
+func (b *BaseTool) configValidator(ref mutator.CollectorRef) mutator.Validator {
+	switch {
+	case b.Cfg.ValidatorBinary != "":
+		return mutator.NewBinaryValidator(b.Cfg.ValidatorBinary, b.Cfg.ValidatorTimeout)
+	case b.Cfg.ValidatorJob:
+		return mutator.NewJobValidator(b.Clients.Clientset, ref, b.Cfg.ValidatorTimeout)
+	}
+	return nil
+}
+
+// telemetryPort returns the internal metrics port of a collector config.
+func telemetryPort(configYAML string) int {
+	cfg, err := collector.ParseConfig([]byte(configYAML))
+	if err != nil {
+		cfg = &collector.CollectorConfig{}
+	}
+	return analysis.TelemetryMetricsPort(cfg)
+}
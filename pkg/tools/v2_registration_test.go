@@ -27,19 +27,23 @@ func TestRegisterV2Tools(t *testing.T) {
 	RegisterV2Tools(registry, base, mgr)
 
 	names := registry.List()
-	if len(names) != 10 {
-		t.Errorf("expected 10 v2 tools, got %d", len(names))
+	if len(names) != 14 {
+		t.Errorf("expected 14 v2 tools, got %d", len(names))
 	}
 
 	expected := []string{
 		"check_health",
 		"start_analysis",
 		"rollback_config",
+		"list_config_revisions",
 		"capture_signals",
 		"cleanup_debug",
 		"detect_issues",
 		"suggest_fixes",
+		"preview_transform",
 		"apply_fix",
+		"plan_changes",
+		"apply_plan",
 		"recommend_sampling",
 		"recommend_sizing",
 	}
@@ -0,0 +1,103 @@
+package topology
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// mergedEdge groups the signals flowing between two nodes into one drawn edge.
+type mergedEdge struct {
+	from, to string
+	signals  []string
+}
+
+// mergeEdges collapses edges by endpoints, keeping the graph's edge order.
+func (g *Graph) mergeEdges() []mergedEdge {
+	var merged []mergedEdge
+	index := make(map[string]int)
+	for _, e := range g.Edges {
+		key := e.From + "|" + e.To
+		i, ok := index[key]
+		if !ok {
+			i = len(merged)
+			index[key] = i
+			merged = append(merged, mergedEdge{from: e.From, to: e.To})
+		}
+		found := false
+		for _, s := range merged[i].signals {
+			if s == e.Signal {
+				found = true
+			}
+		}
+		if !found {
+			merged[i].signals = append(merged[i].signals, e.Signal)
+		}
+	}
+	for i := range merged {
+		sort.Strings(merged[i].signals)
+	}
+	return merged
+}
+
+// label returns the display label of a node.
+func (n Node) label() string {
+	switch n.Kind {
+	case NodeCollector:
+		if n.Mode != "" {
+			return fmt.Sprintf("%s/%s (%s)", n.Namespace, n.Name, n.Mode)
+		}
+		return n.Namespace + "/" + n.Name
+	case NodeService:
+		return "svc " + n.Namespace + "/" + n.Name
+	case NodeUnresolved:
+		return n.Name + " (unresolved)"
+	default:
+		return n.Name
+	}
+}
+
+// Mermaid renders the graph as a Mermaid flowchart.
+func (g *Graph) Mermaid() string {
+	ids := make(map[string]string, len(g.Nodes))
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, n := range g.Nodes {
+		id := fmt.Sprintf("n%d", i)
+		ids[n.ID] = id
+		label := strings.ReplaceAll(n.label(), `"`, "'")
+		switch n.Kind {
+		case NodeCollector:
+			fmt.Fprintf(&b, "  %s[\"%s\"]\n", id, label)
+		case NodeUnresolved:
+			fmt.Fprintf(&b, "  %s{{\"%s\"}}\n", id, label)
+		default:
+			fmt.Fprintf(&b, "  %s[(\"%s\")]\n", id, label)
+		}
+	}
+	for _, e := range g.mergeEdges() {
+		fmt.Fprintf(&b, "  %s -->|%s| %s\n", ids[e.from], strings.Join(e.signals, ", "), ids[e.to])
+	}
+	return b.String()
+}
+
+// DOT renders the graph in Graphviz DOT format.
+func (g *Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph topology {\n  rankdir=LR;\n")
+	for _, n := range g.Nodes {
+		shape := "box"
+		switch n.Kind {
+		case NodeService, NodeExternal:
+			shape = "cylinder"
+		case NodeUnresolved:
+			shape = "hexagon"
+		}
+		fmt.Fprintf(&b, "  %q [label=%q, shape=%s];\n", n.ID, n.label(), shape)
+	}
+	for _, e := range g.mergeEdges() {
+		fmt.Fprintf(&b, "  %q -> %q [label=%q];\n", e.from, e.to, strings.Join(e.signals, ", "))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,463 @@
+// Package topology maps how collectors in a cluster export to each other and to
+// external backends, one edge per exporter and signal.
+package topology
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// NodeKind classifies a node in the topology graph.
+type NodeKind string
+
+const (
+	NodeCollector  NodeKind = "collector"  // a collector discovered in the cluster
+	NodeService    NodeKind = "service"    // an in-cluster Service that is not a collector
+	NodeExternal   NodeKind = "external"   // a host outside the cluster
+	NodeUnresolved NodeKind = "unresolved" // a target that could not be resolved
+)
+
+// Collector is a discovered collector with the config and pod labels needed to
+// place it in the graph. Config is nil when it could not be read.
+type Collector struct {
+	Instance  collector.CollectorInstance
+	Config    *collector.CollectorConfig
+	PodLabels map[string]string
+}
+
+// Node is a collector or an export target.
+type Node struct {
+	ID        string                   `json:"id"`
+	Kind      NodeKind                 `json:"kind"`
+	Name      string                   `json:"name"`
+	Namespace string                   `json:"namespace,omitempty"`
+	Mode      collector.DeploymentMode `json:"mode,omitempty"`
+}
+
+// Edge is one signal flowing from a collector through one of its exporters.
+type Edge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Signal   string `json:"signal"`
+	Exporter string `json:"exporter"`
+	Endpoint string `json:"endpoint"`
+}
+
+// Graph is the collector topology with the issues found while building it.
+type Graph struct {
+	Nodes    []Node                    `json:"nodes"`
+	Edges    []Edge                    `json:"edges"`
+	Findings []types.DiagnosticFinding `json:"findings"`
+}
+
+// builder holds the lookups used while resolving exporter endpoints.
+type builder struct {
+	graph      *Graph
+	collectors map[string]*Collector // node ID -> collector
+	services   map[string]*corev1.Service
+	nodes      map[string]bool
+	edges      map[Edge]bool
+}
+
+// Build resolves the OTLP, loadbalancing and other endpoint-based exporters of
+// every collector to Services and the collectors behind them, then flags cycles,
+// unresolvable hops and signals the next tier does not receive.
+func Build(collectors []Collector, services []corev1.Service) *Graph {
+	b := &builder{
+		graph:      &Graph{},
+		collectors: make(map[string]*Collector),
+		services:   make(map[string]*corev1.Service),
+		nodes:      make(map[string]bool),
+		edges:      make(map[Edge]bool),
+	}
+	for i := range services {
+		b.services[services[i].Namespace+"/"+services[i].Name] = &services[i]
+	}
+	sorted := make([]*Collector, len(collectors))
+	for i := range collectors {
+		sorted[i] = &collectors[i]
+	}
+	sort.Slice(sorted, func(i, j int) bool { return collectorID(sorted[i]) < collectorID(sorted[j]) })
+	for _, c := range sorted {
+		b.collectors[collectorID(c)] = c
+		b.addNode(Node{ID: collectorID(c), Kind: NodeCollector, Name: c.Instance.Name, Namespace: c.Instance.Namespace, Mode: c.Instance.DeploymentMode})
+	}
+
+	for _, c := range sorted {
+		forEachEndpoint(c, func(signal, exporter, endpoint string) {
+			b.resolve(c, signal, exporter, endpoint)
+		})
+	}
+
+	sort.Slice(b.graph.Edges, func(i, j int) bool {
+		ei, ej := b.graph.Edges[i], b.graph.Edges[j]
+		if ei.From != ej.From {
+			return ei.From < ej.From
+		}
+		if ei.Signal != ej.Signal {
+			return ei.Signal < ej.Signal
+		}
+		return ei.To < ej.To
+	})
+	b.checkDroppedSignals()
+	b.checkCycles()
+	return b.graph
+}
+
+func collectorID(c *Collector) string {
+	return "collector:" + c.Instance.Namespace + "/" + c.Instance.Name
+}
+
+func sortedPipelines(cfg *collector.CollectorConfig) []string {
+	names := make([]string, 0, len(cfg.Service.Pipelines))
+	for name := range cfg.Service.Pipelines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// exporterEndpoints returns the targets an exporter sends a signal to. For
+// loadbalancing exporters these are the resolver's Service or hostnames.
+// ServiceNamespaces returns the namespaces whose Services the collectors'
+// exporter endpoints can reference: their own namespaces and the namespace
+// part of every name.namespace or in-cluster DNS endpoint. Build needs the Services of
+// all of them to tell a missing hop from a Service outside the mapped namespace.
+func ServiceNamespaces(collectors []Collector) []string {
+	seen := make(map[string]bool)
+	for i := range collectors {
+		c := &collectors[i]
+		seen[c.Instance.Namespace] = true
+		forEachEndpoint(c, func(_, _, endpoint string) {
+			host := strings.ToLower(strings.TrimSuffix(splitHost(endpoint), "."))
+			if strings.Contains(host, "${") || net.ParseIP(host) != nil {
+				return
+			}
+			if parts := strings.Split(host, "."); len(parts) == 2 || len(parts) > 2 && isClusterHost(host) {
+				seen[parts[1]] = true
+			}
+		})
+	}
+	namespaces := make([]string, 0, len(seen))
+	for ns := range seen {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// forEachEndpoint calls fn for every endpoint of every exporter in the
+// collector's pipelines, skipping connectors.
+func forEachEndpoint(c *Collector, fn func(signal, exporter, endpoint string)) {
+	if c.Config == nil {
+		return
+	}
+	for _, name := range sortedPipelines(c.Config) {
+		signal, _, _ := strings.Cut(name, "/")
+		for _, exporter := range c.Config.Service.Pipelines[name].Exporters {
+			if _, isConnector := c.Config.Connectors[exporter]; isConnector {
+				continue
+			}
+			cfg, _ := c.Config.Exporters[exporter].(map[string]interface{})
+			for _, endpoint := range exporterEndpoints(exporter, cfg, signal) {
+				fn(signal, exporter, endpoint)
+			}
+		}
+	}
+}
+
+func exporterEndpoints(id string, cfg map[string]interface{}, signal string) []string {
+	typ, _, _ := strings.Cut(id, "/")
+	if typ == "loadbalancing" {
+		resolver, _ := cfg["resolver"].(map[string]interface{})
+		if k8s, ok := resolver["k8s"].(map[string]interface{}); ok {
+			if svc, ok := k8s["service"].(string); ok && svc != "" {
+				// name.namespace is a Service reference, not a DNS name
+				if strings.Count(svc, ".") == 1 {
+					svc += ".svc"
+				}
+				return []string{svc}
+			}
+		}
+		if dns, ok := resolver["dns"].(map[string]interface{}); ok {
+			if host, ok := dns["hostname"].(string); ok && host != "" {
+				return []string{host}
+			}
+		}
+		if static, ok := resolver["static"].(map[string]interface{}); ok {
+			var hosts []string
+			if list, ok := static["hostnames"].([]interface{}); ok {
+				for _, h := range list {
+					if s, ok := h.(string); ok {
+						hosts = append(hosts, s)
+					}
+				}
+			}
+			return hosts
+		}
+		return nil
+	}
+	if endpoint, ok := cfg[signal+"_endpoint"].(string); ok && endpoint != "" {
+		return []string{endpoint}
+	}
+	if endpoint, ok := cfg["endpoint"].(string); ok && endpoint != "" {
+		return []string{endpoint}
+	}
+	return nil
+}
+
+// splitHost extracts the host from an endpoint with optional scheme, port and path.
+func splitHost(endpoint string) string {
+	rest := endpoint
+	if _, r, ok := strings.Cut(endpoint, "://"); ok {
+		rest = r
+	}
+	if i := strings.Index(rest, "/"); i >= 0 {
+		rest = rest[:i]
+	}
+	if h, _, err := net.SplitHostPort(rest); err == nil {
+		return h
+	}
+	return rest
+}
+
+// resolve adds the edges for one exporter endpoint.
+func (b *builder) resolve(from *Collector, signal, exporter, endpoint string) {
+	edge := Edge{From: collectorID(from), Signal: signal, Exporter: exporter, Endpoint: endpoint}
+	host := strings.ToLower(strings.TrimSuffix(splitHost(endpoint), "."))
+
+	switch {
+	case host == "localhost" || host == "127.0.0.1" || host == "::1":
+		return // a sidecar or the collector itself, not a hop between collectors
+	case strings.Contains(host, "${"):
+		edge.To = "unresolved:" + host
+		b.addNode(Node{ID: edge.To, Kind: NodeUnresolved, Name: host})
+		b.addEdge(edge)
+		return
+	case net.ParseIP(host) != nil:
+		b.addExternal(edge, host)
+		return
+	}
+
+	parts := strings.Split(host, ".")
+	name, namespace := parts[0], from.Instance.Namespace
+	if len(parts) > 1 {
+		namespace = parts[1]
+	}
+	svc, ok := b.services[namespace+"/"+name]
+	if !ok && !isClusterHost(host) {
+		b.addExternal(edge, host)
+		return
+	}
+	if !ok {
+		edge.To = "unresolved:" + host
+		b.addNode(Node{ID: edge.To, Kind: NodeUnresolved, Name: host})
+		b.addEdge(edge)
+		b.graph.Findings = append(b.graph.Findings, types.DiagnosticFinding{
+			RuleID:     types.RuleTopologyMissingHop,
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryPipeline,
+			Resource:   &types.ResourceRef{Kind: "Service", Namespace: namespace, Name: name},
+			Summary:    fmt.Sprintf("Collector %s/%s exports %s to %s, which is not a Service in the cluster", from.Instance.Namespace, from.Instance.Name, signal, host),
+			Detail:     fmt.Sprintf("Exporter %q targets %s, but no Service %s/%s exists. The hop to the next tier is missing, so the exporter retries until its queue fills and then drops %s.", exporter, endpoint, namespace, name, signal),
+			Suggestion: "Create the Service of the next collector tier or fix the exporter endpoint",
+			Evidence:   &types.Evidence{SignalType: signal, ComponentID: exporter, SampleValues: []string{endpoint}},
+		})
+		return
+	}
+
+	var targets []*Collector
+	if len(svc.Spec.Selector) > 0 {
+		selector := labels.SelectorFromSet(svc.Spec.Selector)
+		for _, id := range sortedKeys(b.collectors) {
+			c := b.collectors[id]
+			if c.Instance.Namespace == svc.Namespace && len(c.PodLabels) > 0 && selector.Matches(labels.Set(c.PodLabels)) {
+				targets = append(targets, c)
+			}
+		}
+	}
+	if len(targets) == 0 {
+		edge.To = "service:" + svc.Namespace + "/" + svc.Name
+		b.addNode(Node{ID: edge.To, Kind: NodeService, Name: svc.Name, Namespace: svc.Namespace})
+		b.addEdge(edge)
+		return
+	}
+	for _, c := range targets {
+		e := edge
+		e.To = collectorID(c)
+		b.addEdge(e)
+	}
+}
+
+// isClusterHost reports whether a hostname can only name an in-cluster Service:
+// a short name or a *.svc / *.cluster.local name. Hosts of the form name.namespace
+// are resolved when such a Service exists and treated as external otherwise.
+func isClusterHost(host string) bool {
+	return !strings.Contains(host, ".") || strings.HasSuffix(host, ".svc") ||
+		strings.HasSuffix(host, ".cluster.local") || strings.Contains(host, ".svc.")
+}
+
+func (b *builder) addExternal(edge Edge, host string) {
+	edge.To = "external:" + host
+	b.addNode(Node{ID: edge.To, Kind: NodeExternal, Name: host})
+	b.addEdge(edge)
+}
+
+func (b *builder) addNode(n Node) {
+	if b.nodes[n.ID] {
+		return
+	}
+	b.nodes[n.ID] = true
+	b.graph.Nodes = append(b.graph.Nodes, n)
+}
+
+func (b *builder) addEdge(e Edge) {
+	if b.edges[e] {
+		return
+	}
+	b.edges[e] = true
+	b.graph.Edges = append(b.graph.Edges, e)
+}
+
+// checkDroppedSignals flags collector-to-collector edges whose target has no
+// pipeline of that signal fed by an OTLP receiver.
+func (b *builder) checkDroppedSignals() {
+	reported := make(map[string]bool)
+	for _, e := range b.graph.Edges {
+		to, ok := b.collectors[e.To]
+		if !ok || to.Config == nil {
+			continue
+		}
+		key := e.From + "|" + e.To + "|" + e.Signal
+		if reported[key] || receivesOTLP(to.Config, e.Signal) {
+			continue
+		}
+		reported[key] = true
+		from := b.collectors[e.From]
+		b.graph.Findings = append(b.graph.Findings, types.DiagnosticFinding{
+			RuleID:     types.RuleTopologyDroppedSignal,
+			Severity:   types.SeverityWarning,
+			Category:   types.CategoryPipeline,
+			Resource:   &types.ResourceRef{Kind: "Collector", Namespace: to.Instance.Namespace, Name: to.Instance.Name},
+			Summary:    fmt.Sprintf("%s sent by %s/%s are dropped by %s/%s", e.Signal, from.Instance.Namespace, from.Instance.Name, to.Instance.Namespace, to.Instance.Name),
+			Detail:     fmt.Sprintf("Exporter %q sends %s to %s/%s, but that collector has no %s pipeline with an OTLP receiver. The data is accepted or rejected at the receiver and never forwarded to the next tier.", e.Exporter, e.Signal, to.Instance.Namespace, to.Instance.Name, e.Signal),
+			Suggestion: fmt.Sprintf("Add a %s pipeline with the otlp receiver to the downstream collector, or stop exporting %s to it", e.Signal, e.Signal),
+			Remediation: fmt.Sprintf(`service:
+  pipelines:
+    %s:
+      receivers: [otlp]
+      processors: [memory_limiter, batch]
+      exporters: [<backend-exporter>]`, e.Signal),
+			Evidence: &types.Evidence{SignalType: e.Signal, ComponentID: e.Exporter, Pipeline: e.Signal},
+		})
+	}
+}
+
+// receivesOTLP reports whether a config has a pipeline of the signal with an otlp receiver.
+func receivesOTLP(cfg *collector.CollectorConfig, signal string) bool {
+	for name, pipeline := range cfg.Service.Pipelines {
+		if s, _, _ := strings.Cut(name, "/"); s != signal {
+			continue
+		}
+		for _, r := range pipeline.Receivers {
+			if typ, _, _ := strings.Cut(r, "/"); typ == "otlp" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkCycles reports each loop of collector-to-collector edges per signal once.
+func (b *builder) checkCycles() {
+	adjacency := make(map[string]map[string][]string) // signal -> from -> to
+	for _, e := range b.graph.Edges {
+		if _, ok := b.collectors[e.To]; !ok {
+			continue
+		}
+		if adjacency[e.Signal] == nil {
+			adjacency[e.Signal] = make(map[string][]string)
+		}
+		adjacency[e.Signal][e.From] = append(adjacency[e.Signal][e.From], e.To)
+	}
+
+	signals := make([]string, 0, len(adjacency))
+	for s := range adjacency {
+		signals = append(signals, s)
+	}
+	sort.Strings(signals)
+
+	for _, signal := range signals {
+		adj := adjacency[signal]
+		seen := make(map[string]bool)
+		state := make(map[string]int) // 0 unvisited, 1 on stack, 2 done
+		var stack []string
+		var visit func(id string)
+		visit = func(id string) {
+			state[id] = 1
+			stack = append(stack, id)
+			for _, next := range adj[id] {
+				switch state[next] {
+				case 0:
+					visit(next)
+				case 1:
+					start := 0
+					for i, n := range stack {
+						if n == next {
+							start = i
+						}
+					}
+					b.reportCycle(signal, append([]string(nil), stack[start:]...), seen)
+				}
+			}
+			stack = stack[:len(stack)-1]
+			state[id] = 2
+		}
+		for _, id := range sortedKeys(b.collectors) {
+			if state[id] == 0 {
+				visit(id)
+			}
+		}
+	}
+}
+
+func (b *builder) reportCycle(signal string, cycle []string, seen map[string]bool) {
+	members := append([]string(nil), cycle...)
+	sort.Strings(members)
+	key := strings.Join(members, ",")
+	if seen[key] {
+		return
+	}
+	seen[key] = true
+
+	names := make([]string, 0, len(cycle)+1)
+	for _, id := range append(cycle, cycle[0]) {
+		c := b.collectors[id]
+		names = append(names, c.Instance.Namespace+"/"+c.Instance.Name)
+	}
+	b.graph.Findings = append(b.graph.Findings, types.DiagnosticFinding{
+		RuleID:     types.RuleTopologyLoop,
+		Severity:   types.SeverityCritical,
+		Category:   types.CategoryPipeline,
+		Summary:    fmt.Sprintf("%s loop between collectors: %s", signal, strings.Join(names, " → ")),
+		Detail:     "Every item that enters the loop is exported back to a collector it already passed through. It circulates until a queue overflows, multiplying load and duplicating data at the backend.",
+		Suggestion: "Remove the exporter that sends data back to an earlier tier",
+		Evidence:   &types.Evidence{SignalType: signal, Components: names, Count: len(cycle)},
+	})
+}
+
+func sortedKeys(m map[string]*Collector) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,201 @@
+package topology
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hrexed/otel-collector-mcp/pkg/collector"
+	"github.com/hrexed/otel-collector-mcp/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testCollector(name string, mode collector.DeploymentMode, yaml string) Collector {
+	cfg, err := collector.ParseConfig([]byte(yaml))
+	if err != nil {
+		panic(err)
+	}
+	return Collector{
+		Instance:  collector.CollectorInstance{Name: name, Namespace: "obs", DeploymentMode: mode},
+		Config:    cfg,
+		PodLabels: map[string]string{"app": name},
+	}
+}
+
+func testService(name, app string) corev1.Service {
+	return corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "obs"},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": app}},
+	}
+}
+
+const agentConfig = `receivers:
+  otlp:
+    protocols:
+      grpc: {}
+exporters:
+  otlp:
+    endpoint: gateway.obs.svc.cluster.local:4317
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [otlp]
+    logs:
+      receivers: [otlp]
+      exporters: [otlp]
+`
+
+const gatewayConfig = `receivers:
+  otlp:
+    protocols:
+      grpc: {}
+exporters:
+  otlphttp:
+    endpoint: https://otlp.vendor.io
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [otlphttp]
+`
+
+func TestBuild_AgentGatewayBackend(t *testing.T) {
+	g := Build(
+		[]Collector{
+			testCollector("agent", collector.ModeDaemonSet, agentConfig),
+			testCollector("gateway", collector.ModeDeployment, gatewayConfig),
+		},
+		[]corev1.Service{testService("gateway", "gateway")},
+	)
+
+	want := []Edge{
+		{From: "collector:obs/agent", To: "collector:obs/gateway", Signal: "logs", Exporter: "otlp", Endpoint: "gateway.obs.svc.cluster.local:4317"},
+		{From: "collector:obs/agent", To: "collector:obs/gateway", Signal: "traces", Exporter: "otlp", Endpoint: "gateway.obs.svc.cluster.local:4317"},
+		{From: "collector:obs/gateway", To: "external:otlp.vendor.io", Signal: "traces", Exporter: "otlphttp", Endpoint: "https://otlp.vendor.io"},
+	}
+	if len(g.Edges) != len(want) {
+		t.Fatalf("expected %d edges, got %d: %+v", len(want), len(g.Edges), g.Edges)
+	}
+	for i := range want {
+		if g.Edges[i] != want[i] {
+			t.Errorf("edge %d: expected %+v, got %+v", i, want[i], g.Edges[i])
+		}
+	}
+
+	// The gateway has no logs pipeline, so logs stop at the second tier
+	if len(g.Findings) != 1 || g.Findings[0].Severity != types.SeverityWarning || !strings.Contains(g.Findings[0].Summary, "logs") {
+		t.Errorf("expected one dropped-logs warning, got %+v", g.Findings)
+	}
+
+	mermaid := g.Mermaid()
+	if !strings.Contains(mermaid, "n0 -->|logs, traces| n1") || !strings.Contains(mermaid, `n2[("otlp.vendor.io")]`) {
+		t.Errorf("unexpected mermaid output:\n%s", mermaid)
+	}
+	dot := g.DOT()
+	if !strings.Contains(dot, `"collector:obs/agent" -> "collector:obs/gateway" [label="logs, traces"];`) {
+		t.Errorf("unexpected dot output:\n%s", dot)
+	}
+}
+
+func TestBuild_CycleAndMissingHop(t *testing.T) {
+	loop := func(target string) string {
+		return `receivers:
+  otlp:
+    protocols:
+      grpc: {}
+exporters:
+  otlp:
+    endpoint: ` + target + `:4317
+  otlp/archive:
+    endpoint: archive:4317
+service:
+  pipelines:
+    metrics:
+      receivers: [otlp]
+      exporters: [otlp, otlp/archive]
+`
+	}
+	g := Build(
+		[]Collector{
+			testCollector("a", collector.ModeDeployment, loop("b")),
+			testCollector("b", collector.ModeDeployment, loop("a")),
+		},
+		[]corev1.Service{testService("a", "a"), testService("b", "b")},
+	)
+
+	var critical, missing int
+	for _, f := range g.Findings {
+		switch {
+		case f.Severity == types.SeverityCritical && strings.Contains(f.Summary, "metrics loop"):
+			critical++
+		case f.Severity == types.SeverityWarning && strings.Contains(f.Summary, "not a Service"):
+			missing++
+		}
+	}
+	if critical != 1 {
+		t.Errorf("expected one cycle finding, got %d: %+v", critical, g.Findings)
+	}
+	if missing != 2 {
+		t.Errorf("expected two missing-hop findings, got %d: %+v", missing, g.Findings)
+	}
+}
+
+func TestBuild_LoadBalancingResolver(t *testing.T) {
+	agent := `exporters:
+  loadbalancing:
+    resolver:
+      k8s:
+        service: sampler-headless.obs
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [loadbalancing]
+`
+	g := Build(
+		[]Collector{
+			testCollector("agent", collector.ModeDaemonSet, agent),
+			testCollector("sampler", collector.ModeStatefulSet, gatewayConfig),
+		},
+		[]corev1.Service{testService("sampler-headless", "sampler")},
+	)
+	if len(g.Edges) < 1 || g.Edges[0].To != "collector:obs/sampler" {
+		t.Errorf("expected edge to sampler collector, got %+v", g.Edges)
+	}
+}
+
+func TestServiceNamespaces(t *testing.T) {
+	agent := `exporters:
+  otlp:
+    endpoint: gateway.platform.svc.cluster.local:4317
+  otlp/local:
+    endpoint: gateway:4317
+  otlp/ip:
+    endpoint: 10.0.0.1:4317
+  otlphttp:
+    endpoint: https://otlp.vendor.io
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [otlp, otlp/local, otlp/ip]
+    logs:
+      receivers: [otlp]
+      exporters: [otlphttp]
+`
+	got := ServiceNamespaces([]Collector{testCollector("agent", collector.ModeDaemonSet, agent)})
+	want := []string{"obs", "platform"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	// With the other namespace's Service listed, the hop resolves.
+	gateway := corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: "platform"}}
+	g := Build([]Collector{testCollector("agent", collector.ModeDaemonSet, agent)}, []corev1.Service{gateway, testService("gateway", "gateway")})
+	for _, f := range g.Findings {
+		if f.RuleID == types.RuleTopologyMissingHop {
+			t.Errorf("unexpected missing hop: %s", f.Summary)
+		}
+	}
+}
@@ -20,6 +20,8 @@ const (
 	ErrCodeMutationFailed    = "MUTATION_FAILED"
 	ErrCodeCaptureFailed     = "CAPTURE_FAILED"
 	ErrCodeGitOpsConflict    = "GITOPS_CONFLICT"
+	ErrCodeInvalidArgument   = "INVALID_ARGUMENT"
+	ErrCodeValidationFailed  = "VALIDATION_FAILED"
 )
 
 // MCPError is a structured error for MCP tool responses.
@@ -30,6 +30,7 @@ type ResourceRef struct {
 
 // DiagnosticFinding represents a single diagnostic finding from analysis.
 type DiagnosticFinding struct {
+	RuleID      string       `json:"ruleId,omitempty"`
 	Severity    string       `json:"severity"`
 	Category    string       `json:"category"`
 	Resource    *ResourceRef `json:"resource,omitempty"`
@@ -37,6 +38,27 @@ type DiagnosticFinding struct {
 	Detail      string       `json:"detail"`
 	Suggestion  string       `json:"suggestion"`
 	Remediation string       `json:"remediation,omitempty"`
+	Evidence    *Evidence    `json:"evidence,omitempty"`
+}
+
+// Evidence is the structured data behind a finding. Fix generators read it
+// to name the real attribute, metric, pipeline or component instead of a
+// placeholder. Fields that do not apply to a rule are left empty.
+type Evidence struct {
+	SignalType    string   `json:"signalType,omitempty"` // traces, metrics or logs
+	Name          string   `json:"name,omitempty"`       // metric, span or log name
+	Names         []string `json:"names,omitempty"`      // every name involved when there are several
+	Kind          string   `json:"kind,omitempty"`       // what was detected, e.g. email address
+	AttributeKey  string   `json:"attributeKey,omitempty"`
+	AttributeKeys []string `json:"attributeKeys,omitempty"` // every key involved when there are several
+	SampleValues  []string `json:"sampleValues,omitempty"`
+	Count         int      `json:"count,omitempty"`
+	Pipeline      string   `json:"pipeline,omitempty"`
+	ComponentID   string   `json:"componentId,omitempty"`
+	Components    []string `json:"components,omitempty"` // every component involved when there are several
+	Path          string   `json:"path,omitempty"`       // setting path inside the component, e.g. headers.authorization
+	Paths         []string `json:"paths,omitempty"`      // setting path in each of Components
+	Ports         []int    `json:"ports,omitempty"`      // ports already taken in the config
 }
 
 // SeverityIcon returns a compact emoji for the severity level.
@@ -0,0 +1,118 @@
+package types
+
+// Rule IDs identify the check that produced a finding. They are stable across
+// releases: fix generators and verification key off them, so rename only with
+// a migration.
+const (
+	// Pipeline structure.
+	RuleMissingBatch             = "pipeline.missing_batch"
+	RuleMissingMemoryLimiter     = "pipeline.missing_memory_limiter"
+	RuleResourceDetectorConflict = "pipeline.resource_detector_conflict"
+	RuleNoCardinalityControl     = "pipeline.no_cardinality_control"
+	RuleCumulativeDeltaStateless = "pipeline.cumulative_delta_stateless"
+
+	// memory_limiter sizing.
+	RuleMemoryLimiterNoContainerLimit = "memory_limiter.no_container_limit"
+	RuleMemoryLimiterSpike            = "memory_limiter.spike_not_below_limit"
+	RuleMemoryLimiterAboveContainer   = "memory_limiter.above_container_limit"
+	RuleMemoryLimiterHeadroom         = "memory_limiter.low_headroom"
+	RuleGoMemLimitAboveContainer      = "memory_limiter.gomemlimit_above_container"
+	RuleGoMemLimitBelowSoftLimit      = "memory_limiter.gomemlimit_below_soft_limit"
+	RuleGoMemLimitUnset               = "memory_limiter.gomemlimit_unset"
+
+	// Exporter resilience.
+	RuleMissingRetry           = "exporter.missing_retry"
+	RuleMissingQueue           = "exporter.missing_queue"
+	RuleExporterBackpressure   = "exporter.backpressure"
+	RuleQueueConsumersExceed   = "sending_queue.consumers_exceed_size"
+	RuleQueueFewConsumers      = "sending_queue.few_consumers"
+	RuleQueueMemoryBudget      = "sending_queue.memory_budget"
+	RuleQueueInvalidStorage    = "sending_queue.invalid_storage"
+	RuleQueueStorageUnmounted  = "sending_queue.storage_unmounted"
+	RuleQueueStorageEphemeral  = "sending_queue.storage_ephemeral"
+	RuleHardcodedToken         = "security.hardcoded_token"
+	RuleInvalidRegex           = "processor.invalid_regex"
+	RuleOTTLInvalidContext     = "ottl.invalid_context"
+	RuleOTTLInvalidExpression  = "ottl.invalid_expression"
+	RuleConnectorUnused        = "connector.unused"
+	RuleConnectorNotExporter   = "connector.not_exporter"
+	RuleConnectorNotReceiver   = "connector.not_receiver"
+	RuleEnvUndefined           = "env.undefined"
+	RuleEnvUnresolvable        = "env.unresolvable"
+	RuleRBACMissingPermissions = "rbac.missing_permissions"
+
+	// Receiver bindings.
+	RulePortConflict             = "receiver.port_conflict"
+	RulePortCollision            = "receiver.port_collision"
+	RuleReceiverDefaultLocalhost = "receiver.default_localhost"
+	RuleReceiverLoopback         = "receiver.loopback"
+	RuleReceiverPortUndeclared   = "receiver.port_undeclared"
+	RuleServiceTargetNoReceiver  = "receiver.service_target_unused"
+	RuleReceiverPortUnexposed    = "receiver.port_unexposed"
+
+	// Deployment mode fitness.
+	RuleTailSamplingDaemonSet    = "deployment.tail_sampling_daemonset"
+	RuleNodeReceiverNotDaemon    = "deployment.node_receiver_not_daemonset"
+	RuleClusterReceiverDaemon    = "deployment.cluster_receiver_daemonset"
+	RuleScrapeReplicasNoTA       = "deployment.scrape_replicas_without_target_allocator"
+	RuleConnectorReplicas        = "deployment.connector_replicas"
+	RuleFilelogPathsNotMounted   = "deployment.filelog_paths_not_mounted"
+	RuleLoadBalancingNoResolver  = "loadbalancing.no_resolver"
+	RuleLoadBalancingExternal    = "loadbalancing.external_resolver"
+	RuleLoadBalancingNoService   = "loadbalancing.service_missing"
+	RuleLoadBalancingNotHeadless = "loadbalancing.service_not_headless"
+	RuleLoadBalancingNoPods      = "loadbalancing.no_pods"
+	RuleLoadBalancingKeyConflict = "loadbalancing.conflicting_routing_keys"
+	RuleLoadBalancingRoutingKey  = "loadbalancing.routing_key_mismatch"
+	RuleLoadBalancingNoReceiver  = "loadbalancing.no_downstream_receiver"
+
+	// Extensions and self-telemetry.
+	RuleExtensionNotEnabled       = "extension.not_enabled"
+	RuleExtensionNotDefined       = "extension.not_defined"
+	RuleDebugExtensionExposed     = "extension.debug_exposed"
+	RuleHealthCheckMissing        = "extension.health_check_missing"
+	RuleProbePortMismatch         = "extension.probe_port_mismatch"
+	RuleHealthCheckUnreachable    = "extension.health_check_unreachable"
+	RuleExtensionBadReference     = "extension.bad_reference"
+	RuleTelemetryMetricsDisabled  = "telemetry.metrics_disabled"
+	RuleTelemetryMetricsLocalhost = "telemetry.metrics_localhost"
+	RuleTelemetryPortUndeclared   = "telemetry.metrics_port_undeclared"
+	RuleTelemetryDebugLogs        = "telemetry.debug_logs"
+
+	// Prometheus scraping and the Target Allocator.
+	RuleScrapeDuplicateJob        = "prometheus.duplicate_job"
+	RuleScrapeTimeoutExceeds      = "prometheus.timeout_exceeds_interval"
+	RuleScrapeUnescapedDollar     = "prometheus.unescaped_dollar"
+	RuleScrapeInvalidRegex        = "prometheus.invalid_regex"
+	RuleTargetAllocatorSidecar    = "target_allocator.sidecar"
+	RuleTargetAllocatorPerNode    = "target_allocator.per_node_strategy"
+	RuleTargetAllocatorDaemonSet  = "target_allocator.daemonset_strategy"
+	RuleTargetAllocatorDeployment = "target_allocator.deployment_mode"
+	RuleTargetAllocatorSelectors  = "target_allocator.no_selectors"
+	RuleTargetAllocatorRBAC       = "target_allocator.rbac"
+
+	// Transport security.
+	RuleTLSVerifyDisabled       = "tls.verify_disabled"
+	RuleTLSIncompletePair       = "tls.incomplete_pair"
+	RuleTLSClientCAWithoutCert  = "tls.client_ca_without_cert"
+	RuleTLSClientCertWithoutCA  = "tls.client_cert_without_ca"
+	RuleTLSWeakMinVersion       = "tls.weak_min_version"
+	RulePlaintextInCluster      = "tls.plaintext_in_cluster"
+	RulePlaintextExternal       = "tls.plaintext_external"
+	RuleReceiverUnauthenticated = "tls.unauthenticated_receiver"
+
+	// Topology across collectors.
+	RuleTopologyLoop          = "topology.loop"
+	RuleTopologyMissingHop    = "topology.missing_hop"
+	RuleTopologyDroppedSignal = "topology.dropped_signal"
+
+	// Runtime analysis of captured signals.
+	RuleRuntimeHighCardinality  = "runtime.high_cardinality"
+	RuleRuntimePII              = "runtime.pii"
+	RuleRuntimeOrphanSpan       = "runtime.orphan_span"
+	RuleRuntimeBloatedAttribute = "runtime.bloated_attribute"
+	RuleRuntimeMissingResource  = "runtime.missing_resource"
+	RuleRuntimeDuplicateSignals = "runtime.duplicate_signals"
+	RuleRuntimeMissingSampling  = "runtime.missing_sampling"
+	RuleRuntimeHighThroughput   = "runtime.high_throughput"
+)